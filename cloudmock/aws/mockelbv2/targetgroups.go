@@ -94,12 +94,13 @@ func (m *MockELBV2) CreateTargetGroup(request *elbv2.CreateTargetGroupInput) (*e
 	klog.Infof("CreateTargetGroup %v", request)
 
 	tg := elbv2.TargetGroup{
-		TargetGroupName:         request.Name,
-		Port:                    request.Port,
-		Protocol:                request.Protocol,
-		VpcId:                   request.VpcId,
-		HealthyThresholdCount:   request.HealthyThresholdCount,
-		UnhealthyThresholdCount: request.UnhealthyThresholdCount,
+		TargetGroupName:            request.Name,
+		Port:                       request.Port,
+		Protocol:                   request.Protocol,
+		VpcId:                      request.VpcId,
+		HealthCheckIntervalSeconds: request.HealthCheckIntervalSeconds,
+		HealthyThresholdCount:      request.HealthyThresholdCount,
+		UnhealthyThresholdCount:    request.UnhealthyThresholdCount,
 	}
 
 	m.tgCount++
@@ -267,6 +267,10 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().StringVar(&options.Image, "image", options.Image, "Machine image for all instances")
 	cmd.RegisterFlagCompletionFunc("image", completeInstanceImage)
+	cmd.Flags().StringVar(&options.ImageDistribution, "image-distribution", options.ImageDistribution, "Distribution of the default machine image for the instances created with the cluster: amazonlinux2023, debian11, debian12, rhel9, rocky9, ubuntu2204, ubuntu2404")
+	cmd.RegisterFlagCompletionFunc("image-distribution", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"amazonlinux2023", "debian11", "debian12", "rhel9", "rocky9", "ubuntu2204", "ubuntu2404"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().StringVar(&options.NodeImage, "node-image", options.NodeImage, "Machine image for worker nodes. Takes precedence over --image")
 	cmd.RegisterFlagCompletionFunc("node-image", completeInstanceImage)
 	cmd.Flags().StringVar(&options.ControlPlaneImage, "control-plane-image", options.ControlPlaneImage, "Machine image for control-plane nodes. Takes precedence over --image")
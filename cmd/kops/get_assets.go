@@ -21,10 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/sbom"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 	"sigs.k8s.io/yaml"
@@ -42,7 +45,15 @@ var (
 	(original) and download (local repository) locations.
 
 	When invoked with the ` + pretty.Bash("--copy") + ` flag, will copy each asset from the
-	canonical to the download location.`))
+	canonical to the download location.
+
+	When invoked with the ` + pretty.Bash("--bundle") + ` flag, will write every asset to a single
+	tarball, for importing into disconnected environments with ` + pretty.Bash("kops toolbox import-assets") + `.
+
+	When invoked with the ` + pretty.Bash("--sbom") + ` flag, will write a software bill of materials of the
+	file and image assets and of the operating system packages that nodeup installs on each instance
+	group, in SPDX or CycloneDX format, instead. The versions of the packages are resolved by the
+	distribution on the nodes; they are listed by running ` + pretty.Bash("nodeup --sbom") + ` on a node.`))
 
 	getAssetsExample = templates.Examples(i18n.T(`
 	# Display all assets.
@@ -50,6 +61,12 @@ var (
 
 	# Copy assets to the local repositories configured in the cluster spec.
 	kops get assets --copy 
+
+	# Write all assets to a bundle.
+	kops get assets --bundle assets.tar
+
+	# Write a software bill of materials in SPDX format.
+	kops get assets --sbom spdx > sbom.spdx.json
 	`))
 
 	getAssetsShort = i18n.T(`Display assets for cluster.`)
@@ -58,6 +75,10 @@ var (
 type GetAssetsOptions struct {
 	*GetOptions
 	Copy bool
+	// Bundle is the path of a bundle to write the assets to
+	Bundle string
+	// SBOM is the format of a software bill of materials to write instead of the assets
+	SBOM string
 }
 
 type Image struct {
@@ -69,6 +90,8 @@ type File struct {
 	Canonical string `json:"canonical"`
 	Download  string `json:"download"`
 	SHA       string `json:"sha"`
+	// Mirrors are the locations tried in order when the file cannot be downloaded from Download
+	Mirrors []string `json:"mirrors,omitempty"`
 }
 
 type AssetResult struct {
@@ -96,11 +119,24 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	}
 
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
+	cmd.Flags().StringVar(&options.Bundle, "bundle", options.Bundle, "write assets to a bundle at this path, for kops toolbox import-assets")
+	cmd.MarkFlagFilename("bundle", "tar")
+	cmd.Flags().StringVar(&options.SBOM, "sbom", options.SBOM, "write a software bill of materials of the assets in this format - "+strings.Join(sbom.Formats, ", "))
+	cmd.RegisterFlagCompletionFunc("sbom", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return sbom.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
 func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *GetAssetsOptions) error {
+	if options.SBOM != "" {
+		// Fail before building the cluster if the format is unknown
+		if err := (&sbom.Document{}).Write(io.Discard, sbom.Format(options.SBOM)); err != nil {
+			return err
+		}
+	}
+
 	updateClusterResults, err := RunUpdateCluster(ctx, f, out, &UpdateClusterOptions{
 		Target:      cloudup.TargetDryRun,
 		GetAssets:   true,
@@ -134,6 +170,9 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 			Download:  fileAsset.DownloadURL.String(),
 			SHA:       fileAsset.SHAValue,
 		}
+		for _, mirrorURL := range fileAsset.MirrorURLs {
+			file.Mirrors = append(file.Mirrors, mirrorURL.String())
+		}
 		if !seen[file.Canonical] {
 			result.Files = append(result.Files, &file)
 			seen[file.Canonical] = true
@@ -147,6 +186,17 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		}
 	}
 
+	if options.Bundle != "" {
+		if err := writeAssetsBundle(ctx, options.Bundle, updateClusterResults.ImageAssets, updateClusterResults.FileAssets); err != nil {
+			return err
+		}
+	}
+
+	if options.SBOM != "" {
+		doc := assets.BuildSBOM(ctx, updateClusterResults.Cluster.ObjectMeta.Name, updateClusterResults.ImageAssets, updateClusterResults.FileAssets, updateClusterResults.InstanceGroups, true)
+		return doc.Write(out, sbom.Format(options.SBOM))
+	}
+
 	switch options.Output {
 	case OutputTable:
 		if err = imageOutputTable(result.Images, out); err != nil {
@@ -202,7 +252,31 @@ func fileOutputTable(files []*File, out io.Writer) error {
 	t.AddColumn("SHA", func(f *File) string {
 		return f.SHA
 	})
+	t.AddColumn("MIRRORS", func(f *File) string {
+		return strings.Join(f.Mirrors, ",")
+	})
 
 	columns := []string{"CANONICAL", "DOWNLOAD", "SHA"}
+	for _, f := range files {
+		if len(f.Mirrors) != 0 {
+			columns = append(columns, "MIRRORS")
+			break
+		}
+	}
 	return t.Render(files, out, columns...)
 }
+
+func writeAssetsBundle(ctx context.Context, bundlePath string, imageAssets []*assets.ImageAsset, fileAssets []*assets.FileAsset) error {
+	f, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+	if err := assets.WriteBundle(ctx, f, imageAssets, fileAssets); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdbackup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getEtcdBackupsLong = templates.LongDesc(i18n.T(`
+	Display the etcd backups of a cluster.
+
+	Backups are taken periodically by etcd-manager and stored in the backup store
+	of each etcd cluster.`))
+
+	getEtcdBackupsExample = templates.Examples(i18n.T(`
+	# List the etcd backups of a cluster
+	kops get etcd-backups k8s-cluster.example.com
+
+	# List the backups of the main etcd cluster
+	kops get etcd-backups k8s-cluster.example.com --cluster main
+	`))
+
+	getEtcdBackupsShort = i18n.T(`Get the etcd backups of a cluster.`)
+)
+
+type GetEtcdBackupsOptions struct {
+	*GetOptions
+
+	// EtcdCluster is the etcd cluster whose backups are listed; all etcd clusters if empty
+	EtcdCluster string
+}
+
+type etcdBackupItem struct {
+	EtcdCluster string `json:"etcdCluster"`
+	*etcdbackup.Backup
+}
+
+func NewCmdGetEtcdBackups(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetEtcdBackupsOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "etcd-backups [CLUSTER]",
+		Aliases:           []string{"etcd-backup"},
+		Short:             getEtcdBackupsShort,
+		Long:              getEtcdBackupsLong,
+		Example:           getEtcdBackupsExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetEtcdBackups(cmd.Context(), f, out, &options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.EtcdCluster, "cluster", options.EtcdCluster, "Name of the etcd cluster whose backups are listed, all etcd clusters if not specified")
+	cmd.RegisterFlagCompletionFunc("cluster", completeEtcdClusterName(f))
+
+	return cmd
+}
+
+func RunGetEtcdBackups(ctx context.Context, f commandutils.Factory, out io.Writer, options *GetEtcdBackupsOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if options.EtcdCluster != "" && findEtcdCluster(cluster, options.EtcdCluster) == nil {
+		return fmt.Errorf("etcd cluster %q not found in cluster %q", options.EtcdCluster, cluster.Name)
+	}
+
+	var items []*etcdBackupItem
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		if options.EtcdCluster != "" && etcdCluster.Name != options.EtcdCluster {
+			continue
+		}
+
+		store, err := etcdbackup.NewStore(cluster, etcdCluster.Name)
+		if err != nil {
+			return err
+		}
+		backups, err := store.ListBackups(ctx)
+		if err != nil {
+			return err
+		}
+		for _, backup := range backups {
+			items = append(items, &etcdBackupItem{
+				EtcdCluster: etcdCluster.Name,
+				Backup:      backup,
+			})
+		}
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if len(items) == 0 {
+			return fmt.Errorf("no etcd backups found")
+		}
+		t := &tables.Table{}
+		t.AddColumn("ETCD-CLUSTER", func(i *etcdBackupItem) string {
+			return i.EtcdCluster
+		})
+		t.AddColumn("NAME", func(i *etcdBackupItem) string {
+			return i.Name
+		})
+		t.AddColumn("TIMESTAMP", func(i *etcdBackupItem) string {
+			if i.Timestamp.IsZero() {
+				return ""
+			}
+			return i.Timestamp.Format(time.RFC3339)
+		})
+		t.AddColumn("ETCD-VERSION", func(i *etcdBackupItem) string {
+			return i.EtcdVersion
+		})
+		return t.Render(items, out, "ETCD-CLUSTER", "NAME", "TIMESTAMP", "ETCD-VERSION")
+
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+func findEtcdCluster(cluster *kopsapi.Cluster, name string) *kopsapi.EtcdClusterSpec {
+	for i := range cluster.Spec.EtcdClusters {
+		if cluster.Spec.EtcdClusters[i].Name == name {
+			return &cluster.Spec.EtcdClusters[i]
+		}
+	}
+	return nil
+}
+
+func completeEtcdClusterName(f commandutils.Factory) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		commandutils.ConfigureKlogForCompletion()
+		ctx := cmd.Context()
+
+		cluster, _, _, directive := GetClusterForCompletion(ctx, f, args)
+		if cluster == nil {
+			return nil, directive
+		}
+
+		var names []string
+		for _, etcdCluster := range cluster.Spec.EtcdClusters {
+			names = append(names, etcdCluster.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getHistoryLong = templates.LongDesc(i18n.T(`
+	Display the revision history of a cluster's configuration.
+
+	A revision is recorded in the state store every time the cluster, its instance groups
+	or its addons are written, for example by kops edit or kops replace.`))
+
+	getHistoryExample = templates.Examples(i18n.T(`
+	# List the revisions of a cluster's configuration
+	kops get history k8s-cluster.example.com
+
+	# Show the changes made in revision 3
+	kops get history k8s-cluster.example.com --revision 3
+	`))
+
+	getHistoryShort = i18n.T(`Get the revision history of a cluster's configuration.`)
+)
+
+type GetHistoryOptions struct {
+	*GetOptions
+
+	// Revision is the revision whose changes should be shown, compared to the previous revision
+	Revision int
+}
+
+func NewCmdGetHistory(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetHistoryOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "history [CLUSTER]",
+		Aliases:           []string{"revisions"},
+		Short:             getHistoryShort,
+		Long:              getHistoryLong,
+		Example:           getHistoryExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetHistory(cmd.Context(), f, out, &options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.Revision, "revision", options.Revision, "Show the changes made in this revision")
+
+	return cmd
+}
+
+func RunGetHistory(ctx context.Context, f commandutils.Factory, out io.Writer, options *GetHistoryOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	history, err := clientset.HistoryFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	if options.Revision != 0 {
+		after, err := history.Get(ctx, options.Revision)
+		if err != nil {
+			return err
+		}
+		afterText, err := renderClusterSnapshot(after)
+		if err != nil {
+			return err
+		}
+
+		beforeText := ""
+		if options.Revision > 1 {
+			before, err := history.Get(ctx, options.Revision-1)
+			if err != nil {
+				return err
+			}
+			beforeText, err = renderClusterSnapshot(before)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(out, "Revision %d (%s): %s\n\n%s", after.Revision, after.Timestamp.Format(time.RFC3339), after.Reason, diff.FormatDiff(beforeText, afterText))
+		return err
+	}
+
+	revisions, err := history.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if len(revisions) == 0 {
+			return fmt.Errorf("no revisions found for cluster %q", cluster.Name)
+		}
+		t := &tables.Table{}
+		t.AddColumn("REVISION", func(r *simple.ClusterRevision) string {
+			return strconv.Itoa(r.Revision)
+		})
+		t.AddColumn("TIMESTAMP", func(r *simple.ClusterRevision) string {
+			return r.Timestamp.Format(time.RFC3339)
+		})
+		t.AddColumn("USER", func(r *simple.ClusterRevision) string {
+			return r.User
+		})
+		t.AddColumn("REASON", func(r *simple.ClusterRevision) string {
+			return r.Reason
+		})
+		return t.Render(revisions, out, "REVISION", "TIMESTAMP", "USER", "REASON")
+
+	case OutputYaml:
+		y, err := yaml.Marshal(revisions)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(revisions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return nil
+}
+
+// currentClusterSnapshot builds a snapshot of the configuration currently in the state store,
+// so that it can be compared against a recorded revision.
+func currentClusterSnapshot(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster) (*simple.ClusterSnapshot, error) {
+	snapshot := &simple.ClusterSnapshot{
+		Cluster: cluster,
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		snapshot.InstanceGroups = append(snapshot.InstanceGroups, &list.Items[i])
+	}
+
+	snapshot.AdditionalObjects, err = clientset.AddonsFor(cluster).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// renderClusterSnapshot renders the objects in a snapshot as YAML, in a stable order suitable for diffing.
+func renderClusterSnapshot(snapshot *simple.ClusterSnapshot) (string, error) {
+	var sections []string
+
+	cluster := snapshot.Cluster.DeepCopy()
+	clearServerManagedFields(&cluster.ObjectMeta)
+	y, err := kopscodecs.ToVersionedYaml(cluster)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling cluster: %v", err)
+	}
+	sections = append(sections, string(y))
+
+	var instanceGroups []*kopsapi.InstanceGroup
+	for _, ig := range snapshot.InstanceGroups {
+		ig = ig.DeepCopy()
+		clearServerManagedFields(&ig.ObjectMeta)
+		instanceGroups = append(instanceGroups, ig)
+	}
+	sort.Slice(instanceGroups, func(i, j int) bool {
+		return instanceGroups[i].Name < instanceGroups[j].Name
+	})
+	for _, ig := range instanceGroups {
+		y, err := kopscodecs.ToVersionedYaml(ig)
+		if err != nil {
+			return "", fmt.Errorf("error marshaling instance group %q: %v", ig.Name, err)
+		}
+		sections = append(sections, string(y))
+	}
+
+	if len(snapshot.AdditionalObjects) != 0 {
+		y, err := snapshot.AdditionalObjects.ToYAML()
+		if err != nil {
+			return "", fmt.Errorf("error marshaling addons: %v", err)
+		}
+		sections = append(sections, string(y))
+	}
+
+	if snapshot.CompletedCluster != nil {
+		y, err := kopscodecs.ToVersionedYaml(snapshot.CompletedCluster)
+		if err != nil {
+			return "", fmt.Errorf("error marshaling completed cluster: %v", err)
+		}
+		sections = append(sections, "# "+registry.PathClusterCompleted+"\n"+string(y))
+	}
+
+	return strings.Join(sections, "---\n"), nil
+}
+
+// clearServerManagedFields clears the metadata that the state store manages,
+// so that it does not show up as a difference between revisions.
+func clearServerManagedFields(meta *metav1.ObjectMeta) {
+	meta.Generation = 0
+	meta.CreationTimestamp = metav1.Time{}
+	meta.ResourceVersion = ""
+	meta.UID = ""
+	meta.SelfLink = ""
+	meta.ManagedFields = nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+)
+
+func TestRenderClusterSnapshot_IgnoresServerManagedFields(t *testing.T) {
+	buildSnapshot := func(generation int64, created time.Time, version string) *simple.ClusterSnapshot {
+		cluster := &kopsapi.Cluster{}
+		cluster.Name = "minimal.example.com"
+		cluster.Generation = generation
+		cluster.CreationTimestamp = metav1.NewTime(created)
+		cluster.Spec.KubernetesVersion = version
+
+		ig := &kopsapi.InstanceGroup{}
+		ig.Name = "nodes"
+		ig.Generation = generation
+		ig.CreationTimestamp = metav1.NewTime(created)
+
+		return &simple.ClusterSnapshot{
+			Cluster:        cluster,
+			InstanceGroups: []*kopsapi.InstanceGroup{ig},
+		}
+	}
+
+	current := buildSnapshot(7, time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC), "1.26.0")
+	revision := buildSnapshot(3, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), "1.26.0")
+
+	currentText, err := renderClusterSnapshot(current)
+	if err != nil {
+		t.Fatalf("error rendering snapshot: %v", err)
+	}
+	revisionText, err := renderClusterSnapshot(revision)
+	if err != nil {
+		t.Fatalf("error rendering snapshot: %v", err)
+	}
+	if currentText != revisionText {
+		t.Errorf("expected snapshots differing only in server-managed fields to match:\n%s\n---\n%s", currentText, revisionText)
+	}
+	if current.Cluster.Generation != 7 {
+		t.Errorf("rendering should not modify the snapshot")
+	}
+
+	changed := buildSnapshot(3, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), "1.27.0")
+	changedText, err := renderClusterSnapshot(changed)
+	if err != nil {
+		t.Fatalf("error rendering snapshot: %v", err)
+	}
+	if changedText == revisionText {
+		t.Errorf("expected snapshots with different specs to differ")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdbackup"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	restoreEtcdLong = pretty.LongDesc(i18n.T(`
+	Restores an etcd cluster from a backup listed by ` + pretty.Bash("kops get etcd-backups") + `.
+
+	This adds a restore command to the backup store of the etcd cluster. etcd-manager executes
+	the command the next time it starts on the control plane nodes, so etcd-manager has to be
+	restarted on all control plane nodes, for example by rolling them.
+
+	A restore involves downtime of the Kubernetes API and cannot be undone, other than by
+	restoring another backup. Any changes made after the backup was taken are lost.
+	`))
+
+	restoreEtcdExample = templates.Examples(i18n.T(`
+	# Restore the main etcd cluster from a backup
+	kops restore etcd k8s-cluster.example.com --cluster main --backup 2023-03-01T10:00:00Z-000001 --yes
+	`))
+
+	restoreEtcdShort = i18n.T("Restore an etcd cluster from a backup.")
+)
+
+type RestoreEtcdOptions struct {
+	ClusterName string
+	// EtcdCluster is the name of the etcd cluster to restore
+	EtcdCluster string
+	// Backup is the name of the backup to restore
+	Backup string
+	Yes    bool
+}
+
+func NewCmdRestoreEtcd(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RestoreEtcdOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "etcd [CLUSTER]",
+		Short:             restoreEtcdShort,
+		Long:              restoreEtcdLong,
+		Example:           restoreEtcdExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRestoreEtcd(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.EtcdCluster, "cluster", options.EtcdCluster, "Name of the etcd cluster to restore")
+	cmd.MarkFlagRequired("cluster")
+	cmd.RegisterFlagCompletionFunc("cluster", completeEtcdClusterName(f))
+	cmd.Flags().StringVar(&options.Backup, "backup", options.Backup, "Name of the backup to restore")
+	cmd.MarkFlagRequired("backup")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Add the restore command, without --yes only the backup is checked")
+
+	return cmd
+}
+
+func RunRestoreEtcd(ctx context.Context, f *util.Factory, out io.Writer, options *RestoreEtcdOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	store, err := etcdbackup.NewStore(cluster, options.EtcdCluster)
+	if err != nil {
+		return err
+	}
+
+	backups, err := store.ListBackups(ctx)
+	if err != nil {
+		return err
+	}
+	var backup *etcdbackup.Backup
+	for _, b := range backups {
+		if b.Name == options.Backup {
+			backup = b
+		}
+	}
+	if backup == nil {
+		return fmt.Errorf("backup %q not found for etcd cluster %q; use %q to list the backups", options.Backup, options.EtcdCluster, "kops get etcd-backups")
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Backup %q of etcd cluster %q can be restored from %s\n", backup.Name, options.EtcdCluster, store.Path())
+		fmt.Fprintf(out, "Must specify --yes to restore\n")
+		return nil
+	}
+
+	if err := store.AddRestoreCommand(ctx, backup.Name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Added command to restore backup %q of etcd cluster %q.\n", backup.Name, options.EtcdCluster)
+	fmt.Fprintf(out, "The backup is restored when etcd-manager restarts; restart etcd-manager on all control plane nodes,\n")
+	fmt.Fprintf(out, "for example with %q.\n", "kops rolling-update cluster "+cluster.Name+" --instance-group-roles=control-plane --force --yes")
+
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollbackClusterLong = pretty.LongDesc(i18n.T(`
+	Restores the configuration of a cluster, its instance groups and its addons in the state store
+	to a revision listed by ` + pretty.Bash("kops get history") + `. Without ` + pretty.Bash("--yes") + `, only
+	the changes that would be made are shown.
+
+	This only changes the configuration in the state store; use ` + pretty.Bash("kops update cluster") + `
+	to apply it to the cloud resources. Until then, nodes keep using the completed configuration that
+	was last applied.
+	`))
+
+	rollbackClusterExample = templates.Examples(i18n.T(`
+	# Preview rolling back the configuration to revision 3
+	kops rollback cluster k8s-cluster.example.com --to 3
+
+	# Roll back the configuration to revision 3
+	kops rollback cluster k8s-cluster.example.com --to 3 --yes
+	`))
+
+	rollbackClusterShort = i18n.T("Roll back a cluster's configuration to a previous revision.")
+)
+
+type RollbackClusterOptions struct {
+	ClusterName string
+	// Revision is the revision to restore
+	Revision int
+	Yes      bool
+}
+
+func NewCmdRollbackCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RollbackClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             rollbackClusterShort,
+		Long:              rollbackClusterLong,
+		Example:           rollbackClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRollbackCluster(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.Revision, "to", options.Revision, "Revision to roll back to")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Roll back the configuration, without --yes only the changes are shown")
+
+	return cmd
+}
+
+func RunRollbackCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollbackClusterOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	history, err := clientset.HistoryFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	target, err := history.Get(ctx, options.Revision)
+	if err != nil {
+		return err
+	}
+
+	current, err := currentClusterSnapshot(ctx, clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	// The completed spec is derived by kops update cluster, so it is not rolled back
+	target.CompletedCluster = nil
+
+	currentText, err := renderClusterSnapshot(current)
+	if err != nil {
+		return err
+	}
+	targetText, err := renderClusterSnapshot(target)
+	if err != nil {
+		return err
+	}
+	if currentText == targetText {
+		fmt.Fprintf(out, "Configuration of cluster %q already matches revision %d\n", cluster.Name, target.Revision)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Rolling back cluster %q to revision %d (%s) would make these changes:\n\n", cluster.Name, target.Revision, target.Reason)
+	fmt.Fprintf(out, "%s\n", diff.FormatDiff(currentText, targetText))
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Must specify --yes to roll back\n")
+		return nil
+	}
+
+	// Write the revision on top of the current objects, so that concurrent changes are still detected.
+	target.Cluster.SetGeneration(cluster.GetGeneration())
+
+	cloud, err := cloudup.BuildCloud(target.Cluster)
+	if err != nil {
+		return err
+	}
+	status, err := cloud.FindClusterStatus(target.Cluster)
+	if err != nil {
+		return err
+	}
+
+	// Check that no instance group changed since the diff was computed, so the rollback does not stop partway through
+	igClient := clientset.InstanceGroupsFor(target.Cluster)
+	if err := checkInstanceGroupGenerations(ctx, igClient, current.InstanceGroups); err != nil {
+		return err
+	}
+
+	existing := make(map[string]int64)
+	for _, ig := range current.InstanceGroups {
+		existing[ig.Name] = ig.GetGeneration()
+	}
+
+	// The writes are recorded as a single revision
+	err = history.Batch(ctx, fmt.Sprintf("rollback to revision %d", target.Revision), func() error {
+		if _, err := clientset.UpdateCluster(ctx, target.Cluster, status); err != nil {
+			return fmt.Errorf("error rolling back cluster: %v", err)
+		}
+
+		for _, ig := range target.InstanceGroups {
+			if generation, found := existing[ig.Name]; found {
+				ig.SetGeneration(generation)
+				if _, err := igClient.Update(ctx, ig, metav1.UpdateOptions{}); err != nil {
+					return fmt.Errorf("error rolling back instance group %q: %v", ig.Name, err)
+				}
+				delete(existing, ig.Name)
+			} else {
+				ig.SetGeneration(0)
+				if _, err := igClient.Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+					return fmt.Errorf("error restoring instance group %q: %v", ig.Name, err)
+				}
+			}
+		}
+		for name := range existing {
+			if err := igClient.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+				return fmt.Errorf("error deleting instance group %q: %v", name, err)
+			}
+		}
+
+		if len(target.AdditionalObjects) != 0 || len(current.AdditionalObjects) != 0 {
+			if err := clientset.AddonsFor(target.Cluster).Replace(target.AdditionalObjects); err != nil {
+				return fmt.Errorf("error rolling back addons: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nCluster configuration rolled back to revision %d.\n", target.Revision)
+	fmt.Fprintf(out, "Run %q to apply the configuration to the cluster.\n", "kops update cluster "+cluster.Name)
+
+	return nil
+}
+
+// checkInstanceGroupGenerations returns an error if the instance groups in the state store
+// are not the ones that were read, at the same generations.
+func checkInstanceGroupGenerations(ctx context.Context, igClient internalversion.InstanceGroupInterface, instanceGroups []*kopsapi.InstanceGroup) error {
+	list, err := igClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	read := make(map[string]int64)
+	for _, ig := range instanceGroups {
+		read[ig.Name] = ig.GetGeneration()
+	}
+	for _, ig := range list.Items {
+		generation, found := read[ig.Name]
+		if !found {
+			return fmt.Errorf("instance group %q was created after the configuration was read; run the rollback again", ig.Name)
+		}
+		if ig.GetGeneration() != generation {
+			return fmt.Errorf("instance group %q is at generation %d in the state store, but generation %d was read; run the rollback again", ig.Name, ig.GetGeneration(), generation)
+		}
+		delete(read, ig.Name)
+	}
+	for name := range read {
+		return fmt.Errorf("instance group %q was deleted after the configuration was read; run the rollback again", name)
+	}
+	return nil
+}
@@ -32,8 +32,13 @@ func NewCmdToolbox(f commandutils.Factory, out io.Writer) *cobra.Command {
 		Short: toolboxShort,
 	}
 
+	cmd.AddCommand(NewCmdToolboxBakeImage(f, out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
+	cmd.AddCommand(NewCmdToolboxEtcdBackup(f, out))
+	cmd.AddCommand(NewCmdToolboxHardening(f, out))
+	cmd.AddCommand(NewCmdToolboxImportAssets(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
+	cmd.AddCommand(NewCmdToolboxTaskGraph(f, out))
 	cmd.AddCommand(NewCmdToolboxInstanceSelector(f, out))
 	cmd.AddCommand(NewCmdToolboxAddons(out))
 
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diskimage"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/prebake"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBakeImageLong = templates.LongDesc(i18n.T(`
+	Installs the assets and container images of an instance group into a node image.
+
+	The nodeup binary, the assets such as kubelet, containerd, runc and the CNI plugins,
+	and the container images of the instance group are downloaded into the nodeup cache
+	of a root filesystem, together with a manifest of what was installed. When an
+	instance boots from the image, nodeup skips the assets whose hash matches, and
+	imports the images instead of pulling them.
+
+	The root filesystem is either a directory, such as a chroot, or a partition of a raw
+	or qcow2 VM disk image. Mounting a disk image requires root privileges, and qemu-nbd
+	for qcow2 images. The cluster must have been updated with "kops update cluster --yes"
+	so the configuration of the instance group is in the state store.`))
+
+	toolboxBakeImageExample = templates.Examples(i18n.T(`
+	# Bake the assets of the nodes instance group into a qcow2 image
+	kops toolbox bake-image k8s-cluster.example.com --instance-group nodes \
+		--disk ubuntu.qcow2 --output nodes.qcow2
+
+	# Bake the assets of an arm64 instance group into a chroot
+	kops toolbox bake-image k8s-cluster.example.com --instance-group nodes-arm64 \
+		--arch arm64 --root /mnt/chroot
+	`))
+
+	toolboxBakeImageShort = i18n.T(`Install the assets of an instance group into a node image.`)
+)
+
+type ToolboxBakeImageOptions struct {
+	ClusterName string
+	// InstanceGroup is the instance group whose assets are installed
+	InstanceGroup string
+	// Architecture is the architecture of the node image
+	Architecture string
+	// Root is the root directory of the node image
+	Root string
+	// Disk is the raw or qcow2 disk image of the node image
+	Disk string
+	// Partition is the partition of the root filesystem in the disk image
+	Partition int
+	// Output is the disk image to write, leaving Disk unchanged; the format is taken from the file extension
+	Output string
+}
+
+func NewCmdToolboxBakeImage(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBakeImageOptions{
+		Architecture: string(architectures.ArchitectureAmd64),
+		Partition:    1,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "bake-image [CLUSTER]",
+		Short:             toolboxBakeImageShort,
+		Long:              toolboxBakeImageLong,
+		Example:           toolboxBakeImageExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBakeImage(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Instance group whose assets are installed")
+	cmd.MarkFlagRequired("instance-group")
+	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(f, nil, nil))
+	cmd.Flags().StringVar(&options.Architecture, "arch", options.Architecture, "Architecture of the node image: amd64 or arm64")
+	cmd.RegisterFlagCompletionFunc("arch", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var archs []string
+		for _, arch := range architectures.GetSupported() {
+			archs = append(archs, string(arch))
+		}
+		return archs, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.Root, "root", options.Root, "Root directory of the node image")
+	cmd.MarkFlagDirname("root")
+	cmd.Flags().StringVar(&options.Disk, "disk", options.Disk, "Raw or qcow2 disk image of the node image")
+	cmd.MarkFlagFilename("disk", "img", "raw", "qcow2")
+	cmd.Flags().IntVar(&options.Partition, "partition", options.Partition, "Partition of the root filesystem in the disk image, or 0 if the disk image has no partition table")
+	cmd.Flags().StringVar(&options.Output, "output", options.Output, "Disk image to write instead of modifying the disk image: the format is taken from the file extension")
+	cmd.MarkFlagFilename("output", "img", "raw", "qcow2")
+	cmd.MarkFlagsMutuallyExclusive("root", "disk")
+
+	return cmd
+}
+
+func RunToolboxBakeImage(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxBakeImageOptions) error {
+	if options.Root == "" && options.Disk == "" {
+		return fmt.Errorf("either --root or --disk is required")
+	}
+	if options.Output != "" && options.Disk == "" {
+		return fmt.Errorf("--output requires --disk")
+	}
+
+	arch := architectures.Architecture(options.Architecture)
+	supported := false
+	for _, a := range architectures.GetSupported() {
+		if a == arch {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported architecture %q", options.Architecture)
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading instance group %q: %w", options.InstanceGroup, err)
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+	configPath := configBase.Join("igconfig", ig.Spec.Role.ToLowerString(), ig.Name, "nodeupconfig.yaml")
+	b, err := configPath.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("nodeup config %q not found, run \"kops update cluster --yes\" first", configPath)
+		}
+		return fmt.Errorf("error reading nodeup config %q: %w", configPath, err)
+	}
+	config := &nodeup.Config{}
+	if err := utils.YamlUnmarshal(b, config); err != nil {
+		return fmt.Errorf("error parsing nodeup config %q: %w", configPath, err)
+	}
+	if _, found := config.Assets[arch]; !found {
+		return fmt.Errorf("instance group %q has no assets for architecture %q", ig.Name, arch)
+	}
+
+	assetBuilder := assets.NewAssetBuilder(cluster.Spec.Assets, cluster.Spec.KubernetesVersion, false)
+	nodeUpAsset, err := cloudup.NodeUpAsset(assetBuilder, arch)
+	if err != nil {
+		return err
+	}
+
+	root := options.Root
+	if options.Disk != "" {
+		disk := options.Disk
+		if options.Output != "" {
+			if diskimage.FormatFromPath(options.Disk) == diskimage.FormatFromPath(options.Output) {
+				if err := copyFile(options.Disk, options.Output); err != nil {
+					return err
+				}
+			} else if err := diskimage.Convert(options.Disk, options.Output); err != nil {
+				return err
+			}
+			disk = options.Output
+		}
+
+		mount, err := diskimage.MountImage(disk, options.Partition)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := mount.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "error unmounting %q: %v\n", disk, err)
+			}
+		}()
+		root = mount.Dir
+	}
+
+	p := &prebake.Prebake{
+		Config:        config,
+		InstanceGroup: ig.Name,
+		Architecture:  arch,
+		Root:          root,
+		NodeupBinary:  nodeUpAsset.Hash.String() + "@" + strings.Join(nodeUpAsset.Locations, ","),
+	}
+	if err := p.Run(); err != nil {
+		return err
+	}
+
+	if options.Output != "" {
+		fmt.Fprintf(out, "Baked instance group %q into %s\n", ig.Name, options.Output)
+	} else if options.Disk != "" {
+		fmt.Fprintf(out, "Baked instance group %q into %s\n", ig.Name, options.Disk)
+	} else {
+		fmt.Fprintf(out, "Baked instance group %q into %s\n", ig.Name, options.Root)
+	}
+	return nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("error copying %q to %q: %w", src, dest, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/etcdbackup"
+	"k8s.io/kops/pkg/model/components/etcdmanager"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxEtcdBackupLong = templates.LongDesc(i18n.T(`
+	Takes a backup of the etcd clusters used by the Kubernetes API, in addition to the
+	periodic backups taken by etcd-manager.
+
+	The snapshot is taken by running etcdctl in an etcd-manager pod, using the current
+	kubectl context, and is stored in the backup store of the etcd cluster where it can
+	be restored with kops restore etcd.`))
+
+	toolboxEtcdBackupExample = templates.Examples(i18n.T(`
+	# Back up all etcd clusters of a cluster
+	kops toolbox etcd-backup k8s-cluster.example.com
+
+	# Back up the main etcd cluster
+	kops toolbox etcd-backup k8s-cluster.example.com --cluster main
+	`))
+
+	toolboxEtcdBackupShort = i18n.T(`Take an on-demand backup of etcd.`)
+)
+
+// etcdManagerPKIDir is where etcd-manager finds the CAs of its etcd cluster, in the etcd-manager container.
+// It exists on every etcd member, whether or not the member also runs kube-apiserver.
+const etcdManagerPKIDir = "/etc/kubernetes/pki/etcd-manager"
+
+// etcdBackupCertificateValidity is the validity of the client certificate issued to take a backup
+const etcdBackupCertificateValidity = time.Hour
+
+type ToolboxEtcdBackupOptions struct {
+	ClusterName string
+	// EtcdCluster is the etcd cluster to back up; all etcd clusters used by kube-apiserver if empty
+	EtcdCluster string
+}
+
+func NewCmdToolboxEtcdBackup(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxEtcdBackupOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "etcd-backup [CLUSTER]",
+		Short:             toolboxEtcdBackupShort,
+		Long:              toolboxEtcdBackupLong,
+		Example:           toolboxEtcdBackupExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxEtcdBackup(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.EtcdCluster, "cluster", options.EtcdCluster, "Name of the etcd cluster to back up, all etcd clusters used by kube-apiserver if not specified")
+	cmd.RegisterFlagCompletionFunc("cluster", completeEtcdClusterName(f))
+
+	return cmd
+}
+
+func RunToolboxEtcdBackup(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxEtcdBackupOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	var etcdClusters []kops.EtcdClusterSpec
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		if options.EtcdCluster != "" && etcdCluster.Name != options.EtcdCluster {
+			continue
+		}
+		if options.EtcdCluster == "" && !apimodel.IsAPIServerEtcdCluster(etcdCluster) {
+			continue
+		}
+		etcdClusters = append(etcdClusters, etcdCluster)
+	}
+	if len(etcdClusters) == 0 {
+		return fmt.Errorf("etcd cluster %q not found in cluster %q", options.EtcdCluster, cluster.Name)
+	}
+
+	contextName := cluster.ObjectMeta.Name
+	clientGetter := genericclioptions.NewConfigFlags(true)
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kubernetes api client for %q: %v", contextName, err)
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	// The etcd clusters used by kube-apiserver share a client CA, so one client certificate works for all of them
+	clientCert, clientKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Signer:   "etcd-clients-ca",
+		Type:     "client",
+		Subject:  pkix.Name{CommonName: "kops-etcd-backup"},
+		Validity: etcdBackupCertificateValidity,
+	}, fi.NewPKIKeystoreAdapter(keyStore))
+	if err != nil {
+		return fmt.Errorf("error issuing etcd client certificate: %w", err)
+	}
+	clientCertPEM, err := clientCert.AsString()
+	if err != nil {
+		return err
+	}
+	clientKeyPEM, err := clientKey.AsString()
+	if err != nil {
+		return err
+	}
+
+	for _, etcdCluster := range etcdClusters {
+		if !apimodel.IsAPIServerEtcdCluster(etcdCluster) {
+			return fmt.Errorf("on-demand backups are only supported for the etcd clusters used by kube-apiserver, not %q", etcdCluster.Name)
+		}
+
+		ports, err := etcdmanager.PortsForCluster(&cluster.Spec, etcdCluster)
+		if err != nil {
+			return err
+		}
+
+		store, err := etcdbackup.NewStore(cluster, etcdCluster.Name)
+		if err != nil {
+			return err
+		}
+		spec, err := store.ExpectedClusterSpec(ctx)
+		if err != nil {
+			return err
+		}
+
+		pod, err := findEtcdManagerPod(ctx, k8sClient, etcdCluster.Name)
+		if err != nil {
+			return err
+		}
+
+		klog.Infof("taking snapshot of etcd cluster %q in pod %s", etcdCluster.Name, pod.Name)
+
+		// The exec command is part of the request URL, which kube-apiserver logs, so the credentials are sent over stdin
+		script := etcdSnapshotScript(etcdCluster.Name, spec.EtcdVersion, ports.ClientPort)
+
+		req := k8sClient.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(pod.Namespace).
+			Name(pod.Name).
+			SubResource("exec").
+			VersionedParams(&corev1.PodExecOptions{
+				Container: "etcd-manager",
+				Command:   []string{"/bin/sh", "-c", script},
+				Stdin:     true,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+
+		executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+		if err != nil {
+			return fmt.Errorf("error connecting to pod %s: %w", pod.Name, err)
+		}
+
+		// The snapshot is streamed to the backup store as it is read from the pod
+		snapshot, snapshotWriter := io.Pipe()
+		var stderr bytes.Buffer
+		streamErr := make(chan error, 1)
+		go func() {
+			err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+				Stdin:  strings.NewReader(clientCertPEM + clientKeyPEM),
+				Stdout: snapshotWriter,
+				Stderr: &stderr,
+			})
+			snapshotWriter.CloseWithError(err)
+			streamErr <- err
+		}()
+
+		name, err := store.AddBackup(ctx, spec.EtcdVersion, snapshot)
+		snapshot.Close()
+		if execErr := <-streamErr; execErr != nil {
+			return fmt.Errorf("error taking snapshot of etcd cluster %q: %w: %s", etcdCluster.Name, execErr, stderr.String())
+		}
+		if err != nil {
+			return fmt.Errorf("error storing backup of etcd cluster %q: %w", etcdCluster.Name, err)
+		}
+		klog.V(2).Infof("etcdctl output: %s", stderr.String())
+
+		fmt.Fprintf(out, "Backed up etcd cluster %q to %s\n", etcdCluster.Name, store.Path().Join(name))
+	}
+
+	return nil
+}
+
+// etcdSnapshotScript builds the script that writes a snapshot of the etcd cluster to stdout, in the etcd-manager container.
+// The client certificate and key are read from stdin, as PEM blocks, into a temporary directory
+// that is removed with the snapshot when the script exits.
+func etcdSnapshotScript(etcdClusterName string, etcdVersion string, clientPort int) string {
+	dir := "/tmp/kops-etcd-backup-" + etcdClusterName
+	snapshotFile := dir + "/snapshot.db"
+	return strings.Join([]string{
+		"set -e",
+		"umask 077",
+		fmt.Sprintf("trap 'rm -rf %s' EXIT", dir),
+		fmt.Sprintf("mkdir -p -m 0700 %s", dir),
+		fmt.Sprintf("cat > %s/client.pem", dir),
+		// etcdctl reads the certificate and the key from the PEM blocks of the same file
+		fmt.Sprintf("ETCDCTL_API=3 /opt/etcd-v%s/etcdctl --cacert=%s/etcd-clients-ca.crt --cert=%s/client.pem --key=%s/client.pem --endpoints=https://127.0.0.1:%d snapshot save %s >&2",
+			etcdVersion, etcdManagerPKIDir, dir, dir, clientPort, snapshotFile),
+		"cat " + snapshotFile,
+	}, "\n")
+}
+
+// findEtcdManagerPod returns a running etcd-manager pod for the named etcd cluster.
+func findEtcdManagerPod(ctx context.Context, k8sClient kubernetes.Interface, etcdClusterName string) (*corev1.Pod, error) {
+	pods, err := k8sClient.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{
+		LabelSelector: "k8s-app=etcd-manager-" + etcdClusterName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing etcd-manager pods: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no running etcd-manager pod found for etcd cluster %q", etcdClusterName)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEtcdSnapshotScript(t *testing.T) {
+	script := etcdSnapshotScript("main", "3.5.7", 4001)
+
+	expected := []string{
+		"umask 077",
+		"trap 'rm -rf /tmp/kops-etcd-backup-main' EXIT",
+		"mkdir -p -m 0700 /tmp/kops-etcd-backup-main",
+		"cat > /tmp/kops-etcd-backup-main/client.pem\n",
+		"/opt/etcd-v3.5.7/etcdctl --cacert=/etc/kubernetes/pki/etcd-manager/etcd-clients-ca.crt --cert=/tmp/kops-etcd-backup-main/client.pem --key=/tmp/kops-etcd-backup-main/client.pem --endpoints=https://127.0.0.1:4001 snapshot save /tmp/kops-etcd-backup-main/snapshot.db",
+		"cat /tmp/kops-etcd-backup-main/snapshot.db",
+	}
+	for _, e := range expected {
+		if !strings.Contains(script, e) {
+			t.Errorf("expected script to contain %q, got:\n%s", e, script)
+		}
+	}
+	// The credentials are sent over stdin, as the command is logged by kube-apiserver
+	if strings.Contains(script, "PRIVATE KEY") || strings.Contains(script, "<<") {
+		t.Errorf("script should not contain the client credentials:\n%s", script)
+	}
+	if strings.Contains(script, "/srv/kubernetes/kube-apiserver") {
+		t.Errorf("script should not depend on the kube-apiserver certificates:\n%s", script)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	toolboxHardeningLong = templates.LongDesc(i18n.T(`
+	Reports the compliance of the instance groups of a cluster with a hardening profile.
+
+	The configuration of the cluster and its instance groups is checked against the
+	recommendations of the CIS benchmarks covered by the profile. The settings are
+	checked as they are applied to the nodes: the sysctl parameters, disabled kernel
+	modules, audit rules and kubelet flags of the spec are merged with those of the
+	hardening profile of each instance group. The file permissions are set by nodeup
+	on the nodes and are not reported. By default, the profile of the cluster
+	is used, or cis-level1 if the cluster has no profile.`))
+
+	toolboxHardeningExample = templates.Examples(i18n.T(`
+	# Report the compliance of a cluster with its hardening profile
+	kops toolbox hardening k8s-cluster.example.com
+
+	# Report the compliance of a cluster with the CIS level 2 profile, as JSON
+	kops toolbox hardening k8s-cluster.example.com --profile cis-level2 -o json
+	`))
+
+	toolboxHardeningShort = i18n.T(`Report the compliance of a cluster with a hardening profile.`)
+)
+
+type ToolboxHardeningOptions struct {
+	ClusterName string
+	// Profile is the hardening profile to check against; the profile of the cluster if empty
+	Profile string
+	// Output is the output format: table, yaml or json
+	Output string
+}
+
+type hardeningCheckItem struct {
+	InstanceGroup string `json:"instanceGroup"`
+	apimodel.HardeningCheck
+}
+
+func NewCmdToolboxHardening(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxHardeningOptions{
+		Output: OutputTable,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "hardening [CLUSTER]",
+		Short:             toolboxHardeningShort,
+		Long:              toolboxHardeningLong,
+		Example:           toolboxHardeningExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxHardening(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Profile, "profile", options.Profile, "Hardening profile to check against, the profile of the cluster if not specified: cis-level1 or cis-level2")
+	cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return kops.SupportedHardeningProfiles, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table, yaml or json")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputTable, OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func RunToolboxHardening(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxHardeningOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	profile := options.Profile
+	if profile == "" && cluster.Spec.Hardening != nil {
+		profile = cluster.Spec.Hardening.Profile
+	}
+	if profile == "" {
+		profile = kops.HardeningProfileCISLevel1
+	}
+	if apimodel.HardeningLevel(profile) == 0 {
+		return fmt.Errorf("unsupported hardening profile %q, supported profiles are %v", profile, kops.SupportedHardeningProfiles)
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var items []*hardeningCheckItem
+	failed := 0
+	for i := range list.Items {
+		ig := &list.Items[i]
+		for _, check := range apimodel.CheckHardening(cluster, ig, profile) {
+			if !check.Passed {
+				failed++
+			}
+			items = append(items, &hardeningCheckItem{
+				InstanceGroup:  ig.ObjectMeta.Name,
+				HardeningCheck: check,
+			})
+		}
+	}
+
+	switch options.Output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("INSTANCE-GROUP", func(i *hardeningCheckItem) string {
+			return i.InstanceGroup
+		})
+		t.AddColumn("CHECK", func(i *hardeningCheckItem) string {
+			return i.ID
+		})
+		t.AddColumn("DESCRIPTION", func(i *hardeningCheckItem) string {
+			return i.Description
+		})
+		t.AddColumn("RESULT", func(i *hardeningCheckItem) string {
+			if i.Passed {
+				return "PASS"
+			}
+			return "FAIL"
+		})
+		t.AddColumn("DETAIL", func(i *hardeningCheckItem) string {
+			return i.Detail
+		})
+		if err := t.Render(items, out, "INSTANCE-GROUP", "CHECK", "DESCRIPTION", "RESULT", "DETAIL"); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n%d of %d checks of profile %s failed\n", failed, len(items), profile)
+		return nil
+
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
+	return nil
+}
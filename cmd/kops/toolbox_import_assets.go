@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxImportAssetsLong = templates.LongDesc(i18n.T(`
+	Uploads the images and files of an assets bundle, as written by kops get assets --bundle,
+	to the container registry and file repository configured in the assets of the cluster spec.
+
+	The checksums of the files are verified before they are uploaded. Assets that are already
+	present at their target locations are skipped.`))
+
+	toolboxImportAssetsExample = templates.Examples(i18n.T(`
+	# On a host with internet access, write the assets of a cluster to a bundle
+	kops get assets --name k8s-cluster.example.com --bundle assets.tar
+
+	# In the disconnected environment, upload the assets to the configured locations
+	kops toolbox import-assets --name k8s-cluster.example.com assets.tar
+	`))
+
+	toolboxImportAssetsShort = i18n.T(`Import an assets bundle into the asset locations of a cluster.`)
+)
+
+type ToolboxImportAssetsOptions struct {
+	ClusterName string
+	// Bundle is the path of the bundle to import
+	Bundle string
+}
+
+func NewCmdToolboxImportAssets(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxImportAssetsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "import-assets BUNDLE",
+		Short:   toolboxImportAssetsShort,
+		Long:    toolboxImportAssetsLong,
+		Example: toolboxImportAssetsExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("bundle path is required")
+			}
+			if len(args) > 1 {
+				return fmt.Errorf("too many arguments")
+			}
+			options.Bundle = args[0]
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"tar"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxImportAssets(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunToolboxImportAssets(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxImportAssetsOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := os.Open(options.Bundle)
+	if err != nil {
+		return fmt.Errorf("error opening bundle: %w", err)
+	}
+	defer bundle.Close()
+
+	if err := assets.ImportBundle(ctx, bundle, cluster); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Imported assets bundle %s\n", options.Bundle)
+	return nil
+}
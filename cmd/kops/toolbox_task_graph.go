@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
+	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/do"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/hetzner"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
+	"k8s.io/kops/upup/pkg/fi/nodeup"
+	"k8s.io/kops/util/pkg/distributions"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxTaskGraphLong = templates.LongDesc(i18n.T(`
+	Prints the graph of the tasks that kops update cluster runs for a cluster,
+	with their lifecycles and the dependencies between them.
+
+	Tasks only run once all the tasks they depend on are done. The graph can be
+	restricted to the tasks of an instance group, the cloud resources tagged with
+	its name, and the tasks they depend on.
+
+	With --nodeup, prints the graph of the tasks that nodeup runs on the instances
+	of an instance group instead. The graph is built without an instance, from the
+	cluster spec, the distribution guessed from the image name and placeholder
+	assets, so it can differ from the graph that nodeup --task-graph prints on a
+	node.
+
+	With --explain, prints why a task exists instead: the model builder that
+	created it, the tasks it depends on and that depend on it, and the fields of
+	the cluster and instance group specs that may have set it. The fields are
+	heuristic matches: fields whose values are equal to the values of the fields
+	of the task.`))
+
+	toolboxTaskGraphExample = templates.Examples(i18n.T(`
+	# Print the task graph of a cluster as DOT, and render it with graphviz
+	kops toolbox task-graph k8s-cluster.example.com | dot -Tsvg > tasks.svg
+
+	# Print the tasks of an instance group as a Mermaid flowchart
+	kops toolbox task-graph k8s-cluster.example.com --instance-group nodes-us-east-1a -o mermaid
+
+	# Print the tasks that nodeup runs on the instances of an instance group
+	kops toolbox task-graph k8s-cluster.example.com --nodeup --instance-group nodes-us-east-1a
+
+	# Explain why a task exists
+	kops toolbox task-graph k8s-cluster.example.com --explain LaunchTemplate/nodes-us-east-1a.k8s-cluster.example.com
+	`))
+
+	toolboxTaskGraphShort = i18n.T(`Print the task graph of a cluster.`)
+)
+
+type ToolboxTaskGraphOptions struct {
+	ClusterName string
+	// Output is the format of the graph: dot, mermaid or json
+	Output string
+	// InstanceGroup restricts the graph to the tasks of the instance group and their dependencies
+	InstanceGroup string
+	// Nodeup prints the graph of the tasks that nodeup runs on the instances of InstanceGroup
+	Nodeup bool
+	// Explain is the key of a task to explain, instead of printing the graph
+	Explain string
+}
+
+func NewCmdToolboxTaskGraph(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxTaskGraphOptions{
+		Output: string(fi.TaskGraphFormatDOT),
+	}
+
+	cmd := &cobra.Command{
+		Use:               "task-graph [CLUSTER]",
+		Short:             toolboxTaskGraphShort,
+		Long:              toolboxTaskGraphLong,
+		Example:           toolboxTaskGraphExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxTaskGraph(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of dot, mermaid or json; text or json with --explain")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return fi.TaskGraphFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Only print the tasks of the instance group, and the tasks they depend on")
+	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(f, nil, nil))
+	cmd.Flags().BoolVar(&options.Nodeup, "nodeup", options.Nodeup, "Print the tasks that nodeup runs on the instances of the instance group")
+	cmd.Flags().StringVar(&options.Explain, "explain", options.Explain, "Key of a task to explain, e.g. VPC/k8s-cluster.example.com. The spec fields are matched by value")
+
+	return cmd
+}
+
+func RunToolboxTaskGraph(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxTaskGraphOptions) error {
+	format := fi.TaskGraphFormat(options.Output)
+	if options.Explain != "" && format != fi.TaskGraphFormatJSON {
+		format = ""
+	} else if err := (&fi.TaskGraph{}).Write(io.Discard, format); err != nil {
+		return err
+	}
+	if options.Nodeup && options.InstanceGroup == "" {
+		return fmt.Errorf("--nodeup requires --instance-group")
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:              cloud,
+		Clientset:          clientset,
+		Cluster:            cluster,
+		DryRun:             true,
+		AllowKopsDowngrade: true,
+		TargetName:         cloudup.TargetDryRun,
+		BuildTasksOnly:     true,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return err
+	}
+
+	var instanceGroup *kops.InstanceGroup
+	for _, ig := range applyCmd.InstanceGroups {
+		if ig.Name == options.InstanceGroup {
+			instanceGroup = ig
+		}
+	}
+	if options.InstanceGroup != "" && instanceGroup == nil {
+		return fmt.Errorf("instance group %q not found", options.InstanceGroup)
+	}
+
+	var graph *fi.TaskGraph
+	var task interface{}
+	if options.Nodeup {
+		architecture, err := cloudup.MachineArchitecture(cloud, instanceGroup.Spec.MachineType)
+		if err != nil {
+			return err
+		}
+		distribution, found := distributions.FindDistributionForImage(instanceGroup.Spec.Image)
+		if !found {
+			return fmt.Errorf("unable to determine the distribution of image %q of instance group %q", instanceGroup.Spec.Image, instanceGroup.Name)
+		}
+		keyStore, err := clientset.KeyStore(applyCmd.Cluster)
+		if err != nil {
+			return err
+		}
+		secretStore, err := clientset.SecretStore(applyCmd.Cluster)
+		if err != nil {
+			return err
+		}
+		var taskMap map[string]fi.NodeupTask
+		graph, taskMap, err = nodeup.BuildInstanceGroupTaskGraph(applyCmd.Cluster, instanceGroup, architecture, distribution, keyStore, secretStore)
+		if err != nil {
+			return err
+		}
+		if t, found := taskMap[options.Explain]; found {
+			task = t
+		}
+	} else {
+		graph = fi.BuildTaskGraph(applyCmd.TaskMap, applyCmd.TaskBuilders)
+		if t, found := applyCmd.TaskMap[options.Explain]; found {
+			task = t
+		}
+	}
+
+	if options.Explain != "" {
+		if task == nil {
+			return fmt.Errorf("task %q not found", options.Explain)
+		}
+		sources := []fi.SpecSource{
+			{Name: "Cluster/" + applyCmd.Cluster.Name, Path: "spec", Object: applyCmd.Cluster.Spec},
+		}
+		for _, ig := range applyCmd.InstanceGroups {
+			sources = append(sources, fi.SpecSource{Name: "InstanceGroup/" + ig.Name, Path: "spec", Object: ig.Spec})
+		}
+		explanation, err := graph.ExplainTask(task, options.Explain, sources)
+		if err != nil {
+			return err
+		}
+		return explanation.Write(out, format)
+	}
+
+	if options.InstanceGroup != "" && !options.Nodeup {
+		var keys []string
+		for key, task := range applyCmd.TaskMap {
+			if isInstanceGroupTask(task, options.InstanceGroup) {
+				keys = append(keys, key)
+			}
+		}
+		graph = graph.Subgraph(keys)
+	}
+
+	return graph.Write(out, format)
+}
+
+// instanceGroupTagKeys are the keys of the tags and labels with which the clouds tag the resources of an instance group
+var instanceGroupTagKeys = []string{
+	nodeidentityaws.CloudTagInstanceGroupName,
+	nodeidentityazure.InstanceGroupNameTag,
+	gce.GceLabelNameInstanceGroup,
+	hetzner.TagKubernetesInstanceGroup,
+	openstack.TagKopsInstanceGroup,
+	do.TagKubernetesInstanceGroup,
+	scaleway.TagInstanceGroup,
+}
+
+// isInstanceGroupTask returns true if the task is a cloud resource of the instance group:
+// it is tagged or labelled with the name of the instance group, or it is named after it.
+func isInstanceGroupTask(task interface{}, instanceGroup string) bool {
+	if hasName, ok := task.(fi.HasName); ok {
+		name := fi.ValueOf(hasName.GetName())
+		if name == instanceGroup || strings.HasPrefix(name, instanceGroup+".") {
+			return true
+		}
+	}
+
+	v := reflect.ValueOf(task)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch {
+		case field.Kind() == reflect.Map && field.Type().Key().Kind() == reflect.String:
+			// Tags, labels and metadata, e.g. map[string]string or map[string]*string
+			for _, key := range instanceGroupTagKeys {
+				value := field.MapIndex(reflect.ValueOf(key))
+				if value.IsValid() && value.Kind() == reflect.Pointer && !value.IsNil() {
+					value = value.Elem()
+				}
+				if value.IsValid() && value.Kind() == reflect.String && value.String() == instanceGroup {
+					return true
+				}
+			}
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			// Tags in the key=value or key:value format
+			for j := 0; j < field.Len(); j++ {
+				for _, key := range instanceGroupTagKeys {
+					tag := field.Index(j).String()
+					if tag == key+"="+instanceGroup || tag == key+":"+instanceGroup {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
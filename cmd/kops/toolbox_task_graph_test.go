@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+)
+
+func TestIsInstanceGroupTask(t *testing.T) {
+	grid := []struct {
+		name     string
+		task     interface{}
+		expected bool
+	}{
+		{
+			name:     "named after the instance group",
+			task:     &awstasks.AutoscalingGroup{Name: fi.PtrTo("nodes.example.com")},
+			expected: true,
+		},
+		{
+			name: "tagged with the instance group",
+			task: &awstasks.LaunchTemplate{
+				Name: fi.PtrTo("lt-nodes"),
+				Tags: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+			},
+			expected: true,
+		},
+		{
+			name: "labelled with the instance group",
+			task: &gcetasks.InstanceTemplate{
+				Name:   fi.PtrTo("nodes-example-com"),
+				Labels: map[string]string{"k8s-io-instance-group": "nodes"},
+			},
+			expected: true,
+		},
+		{
+			name: "tagged in the key:value format",
+			task: &dotasks.Droplet{
+				Name: fi.PtrTo("nodes-example-com"),
+				Tags: []string{"kops-instancegroup:nodes"},
+			},
+			expected: true,
+		},
+		{
+			name: "tagged with another instance group",
+			task: &awstasks.LaunchTemplate{
+				Name: fi.PtrTo("nodes-2.example.com"),
+				Tags: map[string]string{"kops.k8s.io/instancegroup": "nodes-2"},
+			},
+		},
+		{
+			name: "shared by the instance groups",
+			task: &awstasks.IAMRole{Name: fi.PtrTo("nodes-role.example.com")},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			if actual := isInstanceGroupTask(g.task, "nodes"); actual != g.expected {
+				t.Errorf("expected %v, got %v", g.expected, actual)
+			}
+		})
+	}
+}
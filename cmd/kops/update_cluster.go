@@ -66,6 +66,10 @@ type UpdateClusterOptions struct {
 	SSHPublicKey       string
 	RunTasksOptions    fi.RunTasksOptions
 	AllowKopsDowngrade bool
+	// LockTimeout is how long to wait for another update holding the state store lock.
+	LockTimeout time.Duration
+	// BreakLock takes over the state store lock from another update.
+	BreakLock bool
 	// GetAssets is whether this is invoked from the CmdGetAssets.
 	GetAssets bool
 
@@ -78,6 +82,18 @@ type UpdateClusterOptions struct {
 
 	Phase string
 
+	// Output is the format of the dry-run report: text, json or yaml
+	Output string
+
+	// PolicyDir is a local directory of policies to evaluate before applying changes
+	PolicyDir string
+
+	// EmitImports writes Terraform import blocks for the cloud resources that already exist
+	EmitImports bool
+
+	// TerraformModule writes the Terraform output as a reusable module
+	TerraformModule bool
+
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
@@ -125,10 +141,22 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.RegisterFlagCompletionFunc("user", completeKubecfgUser)
 	cmd.Flags().BoolVar(&options.internal, "internal", options.internal, "Use the cluster's internal DNS name. Implies --create-kube-config")
 	cmd.Flags().BoolVar(&options.AllowKopsDowngrade, "allow-kops-downgrade", options.AllowKopsDowngrade, "Allow an older version of kOps to update the cluster than last used")
+	cmd.Flags().DurationVar(&options.LockTimeout, "lock-timeout", options.LockTimeout, "How long to wait for another update of the cluster to release the state store lock")
+	cmd.Flags().BoolVar(&options.BreakLock, "break-lock", options.BreakLock, "Take over the state store lock even if it is held by another update of the cluster")
 	cmd.Flags().StringVar(&options.Phase, "phase", options.Phase, "Subset of tasks to run: "+strings.Join(cloudup.Phases.List(), ", "))
 	cmd.RegisterFlagCompletionFunc("phase", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return cloudup.Phases.List(), cobra.ShellCompDirectiveNoFileComp
 	})
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format of the dry run. One of text, json or yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return fi.DryRunOutputFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.PolicyDir, "policy-dir", options.PolicyDir, "Directory of policies to evaluate before applying changes, in addition to the policies in the state store")
+	cmd.MarkFlagDirname("policy-dir")
+	cmd.Flags().BoolVar(&options.TerraformModule, "terraform-module", options.TerraformModule, "Write the Terraform output as a reusable module, with input variables and without provider configuration. Only valid with --target=terraform")
+	cmd.Flags().BoolVar(&options.EmitImports, "emit-imports", options.EmitImports, "Write Terraform import blocks for cloud resources that already exist. Only valid with --target=terraform")
+	cmd.Flags().IntVar(&options.RunTasksOptions.MaxConcurrency, "max-concurrency", options.RunTasksOptions.MaxConcurrency, "Maximum total weight of the tasks to run at the same time, or 0 for no limit")
+	cmd.Flags().StringToIntVar(&options.RunTasksOptions.TaskWeights, "task-weights", options.RunTasksOptions.TaskWeights, "comma separated list of task weights used with --max-concurrency, example: AutoscalingGroup=4,LaunchTemplate=2")
 	cmd.Flags().StringSliceVar(&options.LifecycleOverrides, "lifecycle-overrides", options.LifecycleOverrides, "comma separated list of phase overrides, example: SecurityGroups=Ignore,InternetGateway=ExistsAndWarnIfChanges")
 	viper.BindPFlag("lifecycle-overrides", cmd.Flags().Lookup("lifecycle-overrides"))
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
@@ -150,6 +178,8 @@ type UpdateClusterResults struct {
 	FileAssets []*assets.FileAsset
 	// Cluster is the cluster spec (output).
 	Cluster *kops.Cluster
+	// InstanceGroups are the instance groups of the cluster (output).
+	InstanceGroups []*kops.InstanceGroup
 }
 
 func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *UpdateClusterOptions) (*UpdateClusterResults, error) {
@@ -189,6 +219,30 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		targetName = cloudup.TargetDryRun
 	}
 
+	dryRunOutput := fi.DryRunOutputFormat(c.Output)
+	switch dryRunOutput {
+	case "", fi.DryRunOutputText:
+	case fi.DryRunOutputJSON, fi.DryRunOutputYAML:
+		if !isDryrun {
+			return results, fmt.Errorf("--output %s is only supported for dry runs", c.Output)
+		}
+	default:
+		return results, fmt.Errorf("unknown output format %q, available formats: %s", c.Output, strings.Join(fi.DryRunOutputFormats, ", "))
+	}
+
+	if c.EmitImports && c.Target != cloudup.TargetTerraform {
+		return results, fmt.Errorf("--emit-imports is only supported with --target=%s", cloudup.TargetTerraform)
+	}
+	if c.TerraformModule {
+		if c.Target != cloudup.TargetTerraform {
+			return results, fmt.Errorf("--terraform-module is only supported with --target=%s", cloudup.TargetTerraform)
+		}
+		if c.EmitImports {
+			// Terraform only allows import blocks in the root module
+			return results, fmt.Errorf("--terraform-module cannot be combined with --emit-imports")
+		}
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
@@ -223,7 +277,12 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	}
 
 	if c.SSHPublicKey != "" {
-		fmt.Fprintf(out, "--ssh-public-key on update is deprecated - please use `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub` instead\n", cluster.ObjectMeta.Name)
+		warnOut := out
+		if dryRunOutput != "" && dryRunOutput != fi.DryRunOutputText {
+			// Keep the output machine-readable
+			warnOut = os.Stderr
+		}
+		fmt.Fprintf(warnOut, "--ssh-public-key on update is deprecated - please use `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub` instead\n", cluster.ObjectMeta.Name)
 
 		c.SSHPublicKey = utils.ExpandPath(c.SSHPublicKey)
 		authorized, err := os.ReadFile(c.SSHPublicKey)
@@ -281,7 +340,13 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		Clientset:          clientset,
 		Cluster:            cluster,
 		DryRun:             isDryrun,
+		DryRunOutput:       dryRunOutput,
+		PolicyDir:          c.PolicyDir,
+		EmitImports:        c.EmitImports,
+		TerraformModule:    c.TerraformModule,
 		AllowKopsDowngrade: c.AllowKopsDowngrade,
+		LockTimeout:        c.LockTimeout,
+		BreakLock:          c.BreakLock,
 		RunTasksOptions:    &c.RunTasksOptions,
 		OutDir:             c.OutDir,
 		Phase:              phase,
@@ -294,14 +359,28 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	if !isDryrun {
+		// Record the completed spec that was applied
+		if history, err := clientset.HistoryFor(cluster); err == nil {
+			if err := history.Record(ctx, "update cluster"); err != nil {
+				klog.Warningf("unable to record revision history for cluster %q: %v", cluster.Name, err)
+			}
+		}
+	}
+
 	results.Target = applyCmd.Target
 	results.TaskMap = applyCmd.TaskMap
 	results.ImageAssets = applyCmd.ImageAssets
 	results.FileAssets = applyCmd.FileAssets
 	results.Cluster = cluster
+	results.InstanceGroups = applyCmd.InstanceGroups
 
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.CloudupDryRunTarget)
+		if dryRunOutput != "" && dryRunOutput != fi.DryRunOutputText {
+			// Keep the output machine-readable
+			return results, nil
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -356,7 +435,10 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Terraform output has been placed into %s\n", c.OutDir)
 
-			if firstRun {
+			if c.TerraformModule {
+				fmt.Fprintf(sb, "Add it as a module, with provider configuration, to apply the configuration\n")
+				fmt.Fprintf(sb, "\n")
+			} else if firstRun {
 				fmt.Fprintf(sb, "Run these commands to apply the configuration:\n")
 				fmt.Fprintf(sb, "   cd %s\n", c.OutDir)
 				fmt.Fprintf(sb, "   terraform plan\n")
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/golden"
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"sigs.k8s.io/yaml"
+)
+
+// TestUpdateClusterDryRunOutput checks that the whole stdout of a dry run with a machine-readable output is the plan
+func TestUpdateClusterDryRunOutput(t *testing.T) {
+	for _, format := range []fi.DryRunOutputFormat{fi.DryRunOutputJSON, fi.DryRunOutputYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			ctx := testcontext.ForTest(t)
+			h := testutils.NewIntegrationTestHarness(t)
+			defer h.Close()
+
+			h.MockKopsVersion("1.21.0-alpha.1")
+			h.SetupMockAWS()
+
+			// The channel recommends newer kops and kubernetes versions, so banners recommend upgrading
+			i := newIntegrationTest("minimal.k8s.local", updateClusterTestBase+"minimal_gossip")
+			var setupOut bytes.Buffer
+			factory := i.setupCluster(t, ctx, "in-"+i.version+".yaml", setupOut)
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			stdout := os.Stdout
+			os.Stdout = w
+			done := make(chan []byte)
+			go func() {
+				b, _ := io.ReadAll(r)
+				done <- b
+			}()
+
+			options := &UpdateClusterOptions{}
+			options.InitDefaults()
+			options.Target = cloudup.TargetDryRun
+			options.Output = string(format)
+			options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+			options.CreateKubecfg = false
+			options.ClusterName = i.clusterName
+			_, err = RunUpdateCluster(ctx, factory, os.Stdout, options)
+
+			os.Stdout = stdout
+			w.Close()
+			out := <-done
+			if err != nil {
+				t.Fatalf("error running update cluster %q: %v", i.clusterName, err)
+			}
+
+			plan := &fi.Plan{}
+			if format == fi.DryRunOutputJSON {
+				decoder := json.NewDecoder(bytes.NewReader(out))
+				decoder.DisallowUnknownFields()
+				if err := decoder.Decode(plan); err != nil {
+					t.Fatalf("stdout is not a JSON plan: %v\n%s", err, out)
+				}
+				if _, err := decoder.Token(); err != io.EOF {
+					t.Fatalf("unexpected output after the JSON plan:\n%s", out)
+				}
+			} else {
+				if err := yaml.UnmarshalStrict(out, plan); err != nil {
+					t.Fatalf("stdout is not a YAML plan: %v\n%s", err, out)
+				}
+			}
+			if plan.SchemaVersion != fi.PlanSchemaVersion {
+				t.Errorf("unexpected schema version %q", plan.SchemaVersion)
+			}
+			if len(plan.Changes) == 0 {
+				t.Errorf("expected the changes of the new cluster in the plan")
+			}
+		})
+	}
+}
+
+var mockIDs = regexp.MustCompile(`(lt|sg|vol)-[0-9]+`)
+
+// TestUpdateClusterEmitImports checks the Terraform import blocks written for a cluster that was created with the direct target
+func TestUpdateClusterEmitImports(t *testing.T) {
+	for _, srcDir := range []string{"emit-imports-nlb", "emit-imports-clb"} {
+		t.Run(srcDir, func(t *testing.T) {
+			ctx := testcontext.ForTest(t)
+			h := testutils.NewIntegrationTestHarness(t)
+			defer h.Close()
+
+			h.MockKopsVersion("1.21.0-alpha.1")
+			h.SetupMockAWS()
+
+			i := newIntegrationTest("minimal.example.com", updateClusterTestBase+srcDir)
+			var stdout bytes.Buffer
+			factory := i.setupCluster(t, ctx, "in-"+i.version+".yaml", stdout)
+
+			{
+				options := &UpdateClusterOptions{}
+				options.InitDefaults()
+				options.Yes = true
+				options.RunTasksOptions.MaxTaskDuration = 10 * time.Second
+
+				// We don't test it here, and it adds a dependency on kubectl
+				options.CreateKubecfg = false
+				options.ClusterName = i.clusterName
+
+				if _, err := RunUpdateCluster(ctx, factory, &stdout, options); err != nil {
+					t.Fatalf("error running update cluster %q: %v", i.clusterName, err)
+				}
+			}
+
+			{
+				options := &UpdateClusterOptions{}
+				options.InitDefaults()
+				options.Target = cloudup.TargetTerraform
+				options.EmitImports = true
+				options.OutDir = path.Join(h.TempDir, "out")
+				options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+
+				// We don't test it here, and it adds a dependency on kubectl
+				options.CreateKubecfg = false
+				options.ClusterName = i.clusterName
+
+				if _, err := RunUpdateCluster(ctx, factory, &stdout, options); err != nil {
+					t.Fatalf("error running update cluster %q: %v", i.clusterName, err)
+				}
+			}
+
+			actualTF, err := os.ReadFile(path.Join(h.TempDir, "out", "kubernetes.tf"))
+			if err != nil {
+				t.Fatalf("unexpected error reading actual terraform output: %v", err)
+			}
+
+			// Only the import blocks are compared, the resources are covered by the other terraform tests
+			var imports strings.Builder
+			inImport := false
+			for _, line := range strings.SplitAfter(string(actualTF), "\n") {
+				if line == "import {\n" {
+					inImport = true
+				}
+				if inImport {
+					if strings.HasPrefix(line, "  id = ") {
+						// The mocks number these resources in the order they are created, which is not deterministic
+						line = mockIDs.ReplaceAllString(line, "${1}-N")
+					}
+					imports.WriteString(line)
+				}
+				if inImport && line == "}\n" {
+					imports.WriteString("\n")
+					inImport = false
+				}
+			}
+			golden.AssertMatchesFile(t, imports.String(), path.Join(i.srcDir, "imports.tf"))
+		})
+	}
+}
@@ -17,15 +17,20 @@ limitations under the License.
 package main // import "k8s.io/kops/cmd/nodeup"
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/pkg/sbom"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/prebake"
 )
 
 const (
@@ -39,18 +44,34 @@ func main() {
 	var flagConf, flagCacheDir, gitVersion string
 	var flagRetries int
 	var dryrun, installSystemdUnit bool
+	var reconcileInterval time.Duration
+	var reconcileApply bool
+	var reconcileKubeconfig string
+	var dryrunOutput string
+	var prebakeConfig, prebakeRoot, prebakeArch, prebakeNodeup string
+	var taskGraph, explain, sbomFormat string
 	target := "direct"
 
 	if kops.GitVersion != "" {
 		gitVersion = fmt.Sprintf(" (git-%s)", kops.GitVersion)
 	}
-	fmt.Printf("nodeup version %s%s\n", kops.Version, gitVersion)
 	flag.StringVar(&flagConf, "conf", "node.yaml", "configuration location")
 	flag.StringVar(&flagCacheDir, "cache", "/var/cache/nodeup", "the location for the local asset cache")
 	flag.IntVar(&flagRetries, "retries", -1, "maximum number of retries on failure: -1 means retry forever")
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
 	flag.StringVar(&target, "target", target, "Target - direct, dryrun")
+	flag.StringVar(&dryrunOutput, "dryrun-output", string(fi.DryRunOutputText), "Output format of the dryrun target - text, json, yaml")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
+	flag.DurationVar(&reconcileInterval, "reconcile-interval", 0, "If set, keep running and check the node for configuration drift at this interval, instead of configuring it once")
+	flag.BoolVar(&reconcileApply, "reconcile-apply", false, "If true, re-apply drifted files and packages when reconciling")
+	flag.StringVar(&reconcileKubeconfig, "reconcile-kubeconfig", nodeup.DefaultReconcileKubeconfig, "kubeconfig used to report configuration drift on the node")
+	flag.StringVar(&prebakeConfig, "prebake-config", "", "If set, install the assets and images of this nodeup config into --prebake-root and exit, instead of configuring the node")
+	flag.StringVar(&prebakeRoot, "prebake-root", "/", "the root filesystem to prebake")
+	flag.StringVar(&prebakeArch, "prebake-arch", "", "the architecture to prebake: defaults to the architecture of nodeup")
+	flag.StringVar(&prebakeNodeup, "prebake-nodeup", "", "the nodeup asset to install when prebaking, in the hash@url format")
+	flag.StringVar(&taskGraph, "task-graph", "", "If set, print the task graph in this format - dot, mermaid, json - and exit, instead of configuring the node")
+	flag.StringVar(&explain, "explain", "", "If set, print why the task with this key exists and exit, instead of configuring the node")
+	flag.StringVar(&sbomFormat, "sbom", "", "If set, print a software bill of materials of the node in this format - spdx, cyclonedx - and exit, instead of configuring the node")
 
 	if dryrun {
 		target = "dryrun"
@@ -59,10 +80,61 @@ func main() {
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	switch fi.DryRunOutputFormat(dryrunOutput) {
+	case fi.DryRunOutputText, fi.DryRunOutputJSON, fi.DryRunOutputYAML:
+	default:
+		klog.Exitf("unknown --dryrun-output %q", dryrunOutput)
+	}
+	if taskGraph != "" {
+		if err := (&fi.TaskGraph{}).Write(io.Discard, fi.TaskGraphFormat(taskGraph)); err != nil {
+			klog.Exitf("invalid --task-graph: %v", err)
+		}
+	}
+	if sbomFormat != "" {
+		if err := (&sbom.Document{}).Write(io.Discard, sbom.Format(sbomFormat)); err != nil {
+			klog.Exitf("invalid --sbom: %v", err)
+		}
+	}
+	machineReadable := fi.DryRunOutputFormat(dryrunOutput) != fi.DryRunOutputText || taskGraph != "" || explain != "" || sbomFormat != ""
+	if machineReadable {
+		// Keep stdout machine-readable
+		klog.Infof("nodeup version %s%s", kops.Version, gitVersion)
+	} else {
+		fmt.Printf("nodeup version %s%s\n", kops.Version, gitVersion)
+	}
+
 	if flagConf == "" {
 		klog.Exitf("--conf is required")
 	}
 
+	if prebakeConfig != "" {
+		p, err := prebake.NewPrebake(prebakeConfig, prebakeArch)
+		if err != nil {
+			klog.Exitf("error reading prebake config: %v", err)
+		}
+		p.Root = prebakeRoot
+		p.CacheDir = flagCacheDir
+		p.NodeupBinary = prebakeNodeup
+		if err := p.Run(); err != nil {
+			klog.Exitf("error prebaking node image: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if reconcileInterval > 0 {
+		r := &nodeup.Reconciler{
+			ConfigLocation: flagConf,
+			CacheDir:       flagCacheDir,
+			Interval:       reconcileInterval,
+			Apply:          reconcileApply,
+			KubeConfig:     reconcileKubeconfig,
+		}
+		if err := r.Run(context.Background()); err != nil {
+			klog.Exitf("error reconciling node: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	retries := flagRetries
 
 	for {
@@ -113,10 +185,16 @@ func main() {
 				ConfigLocation: flagConf,
 				Target:         target,
 				CacheDir:       flagCacheDir,
+				DryRunOutput:   fi.DryRunOutputFormat(dryrunOutput),
+				TaskGraph:      fi.TaskGraphFormat(taskGraph),
+				Explain:        explain,
+				SBOM:           sbom.Format(sbomFormat),
 			}
 			err = cmd.Run(os.Stdout)
 			if err == nil {
-				fmt.Printf("success")
+				if !machineReadable {
+					fmt.Printf("success")
+				}
 				os.Exit(0)
 			}
 		}
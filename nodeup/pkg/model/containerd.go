@@ -18,6 +18,7 @@ package model
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -32,10 +33,16 @@ import (
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/distributions"
 )
 
-const containerdConfigFilePath = "/etc/containerd/config.toml"
+const (
+	containerdConfigFilePath = "/etc/containerd/config.toml"
+
+	// kataConfigFilePath is the path of the Kata Containers configuration in the Kata static release
+	kataConfigFilePath = "/opt/kata/share/defaults/kata-containers/configuration.toml"
+)
 
 // ContainerdBuilder install containerd (just the packages at the moment)
 type ContainerdBuilder struct {
@@ -157,6 +164,10 @@ func (b *ContainerdBuilder) installContainerd(c *fi.NodeupModelBuilderContext) e
 			c.AddTask(fileTask)
 		}
 
+		if err := b.installRuntimes(c); err != nil {
+			return err
+		}
+
 		// Add configuration file for easier use of crictl
 		b.addCrictlConfig(c)
 	}
@@ -188,6 +199,74 @@ func (b *ContainerdBuilder) installContainerd(c *fi.NodeupModelBuilderContext) e
 	return nil
 }
 
+// installRuntimes installs the binaries of the additional runtime handlers from their packages
+func (b *ContainerdBuilder) installRuntimes(c *fi.NodeupModelBuilderContext) error {
+	for _, runtime := range b.NodeupConfig.ContainerdConfig.Runtimes {
+		var packageURL string
+		if runtime.Packages != nil {
+			switch b.Architecture {
+			case architectures.ArchitectureAmd64:
+				packageURL = fi.ValueOf(runtime.Packages.UrlAmd64)
+			case architectures.ArchitectureArm64:
+				packageURL = fi.ValueOf(runtime.Packages.UrlArm64)
+			}
+		}
+		if packageURL == "" {
+			return fmt.Errorf("no package set for runtime %q on %s", runtime.HandlerName(), b.Architecture)
+		}
+		packageName := path.Base(packageURL)
+
+		switch runtime.Type {
+		case kops.ContainerdRuntimeGVisor:
+			// Add runsc and its shim next to the containerd binaries, where containerd looks for shims
+			f := b.Assets.FindMatchesInArchive(packageName, regexp.MustCompile(`(^|/)(runsc|containerd-shim-runsc-v1)$`))
+			if len(f) != 2 {
+				return fmt.Errorf("unable to find runsc and containerd-shim-runsc-v1 in package %q", packageName)
+			}
+			for k, v := range f {
+				c.AddTask(&nodetasks.File{
+					Path:     filepath.Join("/usr/bin", path.Base(k)),
+					Contents: v,
+					Type:     nodetasks.FileType_File,
+					Mode:     fi.PtrTo("0755"),
+				})
+			}
+
+		case kops.ContainerdRuntimeKata:
+			// Extract the Kata static release, which expects to be installed in /opt/kata, as a single archive
+			res, err := b.Assets.Find(packageName, "")
+			if err != nil {
+				return err
+			}
+			if res == nil {
+				return fmt.Errorf("unable to find package %q for runtime %q", packageName, runtime.HandlerName())
+			}
+			archive := &nodetasks.Archive{
+				Name:      runtime.HandlerName(),
+				Source:    packageURL,
+				Contents:  res,
+				TargetDir: "/opt/kata",
+				// The static release contains ./opt/kata/...
+				StripComponents: 3,
+			}
+			if hs, ok := res.(fi.HasSource); ok && hs.GetSource() != nil && hs.GetSource().Hash != nil {
+				archive.Hash = hs.GetSource().Hash.String()
+			}
+			c.AddTask(archive)
+			c.AddTask(&nodetasks.File{
+				Path:    "/usr/bin/containerd-shim-kata-v2",
+				Symlink: fi.PtrTo("/opt/kata/bin/containerd-shim-kata-v2"),
+				Type:    nodetasks.FileType_Symlink,
+			})
+
+		default:
+			return fmt.Errorf("unknown type %q for runtime %q", runtime.Type, runtime.HandlerName())
+		}
+	}
+
+	return nil
+}
+
 func (b *ContainerdBuilder) buildSystemdService(sv semver.Version) *nodetasks.Service {
 	// Based on https://github.com/containerd/containerd/blob/master/containerd.service
 
@@ -500,9 +579,46 @@ func (b *ContainerdBuilder) buildContainerdConfig() (string, error) {
 			return "", err
 		}
 	}
+
+	for _, runtime := range containerd.Runtimes {
+		if err := appendRuntimeConfig(config, runtime); err != nil {
+			return "", err
+		}
+	}
+
 	return config.String(), nil
 }
 
+// appendRuntimeConfig adds the runtime handler of a sandboxed runtime
+func appendRuntimeConfig(config *toml.Tree, runtime kops.ContainerdRuntimeSpec) error {
+	var runtimeConfig map[string]interface{}
+	switch runtime.Type {
+	case kops.ContainerdRuntimeGVisor:
+		runtimeConfig = map[string]interface{}{
+			"runtime_type": "io.containerd.runsc.v1",
+		}
+	case kops.ContainerdRuntimeKata:
+		runtimeConfig = map[string]interface{}{
+			"runtime_type":                    "io.containerd.kata.v2",
+			"privileged_without_host_devices": true,
+			"pod_annotations":                 []interface{}{"io.katacontainers.*"},
+			"options": map[string]interface{}{
+				"ConfigPath": kataConfigFilePath,
+			},
+		}
+	default:
+		return fmt.Errorf("unknown type %q for runtime %q", runtime.Type, runtime.HandlerName())
+	}
+
+	tree, err := toml.TreeFromMap(runtimeConfig)
+	if err != nil {
+		return err
+	}
+	config.SetPath([]string{"plugins", "io.containerd.grpc.v1.cri", "containerd", "runtimes", runtime.HandlerName()}, tree)
+
+	return nil
+}
+
 func appendNvidiaGPURuntimeConfig(config *toml.Tree) error {
 	gpuConfig, err := toml.TreeFromMap(
 		map[string]interface{}{
@@ -29,6 +29,8 @@ import (
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/testutils"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/distributions"
 )
 
@@ -181,6 +183,53 @@ func runContainerdBuilderTest(t *testing.T, key string, distro distributions.Dis
 	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks.yaml"), context)
 }
 
+func TestContainerdBuilder_InstallRuntimes(t *testing.T) {
+	packageURL := "https://github.com/kata-containers/kata-containers/releases/download/3.2.0/kata-static-3.2.0-amd64.tar.xz"
+	b := &ContainerdBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Architecture: architectures.ArchitectureAmd64,
+			Assets:       fi.NewAssetStore(""),
+			NodeupConfig: &nodeup.Config{
+				ContainerdConfig: &kops.ContainerdConfig{
+					Runtimes: []kops.ContainerdRuntimeSpec{
+						{Name: "kata-qemu", Type: kops.ContainerdRuntimeKata, Packages: &kops.PackagesConfig{UrlAmd64: fi.PtrTo(packageURL)}},
+					},
+				},
+			},
+		},
+	}
+	b.Assets.AddForTest("kata-static-3.2.0-amd64.tar.xz", packageURL, "testing kata content")
+
+	c := &fi.NodeupModelBuilderContext{
+		Tasks: make(map[string]fi.NodeupTask),
+	}
+	if err := b.installRuntimes(c); err != nil {
+		t.Fatalf("error from installRuntimes: %v", err)
+	}
+
+	var archives []*nodetasks.Archive
+	var files []*nodetasks.File
+	for _, task := range c.Tasks {
+		switch task := task.(type) {
+		case *nodetasks.Archive:
+			archives = append(archives, task)
+		case *nodetasks.File:
+			files = append(files, task)
+		default:
+			t.Errorf("unexpected task %v", task)
+		}
+	}
+	if len(archives) != 1 {
+		t.Fatalf("expected a single archive task, got %d", len(archives))
+	}
+	if archives[0].TargetDir != "/opt/kata" || archives[0].StripComponents != 3 || archives[0].Contents == nil {
+		t.Errorf("unexpected archive task %v", archives[0])
+	}
+	if len(files) != 1 || files[0].Path != "/usr/bin/containerd-shim-kata-v2" || fi.ValueOf(files[0].Symlink) != "/opt/kata/bin/containerd-shim-kata-v2" {
+		t.Errorf("expected a symlink to the kata shim, got %v", files)
+	}
+}
+
 func TestContainerdConfig(t *testing.T) {
 	cluster := &kops.Cluster{
 		Spec: kops.ClusterSpec{
@@ -271,3 +320,47 @@ func TestAppendGPURuntimeContainerdConfig(t *testing.T) {
 		t.Error("new config did not match expected new config")
 	}
 }
+
+func TestAppendRuntimeContainerdConfig(t *testing.T) {
+	expectedConfig := `
+[plugins]
+
+  [plugins."io.containerd.grpc.v1.cri"]
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.gvisor]
+          runtime_type = "io.containerd.runsc.v1"
+
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.kata-qemu]
+          pod_annotations = ["io.katacontainers.*"]
+          privileged_without_host_devices = true
+          runtime_type = "io.containerd.kata.v2"
+
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.kata-qemu.options]
+            ConfigPath = "/opt/kata/share/defaults/kata-containers/configuration.toml"
+`
+	config, _ := toml.Load("")
+
+	runtimes := []kops.ContainerdRuntimeSpec{
+		{Type: kops.ContainerdRuntimeGVisor},
+		{Name: "kata-qemu", Type: kops.ContainerdRuntimeKata},
+	}
+	for _, runtime := range runtimes {
+		if err := appendRuntimeConfig(config, runtime); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	newConfig, err := config.ToTomlString()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if newConfig != expectedConfig {
+		fmt.Println(diff.FormatDiff(expectedConfig, newConfig))
+		t.Error("new config did not match expected config")
+	}
+}
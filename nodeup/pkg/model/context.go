@@ -69,6 +69,8 @@ type NodeupModelContext struct {
 
 	// HasAPIServer is true if the InstanceGroup has a role of master or apiserver (pupulated by Init)
 	HasAPIServer bool
+	// IsEtcdNode is true if the InstanceGroup has a role of etcd (populated by Init)
+	IsEtcdNode bool
 
 	// IsGossip is true if the cluster runs Gossip DNS.
 	IsGossip bool
@@ -103,6 +105,10 @@ func (c *NodeupModelContext) Init() error {
 		c.HasAPIServer = true
 	}
 
+	if role == kops.InstanceGroupRoleEtcd {
+		c.IsEtcdNode = true
+	}
+
 	if !c.Cluster.UsesNoneDNS() && dns.IsGossipClusterName(c.NodeupConfig.ClusterName) {
 		c.IsGossip = true
 	}
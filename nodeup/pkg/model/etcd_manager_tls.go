@@ -19,6 +19,7 @@ package model
 import (
 	"path/filepath"
 
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
@@ -32,7 +33,7 @@ var _ fi.NodeupModelBuilder = &EtcdManagerTLSBuilder{}
 
 // Build is responsible for TLS configuration for etcd-manager
 func (b *EtcdManagerTLSBuilder) Build(ctx *fi.NodeupModelBuilderContext) error {
-	if !b.IsMaster {
+	if !b.IsMaster && !b.IsEtcdNode {
 		return nil
 	}
 
@@ -48,7 +49,7 @@ func (b *EtcdManagerTLSBuilder) Build(ctx *fi.NodeupModelBuilderContext) error {
 		keys["etcd-clients-ca"] = "etcd-clients-ca-" + k
 
 		// Because API server can only have a single client certificate for etcd, we need to share a client CA
-		if k == "main" || k == "events" {
+		if model.IsAPIServerEtcdCluster(etcdCluster) {
 			keys["etcd-clients-ca"] = "etcd-clients-ca"
 		}
 
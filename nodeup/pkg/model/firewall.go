@@ -17,10 +17,23 @@ limitations under the License.
 package model
 
 import (
+	"fmt"
+	"net"
+	"strings"
+
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const (
+	// hostFirewallTable is the nftables table of the host firewall
+	hostFirewallTable = "kops-host-firewall"
+	// hostFirewallScriptPath is the nftables script that loads the host firewall
+	hostFirewallScriptPath = "/opt/kops/bin/host-firewall-setup"
 )
 
 // FirewallBuilder configures the firewall (iptables)
@@ -36,9 +49,173 @@ func (b *FirewallBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	c.AddTask(b.buildFirewallScript())
 	c.AddTask(b.buildSystemdService())
 
+	if b.NodeupConfig.HostFirewall != nil {
+		if err := b.buildHostFirewall(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildHostFirewall installs nftables and loads the ruleset of the host firewall on boot
+func (b *FirewallBuilder) buildHostFirewall(c *fi.NodeupModelBuilderContext) error {
+	switch b.Distribution {
+	case distributions.DistributionContainerOS, distributions.DistributionFlatcar:
+		// nftables is part of the image
+	default:
+		c.EnsureTask(&nodetasks.Package{Name: "nftables"})
+	}
+
+	ruleset, err := buildHostFirewallRuleset(b.NodeupConfig.HostFirewall.Rules)
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     hostFirewallScriptPath,
+		Contents: fi.NewStringResource(ruleset),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Configure the nftables host firewall for kubernetes")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Wants", "network-pre.target")
+	manifest.Set("Unit", "Before", "network-pre.target")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", hostFirewallScriptPath)
+	manifest.Set("Install", "WantedBy", "basic.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "kops-host-firewall", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "kops-host-firewall.service",
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
 	return nil
 }
 
+// buildHostFirewallRuleset renders the nftables ruleset of the host firewall.
+// Incoming traffic is dropped, unless it is allowed by one of the rules.
+// The table is deleted and created again, so that loading the ruleset is idempotent.
+func buildHostFirewallRuleset(rules []kops.HostFirewallRule) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("#!/usr/sbin/nft -f\n")
+	sb.WriteString("# Built by kops - do not edit\n\n")
+	fmt.Fprintf(&sb, "table inet %s\n", hostFirewallTable)
+	fmt.Fprintf(&sb, "delete table inet %s\n\n", hostFirewallTable)
+	fmt.Fprintf(&sb, "table inet %s {\n", hostFirewallTable)
+	sb.WriteString("\tchain input {\n")
+	sb.WriteString("\t\ttype filter hook input priority 0; policy drop;\n\n")
+	sb.WriteString("\t\tiif \"lo\" accept\n")
+	sb.WriteString("\t\tct state established,related accept\n")
+	sb.WriteString("\t\tct state invalid drop\n")
+	sb.WriteString("\t\tmeta l4proto { icmp, ipv6-icmp } accept\n")
+	sb.WriteString("\t\tip6 saddr fe80::/10 udp dport 546 accept comment \"dhcpv6\"\n")
+
+	if len(rules) != 0 {
+		sb.WriteString("\n")
+	}
+	for _, rule := range rules {
+		match, err := hostFirewallMatch(rule)
+		if err != nil {
+			return "", err
+		}
+		verdict := "accept"
+		if rule.Name != "" {
+			verdict += fmt.Sprintf(" comment %q", rule.Name)
+		}
+
+		if len(rule.CIDRs) == 0 {
+			fmt.Fprintf(&sb, "\t\t%s %s\n", match, verdict)
+			continue
+		}
+
+		ipv4, ipv6, err := splitHostFirewallCIDRs(rule.CIDRs)
+		if err != nil {
+			return "", fmt.Errorf("invalid host firewall rule %q: %w", rule.Name, err)
+		}
+		if len(ipv4) != 0 {
+			fmt.Fprintf(&sb, "\t\tip saddr { %s } %s %s\n", strings.Join(ipv4, ", "), match, verdict)
+		}
+		if len(ipv6) != 0 {
+			fmt.Fprintf(&sb, "\t\tip6 saddr { %s } %s %s\n", strings.Join(ipv6, ", "), match, verdict)
+		}
+	}
+
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// hostFirewallMatch returns the nftables expression that matches the protocol and ports of the rule
+func hostFirewallMatch(rule kops.HostFirewallRule) (string, error) {
+	ports := fmt.Sprintf("%d", rule.Port)
+	if rule.ToPort != 0 && rule.ToPort != rule.Port {
+		ports = fmt.Sprintf("%d-%d", rule.Port, rule.ToPort)
+	}
+
+	switch rule.Protocol {
+	case "", kops.HostFirewallProtocolTCP:
+		return "tcp dport " + ports, nil
+	case kops.HostFirewallProtocolUDP:
+		return "udp dport " + ports, nil
+	case kops.HostFirewallProtocolIPIP:
+		// IP in IP encapsulation is IP protocol 4
+		return "meta l4proto 4", nil
+	default:
+		return "", fmt.Errorf("unsupported protocol %q for host firewall rule %q", rule.Protocol, rule.Name)
+	}
+}
+
+// splitHostFirewallCIDRs splits the CIDRs by IP family.
+// CIDRs contained in other CIDRs are dropped, because nftables rejects sets with overlapping intervals.
+func splitHostFirewallCIDRs(cidrs []string) ([]string, []string, error) {
+	var ipNets []*net.IPNet
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, nil, err
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+
+	var ipv4, ipv6 []string
+	seen := make(map[string]bool)
+	for i, ipNet := range ipNets {
+		contained := false
+		for j, other := range ipNets {
+			if i == j {
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			otherOnes, _ := other.Mask.Size()
+			if otherOnes < ones && other.Contains(ipNet.IP) {
+				contained = true
+				break
+			}
+		}
+		cidr := ipNet.String()
+		if contained || seen[cidr] {
+			continue
+		}
+		seen[cidr] = true
+
+		if ipNet.IP.To4() != nil {
+			ipv4 = append(ipv4, cidr)
+		} else {
+			ipv6 = append(ipv6, cidr)
+		}
+	}
+	return ipv4, ipv6, nil
+}
+
 func (b *FirewallBuilder) buildSystemdService() *nodetasks.Service {
 	manifest := &systemd.Manifest{}
 	manifest.Set("Unit", "Description", "Configure iptables for kubernetes")
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/diff"
+)
+
+func TestBuildHostFirewallRuleset(t *testing.T) {
+	expected := `#!/usr/sbin/nft -f
+# Built by kops - do not edit
+
+table inet kops-host-firewall
+delete table inet kops-host-firewall
+
+table inet kops-host-firewall {
+	chain input {
+		type filter hook input priority 0; policy drop;
+
+		iif "lo" accept
+		ct state established,related accept
+		ct state invalid drop
+		meta l4proto { icmp, ipv6-icmp } accept
+		ip6 saddr fe80::/10 udp dport 546 accept comment "dhcpv6"
+
+		ip saddr { 0.0.0.0/0 } tcp dport 22 accept comment "ssh"
+		ip6 saddr { ::/0 } tcp dport 22 accept comment "ssh"
+		ip saddr { 172.20.0.0/16, 100.96.0.0/11 } udp dport 30000-32767 accept comment "node-ports"
+		ip saddr { 172.20.0.0/16 } meta l4proto 4 accept comment "calico-ipip"
+		tcp dport 9100 accept
+	}
+}
+`
+
+	rules := []kops.HostFirewallRule{
+		{Name: "ssh", Protocol: "tcp", Port: 22, CIDRs: []string{"0.0.0.0/0", "::/0", "172.20.0.0/16"}},
+		{Name: "node-ports", Protocol: "udp", Port: 30000, ToPort: 32767, CIDRs: []string{"172.20.0.0/16", "100.96.0.0/11", "172.20.32.0/19"}},
+		{Name: "calico-ipip", Protocol: "ipip", CIDRs: []string{"172.20.0.0/16"}},
+		{Port: 9100},
+	}
+
+	actual, err := buildHostFirewallRuleset(rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != expected {
+		fmt.Println(diff.FormatDiff(expected, actual))
+		t.Error("ruleset did not match expected ruleset")
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/model/components/kubescheduler"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+const (
+	// hardeningPermissionsScriptPath is the script that restricts the permissions of the kubernetes files
+	hardeningPermissionsScriptPath = "/opt/kops/bin/kops-hardening-permissions"
+)
+
+// HardeningBuilder applies the hardening profile of the instance group
+type HardeningBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &HardeningBuilder{}
+
+// Build is responsible for configuring the sysctls, kernel modules, audit rules and file permissions of the hardening profile
+func (b *HardeningBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	hardening := b.NodeupConfig.Hardening
+	if hardening == nil {
+		return nil
+	}
+	level := apimodel.HardeningLevel(hardening.Profile)
+
+	if level > 0 {
+		// Sorted before 99-k8s-general.conf, so that the settings needed by kubernetes take precedence
+		c.AddTask(&nodetasks.File{
+			Path:            "/etc/sysctl.d/95-kops-hardening.conf",
+			Contents:        fi.NewStringResource(strings.Join(apimodel.HardeningSysctls(level), "\n")),
+			Type:            nodetasks.FileType_File,
+			OnChangeExecute: [][]string{{"sysctl", "--system"}},
+		})
+	}
+
+	modules := append(apimodel.HardeningKernelModules(level, b.BootConfig.CloudProvider), hardening.DisabledKernelModules...)
+	if len(modules) != 0 {
+		var sb strings.Builder
+		sb.WriteString("# Built by kops - do not edit\n")
+		for _, module := range modules {
+			fmt.Fprintf(&sb, "install %s /bin/false\n", module)
+			fmt.Fprintf(&sb, "blacklist %s\n", module)
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     "/etc/modprobe.d/kops-hardening.conf",
+			Contents: fi.NewStringResource(sb.String()),
+			Type:     nodetasks.FileType_File,
+		})
+	}
+
+	rules := append(apimodel.HardeningAuditRules(level), hardening.AuditRules...)
+	if len(rules) != 0 {
+		b.buildAuditRules(c, rules)
+	}
+
+	if level > 0 {
+		b.buildPermissionsService(c)
+	}
+
+	return nil
+}
+
+// buildAuditRules installs auditd and loads the audit rules
+func (b *HardeningBuilder) buildAuditRules(c *fi.NodeupModelBuilderContext, rules []string) {
+	switch {
+	case b.Distribution == distributions.DistributionContainerOS || b.Distribution == distributions.DistributionFlatcar:
+		// auditd is part of the image
+	case b.Distribution.IsDebianFamily():
+		c.EnsureTask(&nodetasks.Package{Name: "auditd"})
+	case b.Distribution.IsRHELFamily():
+		c.EnsureTask(&nodetasks.Package{Name: "audit"})
+	default:
+		klog.Warningf("unknown distribution, skipping auditd install: %v", b.Distribution)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Built by kops - do not edit\n")
+	for _, rule := range rules {
+		sb.WriteString(rule + "\n")
+	}
+	c.AddTask(&nodetasks.File{
+		Path:            "/etc/audit/rules.d/kops-hardening.rules",
+		Contents:        fi.NewStringResource(sb.String()),
+		Type:            nodetasks.FileType_File,
+		Mode:            s("0600"),
+		OnChangeExecute: [][]string{{"augenrules", "--load"}},
+	})
+}
+
+// buildPermissionsService restricts the permissions of the kubernetes configuration and credential files on boot
+func (b *HardeningBuilder) buildPermissionsService(c *fi.NodeupModelBuilderContext) {
+	c.AddTask(&nodetasks.File{
+		Path:     hardeningPermissionsScriptPath,
+		Contents: fi.NewStringResource(b.buildPermissionsScript()),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Restrict the permissions of the kubernetes files")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Before", "kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "ExecStart", hardeningPermissionsScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "kops-hardening-permissions", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "kops-hardening-permissions.service",
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
+	// The etcd volumes are mounted and the certificates are rotated after boot, so the permissions are restricted periodically
+	manifest = &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Restrict the permissions of the kubernetes files periodically")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Timer", "OnUnitInactiveSec", "5min")
+	manifest.Set("Timer", "Unit", "kops-hardening-permissions.service")
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString = manifest.Render()
+	klog.V(8).Infof("Built timer manifest %q\n%s", "kops-hardening-permissions.timer", manifestString)
+
+	timer := &nodetasks.Service{
+		Name:       "kops-hardening-permissions.timer",
+		Definition: s(manifestString),
+	}
+	timer.InitDefaults()
+	c.AddTask(timer)
+}
+
+// buildPermissionsScript builds the script that removes the permissions of the group and others,
+// and the execute permission of the owner, from the kubernetes configuration files.
+// The private keys are set to 0600, the certificates to 0644 and the etcd data directories to 0700.
+// The owners of the files are not changed, as some of them are owned by the users of the components.
+func (b *HardeningBuilder) buildPermissionsScript() string {
+	files := []string{
+		b.KubeletKubeConfig(),
+		b.KubeletBootstrapKubeconfig(),
+		kubeletConfigFilePath,
+		"/var/lib/kube-proxy/kubeconfig",
+		"/var/lib/kube-controller-manager/kubeconfig",
+		kubescheduler.KubeConfigPath,
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/bash\n")
+	sb.WriteString("# Built by kops - do not edit\n\n")
+	for _, file := range files {
+		fmt.Fprintf(&sb, "[ -f %s ] && chmod u-x,go-rwx %s\n", file, file)
+	}
+	sb.WriteString("[ -d /etc/kubernetes/manifests ] && find /etc/kubernetes/manifests -type f -exec chmod u-x,go-rwx {} +\n")
+	// The pki directory holds the keypairs of the components, and those of etcd-manager in /etc/kubernetes/pki/etcd-manager-*
+	for _, dir := range []string{b.PathSrvKubernetes(), "/etc/kubernetes/pki"} {
+		fmt.Fprintf(&sb, "[ -d %s ] && find %s -type f -name '*.key' -exec chmod 0600 {} +\n", dir, dir)
+		fmt.Fprintf(&sb, "[ -d %s ] && find %s -type f -name '*.crt' -exec chmod 0644 {} +\n", dir, dir)
+	}
+	// etcd-manager mounts the etcd volumes in /mnt/master-*
+	sb.WriteString("[ -d /mnt ] && find /mnt -mindepth 1 -maxdepth 1 -type d -name 'master-*' -exec chmod 0700 {} +\n")
+	sb.WriteString("exit 0\n")
+	return sb.String()
+}
@@ -24,11 +24,13 @@ import (
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model/components"
+	"k8s.io/kops/pkg/model/components/etcdmanager"
 	"k8s.io/kops/pkg/tokens"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/pkg/wellknownusers"
@@ -40,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // PathAuthnConfig is the path to the custom webhook authentication config.
@@ -538,18 +541,27 @@ func (b *KubeAPIServerBuilder) allAuthTokens() (map[string]string, error) {
 // buildPod is responsible for generating the kube-apiserver pod and thus manifest file
 func (b *KubeAPIServerBuilder) buildPod(ctx context.Context, kubeAPIServer *kops.KubeAPIServerConfig) (*v1.Pod, error) {
 	// we need to replace 127.0.0.1 for etcd urls with the dns names in case this apiserver is not
-	// running on master nodes
-	if !b.IsMaster {
-		clusterName := b.NodeupConfig.ClusterName
-		mainEtcdDNSName := "main.etcd.internal." + clusterName
-		eventsEtcdDNSName := "events.etcd.internal." + clusterName
+	// running on master nodes, or etcd runs on etcd nodes (reached using the names maintained by protokube)
+	etcdNodesClusters := sets.NewString(b.NodeupConfig.APIServerConfig.EtcdNodesClusters...)
+	for _, etcdCluster := range b.Cluster.Spec.EtcdClusters {
+		if !model.IsAPIServerEtcdCluster(etcdCluster) {
+			continue
+		}
+		if b.IsMaster && !etcdNodesClusters.Has(etcdCluster.Name) {
+			continue
+		}
+
+		ports, err := etcdmanager.PortsForCluster(&b.Cluster.Spec, etcdCluster)
+		if err != nil {
+			return nil, err
+		}
+		localURL := fmt.Sprintf("https://127.0.0.1:%d", ports.ClientPort)
+		etcdURL := fmt.Sprintf("https://%s.etcd.internal.%s:%d", etcdCluster.Name, b.NodeupConfig.ClusterName, ports.ClientPort)
 		for i := range kubeAPIServer.EtcdServers {
-			kubeAPIServer.EtcdServers[i] = strings.ReplaceAll(kubeAPIServer.EtcdServers[i], "127.0.0.1", mainEtcdDNSName)
+			kubeAPIServer.EtcdServers[i] = strings.ReplaceAll(kubeAPIServer.EtcdServers[i], localURL, etcdURL)
 		}
 		for i := range kubeAPIServer.EtcdServersOverrides {
-			if strings.HasPrefix(kubeAPIServer.EtcdServersOverrides[i], "/events") {
-				kubeAPIServer.EtcdServersOverrides[i] = strings.ReplaceAll(kubeAPIServer.EtcdServersOverrides[i], "127.0.0.1", eventsEtcdDNSName)
-			}
+			kubeAPIServer.EtcdServersOverrides[i] = strings.ReplaceAll(kubeAPIServer.EtcdServersOverrides[i], localURL, etcdURL)
 		}
 	}
 
@@ -700,6 +712,11 @@ func (b *KubeAPIServerBuilder) buildPod(ctx context.Context, kubeAPIServer *kops
 
 	kubemanifest.AddHostPathMapping(pod, container, "cloudconfig", InTreeCloudConfigFilePath)
 
+	if len(b.NodeupConfig.APIServerConfig.EtcdNodesClusters) != 0 {
+		// The etcd nodes are added to /etc/hosts by protokube, after kube-apiserver has started
+		kubemanifest.MapEtcHosts(pod, container, true)
+	}
+
 	kubemanifest.AddHostPathMapping(pod, container, "kubernetesca", filepath.Join(b.PathSrvKubernetes(), "ca.crt"))
 
 	pathSrvKAPI := filepath.Join(b.PathSrvKubernetes(), "kube-apiserver")
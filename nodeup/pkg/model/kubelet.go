@@ -28,10 +28,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/rbac"
 	"k8s.io/kops/pkg/systemd"
@@ -40,6 +43,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/distributions"
 	kubelet "k8s.io/kubelet/config/v1beta1"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -50,6 +54,9 @@ const (
 	kubeletService = "kubelet.service"
 
 	kubeletConfigFilePath = "/var/lib/kubelet/kubelet.conf"
+
+	// credentialProviderConfigFilePath is the path of the configuration of the image credential providers
+	credentialProviderConfigFilePath = "/var/lib/kubelet/credential-provider.conf"
 )
 
 // KubeletBuilder installs kubelet
@@ -108,6 +115,17 @@ func (b *KubeletBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			Mode:     s("0755"),
 		})
 	}
+	if len(kubeletConfig.CredentialProviders) != 0 {
+		t, err := b.buildCredentialProviderConfig(kubeletConfig)
+		if err != nil {
+			return err
+		}
+		c.AddTask(t)
+
+		if err := b.addCredentialProviders(c, kubeletConfig); err != nil {
+			return err
+		}
+	}
 	{
 		if kubeletConfig.PodManifestPath != "" {
 			t, err := b.buildManifestDirectory(kubeletConfig)
@@ -125,7 +143,9 @@ func (b *KubeletBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			Mode: s("0755"),
 		})
 
-		if b.HasAPIServer || !b.UseBootstrapTokens() {
+		if b.IsEtcdNode {
+			// Etcd nodes don't join the cluster, the kubelet runs in standalone mode
+		} else if b.HasAPIServer || !b.UseBootstrapTokens() {
 			var kubeconfig fi.Resource
 			if b.HasAPIServer {
 				kubeconfig, err = b.buildControlPlaneKubeletKubeconfig(c)
@@ -213,6 +233,9 @@ func buildKubeletComponentConfig(kubeletConfig *kops.KubeletConfigSpec) (*nodeta
 	if kubeletConfig.ShutdownGracePeriodCriticalPods != nil {
 		componentConfig.ShutdownGracePeriodCriticalPods = *kubeletConfig.ShutdownGracePeriodCriticalPods
 	}
+	if kubeletConfig.MemorySwapBehavior != "" {
+		componentConfig.MemorySwap.SwapBehavior = kubeletConfig.MemorySwapBehavior
+	}
 
 	s := runtime.NewScheme()
 	if err := kubelet.AddToScheme(s); err != nil {
@@ -253,6 +276,91 @@ func (b *KubeletBuilder) kubeletPath() string {
 	return kubeletCommand
 }
 
+// credentialProviderBinDir returns the directory of the image credential provider binaries based on distro
+func (b *KubeletBuilder) credentialProviderBinDir() string {
+	if b.Distribution == distributions.DistributionContainerOS {
+		return "/home/kubernetes/credential-providers"
+	}
+	return "/opt/kubernetes/credential-providers"
+}
+
+// buildCredentialProviderConfig renders the configuration of the image credential providers
+func (b *KubeletBuilder) buildCredentialProviderConfig(kubeletConfig *kops.KubeletConfigSpec) (*nodetasks.File, error) {
+	// The v1 and v1beta1 APIs share the same schema
+	apiVersion := "kubelet.config.k8s.io/v1beta1"
+	providerAPIVersion := "credentialprovider.kubelet.k8s.io/v1beta1"
+	if b.IsKubernetesGTE("1.26") {
+		apiVersion = "kubelet.config.k8s.io/v1"
+		providerAPIVersion = "credentialprovider.kubelet.k8s.io/v1"
+	}
+
+	config := kubelet.CredentialProviderConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+			Kind:       "CredentialProviderConfig",
+		},
+	}
+	for i := range kubeletConfig.CredentialProviders {
+		provider := model.CredentialProviderWithDefaults(&kubeletConfig.CredentialProviders[i])
+
+		p := kubelet.CredentialProvider{
+			Name:                 provider.Name,
+			MatchImages:          provider.MatchImages,
+			DefaultCacheDuration: provider.DefaultCacheDuration,
+			APIVersion:           providerAPIVersion,
+			Args:                 provider.Args,
+		}
+		for _, name := range sets.StringKeySet(provider.Env).List() {
+			p.Env = append(p.Env, kubelet.ExecEnvVar{Name: name, Value: provider.Env[name]})
+		}
+		config.Providers = append(config.Providers, p)
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling credential provider config: %v", err)
+	}
+
+	t := &nodetasks.File{
+		Path:           credentialProviderConfigFilePath,
+		Contents:       fi.NewBytesResource(data),
+		Type:           nodetasks.FileType_File,
+		BeforeServices: []string{kubeletService},
+	}
+
+	return t, nil
+}
+
+// addCredentialProviders installs the binaries of the image credential providers
+func (b *KubeletBuilder) addCredentialProviders(c *fi.NodeupModelBuilderContext, kubeletConfig *kops.KubeletConfigSpec) error {
+	for i := range kubeletConfig.CredentialProviders {
+		provider := &kubeletConfig.CredentialProviders[i]
+
+		assetURL, _, err := model.CredentialProviderURL(provider, b.kubernetesVersion, b.Architecture)
+		if err != nil {
+			return err
+		}
+		assetName := path.Base(assetURL)
+		asset, err := b.Assets.Find(assetName, "")
+		if err != nil {
+			return fmt.Errorf("error trying to locate asset %q: %v", assetName, err)
+		}
+		if asset == nil {
+			return fmt.Errorf("unable to locate asset %q", assetName)
+		}
+
+		c.AddTask(&nodetasks.File{
+			Path:           filepath.Join(b.credentialProviderBinDir(), provider.Name),
+			Contents:       asset,
+			Type:           nodetasks.FileType_File,
+			Mode:           s("0755"),
+			BeforeServices: []string{kubeletService},
+		})
+	}
+
+	return nil
+}
+
 // buildManifestDirectory creates the directory where kubelet expects static manifests to reside
 func (b *KubeletBuilder) buildManifestDirectory(kubeletConfig *kops.KubeletConfigSpec) (*nodetasks.File, error) {
 	if kubeletConfig.PodManifestPath == "" {
@@ -319,7 +427,7 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		}
 	}
 
-	if b.UseKopsControllerForNodeBootstrap() {
+	if b.UseKopsControllerForNodeBootstrap() && !b.IsEtcdNode {
 		flags += " --tls-cert-file=" + b.PathSrvKubernetes() + "/kubelet-server.crt"
 		flags += " --tls-private-key-file=" + b.PathSrvKubernetes() + "/kubelet-server.key"
 	}
@@ -328,6 +436,11 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		flags += " --node-ip=::"
 	}
 
+	if len(kubeletConfig.CredentialProviders) != 0 {
+		flags += " --image-credential-provider-config=" + credentialProviderConfigFilePath
+		flags += " --image-credential-provider-bin-dir=" + b.credentialProviderBinDir()
+	}
+
 	flags += " --config=" + kubeletConfigFilePath
 
 	sysconfig := "DAEMON_ARGS=\"" + flags + "\"\n"
@@ -616,7 +729,7 @@ func (b *KubeletBuilder) buildControlPlaneKubeletKubeconfig(c *fi.NodeupModelBui
 }
 
 func (b *KubeletBuilder) buildKubeletServingCertificate(c *fi.NodeupModelBuilderContext) error {
-	if b.UseKopsControllerForNodeBootstrap() {
+	if b.UseKopsControllerForNodeBootstrap() && !b.IsEtcdNode {
 		name := "kubelet-server"
 		dir := b.PathSrvKubernetes()
 
@@ -666,7 +779,8 @@ func (b *KubeletBuilder) buildKubeletServingCertificate(c *fi.NodeupModelBuilder
 }
 
 func (b *KubeletBuilder) kubeletNames() ([]string, error) {
-	if b.BootConfig.CloudProvider != kops.CloudProviderAWS {
+	// The instance ID is not set when the tasks are built without an instance, e.g. for kops toolbox task-graph
+	if b.BootConfig.CloudProvider != kops.CloudProviderAWS || b.InstanceID == "" {
 		name, err := os.Hostname()
 		if err != nil {
 			return nil, err
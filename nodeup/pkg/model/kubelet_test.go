@@ -178,6 +178,34 @@ func Test_RunKubeletBuilderWarmPool(t *testing.T) {
 	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks.yaml"), context)
 }
 
+func Test_RunKubeletBuilderCredentialProviders(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.18.0")
+	h.SetupMockAWS()
+
+	basedir := "tests/kubelet/credentialproviders"
+
+	context := &fi.NodeupModelBuilderContext{
+		Tasks: make(map[string]fi.NodeupTask),
+	}
+
+	model, err := testutils.LoadModel(basedir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodeUpModelContext, err := BuildNodeupModelContext(model)
+	if err != nil {
+		t.Fatalf("error loading model %q: %v", basedir, err)
+		return
+	}
+	runKubeletBuilder(t, context, nodeUpModelContext)
+
+	testutils.ValidateTasks(t, filepath.Join(basedir, "tasks.yaml"), context)
+}
+
 func runKubeletBuilder(t *testing.T, context *fi.NodeupModelBuilderContext, nodeupModelContext *NodeupModelContext) {
 	if err := nodeupModelContext.Init(); err != nil {
 		t.Fatalf("error from nodeupModelContext.Init(): %v", err)
@@ -207,6 +235,14 @@ func runKubeletBuilder(t *testing.T, context *fi.NodeupModelBuilderContext, node
 		}
 		context.AddTask(fileTask)
 	}
+	if len(kubeletConfig.CredentialProviders) != 0 {
+		fileTask, err := builder.buildCredentialProviderConfig(kubeletConfig)
+		if err != nil {
+			t.Fatalf("error from KubeletBuilder buildCredentialProviderConfig: %v", err)
+			return
+		}
+		context.AddTask(fileTask)
+	}
 	{
 		task, err := builder.buildManifestDirectory(kubeletConfig)
 		if err != nil {
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+func TestPackagesBuilder_CgroupfsMount(t *testing.T) {
+	grid := []struct {
+		distribution distributions.Distribution
+		expected     bool
+	}{
+		{distribution: distributions.DistributionDebian11, expected: true},
+		{distribution: distributions.DistributionDebian12, expected: false},
+		{distribution: distributions.DistributionUbuntu2204, expected: true},
+		{distribution: distributions.DistributionUbuntu2404, expected: false},
+	}
+
+	for _, g := range grid {
+		b := &PackagesBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Distribution: g.distribution,
+				NodeupConfig: &nodeup.Config{},
+			},
+		}
+		c := &fi.NodeupModelBuilderContext{
+			Tasks: make(map[string]fi.NodeupTask),
+		}
+		if err := b.Build(c); err != nil {
+			t.Fatalf("error from PackagesBuilder Build: %v", err)
+		}
+
+		found := false
+		for _, task := range c.Tasks {
+			if p, ok := task.(*nodetasks.Package); ok && p.Name == "cgroupfs-mount" {
+				found = true
+			}
+		}
+		if found != g.expected {
+			t.Errorf("unexpected cgroupfs-mount package for %v: expected %v, got %v", g.distribution, g.expected, found)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// PrePullImagesBuilder pulls the container images of the instance group before the kubelet starts
+type PrePullImagesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &PrePullImagesBuilder{}
+
+func (b *PrePullImagesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if b.NodeupConfig == nil {
+		return nil
+	}
+
+	// The kubelet service depends on the pull tasks, so the node only registers once the images are available.
+	// Warm pool instances pull the images while warming, so they are already cached when the instance starts.
+	for _, image := range b.NodeupConfig.PrePullImages {
+		c.EnsureTask(&nodetasks.PullImageTask{
+			Name:    image,
+			Runtime: b.NodeupConfig.ContainerRuntime,
+		})
+	}
+
+	return nil
+}
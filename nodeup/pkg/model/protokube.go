@@ -45,7 +45,7 @@ var _ fi.NodeupModelBuilder = &ProtokubeBuilder{}
 // Build is responsible for generating the options for protokube
 func (t *ProtokubeBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	// check is not a master and we are not using gossip (https://github.com/kubernetes/kops/pull/3091)
-	if !t.IsMaster && !t.IsGossip {
+	if !t.IsMaster && !t.IsGossip && !t.usesEtcdNodes() {
 		klog.V(2).Infof("skipping the provisioning of protokube on the nodes")
 		return nil
 	}
@@ -155,6 +155,9 @@ type ProtokubeFlags struct {
 	Master            *bool    `json:"master,omitempty" flag:"master"`
 	Zone              []string `json:"zone,omitempty" flag:"zone"`
 
+	// EtcdNodesClusters are the etcd clusters running on etcd nodes, whose addresses protokube maintains in /etc/hosts.
+	EtcdNodesClusters []string `json:"etcdNodesClusters,omitempty" flag:"etcd-nodes-clusters"`
+
 	// BootstrapMasterNodeLabels applies the critical node-role labels to our node,
 	// which lets us bring up the controllers that can only run on masters, which are then
 	// responsible for node labels.  The node is specified by NodeName
@@ -184,6 +187,10 @@ func (t *ProtokubeBuilder) ProtokubeFlags() (*ProtokubeFlags, error) {
 
 	f.ClusterID = fi.PtrTo(t.NodeupConfig.ClusterName)
 
+	if t.usesEtcdNodes() {
+		f.EtcdNodesClusters = t.NodeupConfig.APIServerConfig.EtcdNodesClusters
+	}
+
 	zone := t.Cluster.Spec.DNSZone
 	if zone != "" {
 		if strings.Contains(zone, ".") {
@@ -235,6 +242,11 @@ func (t *ProtokubeBuilder) ProtokubeFlags() (*ProtokubeFlags, error) {
 	return f, nil
 }
 
+// usesEtcdNodes returns true if kube-apiserver runs on this node and uses etcd clusters running on etcd nodes.
+func (t *ProtokubeBuilder) usesEtcdNodes() bool {
+	return t.HasAPIServer && len(t.NodeupConfig.APIServerConfig.EtcdNodesClusters) != 0
+}
+
 func (t *ProtokubeBuilder) buildEnvFile() (*nodetasks.File, error) {
 	envVars := make(map[string]string)
 
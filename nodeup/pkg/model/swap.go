@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// swapScriptPath is the script that provisions and enables the swap device
+	swapScriptPath = "/opt/kops/bin/kops-swap"
+	// swapFilePath is the location of the swap file
+	swapFilePath = "/var/swapfile"
+)
+
+// SwapBuilder provisions the swap device of the instance group
+type SwapBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.NodeupModelBuilder = &SwapBuilder{}
+
+// Build is responsible for creating the swap device and enabling it on every boot, before the kubelet starts
+func (b *SwapBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	swap := b.NodeupConfig.Swap
+	if swap == nil || swap.Size == nil {
+		return nil
+	}
+
+	script, err := buildSwapScript(swap)
+	if err != nil {
+		return err
+	}
+	c.AddTask(&nodetasks.File{
+		Path:     swapScriptPath,
+		Contents: fi.NewStringResource(script),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Provision and enable the swap device")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "Before", "kubelet.service")
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	manifest.Set("Service", "ExecStart", swapScriptPath)
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "kops-swap", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "kops-swap.service",
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+	c.AddTask(service)
+
+	return nil
+}
+
+// buildSwapScript builds the script that creates the swap device when it does not exist, and enables it.
+// The swap file persists across reboots, while the zram device is created again on every boot.
+func buildSwapScript(swap *kops.SwapSpec) (string, error) {
+	size := swap.Size.Value()
+	if size <= 0 {
+		return "", fmt.Errorf("invalid swap size %q", swap.Size.String())
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/bash\n")
+	sb.WriteString("# Built by kops - do not edit\n\n")
+	sb.WriteString("set -o errexit\nset -o nounset\nset -o pipefail\n\n")
+
+	switch swap.Type {
+	case "", kops.SwapTypeFile:
+		fmt.Fprintf(&sb, "SWAPFILE=%s\n", swapFilePath)
+		fmt.Fprintf(&sb, "SIZE=%d\n\n", size)
+		sb.WriteString("if swapon --show=NAME --noheadings | grep -qx \"${SWAPFILE}\"; then\n")
+		sb.WriteString("  exit 0\n")
+		sb.WriteString("fi\n\n")
+		sb.WriteString("if [[ ! -f \"${SWAPFILE}\" || \"$(stat -c %s \"${SWAPFILE}\")\" != \"${SIZE}\" ]]; then\n")
+		sb.WriteString("  rm -f \"${SWAPFILE}\"\n")
+		sb.WriteString("  # fallocate is not supported by all filesystems\n")
+		sb.WriteString("  fallocate -l \"${SIZE}\" \"${SWAPFILE}\" || dd if=/dev/zero of=\"${SWAPFILE}\" bs=1M count=$((SIZE / 1048576))\n")
+		sb.WriteString("  chmod 0600 \"${SWAPFILE}\"\n")
+		sb.WriteString("  mkswap \"${SWAPFILE}\"\n")
+		sb.WriteString("fi\n\n")
+		sb.WriteString("swapon \"${SWAPFILE}\"\n")
+
+	case kops.SwapTypeZram:
+		fmt.Fprintf(&sb, "SIZE=%d\n\n", size)
+		sb.WriteString("if swapon --show=NAME --noheadings | grep -q '^/dev/zram'; then\n")
+		sb.WriteString("  exit 0\n")
+		sb.WriteString("fi\n\n")
+		sb.WriteString("modprobe zram\n")
+		sb.WriteString("DEVICE=$(zramctl --find --size \"${SIZE}\")\n")
+		sb.WriteString("mkswap \"${DEVICE}\"\n")
+		sb.WriteString("# Prefer the compressed memory over any other swap device\n")
+		sb.WriteString("swapon --priority 100 \"${DEVICE}\"\n")
+
+	default:
+		return "", fmt.Errorf("unsupported swap type %q", swap.Type)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func Test_BuildSwapScript(t *testing.T) {
+	grid := []struct {
+		swap     kops.SwapSpec
+		expected []string
+		err      bool
+	}{
+		{
+			swap:     kops.SwapSpec{Size: resource.NewQuantity(2*1024*1024*1024, resource.BinarySI)},
+			expected: []string{"SWAPFILE=/var/swapfile\n", "SIZE=2147483648\n", "mkswap \"${SWAPFILE}\"\n", "swapon \"${SWAPFILE}\"\n"},
+		},
+		{
+			swap:     kops.SwapSpec{Type: kops.SwapTypeZram, Size: resource.NewQuantity(512*1024*1024, resource.BinarySI)},
+			expected: []string{"SIZE=536870912\n", "modprobe zram\n", "zramctl --find --size \"${SIZE}\"", "swapon --priority 100 \"${DEVICE}\"\n"},
+		},
+		{
+			swap: kops.SwapSpec{Type: "partition", Size: resource.NewQuantity(1024, resource.BinarySI)},
+			err:  true,
+		},
+	}
+
+	for _, g := range grid {
+		script, err := buildSwapScript(&g.swap)
+		if g.err {
+			if err == nil {
+				t.Errorf("expected error building swap script for %v", g.swap)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error building swap script for %v: %v", g.swap, err)
+			continue
+		}
+		for _, expected := range g.expected {
+			if !strings.Contains(script, expected) {
+				t.Errorf("swap script for %v does not contain %q:\n%s", g.swap, expected, script)
+			}
+		}
+	}
+}
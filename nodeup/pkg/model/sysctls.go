@@ -148,7 +148,7 @@ func (b *SysctlBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	}
 
 	if b.IsIPv6Only() {
-		if b.Distribution == distributions.DistributionDebian11 {
+		if b.Distribution == distributions.DistributionDebian11 || b.Distribution == distributions.DistributionDebian12 {
 			// Accepting Router Advertisements must be enabled for each existing network interface to take effect.
 			// net.ipv6.conf.all.accept_ra takes effect only for newly created network interfaces.
 			// https://bugzilla.kernel.org/show_bug.cgi?id=11655
@@ -17,8 +17,14 @@ limitations under the License.
 package model
 
 import (
+	"bytes"
+	"crypto/rand"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 
 	"k8s.io/klog/v2"
@@ -54,18 +60,24 @@ func (b *VolumesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			Interface: mount.New(""),
 		}
 
+		// @step: assemble and encrypt the devices when required
+		device, err := ensureVolumeMountDevice(m.Exec, x)
+		if err != nil {
+			return err
+		}
+
 		// @check if the device is already mounted
-		if found, err := b.IsMounted(m, x.Device, x.Path); err != nil {
-			return fmt.Errorf("failed to check if device %q is mounted, error: %w", x.Device, err)
+		if found, err := b.IsMounted(m, device, x.Path); err != nil {
+			return fmt.Errorf("failed to check if device %q is mounted, error: %w", device, err)
 		} else if found {
-			klog.V(3).Infof("Skipping device: %s, path: %s as already mounted", x.Device, x.Path)
+			klog.V(3).Infof("Skipping device: %s, path: %s as already mounted", device, x.Path)
 			continue
 		}
 
-		klog.Infof("Attempting to format and mount device: %s, path: %s", x.Device, x.Path)
+		klog.Infof("Attempting to format and mount device: %s, path: %s", device, x.Path)
 
-		if err := m.FormatAndMount(x.Device, x.Path, x.Filesystem, x.MountOptions); err != nil {
-			klog.Errorf("failed to mount the device: %s on: %s, error: %s", x.Device, x.Path, err)
+		if err := m.FormatAndMount(device, x.Path, x.Filesystem, x.MountOptions); err != nil {
+			klog.Errorf("failed to mount the device: %s on: %s, error: %s", device, x.Path, err)
 
 			return err
 		}
@@ -73,3 +85,103 @@ func (b *VolumesBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 
 	return nil
 }
+
+var (
+	// raidArrayDir is the directory containing the named RAID arrays
+	raidArrayDir = "/dev/md"
+	// deviceMapperDir is the directory containing the opened encrypted devices
+	deviceMapperDir = "/dev/mapper"
+)
+
+// ensureVolumeMountDevice assembles the devices of the volume mount into a RAID0 array and
+// encrypts the result when requested, returning the device to format and mount
+func ensureVolumeMountDevice(e utilexec.Interface, x kops.VolumeMountSpec) (string, error) {
+	device := x.Device
+	name := volumeMountName(x.Path)
+
+	if len(x.Devices) > 0 {
+		var err error
+		if device, err = ensureRAIDArray(e, name, x.Devices); err != nil {
+			return "", err
+		}
+	}
+
+	if fi.ValueOf(x.Encrypted) {
+		var err error
+		if device, err = ensureEncryptedDevice(e, name, device); err != nil {
+			return "", err
+		}
+	}
+
+	return device, nil
+}
+
+// volumeMountName returns the name of the RAID array and of the encrypted device of a volume mount,
+// i.e. /var/lib/kubelet gives kops-var-lib-kubelet
+func volumeMountName(path string) string {
+	return "kops-" + strings.ReplaceAll(strings.Trim(path, "/"), "/", "-")
+}
+
+// ensureRAIDArray assembles the devices into a RAID0 array, creating the array when the devices are not part of one
+func ensureRAIDArray(e utilexec.Interface, name string, devices []string) (string, error) {
+	array := filepath.Join(raidArrayDir, name)
+
+	if _, err := os.Stat(array); err == nil {
+		klog.V(3).Infof("Skipping RAID array: %s as already assembled", array)
+		return filepath.EvalSymlinks(array)
+	}
+
+	if _, err := e.LookPath("mdadm"); err != nil {
+		return "", fmt.Errorf("mdadm is required to assemble the RAID array: %s, error: %w", array, err)
+	}
+
+	// @step: the array survives a reboot, but the instance storage is wiped when the instance is stopped
+	args := append([]string{"--assemble", array}, devices...)
+	if out, err := e.Command("mdadm", args...).CombinedOutput(); err != nil {
+		klog.Infof("Creating RAID array: %s from devices: %v, unable to assemble: %s", array, devices, string(out))
+
+		args = append([]string{"--create", array, "--run", "--level=0", fmt.Sprintf("--raid-devices=%d", len(devices))}, devices...)
+		if out, err := e.Command("mdadm", args...).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to create the RAID array: %s, error: %w, output: %s", array, err, string(out))
+		}
+	}
+
+	// @note: the mounts reference the md device rather than the symlink
+	return filepath.EvalSymlinks(array)
+}
+
+// ensureEncryptedDevice encrypts the device with LUKS and opens it, using a random key that is never persisted.
+// The previous contents of the device are lost, because the key of the previous boot is gone.
+func ensureEncryptedDevice(e utilexec.Interface, name string, device string) (string, error) {
+	mapping := filepath.Join(deviceMapperDir, name)
+
+	// @check the mapping is removed on reboot, so if it exists it was opened during this boot
+	if _, err := os.Stat(mapping); err == nil {
+		klog.V(3).Infof("Skipping encrypted device: %s as already opened", mapping)
+		return mapping, nil
+	}
+
+	if _, err := e.LookPath("cryptsetup"); err != nil {
+		return "", fmt.Errorf("cryptsetup is required to encrypt the device: %s, error: %w", device, err)
+	}
+
+	key := make([]byte, 64)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate the key for device: %s, error: %w", device, err)
+	}
+
+	klog.Infof("Encrypting device: %s as: %s", device, mapping)
+
+	for _, args := range [][]string{
+		{"luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", device},
+		{"open", "--key-file", "-", device, name},
+	} {
+		cmd := e.Command("cryptsetup", args...)
+		cmd.SetStdin(bytes.NewReader(key))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to run cryptsetup %s on device: %s, error: %w, output: %s", args[0], device, err, string(out))
+		}
+	}
+
+	return mapping, nil
+}
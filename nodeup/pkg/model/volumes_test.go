@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	utilexec "k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+)
+
+// fakeCommand returns a command action which records the arguments of the command, and the length
+// of the key passed on stdin, and returns the error. When the command succeeds, it creates the file created.
+func fakeCommand(calls *[][]string, err error, created string) fakeexec.FakeCommandAction {
+	return func(cmd string, args ...string) utilexec.Cmd {
+		fake := &fakeexec.FakeCmd{}
+		fake.CombinedOutputScript = []fakeexec.FakeAction{
+			func() ([]byte, []byte, error) {
+				argv := append([]string{cmd}, args...)
+				if fake.Stdin != nil {
+					key, _ := io.ReadAll(fake.Stdin)
+					argv = append(argv, fmt.Sprintf("<%d", len(key)))
+				}
+				*calls = append(*calls, argv)
+				if err != nil {
+					return []byte("failed"), nil, err
+				}
+				if created != "" {
+					if err := os.WriteFile(created, nil, 0o644); err != nil {
+						return nil, nil, err
+					}
+				}
+				return nil, nil, nil
+			},
+		}
+		return fakeexec.InitFakeCmd(fake, cmd, args...)
+	}
+}
+
+func lookPath(found bool) func(string) (string, error) {
+	return func(file string) (string, error) {
+		if !found {
+			return "", errors.New("not found")
+		}
+		return "/usr/sbin/" + file, nil
+	}
+}
+
+func TestEnsureRAIDArray(t *testing.T) {
+	devices := []string{"/dev/nvme1n1", "/dev/nvme2n1"}
+	failed := errors.New("exit status 1")
+
+	grid := []struct {
+		name      string
+		assembled bool
+		mdadm     bool
+		assemble  error
+		create    error
+		expected  [][]string
+		expectErr bool
+	}{
+		{
+			name:      "already assembled",
+			assembled: true,
+			mdadm:     true,
+		},
+		{
+			name:     "reuse existing array",
+			mdadm:    true,
+			expected: [][]string{{"mdadm", "--assemble", "ARRAY", "/dev/nvme1n1", "/dev/nvme2n1"}},
+		},
+		{
+			name:     "create new array",
+			mdadm:    true,
+			assemble: failed,
+			expected: [][]string{
+				{"mdadm", "--assemble", "ARRAY", "/dev/nvme1n1", "/dev/nvme2n1"},
+				{"mdadm", "--create", "ARRAY", "--run", "--level=0", "--raid-devices=2", "/dev/nvme1n1", "/dev/nvme2n1"},
+			},
+		},
+		{
+			name:     "create fails",
+			mdadm:    true,
+			assemble: failed,
+			create:   failed,
+			expected: [][]string{
+				{"mdadm", "--assemble", "ARRAY", "/dev/nvme1n1", "/dev/nvme2n1"},
+				{"mdadm", "--create", "ARRAY", "--run", "--level=0", "--raid-devices=2", "/dev/nvme1n1", "/dev/nvme2n1"},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "mdadm missing",
+			expectErr: true,
+		},
+	}
+
+	defer func(dir string) { raidArrayDir = dir }(raidArrayDir)
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			raidArrayDir = t.TempDir()
+			array := filepath.Join(raidArrayDir, "kops-data")
+			if g.assembled {
+				if err := os.WriteFile(array, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var calls [][]string
+			fake := &fakeexec.FakeExec{LookPathFunc: lookPath(g.mdadm)}
+			if g.assemble != nil {
+				fake.CommandScript = append(fake.CommandScript, fakeCommand(&calls, g.assemble, ""))
+				fake.CommandScript = append(fake.CommandScript, fakeCommand(&calls, g.create, array))
+			} else {
+				fake.CommandScript = append(fake.CommandScript, fakeCommand(&calls, nil, array))
+			}
+
+			device, err := ensureRAIDArray(fake, "kops-data", devices)
+			if g.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got device %q", device)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if device != array {
+					t.Errorf("expected device %q, got %q", array, device)
+				}
+			}
+
+			for _, call := range g.expected {
+				call[2] = array
+			}
+			if !reflect.DeepEqual(calls, g.expected) {
+				t.Errorf("expected commands %v, got %v", g.expected, calls)
+			}
+		})
+	}
+}
+
+func TestEnsureEncryptedDevice(t *testing.T) {
+	failed := errors.New("exit status 1")
+
+	grid := []struct {
+		name       string
+		opened     bool
+		cryptsetup bool
+		format     error
+		open       error
+		expected   [][]string
+		expectErr  bool
+	}{
+		{
+			name:       "already opened",
+			opened:     true,
+			cryptsetup: true,
+		},
+		{
+			name:       "encrypt and open",
+			cryptsetup: true,
+			expected: [][]string{
+				{"cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", "/dev/md127", "<64"},
+				{"cryptsetup", "open", "--key-file", "-", "/dev/md127", "kops-data", "<64"},
+			},
+		},
+		{
+			name:       "format fails",
+			cryptsetup: true,
+			format:     failed,
+			expected: [][]string{
+				{"cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", "/dev/md127", "<64"},
+			},
+			expectErr: true,
+		},
+		{
+			name:       "open fails",
+			cryptsetup: true,
+			open:       failed,
+			expected: [][]string{
+				{"cryptsetup", "luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", "/dev/md127", "<64"},
+				{"cryptsetup", "open", "--key-file", "-", "/dev/md127", "kops-data", "<64"},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "cryptsetup missing",
+			expectErr: true,
+		},
+	}
+
+	defer func(dir string) { deviceMapperDir = dir }(deviceMapperDir)
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			deviceMapperDir = t.TempDir()
+			mapping := filepath.Join(deviceMapperDir, "kops-data")
+			if g.opened {
+				if err := os.WriteFile(mapping, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var calls [][]string
+			fake := &fakeexec.FakeExec{
+				LookPathFunc: lookPath(g.cryptsetup),
+				CommandScript: []fakeexec.FakeCommandAction{
+					fakeCommand(&calls, g.format, ""),
+					fakeCommand(&calls, g.open, ""),
+				},
+			}
+
+			device, err := ensureEncryptedDevice(fake, "kops-data", "/dev/md127")
+			if g.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got device %q", device)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if device != mapping {
+					t.Errorf("expected device %q, got %q", mapping, device)
+				}
+			}
+
+			if !reflect.DeepEqual(calls, g.expected) {
+				t.Errorf("expected commands %v, got %v", g.expected, calls)
+			}
+		})
+	}
+}
@@ -37,7 +37,7 @@ func (b *WarmPoolBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	// Pre-pull container images during pre-initialization
 	if b.NodeupConfig != nil && b.ConfigurationMode == "Warming" {
 		for _, image := range b.NodeupConfig.WarmPoolImages {
-			c.AddTask(&nodetasks.PullImageTask{
+			c.EnsureTask(&nodetasks.PullImageTask{
 				Name:    image,
 				Runtime: b.NodeupConfig.ContainerRuntime,
 			})
@@ -158,6 +158,8 @@ type ClusterSpec struct {
 	// specified, each parameter must follow the form variable=value, the way
 	// it would appear in sysctl.conf.
 	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// Hardening configures the security hardening of the instances of all instance groups.
+	Hardening *HardeningSpec `json:"hardening,omitempty"`
 	// RollingUpdate defines the default rolling-update settings for instance groups.
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 	// ClusterAutoscaler defines the cluster autoscaler configuration.
@@ -331,8 +333,23 @@ type AssetsSpec struct {
 	ContainerRegistry *string `json:"containerRegistry,omitempty"`
 	// FileRepository is the url for a private file serving repository
 	FileRepository *string `json:"fileRepository,omitempty"`
+	// FileRepositoryMirrors are file serving repositories that are tried in order when a file cannot be downloaded from the FileRepository
+	FileRepositoryMirrors []string `json:"fileRepositoryMirrors,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// ImageVerification configures the verification of the signatures of images
+	ImageVerification *ImageVerificationSpec `json:"imageVerification,omitempty"`
+}
+
+// ImageVerificationSpec configures the verification of cosign signatures of images, with offline public keys
+type ImageVerificationSpec struct {
+	// PublicKeys are the PEM-encoded public keys that images can be signed with
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	// Images are the prefixes of the canonical locations of the container images to verify in their registries; all container images are verified if empty
+	Images []string `json:"images,omitempty"`
+	// Tarballs are the prefixes of the URLs of the image tarballs that nodeup preloads to verify with the cosign sign-blob signatures stored next to them;
+	// no tarball is verified if empty
+	Tarballs []string `json:"tarballs,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -634,6 +651,13 @@ type EtcdClusterSpec struct {
 	Provider EtcdProviderType `json:"provider,omitempty"`
 	// Members stores the configurations for each member of the cluster (including the data volume)
 	Members []EtcdMemberSpec `json:"etcdMembers,omitempty"`
+	// Resources are the resources that kube-apiserver stores in this etcd cluster, as group/resource
+	// (for example coordination.k8s.io/leases, or /events for a resource of the core group).
+	// Required for etcd clusters other than main, events and cilium. Defaults to /events for the events etcd cluster.
+	Resources []string `json:"resources,omitempty"`
+	// PortIndex selects the ports of an additional etcd cluster among the ports kOps reserves for additional etcd clusters.
+	// It is assigned when the etcd cluster is added and must not change, so the ports don't depend on the order of the etcd clusters.
+	PortIndex *int32 `json:"portIndex,omitempty"`
 	// Version is the version of etcd to run.
 	Version string `json:"version,omitempty"`
 	// LeaderElectionTimeout is the time (in milliseconds) for an etcd leader election timeout
@@ -232,6 +232,29 @@ type KubeletConfigSpec struct {
 	// ShutdownGracePeriodCriticalPods specifies the duration used to terminate critical pods during a node shutdown.
 	// Default: 10s
 	ShutdownGracePeriodCriticalPods *metav1.Duration `json:"shutdownGracePeriodCriticalPods,omitempty"`
+	// MemorySwapBehavior is the swap behavior of the pods when the NodeSwap feature gate is enabled:
+	// LimitedSwap or UnlimitedSwap before Kubernetes 1.30, NoSwap or LimitedSwap from Kubernetes 1.30.
+	MemorySwapBehavior string `json:"memorySwapBehavior,omitempty"`
+	// CredentialProviders configures the exec plugins the kubelet uses to fetch credentials for image registries.
+	CredentialProviders []KubeletCredentialProviderSpec `json:"credentialProviders,omitempty" flag:"-"`
+}
+
+// KubeletCredentialProviderSpec configures an image credential provider plugin of the kubelet.
+type KubeletCredentialProviderSpec struct {
+	// Name is the name of the credential provider, which is also the name of the plugin binary.
+	// The defaults for the other fields are filled in for the known providers:
+	// ecr-credential-provider, auth-provider-gcp and acr-credential-provider.
+	Name string `json:"name,omitempty"`
+	// MatchImages is the list of image patterns for which the kubelet invokes the provider.
+	MatchImages []string `json:"matchImages,omitempty"`
+	// DefaultCacheDuration is how long the kubelet caches credentials when the provider does not specify a duration.
+	DefaultCacheDuration *metav1.Duration `json:"defaultCacheDuration,omitempty"`
+	// Args are the arguments passed to the provider binary.
+	Args []string `json:"args,omitempty"`
+	// Env are the environment variables set for the provider binary.
+	Env map[string]string `json:"env,omitempty"`
+	// Packages overrides the URLs and hashes of the provider binary.
+	Packages *PackagesConfig `json:"packages,omitempty"`
 }
 
 // KubeProxyConfig defines the configuration for a proxy
@@ -43,6 +43,8 @@ type ContainerdConfig struct {
 	NvidiaGPU *NvidiaGPUConfig `json:"nvidiaGPU,omitempty"`
 	// Runc configures the runc runtime.
 	Runc *Runc `json:"runc,omitempty"`
+	// Runtimes configures additional runtime handlers for sandboxed runtimes, such as gVisor or Kata Containers.
+	Runtimes []ContainerdRuntimeSpec `json:"runtimes,omitempty"`
 }
 
 type NvidiaGPUConfig struct {
@@ -69,3 +71,32 @@ type Runc struct {
 	// Packages overrides the URL and hash for the packages.
 	Packages *PackagesConfig `json:"packages,omitempty"`
 }
+
+// ContainerdRuntimeSpec configures an additional runtime handler of containerd.
+type ContainerdRuntimeSpec struct {
+	// Name is the name of the runtime handler and of its RuntimeClass.
+	// Default is the type of the runtime.
+	Name string `json:"name,omitempty"`
+	// Type is the sandboxed runtime used by the handler: gvisor or kata.
+	Type string `json:"type,omitempty"`
+	// Packages sets the URL and hash of the .tar.gz archive with the binaries of the runtime.
+	Packages *PackagesConfig `json:"packages,omitempty"`
+}
+
+const (
+	// ContainerdRuntimeGVisor is the type of the gVisor runtime handler.
+	ContainerdRuntimeGVisor = "gvisor"
+	// ContainerdRuntimeKata is the type of the Kata Containers runtime handler.
+	ContainerdRuntimeKata = "kata"
+
+	// ContainerdRuntimeNodeLabelPrefix is the prefix of the node labels set for the runtime handlers of the node.
+	ContainerdRuntimeNodeLabelPrefix = "runtime.kops.k8s.io/"
+)
+
+// HandlerName returns the name of the runtime handler, which defaults to the type of the runtime.
+func (r *ContainerdRuntimeSpec) HandlerName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Type
+}
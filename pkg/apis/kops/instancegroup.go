@@ -63,6 +63,8 @@ const (
 	InstanceGroupRoleBastion InstanceGroupRole = "Bastion"
 	// InstanceGroupRoleAPIServer is an API server role.
 	InstanceGroupRoleAPIServer InstanceGroupRole = "APIServer"
+	// InstanceGroupRoleEtcd is a dedicated etcd role.
+	InstanceGroupRoleEtcd InstanceGroupRole = "Etcd"
 )
 
 // AllInstanceGroupRoles is a slice of all valid InstanceGroupRole values
@@ -71,6 +73,7 @@ var AllInstanceGroupRoles = []InstanceGroupRole{
 	InstanceGroupRoleAPIServer,
 	InstanceGroupRoleNode,
 	InstanceGroupRoleBastion,
+	InstanceGroupRoleEtcd,
 }
 
 const (
@@ -198,6 +201,16 @@ type InstanceGroupSpec struct {
 	//   'STANDARD': (default) standard provisioning with user controlled run time, no discounts
 	//   'SPOT': heavily discounted, no guaranteed run time.
 	GCPProvisioningModel *string `json:"gcpProvisioningModel,omitempty"`
+	// HostFirewall configures an nftables firewall on the instances, which drops the incoming traffic that is not allowed.
+	HostFirewall *HostFirewallSpec `json:"hostFirewall,omitempty"`
+	// Hardening configures the security hardening of the instances, overriding the profile of the cluster.
+	// The kernel modules and audit rules are added to those of the cluster.
+	Hardening *HardeningSpec `json:"hardening,omitempty"`
+	// PrePullImages are container images pulled by nodeup before the kubelet starts, and by warm pool instances while warming.
+	// Images must be pinned by digest, and are remapped to the container registry of the assets.
+	PrePullImages []string `json:"prePullImages,omitempty"`
+	// Swap configures a swap device on the instances, and the kubelet to allow pods to use it.
+	Swap *SwapSpec `json:"swap,omitempty"`
 }
 
 const (
@@ -321,6 +334,11 @@ type VolumeSpec struct {
 type VolumeMountSpec struct {
 	// Device is the device name to provision and mount
 	Device string `json:"device,omitempty"`
+	// Devices are the device names to assemble into a RAID0 array, which is provisioned and mounted instead of Device
+	Devices []string `json:"devices,omitempty"`
+	// Encrypted encrypts the device with LUKS, using a random key generated on every boot.
+	// The data on the device does not survive a reboot.
+	Encrypted *bool `json:"encrypted,omitempty"`
 	// Filesystem is the filesystem to mount
 	Filesystem string `json:"filesystem,omitempty"`
 	// FormatOptions is a collection of options passed when formatting the device
@@ -331,6 +349,24 @@ type VolumeMountSpec struct {
 	Path string `json:"path,omitempty"`
 }
 
+const (
+	// SwapTypeFile provisions swap as a file on the root volume
+	SwapTypeFile = "file"
+	// SwapTypeZram provisions swap as a compressed block device in memory
+	SwapTypeZram = "zram"
+)
+
+// SupportedSwapTypes are the supported types of swap device
+var SupportedSwapTypes = []string{SwapTypeFile, SwapTypeZram}
+
+// SwapSpec configures swap on the instances of an instance group
+type SwapSpec struct {
+	// Type is the type of swap device: file (default) or zram.
+	Type string `json:"type,omitempty"`
+	// Size is the size of the swap file or zram device.
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
 // IAMProfileSpec is the AWS IAM Profile to attach to instances in this instance
 // group. Specify the ARN for the IAM instance profile (AWS only).
 type IAMProfileSpec struct {
@@ -364,6 +400,16 @@ func (g *InstanceGroup) HasAPIServer() bool {
 	return g.IsControlPlane() || g.IsAPIServerOnly()
 }
 
+// IsEtcdOnly checks if instanceGroup only runs etcd members
+func (g *InstanceGroup) IsEtcdOnly() bool {
+	switch g.Spec.Role {
+	case InstanceGroupRoleEtcd:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsBastion checks if instanceGroup is a bastion
 func (g *InstanceGroup) IsBastion() bool {
 	switch g.Spec.Role {
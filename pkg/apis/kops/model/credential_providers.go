@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+const (
+	// CredentialProviderECR is the image credential provider for Amazon ECR.
+	CredentialProviderECR = "ecr-credential-provider"
+	// CredentialProviderGCP is the image credential provider for Google Container Registry and Artifact Registry.
+	CredentialProviderGCP = "auth-provider-gcp"
+	// CredentialProviderACR is the image credential provider for Azure Container Registry.
+	CredentialProviderACR = "acr-credential-provider"
+)
+
+type credentialProviderDefaults struct {
+	matchImages          []string
+	defaultCacheDuration time.Duration
+	args                 []string
+	// releases are the released binaries of the provider, newest first.
+	// Only release locations that publish a .sha256 file next to each binary are listed,
+	// as the hashes are looked up there; other providers must set their packages.
+	releases []credentialProviderRelease
+}
+
+type credentialProviderRelease struct {
+	// kubernetesVersion is the oldest Kubernetes version the release is used for
+	kubernetesVersion string
+	// url is the download location of the provider binary, formatted with the architecture.
+	url string
+}
+
+var knownCredentialProviders = map[string]credentialProviderDefaults{
+	CredentialProviderECR: {
+		matchImages: []string{
+			"*.dkr.ecr.*.amazonaws.com",
+			"*.dkr.ecr.*.amazonaws.com.cn",
+			"*.dkr.ecr-fips.*.amazonaws.com",
+			"*.dkr.ecr.us-iso-east-1.c2s.ic.gov",
+			"*.dkr.ecr.us-isob-east-1.sc2s.sgov.gov",
+		},
+		defaultCacheDuration: 12 * time.Hour,
+		args:                 []string{"get-credentials"},
+		releases: []credentialProviderRelease{
+			{kubernetesVersion: "1.28", url: "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.28.1/linux/%[1]s/ecr-credential-provider-linux-%[1]s"},
+			{kubernetesVersion: "1.0", url: "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.27.1/linux/%[1]s/ecr-credential-provider-linux-%[1]s"},
+		},
+	},
+	CredentialProviderGCP: {
+		matchImages: []string{
+			"container.cloud.google.com",
+			"gcr.io",
+			"*.gcr.io",
+			"*.pkg.dev",
+		},
+		defaultCacheDuration: 1 * time.Minute,
+		args:                 []string{"get-credentials", "--v=3"},
+		// The provider is only published as staging builds
+	},
+	CredentialProviderACR: {
+		matchImages: []string{
+			"*.azurecr.io",
+			"*.azurecr.cn",
+			"*.azurecr.de",
+			"*.azurecr.us",
+		},
+		defaultCacheDuration: 10 * time.Minute,
+		// The provider reads the credentials of the managed identity from the cloud config written by nodeup
+		args: []string{"/etc/kubernetes/cloud.config"},
+		// The GitHub releases of the provider do not publish hashes next to the binaries
+	},
+}
+
+// IsKnownCredentialProvider returns true if kops knows the defaults for the image credential provider.
+func IsKnownCredentialProvider(name string) bool {
+	_, found := knownCredentialProviders[name]
+	return found
+}
+
+// CredentialProviderWithDefaults returns a copy of the image credential provider,
+// with the unset fields filled in from the defaults of the known provider of the same name.
+// The cache duration of unknown providers defaults to one minute.
+func CredentialProviderWithDefaults(provider *kops.KubeletCredentialProviderSpec) *kops.KubeletCredentialProviderSpec {
+	p := provider.DeepCopy()
+	defaults, found := knownCredentialProviders[p.Name]
+	if !found {
+		defaults = credentialProviderDefaults{
+			defaultCacheDuration: 1 * time.Minute,
+		}
+	}
+
+	if len(p.MatchImages) == 0 {
+		p.MatchImages = append([]string(nil), defaults.matchImages...)
+	}
+	if p.DefaultCacheDuration == nil {
+		p.DefaultCacheDuration = &metav1.Duration{Duration: defaults.defaultCacheDuration}
+	}
+	if len(p.Args) == 0 {
+		p.Args = append([]string(nil), defaults.args...)
+	}
+	return p
+}
+
+// CredentialProviderURL returns the download location of the binary of the image credential provider for the architecture,
+// using the release of known providers for the Kubernetes version.
+// The returned hash is empty when it is not known in advance.
+func CredentialProviderURL(provider *kops.KubeletCredentialProviderSpec, kubernetesVersion semver.Version, arch architectures.Architecture) (string, string, error) {
+	if provider.Packages != nil {
+		switch arch {
+		case architectures.ArchitectureAmd64:
+			if provider.Packages.UrlAmd64 != nil {
+				return *provider.Packages.UrlAmd64, stringValue(provider.Packages.HashAmd64), nil
+			}
+		case architectures.ArchitectureArm64:
+			if provider.Packages.UrlArm64 != nil {
+				return *provider.Packages.UrlArm64, stringValue(provider.Packages.HashArm64), nil
+			}
+		}
+	}
+
+	defaults := knownCredentialProviders[provider.Name]
+	for _, release := range defaults.releases {
+		if util.IsKubernetesGTE(release.kubernetesVersion, kubernetesVersion) {
+			return fmt.Sprintf(release.url, arch), "", nil
+		}
+	}
+	return "", "", fmt.Errorf("no download location known for image credential provider %q on %s", provider.Name, arch)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestCredentialProviderURL(t *testing.T) {
+	acrURL, acrHash := "https://example.com/acr-credential-provider", "abc"
+	grid := []struct {
+		name              string
+		kubernetesVersion string
+		arch              architectures.Architecture
+		packages          *kops.PackagesConfig
+		expectedURL       string
+		expectedHash      string
+		expectError       bool
+	}{
+		{
+			name:              CredentialProviderECR,
+			kubernetesVersion: "1.27.3",
+			arch:              architectures.ArchitectureAmd64,
+			expectedURL:       "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.27.1/linux/amd64/ecr-credential-provider-linux-amd64",
+		},
+		{
+			name:              CredentialProviderECR,
+			kubernetesVersion: "1.28.0-alpha.1",
+			arch:              architectures.ArchitectureArm64,
+			expectedURL:       "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.28.1/linux/arm64/ecr-credential-provider-linux-arm64",
+		},
+		{
+			name:              CredentialProviderECR,
+			kubernetesVersion: "1.25.0",
+			arch:              architectures.ArchitectureAmd64,
+			expectedURL:       "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.27.1/linux/amd64/ecr-credential-provider-linux-amd64",
+		},
+		{
+			name:              CredentialProviderGCP,
+			kubernetesVersion: "1.28.0",
+			arch:              architectures.ArchitectureAmd64,
+			expectError:       true,
+		},
+		{
+			name:              CredentialProviderACR,
+			kubernetesVersion: "1.28.0",
+			arch:              architectures.ArchitectureAmd64,
+			packages: &kops.PackagesConfig{
+				UrlAmd64:  &acrURL,
+				HashAmd64: &acrHash,
+			},
+			expectedURL:  "https://example.com/acr-credential-provider",
+			expectedHash: "abc",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name+"-"+g.kubernetesVersion, func(t *testing.T) {
+			kubernetesVersion, err := util.ParseKubernetesVersion(g.kubernetesVersion)
+			if err != nil {
+				t.Fatalf("error parsing kubernetes version: %v", err)
+			}
+			provider := &kops.KubeletCredentialProviderSpec{Name: g.name, Packages: g.packages}
+
+			url, hash, err := CredentialProviderURL(provider, *kubernetesVersion, g.arch)
+			if g.expectError {
+				if err == nil {
+					t.Errorf("expected error, got download location %q", url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if url != g.expectedURL || hash != g.expectedHash {
+				t.Errorf("unexpected download location %q (hash %q), expected %q (hash %q)", url, hash, g.expectedURL, g.expectedHash)
+			}
+		})
+	}
+}
+
+func TestCredentialProviderWithDefaults(t *testing.T) {
+	grid := map[string][]string{
+		CredentialProviderECR: {"get-credentials"},
+		CredentialProviderGCP: {"get-credentials", "--v=3"},
+		CredentialProviderACR: {"/etc/kubernetes/cloud.config"},
+	}
+
+	for name, expectedArgs := range grid {
+		withDefaults := CredentialProviderWithDefaults(&kops.KubeletCredentialProviderSpec{Name: name})
+		if len(withDefaults.MatchImages) == 0 {
+			t.Errorf("expected default image patterns for %q", name)
+		}
+		if !reflect.DeepEqual(withDefaults.Args, expectedArgs) {
+			t.Errorf("unexpected args %v for %q, expected %v", withDefaults.Args, name, expectedArgs)
+		}
+	}
+}
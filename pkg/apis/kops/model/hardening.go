@@ -0,0 +1,445 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/reflectutils"
+)
+
+// HardeningTLSCipherSuites are the TLS cipher suites allowed by the cis-level2 profile.
+var HardeningTLSCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+}
+
+// HardeningLevel returns the CIS level of the hardening profile, 0 if there is no profile.
+func HardeningLevel(profile string) int {
+	switch profile {
+	case kops.HardeningProfileCISLevel1:
+		return 1
+	case kops.HardeningProfileCISLevel2:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// InstanceGroupHardening returns the hardening of the instance group, merged with the hardening of the cluster.
+// It returns nil if the instance group is not hardened.
+func InstanceGroupHardening(cluster *kops.Cluster, ig *kops.InstanceGroup) *kops.HardeningSpec {
+	hardening := &kops.HardeningSpec{}
+	for _, spec := range []*kops.HardeningSpec{cluster.Spec.Hardening, ig.Spec.Hardening} {
+		if spec == nil {
+			continue
+		}
+		if spec.Profile != "" {
+			hardening.Profile = spec.Profile
+		}
+		hardening.DisabledKernelModules = appendUnique(hardening.DisabledKernelModules, spec.DisabledKernelModules...)
+		hardening.AuditRules = appendUnique(hardening.AuditRules, spec.AuditRules...)
+	}
+	if hardening.Profile == "" && len(hardening.DisabledKernelModules) == 0 && len(hardening.AuditRules) == 0 {
+		return nil
+	}
+	return hardening
+}
+
+// ApplyHardeningKubeletDefaults sets the kubelet flags covered by the hardening profile, unless they are already set.
+func ApplyHardeningKubeletDefaults(profile string, kubelet *kops.KubeletConfigSpec) {
+	level := HardeningLevel(profile)
+	if level == 0 {
+		return
+	}
+
+	if kubelet.AnonymousAuth == nil {
+		anonymousAuth := false
+		kubelet.AnonymousAuth = &anonymousAuth
+	}
+	if kubelet.AuthorizationMode == "" {
+		kubelet.AuthorizationMode = "Webhook"
+	}
+	if kubelet.AuthenticationTokenWebhook == nil {
+		authenticationTokenWebhook := true
+		kubelet.AuthenticationTokenWebhook = &authenticationTokenWebhook
+	}
+	if kubelet.ReadOnlyPort == nil {
+		readOnlyPort := int32(0)
+		kubelet.ReadOnlyPort = &readOnlyPort
+	}
+	if kubelet.ProtectKernelDefaults == nil {
+		protectKernelDefaults := true
+		kubelet.ProtectKernelDefaults = &protectKernelDefaults
+	}
+
+	if level >= 2 {
+		if len(kubelet.TLSCipherSuites) == 0 {
+			kubelet.TLSCipherSuites = HardeningTLSCipherSuites
+		}
+		if kubelet.StreamingConnectionIdleTimeout == nil {
+			kubelet.StreamingConnectionIdleTimeout = &metav1.Duration{Duration: 5 * time.Minute}
+		}
+	}
+}
+
+// HardeningSysctls returns the kernel parameters of the hardening profile, as the lines of a sysctl.d file.
+func HardeningSysctls(level int) []string {
+	if level == 0 {
+		return nil
+	}
+
+	sysctls := []string{
+		"# Built by kops - do not edit",
+		"",
+		"# CIS hardening settings",
+		"kernel.randomize_va_space = 2",
+		"fs.suid_dumpable = 0",
+		"fs.protected_hardlinks = 1",
+		"fs.protected_symlinks = 1",
+		"",
+		"net.ipv4.conf.all.send_redirects = 0",
+		"net.ipv4.conf.default.send_redirects = 0",
+		"net.ipv4.conf.all.accept_source_route = 0",
+		"net.ipv4.conf.default.accept_source_route = 0",
+		"net.ipv4.conf.all.accept_redirects = 0",
+		"net.ipv4.conf.default.accept_redirects = 0",
+		"net.ipv4.conf.all.secure_redirects = 0",
+		"net.ipv4.conf.default.secure_redirects = 0",
+		"net.ipv4.conf.all.log_martians = 1",
+		"net.ipv4.conf.default.log_martians = 1",
+		"net.ipv4.icmp_echo_ignore_broadcasts = 1",
+		"net.ipv4.icmp_ignore_bogus_error_responses = 1",
+		"net.ipv4.tcp_syncookies = 1",
+		"net.ipv6.conf.all.accept_source_route = 0",
+		"net.ipv6.conf.default.accept_source_route = 0",
+		"net.ipv6.conf.all.accept_redirects = 0",
+		"net.ipv6.conf.default.accept_redirects = 0",
+		"",
+		"# Kernel parameters checked by the kubelet when protecting the kernel defaults",
+		"vm.panic_on_oom = 0",
+		"kernel.keys.root_maxkeys = 1000000",
+		"kernel.keys.root_maxbytes = 25000000",
+		"",
+	}
+
+	if level >= 2 {
+		sysctls = append(sysctls,
+			"# CIS level 2 hardening settings",
+			"kernel.dmesg_restrict = 1",
+			"kernel.kptr_restrict = 2",
+			"kernel.yama.ptrace_scope = 1",
+			"")
+	}
+
+	return sysctls
+}
+
+// HardeningKernelModules returns the kernel modules disabled by the hardening profile.
+func HardeningKernelModules(level int, cloudProvider kops.CloudProviderID) []string {
+	if level == 0 {
+		return nil
+	}
+
+	// Unused filesystems and network protocols
+	modules := []string{"cramfs", "freevxfs", "jffs2", "hfs", "hfsplus", "dccp", "rds", "tipc"}
+
+	if level >= 2 {
+		modules = append(modules, "usb-storage")
+		// Azure provisions the instances from a UDF formatted disk
+		if cloudProvider != kops.CloudProviderAzure {
+			modules = append(modules, "udf")
+		}
+	}
+
+	return modules
+}
+
+// HardeningAuditRules returns the auditd rules of the hardening profile.
+func HardeningAuditRules(level int) []string {
+	if level == 0 {
+		return nil
+	}
+
+	rules := []string{
+		"-w /etc/passwd -p wa -k identity",
+		"-w /etc/group -p wa -k identity",
+		"-w /etc/shadow -p wa -k identity",
+		"-w /etc/gshadow -p wa -k identity",
+		"-w /etc/sudoers -p wa -k scope",
+		"-w /etc/sudoers.d/ -p wa -k scope",
+		"-w /etc/sysctl.d/ -p wa -k sysctl",
+		"-w /etc/modprobe.d/ -p wa -k modules",
+		"-a always,exit -F arch=b64 -S init_module,finit_module,delete_module -k modules",
+		"-w /etc/kubernetes/ -p wa -k kubernetes",
+		"-w /var/lib/kubelet/kubeconfig -p wa -k kubelet",
+		"-w /var/lib/kubelet/kubelet.conf -p wa -k kubelet",
+		"-w /etc/containerd/ -p wa -k containerd",
+	}
+
+	if level >= 2 {
+		rules = append(rules,
+			"-a always,exit -F arch=b64 -S adjtimex,settimeofday,clock_settime -k time-change",
+			"-w /etc/localtime -p wa -k time-change",
+			"-a always,exit -F arch=b64 -S mount -F auid>=1000 -F auid!=unset -k mounts",
+		)
+	}
+
+	return rules
+}
+
+// HardeningSysctlConflict returns why the sysctl parameter contradicts the hardening profile,
+// or an empty string if it does not set a kernel parameter of the profile to another value.
+func HardeningSysctlConflict(level int, param string) string {
+	key, value, ok := parseSysctl(param)
+	if !ok {
+		return ""
+	}
+	if expected, found := parseSysctls(HardeningSysctls(level))[key]; found && expected != value {
+		return fmt.Sprintf("%s is set to %s, the hardening profile requires %s", key, value, expected)
+	}
+	return ""
+}
+
+// parseSysctls returns the kernel parameters set by the lines of a sysctl.d file; later lines take precedence.
+func parseSysctls(lines []string) map[string]string {
+	sysctls := make(map[string]string)
+	for _, line := range lines {
+		if key, value, ok := parseSysctl(line); ok {
+			sysctls[key] = value
+		}
+	}
+	return sysctls
+}
+
+// parseSysctl parses a "key = value" line of a sysctl.d file.
+func parseSysctl(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return "", "", false
+	}
+	key, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false
+	}
+	key = strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(key), "-"), "/", ".")
+	return key, strings.Join(strings.Fields(value), " "), true
+}
+
+// HardeningCheck is the result of checking a recommendation of a hardening profile.
+type HardeningCheck struct {
+	// ID identifies the recommendation in the CIS benchmark.
+	ID string `json:"id"`
+	// Description describes the recommendation.
+	Description string `json:"description"`
+	// Passed is true if the configuration complies with the recommendation.
+	Passed bool `json:"passed"`
+	// Detail explains why the configuration does not comply.
+	Detail string `json:"detail,omitempty"`
+}
+
+// CheckHardening checks the configuration of the instance group against the recommendations of the hardening profile.
+// The node and kubelet settings are checked as they are applied to the nodes: the settings of the hardening profile
+// of the instance group, merged with the sysctl parameters, kernel modules, audit rules and kubelet flags of the spec.
+func CheckHardening(cluster *kops.Cluster, ig *kops.InstanceGroup, profile string) []HardeningCheck {
+	level := HardeningLevel(profile)
+	if level == 0 {
+		return nil
+	}
+
+	hardening := InstanceGroupHardening(cluster, ig)
+	if hardening == nil {
+		hardening = &kops.HardeningSpec{}
+	}
+	applied := hardening.Profile
+	appliedLevel := HardeningLevel(applied)
+
+	var checks []HardeningCheck
+	add := func(id, description string, passed bool, detail string) {
+		check := HardeningCheck{ID: id, Description: description, Passed: passed}
+		if !passed {
+			check.Detail = detail
+		}
+		checks = append(checks, check)
+	}
+
+	notApplied := fmt.Sprintf("hardening profile %s is not applied", profile)
+	if applied != "" {
+		notApplied = fmt.Sprintf("hardening profile %s is applied instead of %s", applied, profile)
+	}
+	// The node settings are checked as nodeup merges them, so that user settings overriding the profile are reported
+	sysctls := parseSysctls(HardeningSysctls(appliedLevel))
+	for key, value := range parseSysctls(append(append([]string{}, ig.Spec.SysctlParameters...), cluster.Spec.SysctlParameters...)) {
+		sysctls[key] = value
+	}
+	var wrongSysctls []string
+	for key, expected := range parseSysctls(HardeningSysctls(level)) {
+		if sysctls[key] != expected {
+			wrongSysctls = append(wrongSysctls, key)
+		}
+	}
+	sort.Strings(wrongSysctls)
+	sysctlsDetail := notApplied
+	if appliedLevel >= level {
+		sysctlsDetail = "sysctlParameters override " + strings.Join(wrongSysctls, ", ")
+	}
+	add("os-sysctls", "Kernel network and memory parameters are hardened", len(wrongSysctls) == 0, sysctlsDetail)
+
+	cloudProvider := cluster.Spec.GetCloudProvider()
+	modules := append(HardeningKernelModules(appliedLevel, cloudProvider), hardening.DisabledKernelModules...)
+	missingModules := missing(HardeningKernelModules(level, cloudProvider), modules)
+	add("os-kernel-modules", "Unused filesystem and network kernel modules are disabled",
+		len(missingModules) == 0, "kernel modules are not disabled: "+strings.Join(missingModules, ", "))
+
+	rules := append(HardeningAuditRules(appliedLevel), hardening.AuditRules...)
+	missingRules := missing(HardeningAuditRules(level), rules)
+	add("os-auditd", "auditd records changes to identities, kernel modules and kubernetes files",
+		len(missingRules) == 0, fmt.Sprintf("%d audit rules are missing: %s", len(missingRules), strings.Join(missingRules, "; ")))
+
+	// The file permissions (4.1) are set by nodeup on the nodes, and cannot be checked from the configuration
+
+	kubelet := hardeningKubeletConfig(cluster, ig)
+	ApplyHardeningKubeletDefaults(applied, kubelet)
+	if !ig.IsEtcdOnly() {
+		add("4.2.1", "kubelet anonymous authentication is disabled",
+			kubelet.AnonymousAuth != nil && !*kubelet.AnonymousAuth, "anonymousAuth is not false")
+		// nodeup defaults the authorization mode to Webhook
+		add("4.2.2", "kubelet authorization mode is not AlwaysAllow",
+			!strings.Contains(kubelet.AuthorizationMode, "AlwaysAllow"), "authorizationMode is "+kubelet.AuthorizationMode)
+		add("4.2.4", "kubelet read-only port is disabled",
+			kubelet.ReadOnlyPort != nil && *kubelet.ReadOnlyPort == 0, "readOnlyPort is not 0")
+		add("4.2.5", "kubelet streaming connection idle timeout is not disabled",
+			kubelet.StreamingConnectionIdleTimeout == nil || kubelet.StreamingConnectionIdleTimeout.Duration != 0, "streamingConnectionIdleTimeout is 0")
+		// kops defaults protectKernelDefaults to true
+		add("4.2.6", "kubelet protects the kernel defaults",
+			kubelet.ProtectKernelDefaults == nil || *kubelet.ProtectKernelDefaults, "protectKernelDefaults is false")
+		if level >= 2 {
+			add("4.2.13", "kubelet only uses strong TLS cipher suites",
+				len(kubelet.TLSCipherSuites) != 0 && isSubset(kubelet.TLSCipherSuites, HardeningTLSCipherSuites), "tlsCipherSuites allows weak cipher suites")
+		}
+	}
+
+	// The profile of the cluster disables profiling, unless it is explicitly enabled
+	clusterLevel := 0
+	if cluster.Spec.Hardening != nil {
+		clusterLevel = HardeningLevel(cluster.Spec.Hardening.Profile)
+	}
+	profilingDisabled := func(enableProfiling *bool) bool {
+		if enableProfiling == nil {
+			return clusterLevel > 0
+		}
+		return !*enableProfiling
+	}
+
+	if ig.HasAPIServer() {
+		apiServer := cluster.Spec.KubeAPIServer
+		if apiServer == nil {
+			apiServer = &kops.KubeAPIServerConfig{}
+		}
+		add("1.2.18", "kube-apiserver profiling is disabled",
+			profilingDisabled(apiServer.EnableProfiling), "kubeAPIServer.enableProfiling is not false")
+		add("1.2.19", "kube-apiserver audit logging is enabled",
+			apiServer.AuditLogPath != nil && *apiServer.AuditLogPath != "", "kubeAPIServer.auditLogPath is not set")
+		add("1.2.29", "Secrets are encrypted at rest",
+			cluster.Spec.EncryptionConfig != nil && *cluster.Spec.EncryptionConfig, "encryptionConfig is not enabled")
+	}
+
+	if ig.IsControlPlane() {
+		kcm := cluster.Spec.KubeControllerManager
+		if kcm == nil {
+			kcm = &kops.KubeControllerManagerConfig{}
+		}
+		add("1.3.2", "kube-controller-manager profiling is disabled",
+			profilingDisabled(kcm.EnableProfiling), "kubeControllerManager.enableProfiling is not false")
+		scheduler := cluster.Spec.KubeScheduler
+		if scheduler == nil {
+			scheduler = &kops.KubeSchedulerConfig{}
+		}
+		add("1.4.1", "kube-scheduler profiling is disabled",
+			profilingDisabled(scheduler.EnableProfiling), "kubeScheduler.enableProfiling is not false")
+	}
+
+	return checks
+}
+
+// hardeningKubeletConfig returns the kubelet configuration of the instance group, merged with the configuration of the cluster.
+func hardeningKubeletConfig(cluster *kops.Cluster, ig *kops.InstanceGroup) *kops.KubeletConfigSpec {
+	kubelet := &kops.KubeletConfigSpec{}
+	if ig.IsControlPlane() {
+		if cluster.Spec.ControlPlaneKubelet != nil {
+			kubelet = cluster.Spec.ControlPlaneKubelet.DeepCopy()
+		}
+	} else if cluster.Spec.Kubelet != nil {
+		kubelet = cluster.Spec.Kubelet.DeepCopy()
+	}
+	if ig.Spec.Kubelet != nil {
+		reflectutils.JSONMergeStruct(kubelet, ig.Spec.Kubelet)
+	}
+	return kubelet
+}
+
+func appendUnique(l []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range l {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// missing returns the values of expected that are not in l.
+func missing(expected []string, l []string) []string {
+	var missing []string
+	for _, v := range expected {
+		if !isSubset([]string{v}, l) {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+func isSubset(l []string, allowed []string) bool {
+	for _, v := range l {
+		found := false
+		for _, a := range allowed {
+			if a == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
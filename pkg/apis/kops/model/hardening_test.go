@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func Test_InstanceGroupHardening(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Hardening: &kops.HardeningSpec{
+				Profile:               kops.HardeningProfileCISLevel1,
+				DisabledKernelModules: []string{"sctp"},
+			},
+		},
+	}
+
+	grid := []struct {
+		hardening *kops.HardeningSpec
+		expected  *kops.HardeningSpec
+	}{
+		{
+			hardening: nil,
+			expected: &kops.HardeningSpec{
+				Profile:               kops.HardeningProfileCISLevel1,
+				DisabledKernelModules: []string{"sctp"},
+			},
+		},
+		{
+			hardening: &kops.HardeningSpec{
+				Profile:               kops.HardeningProfileCISLevel2,
+				DisabledKernelModules: []string{"sctp", "bluetooth"},
+				AuditRules:            []string{"-w /etc/hosts -p wa -k hosts"},
+			},
+			expected: &kops.HardeningSpec{
+				Profile:               kops.HardeningProfileCISLevel2,
+				DisabledKernelModules: []string{"sctp", "bluetooth"},
+				AuditRules:            []string{"-w /etc/hosts -p wa -k hosts"},
+			},
+		},
+	}
+
+	for _, g := range grid {
+		ig := &kops.InstanceGroup{
+			Spec: kops.InstanceGroupSpec{
+				Role:      kops.InstanceGroupRoleNode,
+				Hardening: g.hardening,
+			},
+		}
+		actual := InstanceGroupHardening(cluster, ig)
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("unexpected hardening: expected %+v, got %+v", g.expected, actual)
+		}
+	}
+
+	if actual := InstanceGroupHardening(&kops.Cluster{}, &kops.InstanceGroup{}); actual != nil {
+		t.Errorf("expected no hardening, got %+v", actual)
+	}
+}
+
+func Test_CheckHardening(t *testing.T) {
+	anonymousAuth := true
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Hardening: &kops.HardeningSpec{
+				Profile: kops.HardeningProfileCISLevel1,
+			},
+			Kubelet: &kops.KubeletConfigSpec{
+				AnonymousAuth: &anonymousAuth,
+			},
+		},
+	}
+	ig := &kops.InstanceGroup{
+		Spec: kops.InstanceGroupSpec{
+			Role: kops.InstanceGroupRoleNode,
+		},
+	}
+
+	grid := []struct {
+		profile               string
+		sysctlParameters      []string
+		disabledKernelModules []string
+		failed                []string
+	}{
+		{
+			profile: kops.HardeningProfileCISLevel1,
+			failed:  []string{"4.2.1"},
+		},
+		{
+			profile: kops.HardeningProfileCISLevel2,
+			failed:  []string{"os-sysctls", "os-kernel-modules", "os-auditd", "4.2.1", "4.2.13"},
+		},
+		{
+			profile:          kops.HardeningProfileCISLevel1,
+			sysctlParameters: []string{"net.ipv4.conf.all.accept_redirects = 1"},
+			failed:           []string{"os-sysctls", "4.2.1"},
+		},
+		{
+			profile:          kops.HardeningProfileCISLevel1,
+			sysctlParameters: []string{"net.ipv4.conf.all.accept_redirects=0", "vm.max_map_count = 524288"},
+			failed:           []string{"4.2.1"},
+		},
+		{
+			profile:               kops.HardeningProfileCISLevel2,
+			sysctlParameters:      []string{"kernel.dmesg_restrict = 1", "kernel.kptr_restrict = 2", "kernel.yama.ptrace_scope = 1"},
+			disabledKernelModules: []string{"usb-storage", "udf"},
+			failed:                []string{"os-auditd", "4.2.1", "4.2.13"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := ig.DeepCopy()
+		ig.Spec.SysctlParameters = g.sysctlParameters
+		if g.disabledKernelModules != nil {
+			ig.Spec.Hardening = &kops.HardeningSpec{DisabledKernelModules: g.disabledKernelModules}
+		}
+		var failed []string
+		for _, check := range CheckHardening(cluster, ig, g.profile) {
+			if !check.Passed {
+				failed = append(failed, check.ID)
+			}
+		}
+		if !reflect.DeepEqual(failed, g.failed) {
+			t.Errorf("unexpected failed checks for %s: expected %v, got %v", g.profile, g.failed, failed)
+		}
+	}
+}
+
+func Test_HardeningSysctlConflict(t *testing.T) {
+	grid := []struct {
+		level    int
+		param    string
+		conflict bool
+	}{
+		{level: 1, param: "net.ipv4.conf.all.accept_redirects = 1", conflict: true},
+		{level: 1, param: "net/ipv4/conf/all/accept_redirects=1", conflict: true},
+		{level: 1, param: "net.ipv4.conf.all.accept_redirects = 0"},
+		{level: 1, param: "vm.max_map_count = 524288"},
+		{level: 1, param: "kernel.dmesg_restrict = 0"},
+		{level: 2, param: "kernel.dmesg_restrict = 0", conflict: true},
+		{level: 0, param: "net.ipv4.conf.all.accept_redirects = 1"},
+	}
+
+	for _, g := range grid {
+		if conflict := HardeningSysctlConflict(g.level, g.param); (conflict != "") != g.conflict {
+			t.Errorf("unexpected conflict of %q with level %d: %q", g.param, g.level, conflict)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"net"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/wellknownports"
+)
+
+// HostFirewallRules returns the rules of the host firewall of the instance group:
+// the rules allowing the traffic of the cluster components that run on its instances,
+// followed by the rules of the instance group.
+func HostFirewallRules(cluster *kops.Cluster, ig *kops.InstanceGroup) []kops.HostFirewallRule {
+	b := &hostFirewallRulesBuilder{}
+	clusterCIDRs := hostFirewallClusterCIDRs(cluster)
+
+	b.allowTCP("ssh", wellknownports.SSH, wellknownports.SSH, cluster.Spec.SSHAccess, clusterCIDRs)
+
+	if !ig.IsBastion() && !ig.IsEtcdOnly() {
+		b.allowTCP("kubelet", wellknownports.KubeletAPI, wellknownports.KubeletAPI, clusterCIDRs)
+		b.allowTCPAndUDP("dns", wellknownports.DNS, wellknownports.DNS, clusterCIDRs)
+
+		nodePortRange := hostFirewallNodePortRange(cluster)
+		b.allowTCPAndUDP("node-ports", nodePortRange.Base, nodePortRange.Base+nodePortRange.Size-1, cluster.Spec.NodePortAccess, clusterCIDRs)
+
+		b.addCNIRules(&cluster.Spec.Networking, clusterCIDRs)
+	}
+
+	if !ig.IsBastion() && cluster.IsGossip() {
+		for _, portRange := range wellknownports.DNSGossipPortRanges() {
+			b.allowTCPAndUDP("gossip", portRange.Min, portRange.Max, clusterCIDRs)
+		}
+	}
+
+	if ig.HasAPIServer() {
+		b.allowTCP("kube-apiserver", wellknownports.KubeAPIServer, wellknownports.KubeAPIServer, cluster.Spec.API.Access, clusterCIDRs)
+		b.allowTCP("kube-apiserver-healthcheck", wellknownports.KubeAPIServerHealthCheck, wellknownports.KubeAPIServerHealthCheck, clusterCIDRs)
+	}
+
+	if ig.IsControlPlane() {
+		b.allowTCP("kops-controller", wellknownports.KopsControllerPort, wellknownports.KopsControllerPort, clusterCIDRs)
+	}
+
+	if ig.IsControlPlane() || ig.IsEtcdOnly() {
+		b.addEtcdRules(cluster, clusterCIDRs)
+	}
+
+	if ig.Spec.HostFirewall != nil {
+		b.rules = append(b.rules, ig.Spec.HostFirewall.Rules...)
+	}
+
+	return b.rules
+}
+
+type hostFirewallRulesBuilder struct {
+	rules []kops.HostFirewallRule
+}
+
+// allow adds a rule allowing the traffic from the union of the CIDRs.
+// The rule is skipped when there are no CIDRs, because a rule without CIDRs allows any source.
+func (b *hostFirewallRulesBuilder) allow(name string, protocol string, port int, toPort int, cidrs ...[]string) {
+	rule := kops.HostFirewallRule{
+		Name:     name,
+		Protocol: protocol,
+		Port:     int32(port),
+	}
+	if toPort != port {
+		rule.ToPort = int32(toPort)
+	}
+
+	seen := make(map[string]bool)
+	for _, l := range cidrs {
+		for _, cidr := range l {
+			if !seen[cidr] {
+				seen[cidr] = true
+				rule.CIDRs = append(rule.CIDRs, cidr)
+			}
+		}
+	}
+	if len(rule.CIDRs) == 0 {
+		return
+	}
+
+	b.rules = append(b.rules, rule)
+}
+
+func (b *hostFirewallRulesBuilder) allowTCP(name string, port int, toPort int, cidrs ...[]string) {
+	b.allow(name, kops.HostFirewallProtocolTCP, port, toPort, cidrs...)
+}
+
+func (b *hostFirewallRulesBuilder) allowUDP(name string, port int, toPort int, cidrs ...[]string) {
+	b.allow(name, kops.HostFirewallProtocolUDP, port, toPort, cidrs...)
+}
+
+func (b *hostFirewallRulesBuilder) allowTCPAndUDP(name string, port int, toPort int, cidrs ...[]string) {
+	b.allowTCP(name, port, toPort, cidrs...)
+	b.allowUDP(name, port, toPort, cidrs...)
+}
+
+// addCNIRules allows the traffic between the instances that is needed by the CNI.
+func (b *hostFirewallRulesBuilder) addCNIRules(networking *kops.NetworkingSpec, clusterCIDRs []string) {
+	if networking.Cilium != nil {
+		b.allowUDP("cilium-vxlan", wellknownports.VxlanUDP, wellknownports.VxlanUDP, clusterCIDRs)
+		b.allowTCP("cilium-health", wellknownports.CiliumHealth, wellknownports.CiliumHealth, clusterCIDRs)
+		b.allowTCP("hubble-server", wellknownports.HubbleServer, wellknownports.HubbleServer, clusterCIDRs)
+	}
+
+	if networking.Calico != nil {
+		b.allowTCP("calico-bgp", wellknownports.BGP, wellknownports.BGP, clusterCIDRs)
+		b.allow("calico-ipip", kops.HostFirewallProtocolIPIP, 0, 0, clusterCIDRs)
+		b.allowUDP("calico-vxlan", wellknownports.VxlanIANAUDP, wellknownports.VxlanIANAUDP, clusterCIDRs)
+		b.allowTCP("calico-typha", wellknownports.CalicoTypha, wellknownports.CalicoTypha, clusterCIDRs)
+	}
+
+	if networking.Canal != nil {
+		b.allowUDP("flannel-vxlan", wellknownports.VxlanUDP, wellknownports.VxlanUDP, clusterCIDRs)
+		b.allowTCP("calico-typha", wellknownports.CalicoTypha, wellknownports.CalicoTypha, clusterCIDRs)
+	}
+
+	if networking.Flannel != nil {
+		switch networking.Flannel.Backend {
+		case "", "udp":
+			b.allowUDP("flannel-udp", wellknownports.FlannelUDP, wellknownports.FlannelUDP, clusterCIDRs)
+		case "vxlan":
+			b.allowUDP("flannel-vxlan", wellknownports.VxlanUDP, wellknownports.VxlanUDP, clusterCIDRs)
+		}
+	}
+
+	if networking.KubeRouter != nil {
+		b.allowTCP("kube-router-bgp", wellknownports.BGP, wellknownports.BGP, clusterCIDRs)
+		b.allow("kube-router-ipip", kops.HostFirewallProtocolIPIP, 0, 0, clusterCIDRs)
+	}
+
+	if networking.Kopeio != nil {
+		b.allowUDP("kopeio-vxlan", wellknownports.VxlanIANAUDP, wellknownports.VxlanIANAUDP, clusterCIDRs)
+	}
+
+	if networking.Weave != nil {
+		b.allowTCPAndUDP("weave", wellknownports.WeaveMesh, wellknownports.WeaveMesh, clusterCIDRs)
+		b.allowUDP("weave-fastdp", wellknownports.WeaveFastDatapath, wellknownports.WeaveFastDatapath, clusterCIDRs)
+	}
+}
+
+// addEtcdRules allows the traffic of the etcd clusters and of etcd-manager.
+func (b *hostFirewallRulesBuilder) addEtcdRules(cluster *kops.Cluster, clusterCIDRs []string) {
+	b.allowTCP("etcd-main", wellknownports.EtcdMainClientPort, wellknownports.EtcdMainClientPort, clusterCIDRs)
+	b.allowTCP("etcd-main-peer", wellknownports.EtcdMainPeerPort, wellknownports.EtcdMainPeerPort, clusterCIDRs)
+	b.allowTCP("etcd-events", wellknownports.EtcdEventsClientPort, wellknownports.EtcdEventsClientPort, clusterCIDRs)
+	b.allowTCP("etcd-events-peer", wellknownports.EtcdEventsPeerPort, wellknownports.EtcdEventsPeerPort, clusterCIDRs)
+	for _, portRange := range wellknownports.ETCDPortRanges() {
+		b.allowTCP("etcd-manager", portRange.Min, portRange.Max, clusterCIDRs)
+	}
+
+	if UseCiliumEtcd(cluster) {
+		b.allowTCP("etcd-cilium", wellknownports.EtcdCiliumClientPort, wellknownports.EtcdCiliumClientPort, clusterCIDRs)
+		b.allowTCP("etcd-cilium-peer", wellknownports.EtcdCiliumPeerPort, wellknownports.EtcdCiliumPeerPort, clusterCIDRs)
+		b.allowTCP("etcd-manager-cilium", wellknownports.EtcdCiliumGRPC, wellknownports.EtcdCiliumQuarantinedClientPort, clusterCIDRs)
+	}
+
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		index := ExtraEtcdClusterPortIndex(&cluster.Spec, etcdCluster.Name)
+		if index < 0 {
+			continue
+		}
+		name := "etcd-" + etcdCluster.Name
+		b.allowTCP(name, wellknownports.EtcdExtraClientPort+index, wellknownports.EtcdExtraClientPort+index, clusterCIDRs)
+		b.allowTCP(name+"-peer", wellknownports.EtcdExtraPeerPort+index, wellknownports.EtcdExtraPeerPort+index, clusterCIDRs)
+		b.allowTCP("etcd-manager-"+etcdCluster.Name, wellknownports.EtcdExtraGRPC+index, wellknownports.EtcdExtraGRPC+index, clusterCIDRs)
+		b.allowTCP("etcd-manager-"+etcdCluster.Name, wellknownports.EtcdExtraQuarantinedClientPort+index, wellknownports.EtcdExtraQuarantinedClientPort+index, clusterCIDRs)
+	}
+}
+
+// hostFirewallClusterCIDRs returns the CIDRs of the instances and pods of the cluster.
+func hostFirewallClusterCIDRs(cluster *kops.Cluster) []string {
+	var cidrs []string
+	seen := make(map[string]bool)
+	add := func(cidr string) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil || seen[cidr] {
+			return
+		}
+		seen[cidr] = true
+		cidrs = append(cidrs, cidr)
+	}
+
+	networking := &cluster.Spec.Networking
+	add(networking.NetworkCIDR)
+	for _, cidr := range networking.AdditionalNetworkCIDRs {
+		add(cidr)
+	}
+	for _, subnet := range networking.Subnets {
+		add(subnet.CIDR)
+		add(subnet.IPv6CIDR)
+	}
+	add(networking.PodCIDR)
+
+	return cidrs
+}
+
+// hostFirewallNodePortRange returns the range of ports allocated to NodePorts.
+func hostFirewallNodePortRange(cluster *kops.Cluster) utilnet.PortRange {
+	nodePortRange := utilnet.PortRange{Base: 30000, Size: 2768}
+	if cluster.Spec.KubeAPIServer != nil && cluster.Spec.KubeAPIServer.ServiceNodePortRange != "" {
+		// The range is checked by validation.
+		_ = nodePortRange.Set(cluster.Spec.KubeAPIServer.ServiceNodePortRange)
+	}
+	return nodePortRange
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func Test_HostFirewallRules(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			API: kops.APISpec{
+				Access: []string{"0.0.0.0/0"},
+			},
+			SSHAccess: []string{"203.0.113.0/24"},
+			EtcdClusters: []kops.EtcdClusterSpec{
+				{Name: "main"},
+				{Name: "events"},
+			},
+			Networking: kops.NetworkingSpec{
+				NetworkCIDR: "172.20.0.0/16",
+				PodCIDR:     "100.96.0.0/11",
+				Subnets: []kops.ClusterSubnetSpec{
+					{Name: "a", CIDR: "172.20.32.0/19"},
+				},
+				Calico: &kops.CalicoNetworkingSpec{},
+			},
+		},
+	}
+
+	grid := []struct {
+		role     kops.InstanceGroupRole
+		expected []string
+	}{
+		{
+			role: kops.InstanceGroupRoleControlPlane,
+			expected: []string{
+				"ssh", "kubelet", "dns", "dns", "node-ports", "node-ports",
+				"calico-bgp", "calico-ipip", "calico-vxlan", "calico-typha",
+				"kube-apiserver", "kube-apiserver-healthcheck", "kops-controller",
+				"etcd-main", "etcd-main-peer", "etcd-events", "etcd-events-peer", "etcd-manager",
+				"custom",
+			},
+		},
+		{
+			role: kops.InstanceGroupRoleNode,
+			expected: []string{
+				"ssh", "kubelet", "dns", "dns", "node-ports", "node-ports",
+				"calico-bgp", "calico-ipip", "calico-vxlan", "calico-typha",
+				"custom",
+			},
+		},
+		{
+			role: kops.InstanceGroupRoleEtcd,
+			expected: []string{
+				"ssh",
+				"etcd-main", "etcd-main-peer", "etcd-events", "etcd-events-peer", "etcd-manager",
+				"custom",
+			},
+		},
+		{
+			role:     kops.InstanceGroupRoleBastion,
+			expected: []string{"ssh", "custom"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(string(g.role), func(t *testing.T) {
+			ig := &kops.InstanceGroup{
+				Spec: kops.InstanceGroupSpec{
+					Role: g.role,
+					HostFirewall: &kops.HostFirewallSpec{
+						Rules: []kops.HostFirewallRule{
+							{Name: "custom", Port: 9100},
+						},
+					},
+				},
+			}
+
+			rules := HostFirewallRules(cluster, ig)
+
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+			if !reflect.DeepEqual(names, g.expected) {
+				t.Errorf("unexpected rules, expected %v, got %v", g.expected, names)
+			}
+
+			ssh := rules[0]
+			expectedCIDRs := []string{"203.0.113.0/24", "172.20.0.0/16", "172.20.32.0/19", "100.96.0.0/11"}
+			if !reflect.DeepEqual(ssh.CIDRs, expectedCIDRs) {
+				t.Errorf("unexpected CIDRs of the ssh rule, expected %v, got %v", expectedCIDRs, ssh.CIDRs)
+			}
+		})
+	}
+}
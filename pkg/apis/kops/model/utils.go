@@ -48,3 +48,118 @@ func FindZonesForInstanceGroup(c *kops.Cluster, ig *kops.InstanceGroup) ([]strin
 	}
 	return zones.List(), nil
 }
+
+// UsesEtcdNodes returns true if the members of the etcd cluster run on instance groups with the Etcd role,
+// rather than on the control plane.
+func UsesEtcdNodes(etcdCluster *kops.EtcdClusterSpec, instanceGroups []*kops.InstanceGroup) bool {
+	for _, member := range etcdCluster.Members {
+		for _, ig := range instanceGroups {
+			if member.InstanceGroup != nil && ig.ObjectMeta.Name == *member.InstanceGroup {
+				return ig.IsEtcdOnly()
+			}
+		}
+	}
+	return false
+}
+
+// FindEtcdNodesClusters returns the names of the etcd clusters that run on instance groups with the Etcd role.
+func FindEtcdNodesClusters(c *kops.Cluster, instanceGroups []*kops.InstanceGroup) []string {
+	var names []string
+	for i := range c.Spec.EtcdClusters {
+		if UsesEtcdNodes(&c.Spec.EtcdClusters[i], instanceGroups) {
+			names = append(names, c.Spec.EtcdClusters[i].Name)
+		}
+	}
+	return names
+}
+
+// IsAPIServerEtcdCluster returns true if kube-apiserver stores resources in the etcd cluster.
+// These etcd clusters share the etcd-clients-ca, because kube-apiserver can only have a single etcd client certificate.
+func IsAPIServerEtcdCluster(etcdCluster kops.EtcdClusterSpec) bool {
+	return etcdCluster.Name != "cilium"
+}
+
+// IsExtraEtcdCluster returns true if the etcd cluster is an additional etcd cluster, beyond main, events and cilium.
+func IsExtraEtcdCluster(etcdCluster kops.EtcdClusterSpec) bool {
+	switch etcdCluster.Name {
+	case "main", "events", "cilium":
+		return false
+	default:
+		return true
+	}
+}
+
+// ExtraEtcdClusterPortIndex returns the index of the ports that an additional etcd cluster uses,
+// or -1 if there is no such additional etcd cluster.
+// Additional etcd clusters without a PortIndex get the index AssignExtraEtcdClusterPortIndexes would assign them.
+func ExtraEtcdClusterPortIndex(clusterSpec *kops.ClusterSpec, name string) int {
+	if index, found := extraEtcdClusterPortIndexes(clusterSpec)[name]; found {
+		return index
+	}
+	return -1
+}
+
+// AssignExtraEtcdClusterPortIndexes sets the PortIndex of the additional etcd clusters that don't have one yet,
+// using the lowest indexes that are not in use, in the order of the etcd clusters.
+func AssignExtraEtcdClusterPortIndexes(clusterSpec *kops.ClusterSpec) {
+	indexes := extraEtcdClusterPortIndexes(clusterSpec)
+	for i := range clusterSpec.EtcdClusters {
+		etcdCluster := &clusterSpec.EtcdClusters[i]
+		if IsExtraEtcdCluster(*etcdCluster) && etcdCluster.PortIndex == nil {
+			index := int32(indexes[etcdCluster.Name])
+			etcdCluster.PortIndex = &index
+		}
+	}
+}
+
+func extraEtcdClusterPortIndexes(clusterSpec *kops.ClusterSpec) map[string]int {
+	used := make(map[int]bool)
+	for _, etcdCluster := range clusterSpec.EtcdClusters {
+		if IsExtraEtcdCluster(etcdCluster) && etcdCluster.PortIndex != nil {
+			used[int(*etcdCluster.PortIndex)] = true
+		}
+	}
+
+	indexes := make(map[string]int)
+	next := 0
+	for _, etcdCluster := range clusterSpec.EtcdClusters {
+		if !IsExtraEtcdCluster(etcdCluster) {
+			continue
+		}
+		if etcdCluster.PortIndex != nil {
+			indexes[etcdCluster.Name] = int(*etcdCluster.PortIndex)
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		indexes[etcdCluster.Name] = next
+		used[next] = true
+	}
+	return indexes
+}
+
+// ContainerdRuntimes returns the additional containerd runtime handlers configured for the cluster
+// or for any of its instance groups, sorted by handler name.
+func ContainerdRuntimes(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) []kops.ContainerdRuntimeSpec {
+	var runtimes []kops.ContainerdRuntimeSpec
+	if cluster.Spec.Containerd != nil {
+		runtimes = append(runtimes, cluster.Spec.Containerd.Runtimes...)
+	}
+	for _, ig := range instanceGroups {
+		if ig.Spec.Containerd != nil {
+			runtimes = append(runtimes, ig.Spec.Containerd.Runtimes...)
+		}
+	}
+
+	byName := make(map[string]kops.ContainerdRuntimeSpec)
+	for _, runtime := range runtimes {
+		byName[runtime.HandlerName()] = runtime
+	}
+
+	var result []kops.ContainerdRuntimeSpec
+	for _, name := range sets.StringKeySet(byName).List() {
+		result = append(result, byName[name])
+	}
+	return result
+}
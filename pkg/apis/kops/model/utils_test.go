@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 )
 
@@ -173,3 +174,86 @@ func Test_FindZonesForInstanceGroup(t *testing.T) {
 		}
 	}
 }
+
+// Test_FindEtcdNodesClusters tests FindEtcdNodesClusters
+func Test_FindEtcdNodesClusters(t *testing.T) {
+	etcdIG := "etcd-a"
+	masterIG := "master-a"
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			EtcdClusters: []kops.EtcdClusterSpec{
+				{
+					Name: "main",
+					Members: []kops.EtcdMemberSpec{
+						{Name: "a", InstanceGroup: &etcdIG},
+					},
+				},
+				{
+					Name: "events",
+					Members: []kops.EtcdMemberSpec{
+						{Name: "a", InstanceGroup: &masterIG},
+					},
+				},
+			},
+		},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-a"},
+			Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleControlPlane},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-a"},
+			Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleEtcd},
+		},
+	}
+
+	actual := FindEtcdNodesClusters(cluster, instanceGroups)
+	expected := []string{"main"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected etcd nodes clusters: expected %v, got %v", expected, actual)
+	}
+
+	if actual := FindEtcdNodesClusters(cluster, instanceGroups[:1]); len(actual) != 0 {
+		t.Errorf("unexpected etcd nodes clusters without etcd instance groups: %v", actual)
+	}
+}
+
+func Test_ExtraEtcdClusterPortIndex(t *testing.T) {
+	portIndex := int32(0)
+	clusterSpec := &kops.ClusterSpec{
+		EtcdClusters: []kops.EtcdClusterSpec{
+			{Name: "main"},
+			{Name: "leases"},
+			{Name: "nodes", PortIndex: &portIndex},
+			{Name: "events"},
+			{Name: "pods"},
+		},
+	}
+
+	expected := map[string]int{"main": -1, "events": -1, "leases": 1, "nodes": 0, "pods": 2, "missing": -1}
+	for name, index := range expected {
+		if actual := ExtraEtcdClusterPortIndex(clusterSpec, name); actual != index {
+			t.Errorf("unexpected port index for %q: expected %d, got %d", name, index, actual)
+		}
+	}
+
+	AssignExtraEtcdClusterPortIndexes(clusterSpec)
+	for _, etcdCluster := range clusterSpec.EtcdClusters {
+		if !IsExtraEtcdCluster(etcdCluster) {
+			if etcdCluster.PortIndex != nil {
+				t.Errorf("unexpected port index assigned to %q", etcdCluster.Name)
+			}
+			continue
+		}
+		if etcdCluster.PortIndex == nil || int(*etcdCluster.PortIndex) != expected[etcdCluster.Name] {
+			t.Errorf("unexpected port index assigned to %q: expected %d, got %v", etcdCluster.Name, expected[etcdCluster.Name], etcdCluster.PortIndex)
+		}
+	}
+
+	// Once assigned, the ports don't depend on the other etcd clusters
+	clusterSpec.EtcdClusters = append(clusterSpec.EtcdClusters[:1], clusterSpec.EtcdClusters[2:]...)
+	if actual := ExtraEtcdClusterPortIndex(clusterSpec, "pods"); actual != 2 {
+		t.Errorf("unexpected port index for %q after removing an etcd cluster: expected 2, got %d", "pods", actual)
+	}
+}
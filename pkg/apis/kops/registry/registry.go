@@ -31,6 +31,10 @@ const (
 	PathClusterCompleted = "cluster-completed.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathApplyLock is the path for the lease held while applying changes to the cluster.
+	PathApplyLock = "apply.lock"
+	// PathPolicies is the directory of the policies evaluated before applying changes to the cluster.
+	PathPolicies = "policies"
 )
 
 func ConfigBase(c *api.Cluster) (vfs.Path, error) {
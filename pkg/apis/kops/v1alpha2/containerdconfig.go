@@ -40,6 +40,8 @@ type ContainerdConfig struct {
 	NvidiaGPU *NvidiaGPUConfig `json:"nvidiaGPU,omitempty"`
 	// Runc configures the runc runtime.
 	Runc *Runc `json:"runc,omitempty"`
+	// Runtimes configures additional runtime handlers for sandboxed runtimes, such as gVisor or Kata Containers.
+	Runtimes []ContainerdRuntimeSpec `json:"runtimes,omitempty"`
 }
 
 type NvidiaGPUConfig struct {
@@ -66,3 +68,14 @@ type Runc struct {
 	// Packages overrides the URL and hash for the packages.
 	Packages *PackagesConfig `json:"packages,omitempty"`
 }
+
+// ContainerdRuntimeSpec configures an additional runtime handler of containerd.
+type ContainerdRuntimeSpec struct {
+	// Name is the name of the runtime handler and of its RuntimeClass.
+	// Default is the type of the runtime.
+	Name string `json:"name,omitempty"`
+	// Type is the sandboxed runtime used by the handler: gvisor or kata.
+	Type string `json:"type,omitempty"`
+	// Packages sets the URL and hash of the .tar.gz archive with the binaries of the runtime.
+	Packages *PackagesConfig `json:"packages,omitempty"`
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+// HostFirewallSpec configures the nftables host firewall of the instances.
+type HostFirewallSpec struct {
+	// Enabled turns on the host firewall.
+	// The traffic needed by the cluster components running on the instances is allowed by default.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Rules allow additional incoming traffic.
+	Rules []HostFirewallRule `json:"rules,omitempty"`
+}
+
+// HostFirewallRule allows incoming traffic through the host firewall.
+type HostFirewallRule struct {
+	// Name describes the rule.
+	Name string `json:"name,omitempty"`
+	// Protocol is the protocol of the traffic: tcp, udp or ipip. Default is tcp.
+	Protocol string `json:"protocol,omitempty"`
+	// Port is the destination port of the traffic.
+	Port int32 `json:"port,omitempty"`
+	// ToPort is the last destination port of a range of ports. Default is Port.
+	ToPort int32 `json:"toPort,omitempty"`
+	// CIDRs are the source CIDRs of the traffic. Default is any source.
+	CIDRs []string `json:"cidrs,omitempty"`
+}
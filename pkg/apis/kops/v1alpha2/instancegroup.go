@@ -184,6 +184,16 @@ type InstanceGroupSpec struct {
 	//   'STANDARD': (default) standard provisioning with user controlled run time, no discounts
 	//   'SPOT': heavily discounted, no guaranteed run time.
 	GCPProvisioningModel *string `json:"gcpProvisioningModel,omitempty"`
+	// HostFirewall configures an nftables firewall on the instances, which drops the incoming traffic that is not allowed.
+	HostFirewall *HostFirewallSpec `json:"hostFirewall,omitempty"`
+	// Hardening configures the security hardening of the instances, overriding the profile of the cluster.
+	// The kernel modules and audit rules are added to those of the cluster.
+	Hardening *HardeningSpec `json:"hardening,omitempty"`
+	// PrePullImages are container images pulled by nodeup before the kubelet starts, and by warm pool instances while warming.
+	// Images must be pinned by digest, and are remapped to the container registry of the assets.
+	PrePullImages []string `json:"prePullImages,omitempty"`
+	// Swap configures a swap device on the instances, and the kubelet to allow pods to use it.
+	Swap *SwapSpec `json:"swap,omitempty"`
 }
 
 // InstanceMetadataOptions defines the EC2 instance metadata service options (AWS Only)
@@ -267,6 +277,11 @@ type VolumeSpec struct {
 type VolumeMountSpec struct {
 	// Device is the device name to provision and mount
 	Device string `json:"device,omitempty"`
+	// Devices are the device names to assemble into a RAID0 array, which is provisioned and mounted instead of Device
+	Devices []string `json:"devices,omitempty"`
+	// Encrypted encrypts the device with LUKS, using a random key generated on every boot.
+	// The data on the device does not survive a reboot.
+	Encrypted *bool `json:"encrypted,omitempty"`
 	// Filesystem is the filesystem to mount
 	Filesystem string `json:"filesystem,omitempty"`
 	// FormatOptions is a collection of options passed when formatting the device
@@ -277,6 +292,14 @@ type VolumeMountSpec struct {
 	Path string `json:"path,omitempty"`
 }
 
+// SwapSpec configures swap on the instances of an instance group
+type SwapSpec struct {
+	// Type is the type of swap device: file (default) or zram.
+	Type string `json:"type,omitempty"`
+	// Size is the size of the swap file or zram device.
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
 // IAMProfileSpec is the AWS IAM Profile to attach to instances in this instance
 // group. Specify the ARN for the IAM instance profile (AWS only).
 type IAMProfileSpec struct {
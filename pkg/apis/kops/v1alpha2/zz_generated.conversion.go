@@ -294,6 +294,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdRuntimeSpec)(nil), (*kops.ContainerdRuntimeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec(a.(*ContainerdRuntimeSpec), b.(*kops.ContainerdRuntimeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdRuntimeSpec)(nil), (*ContainerdRuntimeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec(a.(*kops.ContainerdRuntimeSpec), b.(*ContainerdRuntimeSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DCGMExporterConfig)(nil), (*kops.DCGMExporterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(a.(*DCGMExporterConfig), b.(*kops.DCGMExporterConfig), scope)
 	}); err != nil {
@@ -494,6 +504,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HardeningSpec)(nil), (*kops.HardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(a.(*HardeningSpec), b.(*kops.HardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HardeningSpec)(nil), (*HardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(a.(*kops.HardeningSpec), b.(*HardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HostFirewallRule)(nil), (*kops.HostFirewallRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule(a.(*HostFirewallRule), b.(*kops.HostFirewallRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HostFirewallRule)(nil), (*HostFirewallRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule(a.(*kops.HostFirewallRule), b.(*HostFirewallRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HostFirewallSpec)(nil), (*kops.HostFirewallSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec(a.(*HostFirewallSpec), b.(*kops.HostFirewallSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HostFirewallSpec)(nil), (*HostFirewallSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec(a.(*kops.HostFirewallSpec), b.(*HostFirewallSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HubbleSpec)(nil), (*kops.HubbleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HubbleSpec_To_kops_HubbleSpec(a.(*HubbleSpec), b.(*kops.HubbleSpec), scope)
 	}); err != nil {
@@ -524,6 +564,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageVerificationSpec)(nil), (*kops.ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(a.(*ImageVerificationSpec), b.(*kops.ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ImageVerificationSpec)(nil), (*ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(a.(*kops.ImageVerificationSpec), b.(*ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroup)(nil), (*kops.InstanceGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(a.(*InstanceGroup), b.(*kops.InstanceGroup), scope)
 	}); err != nil {
@@ -694,6 +744,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeletCredentialProviderSpec)(nil), (*kops.KubeletCredentialProviderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec(a.(*KubeletCredentialProviderSpec), b.(*kops.KubeletCredentialProviderSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KubeletCredentialProviderSpec)(nil), (*KubeletCredentialProviderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec(a.(*kops.KubeletCredentialProviderSpec), b.(*KubeletCredentialProviderSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubenetNetworkingSpec)(nil), (*kops.KubenetNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KubenetNetworkingSpec_To_kops_KubenetNetworkingSpec(a.(*KubenetNetworkingSpec), b.(*kops.KubenetNetworkingSpec), scope)
 	}); err != nil {
@@ -1094,6 +1154,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SwapSpec)(nil), (*kops.SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(a.(*SwapSpec), b.(*kops.SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SwapSpec)(nil), (*SwapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(a.(*kops.SwapSpec), b.(*SwapSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1528,7 +1598,17 @@ func Convert_kops_AmazonVPCNetworkingSpec_To_v1alpha2_AmazonVPCNetworkingSpec(in
 func autoConvert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kops.AssetsSpec, s conversion.Scope) error {
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
+	out.FileRepositoryMirrors = in.FileRepositoryMirrors
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(kops.ImageVerificationSpec)
+		if err := Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -1540,7 +1620,17 @@ func Convert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kops.As
 func autoConvert_kops_AssetsSpec_To_v1alpha2_AssetsSpec(in *kops.AssetsSpec, out *AssetsSpec, s conversion.Scope) error {
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
+	out.FileRepositoryMirrors = in.FileRepositoryMirrors
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerificationSpec)
+		if err := Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -2631,6 +2721,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(kops.HardeningSpec)
+		if err := Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(kops.RollingUpdate)
@@ -2968,6 +3067,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.UseHostCertificates = in.UseHostCertificates
 	out.SysctlParameters = in.SysctlParameters
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningSpec)
+		if err := Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
 	if in.RollingUpdate != nil {
 		in, out := &in.RollingUpdate, &out.RollingUpdate
 		*out = new(RollingUpdate)
@@ -3110,6 +3218,17 @@ func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *Containe
 	} else {
 		out.Runc = nil
 	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]kops.ContainerdRuntimeSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Runtimes = nil
+	}
 	return nil
 }
 
@@ -3154,6 +3273,17 @@ func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Con
 	} else {
 		out.Runc = nil
 	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]ContainerdRuntimeSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Runtimes = nil
+	}
 	return nil
 }
 
@@ -3162,6 +3292,46 @@ func Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.Contain
 	return autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec(in *ContainerdRuntimeSpec, out *kops.ContainerdRuntimeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Type = in.Type
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(kops.PackagesConfig)
+		if err := Convert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Packages = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec(in *ContainerdRuntimeSpec, out *kops.ContainerdRuntimeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdRuntimeSpec_To_kops_ContainerdRuntimeSpec(in, out, s)
+}
+
+func autoConvert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec(in *kops.ContainerdRuntimeSpec, out *ContainerdRuntimeSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Type = in.Type
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(PackagesConfig)
+		if err := Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Packages = nil
+	}
+	return nil
+}
+
+// Convert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec is an autogenerated conversion function.
+func Convert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec(in *kops.ContainerdRuntimeSpec, out *ContainerdRuntimeSpec, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdRuntimeSpec_To_v1alpha2_ContainerdRuntimeSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_DCGMExporterConfig_To_kops_DCGMExporterConfig(in *DCGMExporterConfig, out *kops.DCGMExporterConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	return nil
@@ -3484,6 +3654,8 @@ func autoConvert_v1alpha2_EtcdClusterSpec_To_kops_EtcdClusterSpec(in *EtcdCluste
 	} else {
 		out.Members = nil
 	}
+	out.Resources = in.Resources
+	out.PortIndex = in.PortIndex
 	// INFO: in.EnableEtcdTLS opted out of conversion generation
 	// INFO: in.EnableTLSAuth opted out of conversion generation
 	out.Version = in.Version
@@ -3532,6 +3704,8 @@ func autoConvert_kops_EtcdClusterSpec_To_v1alpha2_EtcdClusterSpec(in *kops.EtcdC
 	} else {
 		out.Members = nil
 	}
+	out.Resources = in.Resources
+	out.PortIndex = in.PortIndex
 	out.Version = in.Version
 	out.LeaderElectionTimeout = in.LeaderElectionTimeout
 	out.HeartbeatInterval = in.HeartbeatInterval
@@ -3880,6 +4054,30 @@ func Convert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in *HardeningSpec, out *kops.HardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.DisabledKernelModules = in.DisabledKernelModules
+	out.AuditRules = in.AuditRules
+	return nil
+}
+
+// Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec is an autogenerated conversion function.
+func Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in *HardeningSpec, out *kops.HardeningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(in, out, s)
+}
+
+func autoConvert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in *kops.HardeningSpec, out *HardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.DisabledKernelModules = in.DisabledKernelModules
+	out.AuditRules = in.AuditRules
+	return nil
+}
+
+// Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec is an autogenerated conversion function.
+func Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in *kops.HardeningSpec, out *HardeningSpec, s conversion.Scope) error {
+	return autoConvert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.HookSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Enabled = in.Enabled
@@ -3936,6 +4134,76 @@ func autoConvert_kops_HookSpec_To_v1alpha2_HookSpec(in *kops.HookSpec, out *Hook
 	return nil
 }
 
+func autoConvert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule(in *HostFirewallRule, out *kops.HostFirewallRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	out.ToPort = in.ToPort
+	out.CIDRs = in.CIDRs
+	return nil
+}
+
+// Convert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule is an autogenerated conversion function.
+func Convert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule(in *HostFirewallRule, out *kops.HostFirewallRule, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule(in, out, s)
+}
+
+func autoConvert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec(in *HostFirewallSpec, out *kops.HostFirewallSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]kops.HostFirewallRule, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_HostFirewallRule_To_kops_HostFirewallRule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Rules = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec is an autogenerated conversion function.
+func Convert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec(in *HostFirewallSpec, out *kops.HostFirewallSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec(in, out, s)
+}
+
+func autoConvert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule(in *kops.HostFirewallRule, out *HostFirewallRule, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	out.ToPort = in.ToPort
+	out.CIDRs = in.CIDRs
+	return nil
+}
+
+// Convert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule is an autogenerated conversion function.
+func Convert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule(in *kops.HostFirewallRule, out *HostFirewallRule, s conversion.Scope) error {
+	return autoConvert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule(in, out, s)
+}
+
+func autoConvert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec(in *kops.HostFirewallSpec, out *HostFirewallSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HostFirewallRule, len(*in))
+		for i := range *in {
+			if err := Convert_kops_HostFirewallRule_To_v1alpha2_HostFirewallRule(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Rules = nil
+	}
+	return nil
+}
+
+// Convert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec is an autogenerated conversion function.
+func Convert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec(in *kops.HostFirewallSpec, out *HostFirewallSpec, s conversion.Scope) error {
+	return autoConvert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HubbleSpec_To_kops_HubbleSpec(in *HubbleSpec, out *kops.HubbleSpec, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Metrics = in.Metrics
@@ -4026,6 +4294,30 @@ func Convert_kops_IAMSpec_To_v1alpha2_IAMSpec(in *kops.IAMSpec, out *IAMSpec, s
 	return autoConvert_kops_IAMSpec_To_v1alpha2_IAMSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	out.PublicKeys = in.PublicKeys
+	out.Images = in.Images
+	out.Tarballs = in.Tarballs
+	return nil
+}
+
+// Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in, out, s)
+}
+
+func autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	out.PublicKeys = in.PublicKeys
+	out.Images = in.Images
+	out.Tarballs = in.Tarballs
+	return nil
+}
+
+// Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(in *InstanceGroup, out *kops.InstanceGroup, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4275,6 +4567,34 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	}
 	out.MaxInstanceLifetime = in.MaxInstanceLifetime
 	out.GCPProvisioningModel = in.GCPProvisioningModel
+	if in.HostFirewall != nil {
+		in, out := &in.HostFirewall, &out.HostFirewall
+		*out = new(kops.HostFirewallSpec)
+		if err := Convert_v1alpha2_HostFirewallSpec_To_kops_HostFirewallSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HostFirewall = nil
+	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(kops.HardeningSpec)
+		if err := Convert_v1alpha2_HardeningSpec_To_kops_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
+	out.PrePullImages = in.PrePullImages
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(kops.SwapSpec)
+		if err := Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
 	return nil
 }
 
@@ -4451,6 +4771,34 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	}
 	out.MaxInstanceLifetime = in.MaxInstanceLifetime
 	out.GCPProvisioningModel = in.GCPProvisioningModel
+	if in.HostFirewall != nil {
+		in, out := &in.HostFirewall, &out.HostFirewall
+		*out = new(HostFirewallSpec)
+		if err := Convert_kops_HostFirewallSpec_To_v1alpha2_HostFirewallSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HostFirewall = nil
+	}
+	if in.Hardening != nil {
+		in, out := &in.Hardening, &out.Hardening
+		*out = new(HardeningSpec)
+		if err := Convert_kops_HardeningSpec_To_v1alpha2_HardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Hardening = nil
+	}
+	out.PrePullImages = in.PrePullImages
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(SwapSpec)
+		if err := Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Swap = nil
+	}
 	return nil
 }
 
@@ -5379,6 +5727,18 @@ func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *Kubele
 	out.PodPidsLimit = in.PodPidsLimit
 	out.ShutdownGracePeriod = in.ShutdownGracePeriod
 	out.ShutdownGracePeriodCriticalPods = in.ShutdownGracePeriodCriticalPods
+	out.MemorySwapBehavior = in.MemorySwapBehavior
+	if in.CredentialProviders != nil {
+		in, out := &in.CredentialProviders, &out.CredentialProviders
+		*out = make([]kops.KubeletCredentialProviderSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.CredentialProviders = nil
+	}
 	return nil
 }
 
@@ -5478,6 +5838,18 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.K
 	out.PodPidsLimit = in.PodPidsLimit
 	out.ShutdownGracePeriod = in.ShutdownGracePeriod
 	out.ShutdownGracePeriodCriticalPods = in.ShutdownGracePeriodCriticalPods
+	out.MemorySwapBehavior = in.MemorySwapBehavior
+	if in.CredentialProviders != nil {
+		in, out := &in.CredentialProviders, &out.CredentialProviders
+		*out = make([]KubeletCredentialProviderSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.CredentialProviders = nil
+	}
 	return nil
 }
 
@@ -5486,6 +5858,52 @@ func Convert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.Kubel
 	return autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec(in *KubeletCredentialProviderSpec, out *kops.KubeletCredentialProviderSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.MatchImages = in.MatchImages
+	out.DefaultCacheDuration = in.DefaultCacheDuration
+	out.Args = in.Args
+	out.Env = in.Env
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(kops.PackagesConfig)
+		if err := Convert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Packages = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec is an autogenerated conversion function.
+func Convert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec(in *KubeletCredentialProviderSpec, out *kops.KubeletCredentialProviderSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KubeletCredentialProviderSpec_To_kops_KubeletCredentialProviderSpec(in, out, s)
+}
+
+func autoConvert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec(in *kops.KubeletCredentialProviderSpec, out *KubeletCredentialProviderSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.MatchImages = in.MatchImages
+	out.DefaultCacheDuration = in.DefaultCacheDuration
+	out.Args = in.Args
+	out.Env = in.Env
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(PackagesConfig)
+		if err := Convert_kops_PackagesConfig_To_v1alpha2_PackagesConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Packages = nil
+	}
+	return nil
+}
+
+// Convert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec is an autogenerated conversion function.
+func Convert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec(in *kops.KubeletCredentialProviderSpec, out *KubeletCredentialProviderSpec, s conversion.Scope) error {
+	return autoConvert_kops_KubeletCredentialProviderSpec_To_v1alpha2_KubeletCredentialProviderSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_KubenetNetworkingSpec_To_kops_KubenetNetworkingSpec(in *KubenetNetworkingSpec, out *kops.KubenetNetworkingSpec, s conversion.Scope) error {
 	return nil
 }
@@ -7078,6 +7496,28 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Size = in.Size
+	return nil
+}
+
+// Convert_v1alpha2_SwapSpec_To_kops_SwapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SwapSpec_To_kops_SwapSpec(in *SwapSpec, out *kops.SwapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SwapSpec_To_kops_SwapSpec(in, out, s)
+}
+
+func autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Size = in.Size
+	return nil
+}
+
+// Convert_kops_SwapSpec_To_v1alpha2_SwapSpec is an autogenerated conversion function.
+func Convert_kops_SwapSpec_To_v1alpha2_SwapSpec(in *kops.SwapSpec, out *SwapSpec, s conversion.Scope) error {
+	return autoConvert_kops_SwapSpec_To_v1alpha2_SwapSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -7195,6 +7635,8 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
+	out.Devices = in.Devices
+	out.Encrypted = in.Encrypted
 	out.Filesystem = in.Filesystem
 	out.FormatOptions = in.FormatOptions
 	out.MountOptions = in.MountOptions
@@ -7209,6 +7651,8 @@ func Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpe
 
 func autoConvert_kops_VolumeMountSpec_To_v1alpha2_VolumeMountSpec(in *kops.VolumeMountSpec, out *VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
+	out.Devices = in.Devices
+	out.Encrypted = in.Encrypted
 	out.Filesystem = in.Filesystem
 	out.FormatOptions = in.FormatOptions
 	out.MountOptions = in.MountOptions
@@ -0,0 +1,29 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+// HardeningSpec configures the security hardening of the instances.
+type HardeningSpec struct {
+	// Profile is the hardening profile applied to the instances: cis-level1 or cis-level2.
+	// The profile sets sysctls, restricts the permissions of the kubernetes files, installs auditd rules,
+	// disables unused kernel modules and sets the defaults of the kubelet flags covered by the CIS benchmarks.
+	Profile string `json:"profile,omitempty"`
+	// DisabledKernelModules are kernel modules that are prevented from loading, in addition to those of the profile.
+	DisabledKernelModules []string `json:"disabledKernelModules,omitempty"`
+	// AuditRules are auditd rules, in addition to those of the profile.
+	AuditRules []string `json:"auditRules,omitempty"`
+}
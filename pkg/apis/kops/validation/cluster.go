@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
@@ -44,6 +45,11 @@ func ValidateClusterUpdate(obj *kops.Cluster, status *kops.ClusterStatus, old *k
 
 			if oldCluster, ok := oldClusters[k]; ok {
 				allErrs = append(allErrs, validateEtcdClusterUpdate(fp, newCluster, status, oldCluster)...)
+
+				// Changing the ports of a running etcd cluster would break it
+				if model.ExtraEtcdClusterPortIndex(&obj.Spec, k) != model.ExtraEtcdClusterPortIndex(&old.Spec, k) {
+					allErrs = append(allErrs, field.Forbidden(fp.Child("portIndex"), "the ports of additional etcd clusters cannot be changed"))
+				}
 			}
 		}
 		for k := range oldClusters {
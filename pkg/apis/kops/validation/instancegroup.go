@@ -24,11 +24,14 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/google/go-containerregistry/pkg/name"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
@@ -58,6 +61,16 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 	case kops.InstanceGroupRoleNode:
 	case kops.InstanceGroupRoleBastion:
 	case kops.InstanceGroupRoleAPIServer:
+	case kops.InstanceGroupRoleEtcd:
+		if len(g.Spec.Subnets) == 0 {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "subnets"), "etcd InstanceGroup must specify at least one Subnet"))
+		}
+		if fi.ValueOf(g.Spec.MinSize) > 1 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "minSize"), fi.ValueOf(g.Spec.MinSize), "etcd InstanceGroup must have minSize set to 1"))
+		}
+		if fi.ValueOf(g.Spec.MaxSize) > 1 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "maxSize"), fi.ValueOf(g.Spec.MaxSize), "etcd InstanceGroup must have maxSize set to 1, add more InstanceGroups instead"))
+		}
 	default:
 		var supported []string
 		for _, role := range kops.AllInstanceGroupRoles {
@@ -129,17 +142,31 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 	}
 
 	// @step: iterate and check the volume mount specs
+	usedDevices := make(map[string]bool)
+	usedPaths := make(map[string]bool)
 	for i, x := range g.Spec.VolumeMounts {
-		used := make(map[string]bool)
 		path := field.NewPath("spec", "volumeMounts").Index(i)
 
 		allErrs = append(allErrs, validateVolumeMountSpec(path, x)...)
-		if _, found := used[x.Device]; found {
-			allErrs = append(allErrs, field.Duplicate(path.Child("device"), x.Device))
+
+		// @check a device is used by a single volume mount or RAID array
+		if x.Device != "" {
+			if usedDevices[x.Device] {
+				allErrs = append(allErrs, field.Duplicate(path.Child("device"), x.Device))
+			}
+			usedDevices[x.Device] = true
+		} else {
+			for j, device := range x.Devices {
+				if usedDevices[device] {
+					allErrs = append(allErrs, field.Duplicate(path.Child("devices").Index(j), device))
+				}
+				usedDevices[device] = true
+			}
 		}
-		if _, found := used[x.Path]; found {
+		if usedPaths[x.Path] {
 			allErrs = append(allErrs, field.Duplicate(path.Child("path"), x.Path))
 		}
+		usedPaths[x.Path] = true
 	}
 
 	allErrs = append(allErrs, validateInstanceProfile(g.Spec.IAM, field.NewPath("spec", "iam"))...)
@@ -156,6 +183,20 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 		allErrs = append(allErrs, validateIGCloudLabels(g, field.NewPath("spec", "cloudLabels"))...)
 	}
 
+	if g.Spec.HostFirewall != nil {
+		allErrs = append(allErrs, validateHostFirewall(g.Spec.HostFirewall, field.NewPath("spec", "hostFirewall"))...)
+	}
+
+	if g.Spec.Hardening != nil {
+		allErrs = append(allErrs, validateHardening(g.Spec.Hardening, field.NewPath("spec", "hardening"))...)
+	}
+
+	allErrs = append(allErrs, validatePrePullImages(g.Spec.PrePullImages, field.NewPath("spec", "prePullImages"))...)
+
+	if g.Spec.Swap != nil {
+		allErrs = append(allErrs, validateSwap(g.Spec.Swap, field.NewPath("spec", "swap"))...)
+	}
+
 	if cloud != nil {
 		switch cloud.ProviderID() {
 		case kops.CloudProviderAWS:
@@ -208,8 +249,13 @@ func validateVolumeSpec(path *field.Path, v kops.VolumeSpec) field.ErrorList {
 func validateVolumeMountSpec(path *field.Path, spec kops.VolumeMountSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if spec.Device == "" {
+	switch {
+	case spec.Device == "" && len(spec.Devices) == 0:
 		allErrs = append(allErrs, field.Required(path.Child("device"), "device name required"))
+	case spec.Device != "" && len(spec.Devices) != 0:
+		allErrs = append(allErrs, field.Forbidden(path.Child("devices"), "devices cannot be used together with device"))
+	case len(spec.Devices) == 1:
+		allErrs = append(allErrs, field.Invalid(path.Child("devices"), spec.Devices, "at least two devices are required for a RAID0 array"))
 	}
 	if spec.Filesystem == "" {
 		allErrs = append(allErrs, field.Required(path.Child("filesystem"), "filesystem type required"))
@@ -240,6 +286,29 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		}
 	}
 
+	if g.Spec.Role == kops.InstanceGroupRoleEtcd {
+		if !featureflag.EtcdNodes.Enabled() {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "Etcd role requires the EtcdNodes feature flag"))
+		}
+		if cluster.Spec.GetCloudProvider() != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "Etcd role only supported on AWS"))
+		}
+		hasEtcd := false
+		for _, etcd := range cluster.Spec.EtcdClusters {
+			for _, m := range etcd.Members {
+				if fi.ValueOf(m.InstanceGroup) == g.ObjectMeta.Name {
+					hasEtcd = true
+					if etcd.Name == "cilium" {
+						allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "role"), "the cilium etcd cluster cannot run on InstanceGroups with role Etcd"))
+					}
+				}
+			}
+		}
+		if !hasEtcd {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "metadata", "name"), fmt.Sprintf("InstanceGroup %q with role Etcd must have a member in an etcd cluster", g.ObjectMeta.Name)))
+		}
+	}
+
 	// Check that instance groups are defined in subnets that are defined in the cluster
 	{
 		clusterSubnets := make(map[string]*kops.ClusterSubnetSpec)
@@ -288,11 +357,42 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		allErrs = append(allErrs, validateContainerdConfig(&cluster.Spec, g.Spec.Containerd, field.NewPath("spec", "containerd"), false)...)
 	}
 
+	if hardening := model.InstanceGroupHardening(cluster, g); hardening != nil && hardening.Profile != "" {
+		allErrs = append(allErrs, validateHardeningSysctls(hardening.Profile, g.Spec.SysctlParameters, field.NewPath("spec", "sysctlParameters"))...)
+		// The sysctl parameters of the cluster are checked against the profile of the cluster when validating the cluster
+		if cluster.Spec.Hardening == nil || cluster.Spec.Hardening.Profile != hardening.Profile {
+			for _, err := range validateHardeningSysctls(hardening.Profile, cluster.Spec.SysctlParameters, field.NewPath("spec", "sysctlParameters")) {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "hardening", "profile"), "sysctlParameters of the cluster "+err.Detail))
+			}
+		}
+	}
+
+	if g.Spec.Kubelet != nil && g.Spec.Kubelet.CredentialProviders != nil {
+		allErrs = append(allErrs, validateKubeletCredentialProviders(g.Spec.Kubelet.CredentialProviders, cluster, field.NewPath("spec", "kubelet", "credentialProviders"))...)
+	}
+
+	if g.Spec.Kubelet != nil && g.Spec.Kubelet.MemorySwapBehavior != "" {
+		allErrs = append(allErrs, validateMemorySwapBehavior(g.Spec.Kubelet.MemorySwapBehavior, cluster, field.NewPath("spec", "kubelet", "memorySwapBehavior"))...)
+	}
+
+	if g.Spec.Swap != nil {
+		if cluster.IsKubernetesLT("1.28") {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "swap"), "swap requires Kubernetes 1.28 or later"))
+		}
+		if g.Spec.Kubelet != nil && fi.ValueOf(g.Spec.Kubelet.FailSwapOn) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "kubelet", "failSwapOn"), "failSwapOn cannot be true when swap is enabled"))
+		}
+	}
+
 	return allErrs
 }
 
 func ValidateControlPlaneInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if featureflag.EtcdNodes.Enabled() {
+		// Etcd clusters may run on InstanceGroups with role Etcd instead, which is checked by validateEtcdMemberInstanceGroups
+		return allErrs
+	}
 	for _, etcd := range cluster.Spec.EtcdClusters {
 		hasEtcd := false
 		for _, m := range etcd.Members {
@@ -308,6 +408,39 @@ func ValidateControlPlaneInstanceGroup(g *kops.InstanceGroup, cluster *kops.Clus
 	return allErrs
 }
 
+// validateEtcdMemberInstanceGroups checks that the InstanceGroup of every etcd member exists and has role ControlPlane or Etcd.
+// It replaces the check of ValidateControlPlaneInstanceGroup when the EtcdNodes feature flag is enabled.
+func validateEtcdMemberInstanceGroups(cluster *kops.Cluster, groups []*kops.InstanceGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	roles := make(map[string]kops.InstanceGroupRole)
+	for _, g := range groups {
+		roles[g.ObjectMeta.Name] = g.Spec.Role
+	}
+
+	fieldPath := field.NewPath("spec", "etcdClusters")
+	for i, etcd := range cluster.Spec.EtcdClusters {
+		for j, m := range etcd.Members {
+			name := fi.ValueOf(m.InstanceGroup)
+			if name == "" {
+				// Reported by validateEtcdMemberSpec
+				continue
+			}
+			memberPath := fieldPath.Index(i).Child("etcdMembers").Index(j).Child("instanceGroup")
+			role, found := roles[name]
+			switch {
+			case !found:
+				allErrs = append(allErrs, field.NotFound(memberPath, name))
+			case role == kops.InstanceGroupRoleControlPlane, role == kops.InstanceGroupRoleEtcd:
+			default:
+				allErrs = append(allErrs, field.Invalid(memberPath, name, fmt.Sprintf("etcd members must run on InstanceGroups with role ControlPlane or Etcd, not %s", role)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
 var validUserDataTypes = []string{
 	"text/x-include-once-url",
 	"text/x-include-url",
@@ -383,6 +516,43 @@ func validateIGCloudLabels(ig *kops.InstanceGroup, fldPath *field.Path) (allErrs
 	return allErrs
 }
 
+func validateHostFirewall(spec *kops.HostFirewallSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, rule := range spec.Rules {
+		rulePath := fldPath.Child("rules").Index(i)
+
+		if len(rule.Name) > 128 || strings.ContainsAny(rule.Name, "\"\\\n") {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("name"), rule.Name, "name must have at most 128 characters and must not contain quotes, backslashes or newlines"))
+		}
+
+		switch rule.Protocol {
+		case "", kops.HostFirewallProtocolTCP, kops.HostFirewallProtocolUDP:
+			if rule.Port < 1 || rule.Port > 65535 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("port"), rule.Port, "port must be between 1 and 65535"))
+			}
+			if rule.ToPort != 0 && (rule.ToPort < rule.Port || rule.ToPort > 65535) {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("toPort"), rule.ToPort, "toPort must be between port and 65535"))
+			}
+		case kops.HostFirewallProtocolIPIP:
+			if rule.Port != 0 {
+				allErrs = append(allErrs, field.Forbidden(rulePath.Child("port"), "port cannot be set for the ipip protocol"))
+			}
+			if rule.ToPort != 0 {
+				allErrs = append(allErrs, field.Forbidden(rulePath.Child("toPort"), "toPort cannot be set for the ipip protocol"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("protocol"), rule.Protocol, []string{kops.HostFirewallProtocolTCP, kops.HostFirewallProtocolUDP, kops.HostFirewallProtocolIPIP}))
+		}
+
+		for j, cidr := range rule.CIDRs {
+			allErrs = append(allErrs, validateCIDR(rulePath.Child("cidrs").Index(j), cidr)...)
+		}
+	}
+
+	return allErrs
+}
+
 func validateExternalLoadBalancer(lb *kops.LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -430,3 +600,37 @@ func validateExternalLoadBalancer(lb *kops.LoadBalancerSpec, fldPath *field.Path
 
 	return allErrs
 }
+
+// validatePrePullImages checks that the images to pre-pull are pinned by digest
+func validatePrePullImages(images []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	used := sets.NewString()
+	for i, image := range images {
+		if _, err := name.NewDigest(image); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), image, "must be an image reference pinned by digest, such as registry.example.com/image:tag@sha256:<digest>"))
+		}
+		if used.Has(image) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), image))
+		}
+		used.Insert(image)
+	}
+
+	return allErrs
+}
+
+func validateSwap(swap *kops.SwapSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if swap.Type != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("type"), &swap.Type, kops.SupportedSwapTypes)...)
+	}
+
+	if swap.Size == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("size"), "swap size must be specified"))
+	} else if swap.Size.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), swap.Size.String(), "swap size must be positive"))
+	}
+
+	return allErrs
+}
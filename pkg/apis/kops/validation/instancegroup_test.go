@@ -17,10 +17,12 @@ limitations under the License.
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
@@ -182,6 +184,39 @@ func TestValidMasterInstanceGroup(t *testing.T) {
 	}
 }
 
+func TestValidateEtcdMemberInstanceGroups(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			EtcdClusters: []kops.EtcdClusterSpec{
+				{
+					Name: "main",
+					Members: []kops.EtcdMemberSpec{
+						{Name: "a", InstanceGroup: fi.PtrTo("control-plane-a")},
+						{Name: "b", InstanceGroup: fi.PtrTo("etcd-b")},
+						{Name: "c", InstanceGroup: fi.PtrTo("nodes-c")},
+						{Name: "d", InstanceGroup: fi.PtrTo("missing-d")},
+					},
+				},
+			},
+		},
+	}
+	groups := []*kops.InstanceGroup{
+		{ObjectMeta: v1.ObjectMeta{Name: "control-plane-a"}, Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleControlPlane}},
+		{ObjectMeta: v1.ObjectMeta{Name: "etcd-b"}, Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleEtcd}},
+		{ObjectMeta: v1.ObjectMeta{Name: "nodes-c"}, Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}},
+	}
+
+	errs := validateEtcdMemberInstanceGroups(cluster, groups)
+	expected := []string{
+		"Invalid value::spec.etcdClusters[0].etcdMembers[2].instanceGroup",
+		"Not found::spec.etcdClusters[0].etcdMembers[3].instanceGroup",
+	}
+	testErrors(t, "etcd members", errs, expected)
+	if len(errs) != len(expected) {
+		t.Errorf("expected %d errors, got %v", len(expected), errs)
+	}
+}
+
 func TestValidBootDevice(t *testing.T) {
 	cluster := &kops.Cluster{
 		Spec: kops.ClusterSpec{
@@ -345,6 +380,443 @@ func TestValidTaints(t *testing.T) {
 	}
 }
 
+func TestValidVolumeMounts(t *testing.T) {
+	grid := []struct {
+		volumeMount kops.VolumeMountSpec
+		expected    []string
+	}{
+		{
+			volumeMount: kops.VolumeMountSpec{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/data"},
+		},
+		{
+			volumeMount: kops.VolumeMountSpec{Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, Encrypted: fi.PtrTo(true), Filesystem: "xfs", Path: "/var/lib/kubelet"},
+		},
+		{
+			volumeMount: kops.VolumeMountSpec{Filesystem: "ext4", Path: "/data"},
+			expected:    []string{"Required value::spec.volumeMounts[0].device"},
+		},
+		{
+			volumeMount: kops.VolumeMountSpec{Device: "/dev/nvme1n1", Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, Filesystem: "ext4", Path: "/data"},
+			expected:    []string{"Forbidden::spec.volumeMounts[0].devices"},
+		},
+		{
+			volumeMount: kops.VolumeMountSpec{Devices: []string{"/dev/nvme1n1"}, Filesystem: "ext4", Path: "/data"},
+			expected:    []string{"Invalid value::spec.volumeMounts[0].devices"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.VolumeMounts = []kops.VolumeMountSpec{g.volumeMount}
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.volumeMount, errs, g.expected)
+	}
+}
+
+func TestDuplicateVolumeMountDevices(t *testing.T) {
+	grid := []struct {
+		volumeMounts []kops.VolumeMountSpec
+		expected     []string
+	}{
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/data"},
+				{Devices: []string{"/dev/nvme2n1", "/dev/nvme3n1"}, Filesystem: "xfs", Path: "/var/lib/kubelet"},
+			},
+		},
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/data"},
+				{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/logs"},
+			},
+			expected: []string{"Duplicate value::spec.volumeMounts[1].device"},
+		},
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/data"},
+				{Devices: []string{"/dev/nvme2n1", "/dev/nvme1n1"}, Filesystem: "xfs", Path: "/var/lib/kubelet"},
+			},
+			expected: []string{"Duplicate value::spec.volumeMounts[1].devices[1]"},
+		},
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}, Filesystem: "xfs", Path: "/var/lib/kubelet"},
+				{Devices: []string{"/dev/nvme3n1", "/dev/nvme2n1"}, Filesystem: "xfs", Path: "/data"},
+			},
+			expected: []string{"Duplicate value::spec.volumeMounts[1].devices[1]"},
+		},
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Devices: []string{"/dev/nvme1n1", "/dev/nvme1n1"}, Filesystem: "xfs", Path: "/var/lib/kubelet"},
+			},
+			expected: []string{"Duplicate value::spec.volumeMounts[0].devices[1]"},
+		},
+		{
+			volumeMounts: []kops.VolumeMountSpec{
+				{Device: "/dev/nvme1n1", Filesystem: "ext4", Path: "/data"},
+				{Device: "/dev/nvme2n1", Filesystem: "ext4", Path: "/data"},
+			},
+			expected: []string{"Duplicate value::spec.volumeMounts[1].path"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.VolumeMounts = g.volumeMounts
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.volumeMounts, errs, g.expected)
+	}
+}
+
+func TestValidHostFirewall(t *testing.T) {
+	grid := []struct {
+		rules    []kops.HostFirewallRule
+		expected []string
+	}{
+		{
+			rules: []kops.HostFirewallRule{
+				{Name: "metrics", Port: 9100, CIDRs: []string{"10.0.0.0/8", "fd00::/8"}},
+				{Protocol: "udp", Port: 5000, ToPort: 5010},
+				{Protocol: "ipip", CIDRs: []string{"10.0.0.0/8"}},
+			},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Protocol: "sctp", Port: 9100},
+			},
+			expected: []string{"Unsupported value::spec.hostFirewall.rules[0].protocol"},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Name: "metrics"},
+			},
+			expected: []string{"Invalid value::spec.hostFirewall.rules[0].port"},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Port: 9100, ToPort: 9000},
+			},
+			expected: []string{"Invalid value::spec.hostFirewall.rules[0].toPort"},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Protocol: "ipip", Port: 4},
+			},
+			expected: []string{"Forbidden::spec.hostFirewall.rules[0].port"},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Name: `"metrics"`, Port: 9100},
+			},
+			expected: []string{"Invalid value::spec.hostFirewall.rules[0].name"},
+		},
+		{
+			rules: []kops.HostFirewallRule{
+				{Port: 9100, CIDRs: []string{"10.0.0.1"}},
+			},
+			expected: []string{"Invalid value::spec.hostFirewall.rules[0].cidrs[0]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.HostFirewall = &kops.HostFirewallSpec{
+			Enabled: fi.PtrTo(true),
+			Rules:   g.rules,
+		}
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.rules, errs, g.expected)
+	}
+}
+
+func TestValidHardening(t *testing.T) {
+	grid := []struct {
+		hardening kops.HardeningSpec
+		expected  []string
+	}{
+		{
+			hardening: kops.HardeningSpec{
+				Profile:               "cis-level1",
+				DisabledKernelModules: []string{"sctp"},
+				AuditRules:            []string{"-w /etc/hosts -p wa -k hosts"},
+			},
+		},
+		{
+			hardening: kops.HardeningSpec{
+				Profile: "cis-level3",
+			},
+			expected: []string{"Unsupported value::spec.hardening.profile"},
+		},
+		{
+			hardening: kops.HardeningSpec{
+				DisabledKernelModules: []string{"sctp dccp"},
+			},
+			expected: []string{"Invalid value::spec.hardening.disabledKernelModules[0]"},
+		},
+		{
+			hardening: kops.HardeningSpec{
+				AuditRules: []string{"w /etc/hosts"},
+			},
+			expected: []string{"Invalid value::spec.hardening.auditRules[0]"},
+		},
+		{
+			hardening: kops.HardeningSpec{
+				AuditRules: []string{"-e 0"},
+			},
+			expected: []string{"Forbidden::spec.hardening.auditRules[0]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.Hardening = &g.hardening
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.hardening, errs, g.expected)
+	}
+}
+
+func TestHardeningSysctlParameters(t *testing.T) {
+	grid := []struct {
+		description     string
+		clusterProfile  string
+		clusterSysctls  []string
+		profile         string
+		sysctls         []string
+		expected        []string
+		expectedCluster []string
+	}{
+		{
+			description:    "no conflict",
+			clusterProfile: "cis-level1",
+			clusterSysctls: []string{"net.ipv4.conf.all.accept_redirects = 0"},
+			sysctls:        []string{"vm.max_map_count = 524288"},
+		},
+		{
+			description:     "cluster sysctl contradicts the cluster profile",
+			clusterProfile:  "cis-level1",
+			clusterSysctls:  []string{"net.ipv4.conf.all.accept_redirects = 1"},
+			expectedCluster: []string{"Forbidden::spec.sysctlParameters[0]"},
+		},
+		{
+			description:    "instance group sysctl contradicts the cluster profile",
+			clusterProfile: "cis-level1",
+			sysctls:        []string{"vm.max_map_count = 524288", "fs.suid_dumpable = 2"},
+			expected:       []string{"Forbidden::spec.sysctlParameters[1]"},
+		},
+		{
+			description:    "cluster sysctl contradicts the instance group profile",
+			clusterSysctls: []string{"kernel.dmesg_restrict = 0"},
+			profile:        "cis-level2",
+			expected:       []string{"Forbidden::spec.hardening.profile"},
+		},
+		{
+			description:    "sysctl of a higher level without the profile",
+			clusterProfile: "cis-level1",
+			sysctls:        []string{"kernel.dmesg_restrict = 0"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						AWS: &kops.AWSSpec{},
+					},
+					SysctlParameters: g.clusterSysctls,
+				},
+			}
+			if g.clusterProfile != "" {
+				cluster.Spec.Hardening = &kops.HardeningSpec{Profile: g.clusterProfile}
+				errs := validateClusterSpec(&cluster.Spec, cluster, field.NewPath("spec"), true)
+				var hardeningErrs field.ErrorList
+				for _, err := range errs {
+					if strings.HasPrefix(err.Field, "spec.sysctlParameters") {
+						hardeningErrs = append(hardeningErrs, err)
+					}
+				}
+				testErrors(t, g.clusterSysctls, hardeningErrs, g.expectedCluster)
+			}
+
+			ig := createMinimalInstanceGroup()
+			ig.Spec.SysctlParameters = g.sysctls
+			if g.profile != "" {
+				ig.Spec.Hardening = &kops.HardeningSpec{Profile: g.profile}
+			}
+			errs := CrossValidateInstanceGroup(ig, cluster, nil, true)
+			testErrors(t, g.sysctls, errs, g.expected)
+		})
+	}
+}
+
+func TestValidPrePullImages(t *testing.T) {
+	grid := []struct {
+		images   []string
+		expected []string
+	}{
+		{
+			images: []string{
+				"registry.example.com/app:v1@sha256:0000000000000000000000000000000000000000000000000000000000000001",
+				"registry.example.com/model@sha256:0000000000000000000000000000000000000000000000000000000000000001",
+			},
+		},
+		{
+			images:   []string{"registry.example.com/app:v1"},
+			expected: []string{"Invalid value::spec.prePullImages[0]"},
+		},
+		{
+			images: []string{
+				"registry.example.com/app@sha256:0000000000000000000000000000000000000000000000000000000000000001",
+				"registry.example.com/app@sha256:0000000000000000000000000000000000000000000000000000000000000001",
+			},
+			expected: []string{"Duplicate value::spec.prePullImages[1]"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.PrePullImages = g.images
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.images, errs, g.expected)
+	}
+}
+
+func TestValidSwap(t *testing.T) {
+	size := resource.MustParse("2Gi")
+	zero := resource.MustParse("0")
+	grid := []struct {
+		swap     kops.SwapSpec
+		expected []string
+	}{
+		{
+			swap: kops.SwapSpec{Size: &size},
+		},
+		{
+			swap: kops.SwapSpec{Type: "zram", Size: &size},
+		},
+		{
+			swap:     kops.SwapSpec{Type: "partition", Size: &size},
+			expected: []string{"Unsupported value::spec.swap.type"},
+		},
+		{
+			swap:     kops.SwapSpec{},
+			expected: []string{"Required value::spec.swap.size"},
+		},
+		{
+			swap:     kops.SwapSpec{Size: &zero},
+			expected: []string{"Invalid value::spec.swap.size"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+
+		ig.Spec.Swap = &g.swap
+		errs := ValidateInstanceGroup(ig, nil, true)
+		testErrors(t, g.swap, errs, g.expected)
+	}
+}
+
+func TestSwapKubernetesVersion(t *testing.T) {
+	size := resource.MustParse("2Gi")
+	grid := []struct {
+		kubernetesVersion string
+		failSwapOn        *bool
+		expected          []string
+	}{
+		{
+			kubernetesVersion: "1.28.0",
+		},
+		{
+			kubernetesVersion: "1.27.0",
+			expected:          []string{"Forbidden::spec.swap"},
+		},
+		{
+			kubernetesVersion: "1.28.0",
+			failSwapOn:        fi.PtrTo(true),
+			expected:          []string{"Forbidden::spec.kubelet.failSwapOn"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.kubernetesVersion,
+				CloudProvider: kops.CloudProviderSpec{
+					AWS: &kops.AWSSpec{},
+				},
+			},
+		}
+		ig := createMinimalInstanceGroup()
+		ig.Spec.Swap = &kops.SwapSpec{Size: &size}
+		if g.failSwapOn != nil {
+			ig.Spec.Kubelet = &kops.KubeletConfigSpec{FailSwapOn: g.failSwapOn}
+		}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil, true)
+		testErrors(t, g.kubernetesVersion, errs, g.expected)
+	}
+}
+
+func TestMemorySwapBehaviorKubernetesVersion(t *testing.T) {
+	grid := []struct {
+		kubernetesVersion string
+		behavior          string
+		expected          []string
+	}{
+		{
+			kubernetesVersion: "1.29.0",
+			behavior:          "LimitedSwap",
+		},
+		{
+			kubernetesVersion: "1.29.0",
+			behavior:          "UnlimitedSwap",
+		},
+		{
+			kubernetesVersion: "1.29.0",
+			behavior:          "NoSwap",
+			expected:          []string{"Unsupported value::spec.kubelet.memorySwapBehavior"},
+		},
+		{
+			kubernetesVersion: "1.30.0",
+			behavior:          "LimitedSwap",
+		},
+		{
+			kubernetesVersion: "1.30.0",
+			behavior:          "NoSwap",
+		},
+		{
+			kubernetesVersion: "1.30.0",
+			behavior:          "UnlimitedSwap",
+			expected:          []string{"Unsupported value::spec.kubelet.memorySwapBehavior"},
+		},
+		{
+			kubernetesVersion: "1.27.0",
+			behavior:          "LimitedSwap",
+			expected:          []string{"Forbidden::spec.kubelet.memorySwapBehavior"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.kubernetesVersion,
+				CloudProvider: kops.CloudProviderSpec{
+					AWS: &kops.AWSSpec{},
+				},
+			},
+		}
+		ig := createMinimalInstanceGroup()
+		ig.Spec.Kubelet = &kops.KubeletConfigSpec{MemorySwapBehavior: g.behavior}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil, true)
+		testErrors(t, g.kubernetesVersion+"/"+g.behavior, errs, g.expected)
+	}
+}
+
 func TestIGUpdatePolicy(t *testing.T) {
 	const unsupportedValueError = "Unsupported value::spec.updatePolicy"
 	for _, test := range []struct {
@@ -283,6 +283,12 @@ func DeepValidate(c *kops.Cluster, groups []*kops.InstanceGroup, strict bool, cl
 		return fmt.Errorf("must configure at least one Node InstanceGroup")
 	}
 
+	if featureflag.EtcdNodes.Enabled() {
+		if errs := validateEtcdMemberInstanceGroups(c, groups); len(errs) != 0 {
+			return errs.ToAggregate()
+		}
+	}
+
 	for _, g := range groups {
 		errs := CrossValidateInstanceGroup(g, c, cloud, strict)
 
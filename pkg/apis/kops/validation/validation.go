@@ -17,10 +17,14 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -38,11 +42,16 @@ import (
 	"k8s.io/kops/pkg/util/subnet"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/architectures"
 )
 
 func newValidateCluster(cluster *kops.Cluster, strict bool) field.ErrorList {
@@ -190,6 +199,7 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 			}
 			allErrs = append(allErrs, validateEtcdBackupStore(spec.EtcdClusters, fieldEtcdClusters)...)
 			allErrs = append(allErrs, validateEtcdStorage(spec.EtcdClusters, fieldEtcdClusters)...)
+			allErrs = append(allErrs, validateEtcdResources(spec.EtcdClusters, fieldEtcdClusters)...)
 		}
 	}
 
@@ -209,12 +219,23 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
 		}
+		if len(spec.Assets.FileRepositoryMirrors) > 0 {
+			allErrs = append(allErrs, validateFileRepositoryMirrors(spec.Assets, fieldPath.Child("assets"))...)
+		}
+		if spec.Assets.ImageVerification != nil {
+			allErrs = append(allErrs, validateImageVerification(spec.Assets.ImageVerification, fieldPath.Child("assets", "imageVerification"))...)
+		}
 	}
 
 	if spec.RollingUpdate != nil {
 		allErrs = append(allErrs, validateRollingUpdate(spec.RollingUpdate, fieldPath.Child("rollingUpdate"), false)...)
 	}
 
+	if spec.Hardening != nil {
+		allErrs = append(allErrs, validateHardening(spec.Hardening, fieldPath.Child("hardening"))...)
+		allErrs = append(allErrs, validateHardeningSysctls(spec.Hardening.Profile, spec.SysctlParameters, fieldPath.Child("sysctlParameters"))...)
+	}
+
 	if spec.API.LoadBalancer != nil {
 		lbSpec := spec.API.LoadBalancer
 		lbPath := fieldPath.Child("api", "loadBalancer")
@@ -911,10 +932,79 @@ func validateKubelet(k *kops.KubeletConfigSpec, c *kops.Cluster, kubeletPath *fi
 				allErrs = append(allErrs, field.Invalid(kubeletPath.Child("shutdownGracePeriodCriticalPods"), k.ShutdownGracePeriodCriticalPods.String(), "shutdownGracePeriodCriticalPods cannot be greater than shutdownGracePeriod"))
 			}
 		}
+
+		if k.CredentialProviders != nil {
+			allErrs = append(allErrs, validateKubeletCredentialProviders(k.CredentialProviders, c, kubeletPath.Child("credentialProviders"))...)
+		}
+
+		if k.MemorySwapBehavior != "" {
+			allErrs = append(allErrs, validateMemorySwapBehavior(k.MemorySwapBehavior, c, kubeletPath.Child("memorySwapBehavior"))...)
+		}
 	}
 	return allErrs
 }
 
+func validateMemorySwapBehavior(behavior string, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.IsKubernetesLT("1.28") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "memorySwapBehavior requires Kubernetes 1.28 or later"))
+	}
+	// Kubernetes 1.30 replaced UnlimitedSwap with NoSwap
+	if c.IsKubernetesLT("1.30") {
+		allErrs = append(allErrs, IsValidValue(fldPath, &behavior, []string{"LimitedSwap", "UnlimitedSwap"})...)
+	} else {
+		allErrs = append(allErrs, IsValidValue(fldPath, &behavior, []string{"NoSwap", "LimitedSwap"})...)
+	}
+
+	return allErrs
+}
+
+func validateKubeletCredentialProviders(providers []kops.KubeletCredentialProviderSpec, c *kops.Cluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(providers) > 0 && c.IsKubernetesLT("1.24") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "image credential providers require Kubernetes 1.24 or later"))
+	}
+
+	names := sets.NewString()
+	for i := range providers {
+		provider := &providers[i]
+		providerPath := fldPath.Index(i)
+
+		if provider.Name == "" {
+			allErrs = append(allErrs, field.Required(providerPath.Child("name"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(provider.Name) {
+				allErrs = append(allErrs, field.Invalid(providerPath.Child("name"), provider.Name, msg))
+			}
+			if names.Has(provider.Name) {
+				allErrs = append(allErrs, field.Duplicate(providerPath.Child("name"), provider.Name))
+			}
+			names.Insert(provider.Name)
+		}
+
+		if len(provider.MatchImages) == 0 && !model.IsKnownCredentialProvider(provider.Name) {
+			allErrs = append(allErrs, field.Required(providerPath.Child("matchImages"), "matchImages must be set for image credential providers unknown to kops"))
+		}
+		for j, image := range provider.MatchImages {
+			if image == "" {
+				allErrs = append(allErrs, field.Required(providerPath.Child("matchImages").Index(j), ""))
+			}
+		}
+
+		if kubernetesVersion, err := util.ParseKubernetesVersion(c.Spec.KubernetesVersion); err == nil {
+			for _, arch := range architectures.GetSupported() {
+				if _, _, err := model.CredentialProviderURL(provider, *kubernetesVersion, arch); err != nil {
+					allErrs = append(allErrs, field.Required(providerPath.Child("packages"), fmt.Sprintf("a download location must be set for image credential provider %q on %s", provider.Name, arch)))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
 func validateNetworking(cluster *kops.Cluster, v *kops.NetworkingSpec, fldPath *field.Path, strict bool, providerConstraints *cloudProviderConstraints) field.ErrorList {
 	c := &cluster.Spec
 	allErrs := field.ErrorList{}
@@ -1366,7 +1456,24 @@ func validateExternalPolicies(role string, policies []string, fldPath *field.Pat
 func validateEtcdClusterSpec(spec kops.EtcdClusterSpec, c *kops.Cluster, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	allErrs = append(allErrs, IsValidValue(fieldPath.Child("name"), &spec.Name, []string{"cilium", "main", "events"})...)
+	switch spec.Name {
+	case "main", "cilium":
+		if len(spec.Resources) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("resources"), fmt.Sprintf("resources cannot be set for the %q etcd cluster", spec.Name)))
+		}
+	case "events":
+	default:
+		// Additional etcd clusters, which store the resources they declare
+		for _, msg := range utilvalidation.IsDNS1123Label(spec.Name) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), spec.Name, msg))
+		}
+		if len(spec.Resources) == 0 {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("resources"), "additional etcd clusters must declare the resources they store"))
+		}
+	}
+	for i, resource := range spec.Resources {
+		allErrs = append(allErrs, validateEtcdResource(resource, fieldPath.Child("resources").Index(i))...)
+	}
 
 	if spec.Provider != "" {
 		allErrs = append(allErrs, IsValidValue(fieldPath.Child("provider"), &spec.Provider, []kops.EtcdProviderType{kops.EtcdProviderTypeManager})...)
@@ -1399,6 +1506,65 @@ func validateEtcdBackupStore(specs []kops.EtcdClusterSpec, fieldPath *field.Path
 	return allErrs
 }
 
+// validateEtcdResource checks that the resource is in the group/resource format used by kube-apiserver.
+func validateEtcdResource(resource string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	group, name, found := strings.Cut(resource, "/")
+	if !found {
+		return append(allErrs, field.Invalid(fieldPath, resource, "must be in the form group/resource, or /resource for a resource of the core group"))
+	}
+	if group != "" {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(group) {
+			allErrs = append(allErrs, field.Invalid(fieldPath, resource, msg))
+		}
+	}
+	for _, msg := range utilvalidation.IsDNS1123Label(name) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, resource, msg))
+	}
+
+	return allErrs
+}
+
+// validateEtcdResources checks that each resource is stored in at most one etcd cluster,
+// and that ports are available for the additional etcd clusters.
+func validateEtcdResources(specs []kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	extraClusters := 0
+	portIndexes := make(map[int32]string)
+	resources := make(map[string]string)
+	for i, spec := range specs {
+		if model.IsExtraEtcdCluster(spec) {
+			extraClusters++
+			if extraClusters == wellknownports.EtcdExtraClusters+1 {
+				allErrs = append(allErrs, field.TooMany(fieldPath, extraClusters, wellknownports.EtcdExtraClusters))
+			}
+		}
+		if spec.PortIndex != nil {
+			portIndex := *spec.PortIndex
+			portIndexPath := fieldPath.Index(i).Child("portIndex")
+			if !model.IsExtraEtcdCluster(spec) {
+				allErrs = append(allErrs, field.Forbidden(portIndexPath, fmt.Sprintf("portIndex cannot be set for the %q etcd cluster", spec.Name)))
+			} else if portIndex < 0 || portIndex >= wellknownports.EtcdExtraClusters {
+				allErrs = append(allErrs, field.Invalid(portIndexPath, portIndex, fmt.Sprintf("must be between 0 and %d", wellknownports.EtcdExtraClusters-1)))
+			} else if other, found := portIndexes[portIndex]; found {
+				allErrs = append(allErrs, field.Duplicate(portIndexPath, fmt.Sprintf("%d is already used by the %q etcd cluster", portIndex, other)))
+			} else {
+				portIndexes[portIndex] = spec.Name
+			}
+		}
+		for j, resource := range spec.Resources {
+			if other, found := resources[resource]; found {
+				allErrs = append(allErrs, field.Duplicate(fieldPath.Index(i).Child("resources").Index(j), fmt.Sprintf("%s is already stored in the %q etcd cluster", resource, other)))
+			}
+			resources[resource] = spec.Name
+		}
+	}
+
+	return allErrs
+}
+
 // validateEtcdStorage is responsible for checking versions are identical.
 func validateEtcdStorage(specs []kops.EtcdClusterSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -1694,6 +1860,78 @@ func validateContainerdConfig(spec *kops.ClusterSpec, config *kops.ContainerdCon
 		allErrs = append(allErrs, validateNvidiaConfig(spec, config.NvidiaGPU, fldPath.Child("nvidia"), inClusterConfig)...)
 	}
 
+	if config.Runtimes != nil {
+		if config.ConfigOverride != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("runtimes"), "runtimes cannot be used with configOverride"))
+		}
+		allErrs = append(allErrs, validateContainerdRuntimes(config.Runtimes, fldPath.Child("runtimes"))...)
+	}
+
+	return allErrs
+}
+
+func validateContainerdRuntimes(runtimes []kops.ContainerdRuntimeSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	reserved := sets.NewString("runc", "nvidia")
+	names := sets.NewString()
+	types := sets.NewString()
+	for i := range runtimes {
+		runtime := &runtimes[i]
+		runtimePath := fldPath.Index(i)
+
+		allErrs = append(allErrs, IsValidValue(runtimePath.Child("type"), &runtime.Type, []string{kops.ContainerdRuntimeGVisor, kops.ContainerdRuntimeKata})...)
+		if types.Has(runtime.Type) {
+			allErrs = append(allErrs, field.Duplicate(runtimePath.Child("type"), runtime.Type))
+		}
+		types.Insert(runtime.Type)
+
+		name := runtime.HandlerName()
+		if name != "" {
+			for _, msg := range utilvalidation.IsDNS1123Label(name) {
+				allErrs = append(allErrs, field.Invalid(runtimePath.Child("name"), name, msg))
+			}
+			if reserved.Has(name) {
+				allErrs = append(allErrs, field.Invalid(runtimePath.Child("name"), name, "name is reserved for the runtimes managed by kOps"))
+			} else if names.Has(name) {
+				allErrs = append(allErrs, field.Duplicate(runtimePath.Child("name"), name))
+			}
+			names.Insert(name)
+		}
+
+		packages := runtime.Packages
+		if packages == nil || (packages.UrlAmd64 == nil && packages.UrlArm64 == nil) {
+			allErrs = append(allErrs, field.Required(runtimePath.Child("packages"), "the URL and hash of the runtime package must be set"))
+			continue
+		}
+		if (packages.UrlAmd64 == nil) != (packages.HashAmd64 == nil) {
+			allErrs = append(allErrs, field.Required(runtimePath.Child("packages"), "both the URL and the hash of the AMD64 package must be set"))
+		}
+		if (packages.UrlArm64 == nil) != (packages.HashArm64 == nil) {
+			allErrs = append(allErrs, field.Required(runtimePath.Child("packages"), "both the URL and the hash of the ARM64 package must be set"))
+		}
+		allErrs = append(allErrs, validateContainerdRuntimePackageURL(packages.UrlAmd64, runtimePath.Child("packages", "urlAmd64"))...)
+		allErrs = append(allErrs, validateContainerdRuntimePackageURL(packages.UrlArm64, runtimePath.Child("packages", "urlArm64"))...)
+	}
+
+	return allErrs
+}
+
+// validateContainerdRuntimePackageURL checks that a runtime package is an archive that nodeup can extract
+func validateContainerdRuntimePackageURL(url *string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if url == nil {
+		return allErrs
+	}
+	name := strings.ToLower(path.Base(*url))
+	for _, suffix := range []string{".tar.gz", ".tgz", ".tar.xz", ".txz"} {
+		if strings.HasSuffix(name, suffix) {
+			return allErrs
+		}
+	}
+	allErrs = append(allErrs, field.Invalid(fldPath, *url, "runtime packages must be .tar.gz, .tgz, .tar.xz or .txz archives; the gVisor runsc and containerd-shim-runsc-v1 binaries must be packed into a single archive"))
+
 	return allErrs
 }
 
@@ -1963,3 +2201,89 @@ func validateCertManager(cluster *kops.Cluster, spec *kops.CertManagerConfig, fl
 	}
 	return allErrs
 }
+
+// validateHardeningSysctls checks that the sysctl parameters do not override the kernel parameters of the hardening profile,
+// as they are applied after the settings of the profile
+func validateHardeningSysctls(profile string, params []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	level := model.HardeningLevel(profile)
+	for i, param := range params {
+		if conflict := model.HardeningSysctlConflict(level, param); conflict != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i), fmt.Sprintf("contradicts hardening profile %s: %s", profile, conflict)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateHardening(spec *kops.HardeningSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Profile != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("profile"), &spec.Profile, kops.SupportedHardeningProfiles)...)
+	}
+
+	for i, module := range spec.DisabledKernelModules {
+		if module == "" || strings.ContainsAny(module, " \t\n/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("disabledKernelModules").Index(i), module, "must be the name of a kernel module"))
+		}
+	}
+
+	for i, rule := range spec.AuditRules {
+		if !strings.HasPrefix(rule, "-") || strings.Contains(rule, "\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("auditRules").Index(i), rule, "must be a single auditctl rule"))
+		} else if strings.HasPrefix(rule, "-e") || strings.HasPrefix(rule, "-D") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("auditRules").Index(i), "rules cannot change the audit system configuration"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateFileRepositoryMirrors(spec *kops.AssetsSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.FileRepository == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("fileRepository"), "fileRepository is required when fileRepositoryMirrors are set"))
+	}
+	seen := sets.NewString()
+	if spec.FileRepository != nil {
+		seen.Insert(*spec.FileRepository)
+	}
+	for i, mirror := range spec.FileRepositoryMirrors {
+		u, err := url.Parse(mirror)
+		if err != nil || u.Scheme == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("fileRepositoryMirrors").Index(i), mirror, "mirror must be an absolute URL"))
+			continue
+		}
+		if seen.Has(mirror) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("fileRepositoryMirrors").Index(i), mirror))
+		}
+		seen.Insert(mirror)
+	}
+
+	return allErrs
+}
+
+func validateImageVerification(spec *kops.ImageVerificationSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.PublicKeys) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("publicKeys"), "at least one public key is required"))
+	}
+	for i, key := range spec.PublicKeys {
+		publicKey, err := pki.ParsePEMPublicKey([]byte(key))
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKeys").Index(i), key, fmt.Sprintf("could not parse public key: %v", err)))
+			continue
+		}
+		switch publicKey.Key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKeys").Index(i), key, "public key must be an ECDSA, RSA or Ed25519 key"))
+		}
+	}
+
+	return allErrs
+}
@@ -1428,6 +1428,297 @@ func TestValidateSAExternalPermissions(t *testing.T) {
 	}
 }
 
+func Test_Validate_EtcdResources(t *testing.T) {
+	member := []kops.EtcdMemberSpec{
+		{
+			Name:          "a",
+			InstanceGroup: fi.PtrTo("master-a"),
+		},
+	}
+	grid := []struct {
+		Description    string
+		Input          []kops.EtcdClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Description: "Additional etcd cluster",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "events", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}},
+			},
+		},
+		{
+			Description: "Events resources",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "events", Members: member, Resources: []string{"/events", "events.k8s.io/events"}},
+			},
+		},
+		{
+			Description: "Main resources",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member, Resources: []string{"/pods"}},
+			},
+			ExpectedErrors: []string{"Forbidden::etcdClusters[0].resources"},
+		},
+		{
+			Description: "Missing resources",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member},
+			},
+			ExpectedErrors: []string{"Required value::etcdClusters[1].resources"},
+		},
+		{
+			Description: "Invalid name",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "Leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}},
+			},
+			ExpectedErrors: []string{"Invalid value::etcdClusters[1].name"},
+		},
+		{
+			Description: "Invalid resource",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"leases"}},
+			},
+			ExpectedErrors: []string{"Invalid value::etcdClusters[1].resources[0]"},
+		},
+		{
+			Description: "Duplicate resource",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}},
+				{Name: "more-leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}},
+			},
+			ExpectedErrors: []string{"Duplicate value::etcdClusters[2].resources[0]"},
+		},
+		{
+			Description: "Port indexes",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}, PortIndex: fi.PtrTo[int32](3)},
+				{Name: "nodes", Members: member, Resources: []string{"/nodes"}, PortIndex: fi.PtrTo[int32](0)},
+			},
+		},
+		{
+			Description: "Duplicate port index",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}, PortIndex: fi.PtrTo[int32](1)},
+				{Name: "nodes", Members: member, Resources: []string{"/nodes"}, PortIndex: fi.PtrTo[int32](1)},
+			},
+			ExpectedErrors: []string{"Duplicate value::etcdClusters[2].portIndex"},
+		},
+		{
+			Description: "Port index out of range",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member},
+				{Name: "leases", Members: member, Resources: []string{"coordination.k8s.io/leases"}, PortIndex: fi.PtrTo[int32](8)},
+			},
+			ExpectedErrors: []string{"Invalid value::etcdClusters[1].portIndex"},
+		},
+		{
+			Description: "Port index of main",
+			Input: []kops.EtcdClusterSpec{
+				{Name: "main", Members: member, PortIndex: fi.PtrTo[int32](0)},
+			},
+			ExpectedErrors: []string{"Forbidden::etcdClusters[0].portIndex"},
+		},
+	}
+
+	for _, g := range grid {
+		fldPath := field.NewPath("etcdClusters")
+		t.Run(g.Description, func(t *testing.T) {
+			var errs field.ErrorList
+			for i, etcdCluster := range g.Input {
+				errs = append(errs, validateEtcdClusterSpec(etcdCluster, &kops.Cluster{}, fldPath.Index(i))...)
+			}
+			errs = append(errs, validateEtcdResources(g.Input, fldPath)...)
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_KubeletCredentialProviders(t *testing.T) {
+	packages := &kops.PackagesConfig{
+		UrlAmd64: fi.PtrTo("https://example.com/example-credential-provider-amd64"),
+		UrlArm64: fi.PtrTo("https://example.com/example-credential-provider-arm64"),
+	}
+	grid := []struct {
+		Description       string
+		KubernetesVersion string
+		Input             []kops.KubeletCredentialProviderSpec
+		ExpectedErrors    []string
+	}{
+		{
+			Description: "Known provider",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "ecr-credential-provider"},
+			},
+		},
+		{
+			Description: "Custom provider",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "example-credential-provider", MatchImages: []string{"registry.example.com"}, Packages: packages},
+			},
+		},
+		{
+			Description:       "Unsupported Kubernetes version",
+			KubernetesVersion: "1.23.0",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "ecr-credential-provider"},
+			},
+			ExpectedErrors: []string{"Forbidden::credentialProviders"},
+		},
+		{
+			Description: "Missing name",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{MatchImages: []string{"registry.example.com"}, Packages: packages},
+			},
+			ExpectedErrors: []string{"Required value::credentialProviders[0].name"},
+		},
+		{
+			Description: "Duplicate name",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "ecr-credential-provider"},
+				{Name: "ecr-credential-provider"},
+			},
+			ExpectedErrors: []string{"Duplicate value::credentialProviders[1].name"},
+		},
+		{
+			Description: "Custom provider without images",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "example-credential-provider", Packages: packages},
+			},
+			ExpectedErrors: []string{"Required value::credentialProviders[0].matchImages"},
+		},
+		{
+			Description: "Known provider with default download location",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "ecr-credential-provider"},
+			},
+		},
+		{
+			Description: "Known providers without released download locations",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "auth-provider-gcp"},
+				{Name: "acr-credential-provider"},
+			},
+			ExpectedErrors: []string{
+				"Required value::credentialProviders[0].packages",
+				"Required value::credentialProviders[1].packages",
+			},
+		},
+		{
+			Description: "Custom provider without download location",
+			Input: []kops.KubeletCredentialProviderSpec{
+				{Name: "example-credential-provider", MatchImages: []string{"registry.example.com"}},
+			},
+			ExpectedErrors: []string{"Required value::credentialProviders[0].packages"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					KubernetesVersion: "1.26.0",
+				},
+			}
+			if g.KubernetesVersion != "" {
+				cluster.Spec.KubernetesVersion = g.KubernetesVersion
+			}
+			errs := validateKubeletCredentialProviders(g.Input, cluster, field.NewPath("credentialProviders"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_ContainerdRuntimes(t *testing.T) {
+	packages := &kops.PackagesConfig{
+		UrlAmd64:  fi.PtrTo("https://example.com/runtime-amd64.tar.gz"),
+		HashAmd64: fi.PtrTo("5f3f2e9e3ac1ed1b0e8d3c7a54b5a8d6cbd1f5c0b8a8ee9aa0f6cf3d6b3f1e2a"),
+	}
+	grid := []struct {
+		Description    string
+		Input          []kops.ContainerdRuntimeSpec
+		ExpectedErrors []string
+	}{
+		{
+			Description: "gVisor and Kata",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "gvisor", Packages: packages},
+				{Name: "kata-qemu", Type: "kata", Packages: packages},
+			},
+		},
+		{
+			Description: "Unsupported type",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "youki", Packages: packages},
+			},
+			ExpectedErrors: []string{"Unsupported value::runtimes[0].type"},
+		},
+		{
+			Description: "Duplicate type",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "gvisor", Packages: packages},
+				{Name: "runsc", Type: "gvisor", Packages: packages},
+			},
+			ExpectedErrors: []string{"Duplicate value::runtimes[1].type"},
+		},
+		{
+			Description: "Reserved name",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Name: "runc", Type: "gvisor", Packages: packages},
+			},
+			ExpectedErrors: []string{"Invalid value::runtimes[0].name"},
+		},
+		{
+			Description: "Missing packages",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "kata"},
+			},
+			ExpectedErrors: []string{"Required value::runtimes[0].packages"},
+		},
+		{
+			Description: "Missing hash",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "kata", Packages: &kops.PackagesConfig{UrlArm64: fi.PtrTo("https://example.com/runtime-arm64.tar.gz")}},
+			},
+			ExpectedErrors: []string{"Required value::runtimes[0].packages"},
+		},
+		{
+			Description: "Kata xz archive",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "kata", Packages: &kops.PackagesConfig{
+					UrlAmd64:  fi.PtrTo("https://github.com/kata-containers/kata-containers/releases/download/3.2.0/kata-static-3.2.0-amd64.tar.xz"),
+					HashAmd64: fi.PtrTo("5f3f2e9e3ac1ed1b0e8d3c7a54b5a8d6cbd1f5c0b8a8ee9aa0f6cf3d6b3f1e2a"),
+				}},
+			},
+		},
+		{
+			Description: "gVisor binary",
+			Input: []kops.ContainerdRuntimeSpec{
+				{Type: "gvisor", Packages: &kops.PackagesConfig{
+					UrlAmd64:  fi.PtrTo("https://storage.googleapis.com/gvisor/releases/release/20231009/x86_64/runsc"),
+					HashAmd64: fi.PtrTo("5f3f2e9e3ac1ed1b0e8d3c7a54b5a8d6cbd1f5c0b8a8ee9aa0f6cf3d6b3f1e2a"),
+				}},
+			},
+			ExpectedErrors: []string{"Invalid value::runtimes[0].packages.urlAmd64"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			errs := validateContainerdRuntimes(g.Input, field.NewPath("runtimes"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
 func Test_Validate_Nvidia_Cluster(t *testing.T) {
 	grid := []struct {
 		Input          kops.ClusterSpec
@@ -1560,3 +1851,108 @@ func Test_Validate_Nvidia_Ig(t *testing.T) {
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_FileRepositoryMirrors(t *testing.T) {
+	grid := []struct {
+		Description    string
+		Input          *kops.AssetsSpec
+		ExpectedErrors []string
+	}{
+		{
+			Description: "valid",
+			Input: &kops.AssetsSpec{
+				FileRepository:        fi.PtrTo("https://artifactory.example.com/kops"),
+				FileRepositoryMirrors: []string{"https://mirror.example.com/kops", "s3://example-bucket/kops"},
+			},
+		},
+		{
+			Description: "no file repository",
+			Input: &kops.AssetsSpec{
+				FileRepositoryMirrors: []string{"https://mirror.example.com/kops"},
+			},
+			ExpectedErrors: []string{"Required value::assets.fileRepository"},
+		},
+		{
+			Description: "relative mirror",
+			Input: &kops.AssetsSpec{
+				FileRepository:        fi.PtrTo("https://artifactory.example.com/kops"),
+				FileRepositoryMirrors: []string{"mirror.example.com/kops"},
+			},
+			ExpectedErrors: []string{"Invalid value::assets.fileRepositoryMirrors[0]"},
+		},
+		{
+			Description: "duplicate mirror",
+			Input: &kops.AssetsSpec{
+				FileRepository:        fi.PtrTo("https://artifactory.example.com/kops"),
+				FileRepositoryMirrors: []string{"https://artifactory.example.com/kops"},
+			},
+			ExpectedErrors: []string{"Duplicate value::assets.fileRepositoryMirrors[0]"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			errs := validateFileRepositoryMirrors(g.Input, field.NewPath("assets"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
+
+func Test_Validate_ImageVerification(t *testing.T) {
+	ecdsaKey := `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE4ks3vv19GrUgUvE7Dwn0qrLvILTc
+53IumhthagMenM5nDEonGvu08rJGmJ036gj4WA1YsZpC1qYfdgBKLy+pgg==
+-----END PUBLIC KEY-----
+`
+	dsaKey := `-----BEGIN PUBLIC KEY-----
+MIIBvzCCATQGByqGSM44BAEwggEnAoGBALveaK2n1g4XzHOVzgfagUHIg4uEsoE3
+cwWZsXYbN79MYbO20rFRYMex0U+GzjzOB3XNhod1dT1qPQvE67/Z6v/UO1Feo3NE
+zcgPpCl3dtyvzks1arqXg4ojzI48LF/o/4Q1Ht76HZzJzLY+2/o/nwzGNepEbJsi
+3POEb3w7rssNAh0A96oAta3jOnHauLy6/CIIWF7s8pNjESBPIw1UTQKBgQCmCeNt
+EhVk81sM2ea031u+jCvwo02nFKXQ483oZifcnnvmofhcbAKJpVgR6rfrEBUV0ckG
+RMx1dlNL9btIbyKxJiqsgrs7J9pzYF+j2ndlVrFBwWEK6udOreTLj3+v8vq+uels
+/dttXe57ltRKYEJMzL5QjS158Y9gZSuijzimBgOBhAACgYAVDkkh5zVYqCKjchhH
+pTf1SQD/IIIJaTSOfsLBAvtNMICeJGblB+ZK99Hw5Bh6sWGPyC5TfbAOe4+6e6XU
+XdZ0fx94dIsw7l2PIRoT7h8I981cm2f7YA66rMRwraGsPFtrmVVg56svq6vQ++r9
+QU+S2fLTmIoVkG8TpcsqemRZ1Q==
+-----END PUBLIC KEY-----
+`
+
+	grid := []struct {
+		Description    string
+		Input          *kops.ImageVerificationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Description: "valid",
+			Input: &kops.ImageVerificationSpec{
+				PublicKeys: []string{ecdsaKey},
+				Images:     []string{"registry.example.com/"},
+			},
+		},
+		{
+			Description:    "no public keys",
+			Input:          &kops.ImageVerificationSpec{},
+			ExpectedErrors: []string{"Required value::assets.imageVerification.publicKeys"},
+		},
+		{
+			Description: "invalid public key",
+			Input: &kops.ImageVerificationSpec{
+				PublicKeys: []string{ecdsaKey, "not a key"},
+			},
+			ExpectedErrors: []string{"Invalid value::assets.imageVerification.publicKeys[1]"},
+		},
+		{
+			Description: "unsupported public key",
+			Input: &kops.ImageVerificationSpec{
+				PublicKeys: []string{dsaKey},
+			},
+			ExpectedErrors: []string{"Invalid value::assets.imageVerification.publicKeys[0]"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Description, func(t *testing.T) {
+			errs := validateImageVerification(g.Input, field.NewPath("assets", "imageVerification"))
+			testErrors(t, g.Input, errs, g.ExpectedErrors)
+		})
+	}
+}
@@ -33,6 +33,12 @@ type Config struct {
 	Assets map[architectures.Architecture][]string `json:",omitempty"`
 	// Images are a list of images we should preload
 	Images map[architectures.Architecture][]*Image `json:"images,omitempty"`
+	// ImageVerificationPublicKeys are the public keys that the signatures of the images to preload must be made with, when set
+	ImageVerificationPublicKeys []string `json:"imageVerificationPublicKeys,omitempty"`
+	// ImageVerificationTarballs are the prefixes of the canonical sources of the images to preload that are verified; no image is verified if empty
+	ImageVerificationTarballs []string `json:"imageVerificationTarballs,omitempty"`
+	// PrePullImages are the container images to pull before the kubelet starts, remapped to the assets registry.
+	PrePullImages []string `json:"prePullImages,omitempty"`
 	// ClusterName is the name of the cluster
 	ClusterName string `json:",omitempty"`
 	// Channels is a list of channels that we should apply
@@ -94,6 +100,12 @@ type Config struct {
 	APIServerConfig *APIServerConfig `json:",omitempty"`
 	// NvidiaGPU contains the configuration for nvidia
 	NvidiaGPU *kops.NvidiaGPUConfig `json:",omitempty"`
+	// HostFirewall is the configuration of the nftables host firewall, which is enabled when set.
+	HostFirewall *HostFirewallConfig `json:"hostFirewall,omitempty"`
+	// Hardening is the security hardening of the instances, merged from the cluster and instance group.
+	Hardening *kops.HardeningSpec `json:"hardening,omitempty"`
+	// Swap is the swap device to provision on the instances.
+	Swap *kops.SwapSpec `json:"swap,omitempty"`
 
 	// AWS-specific
 	// DisableSecurityGroupIngress disables the Cloud Controller Manager's creation
@@ -150,6 +162,10 @@ type Image struct {
 	Sources []string `json:"sources,omitempty"`
 	// Hash is the hash of the file, to verify image integrity (even over http)
 	Hash string `json:"hash,omitempty"`
+	// CanonicalSource is the original URL of the image, before it is remapped to the assets repository
+	CanonicalSource string `json:"canonicalSource,omitempty"`
+	// Signature is the signature that cosign sign-blob wrote for the image, when the image is verified
+	Signature string `json:"signature,omitempty"`
 }
 
 // StaticManifest is a generic static manifest
@@ -160,6 +176,12 @@ type StaticManifest struct {
 	Path string `json:"path,omitempty"`
 }
 
+// HostFirewallConfig is the configuration of the nftables host firewall.
+type HostFirewallConfig struct {
+	// Rules allow incoming traffic through the host firewall.
+	Rules []kops.HostFirewallRule `json:"rules,omitempty"`
+}
+
 // APIServerConfig is additional configuration for nodes running an APIServer.
 type APIServerConfig struct {
 	// KubeAPIServer is a copy of the KubeAPIServerConfig from the cluster spec.
@@ -172,6 +194,8 @@ type APIServerConfig struct {
 	EncryptionConfigSecretHash string `json:",omitempty"`
 	// ServiceAccountPublicKeys are the service-account public keys to trust.
 	ServiceAccountPublicKeys string
+	// EtcdNodesClusters are the names of the etcd clusters running on instance groups with the Etcd role.
+	EtcdNodesClusters []string `json:",omitempty"`
 }
 
 func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Config, *BootConfig) {
@@ -198,6 +222,11 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		Docker:           cluster.Spec.Docker,
 	}
 
+	if cluster.Spec.Assets != nil && cluster.Spec.Assets.ImageVerification != nil {
+		config.ImageVerificationPublicKeys = cluster.Spec.Assets.ImageVerification.PublicKeys
+		config.ImageVerificationTarballs = cluster.Spec.Assets.ImageVerification.Tarballs
+	}
+
 	bootConfig := BootConfig{
 		CloudProvider:     cluster.Spec.GetCloudProvider(),
 		InstanceGroupName: instanceGroup.ObjectMeta.Name,
@@ -212,6 +241,15 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.NvidiaGPU = buildNvidiaConfig(cluster, instanceGroup)
 	}
 
+	if instanceGroup.Spec.HostFirewall != nil && aws.BoolValue(instanceGroup.Spec.HostFirewall.Enabled) {
+		config.HostFirewall = &HostFirewallConfig{
+			Rules: model.HostFirewallRules(cluster, instanceGroup),
+		}
+	}
+
+	config.Hardening = model.InstanceGroupHardening(cluster, instanceGroup)
+	config.Swap = instanceGroup.Spec.Swap
+
 	config.KubeProxy = buildKubeProxy(cluster, instanceGroup)
 
 	if cluster.Spec.NTP != nil && cluster.Spec.NTP.Managed != nil && !*cluster.Spec.NTP.Managed {
@@ -286,6 +324,15 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.KubeletConfig = *instanceGroup.Spec.Kubelet
 	}
 
+	if instanceGroup.IsEtcdOnly() {
+		// Etcd nodes don't join the cluster; the kubelet only runs the etcd-manager static pods
+		config.KubeletConfig.KubeconfigPath = ""
+		config.KubeletConfig.BootstrapKubeconfig = ""
+		config.KubeProxy = nil
+		config.UseCiliumEtcd = false
+		config.Networking.KubeRouter = nil
+	}
+
 	if instanceGroup.HasAPIServer() {
 		config.APIServerConfig = &APIServerConfig{
 			KubeAPIServer: cluster.Spec.KubeAPIServer,
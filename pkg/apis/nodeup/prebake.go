@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import "k8s.io/kops/util/pkg/architectures"
+
+// PrebakeManifestFile is the name of the prebake manifest, in the nodeup cache directory.
+const PrebakeManifestFile = "prebake.json"
+
+// PrebakeManifest records the assets and container images installed in a node image before boot.
+// nodeup does not download the assets again when their hash matches, and imports the images instead of pulling them.
+type PrebakeManifest struct {
+	// InstanceGroup is the name of the instance group the image was baked for.
+	InstanceGroup string `json:"instanceGroup,omitempty"`
+	// Architecture is the architecture of the assets and images.
+	Architecture architectures.Architecture `json:"architecture,omitempty"`
+	// Assets are the assets downloaded to the nodeup cache, in the hash@url format of the nodeup config.
+	Assets []string `json:"assets,omitempty"`
+	// Images are the container images saved to the nodeup cache.
+	Images []PrebakedImage `json:"images,omitempty"`
+}
+
+// PrebakedImage is a container image saved to the nodeup cache.
+type PrebakedImage struct {
+	// Name is the reference of the image, as pulled by nodeup.
+	Name string `json:"name"`
+	// File is the path of the OCI image archive, relative to the nodeup cache directory.
+	File string `json:"file"`
+	// Hash is the hash of the OCI image archive.
+	Hash string `json:"hash"`
+	// Digest is the digest of the manifest of the image in the registry, which the archive keeps.
+	Digest string `json:"digest,omitempty"`
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package assets
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -47,6 +49,11 @@ type AssetBuilder struct {
 	AssetsLocation *kops.AssetsSpec
 	GetAssets      bool
 
+	// ImageVerifier verifies the signatures of the images, which are then pinned to the verified digests; images are not verified if nil
+	ImageVerifier *ImageVerifier
+	// verifiedDigests are the digests of the images that have been verified, by image
+	verifiedDigests map[string]string
+
 	// KubernetesVersion is the version of kubernetes we are installing
 	KubernetesVersion semver.Version
 
@@ -98,6 +105,8 @@ type FileAsset struct {
 	CanonicalURL *url.URL
 	// SHAValue is the SHA hash of the FileAsset.
 	SHAValue string
+	// MirrorURLs are the locations to try in order when the file cannot be downloaded from the DownloadURL.
+	MirrorURLs []*url.URL
 }
 
 // NewAssetBuilder creates a new AssetBuilder.
@@ -217,6 +226,10 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 
 	a.ImageAssets = append(a.ImageAssets, asset)
 
+	if a.ImageVerifier.Matches(asset.CanonicalLocation) {
+		return a.pinVerifiedImage(image)
+	}
+
 	if !featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "" {
 		return image, nil
 	}
@@ -234,6 +247,28 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 	return image + "@" + digest, nil
 }
 
+// pinVerifiedImage verifies the signature of the image and pins the image to the verified digest,
+// so that nodes cannot pull a different image if the tag is moved.
+func (a *AssetBuilder) pinVerifiedImage(image string) (string, error) {
+	digest, found := a.verifiedDigests[image]
+	if !found {
+		hash, err := a.ImageVerifier.VerifyImage(context.TODO(), image)
+		if err != nil {
+			return "", fmt.Errorf("error verifying image: %w", err)
+		}
+		digest = hash.String()
+		if a.verifiedDigests == nil {
+			a.verifiedDigests = make(map[string]string)
+		}
+		a.verifiedDigests[image] = digest
+	}
+
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+	return image + "@" + digest, nil
+}
+
 // RemapFileAndSHA returns a remapped URL for the file, if AssetsLocation is defined.
 // It also returns the SHA hash of the file.
 func (a *AssetBuilder) RemapFileAndSHA(fileURL *url.URL) (*url.URL, *hashing.Hash, error) {
@@ -255,6 +290,11 @@ func (a *AssetBuilder) RemapFileAndSHA(fileURL *url.URL) (*url.URL, *hashing.Has
 
 		fileAsset.DownloadURL = normalizedFileURL
 
+		fileAsset.MirrorURLs, err = a.remapMirrorURLs(fileURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		klog.V(4).Infof("adding remapped file: %+v", fileAsset)
 	}
 
@@ -289,6 +329,12 @@ func (a *AssetBuilder) RemapFileAndSHAValue(fileURL *url.URL, shaValue string) (
 		}
 
 		fileAsset.DownloadURL = normalizedFile
+
+		fileAsset.MirrorURLs, err = a.remapMirrorURLs(fileURL)
+		if err != nil {
+			return nil, err
+		}
+
 		klog.V(4).Infof("adding remapped file: %q", fileAsset.DownloadURL.String())
 	}
 
@@ -321,6 +367,14 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 		return nil, fmt.Errorf("file url is not defined")
 	}
 
+	// Fall back to the mirrors of the file repository in order, like the nodes do
+	locations := []string{u.String()}
+	if !a.GetAssets {
+		for _, mirrorURL := range file.MirrorURLs {
+			locations = append(locations, mirrorURL.String())
+		}
+	}
+
 	// We now prefer sha256 hashes
 	for backoffSteps := 1; backoffSteps <= 3; backoffSteps++ {
 		// We try first with a short backoff, so we don't
@@ -333,25 +387,30 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 		}
 
 		for _, ext := range []string{".sha256", ".sha1"} {
-			for _, mirror := range mirrors.FindUrlMirrors(u.String()) {
-				hashURL := mirror + ext
-				klog.V(3).Infof("Trying to read hash fie: %q", hashURL)
-				b, err := vfs.Context.ReadFile(hashURL, vfs.WithBackoff(backoff))
-				if err != nil {
-					// Try to log without being too alarming - issue #7550
-					klog.V(2).Infof("Unable to read hash file %q: %v", hashURL, err)
-					continue
-				}
-				hashString := strings.TrimSpace(string(b))
-				klog.V(2).Infof("Found hash %q for %q", hashString, u)
-
-				// Accept a hash string that is `<hash> <filename>`
-				fields := strings.Fields(hashString)
-				if len(fields) == 0 {
-					klog.Infof("Hash file was empty %q", hashURL)
-					continue
+			for i, location := range locations {
+				for _, mirror := range mirrors.FindUrlMirrors(location) {
+					hashURL := mirror + ext
+					klog.V(3).Infof("Trying to read hash fie: %q", hashURL)
+					b, err := vfs.Context.ReadFile(hashURL, vfs.WithBackoff(backoff))
+					if err != nil {
+						// Try to log without being too alarming - issue #7550
+						klog.V(2).Infof("Unable to read hash file %q: %v", hashURL, err)
+						continue
+					}
+					hashString := strings.TrimSpace(string(b))
+					klog.V(2).Infof("Found hash %q for %q", hashString, u)
+					if i > 0 {
+						klog.Infof("Using hash file %q from mirror for %q", hashURL, u)
+					}
+
+					// Accept a hash string that is `<hash> <filename>`
+					fields := strings.Fields(hashString)
+					if len(fields) == 0 {
+						klog.Infof("Hash file was empty %q", hashURL)
+						continue
+					}
+					return hashing.FromString(fields[0])
 				}
-				return hashing.FromString(fields[0])
 			}
 			if ext == ".sha256" {
 				klog.V(2).Infof("Unable to read new sha256 hash file (is this an older/unsupported kubernetes release?)")
@@ -365,6 +424,39 @@ func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 	return nil, fmt.Errorf("cannot determine hash for %q (have you specified a valid file location?)", u)
 }
 
+// BuildMirroredAsset builds the mirrored asset for a file that was remapped by the AssetBuilder,
+// falling back to the mirrors of the file repository and then the canonical location.
+func (a *AssetBuilder) BuildMirroredAsset(u *url.URL, hash *hashing.Hash) *mirrors.MirroredAsset {
+	asset := mirrors.BuildMirroredAsset(u, hash)
+
+	var mirrorURLs []*url.URL
+	for _, fileAsset := range a.FileAssets {
+		if fileAsset.DownloadURL.String() == u.String() {
+			mirrorURLs = fileAsset.MirrorURLs
+			break
+		}
+	}
+	if len(mirrorURLs) == 0 {
+		return asset
+	}
+	if hash == nil {
+		klog.Warningf("not using file repository mirrors for asset %s as it does not have a known hash", u.String())
+		return asset
+	}
+
+	seen := sets.NewString(asset.Locations...)
+	for _, mirrorURL := range mirrorURLs {
+		for _, location := range mirrors.FindUrlMirrors(mirrorURL.String()) {
+			if !seen.Has(location) {
+				seen.Insert(location)
+				asset.Locations = append(asset.Locations, location)
+			}
+		}
+	}
+
+	return asset
+}
+
 func (a *AssetBuilder) remapURL(canonicalURL *url.URL) (*url.URL, error) {
 	f := ""
 	if a.AssetsLocation != nil {
@@ -374,9 +466,33 @@ func (a *AssetBuilder) remapURL(canonicalURL *url.URL) (*url.URL, error) {
 		return nil, fmt.Errorf("assetsLocation.fileRepository must be set to remap asset %v", canonicalURL)
 	}
 
-	fileRepo, err := url.Parse(f)
+	return remapURLToRepository(f, canonicalURL)
+}
+
+// remapMirrorURLs returns the locations of the file in the mirrors of the file repository,
+// followed by the canonical location, or nil if no mirrors are configured.
+func (a *AssetBuilder) remapMirrorURLs(canonicalURL *url.URL) ([]*url.URL, error) {
+	if a.AssetsLocation == nil || len(a.AssetsLocation.FileRepositoryMirrors) == 0 {
+		return nil, nil
+	}
+
+	var mirrorURLs []*url.URL
+	for _, mirror := range a.AssetsLocation.FileRepositoryMirrors {
+		mirrorURL, err := remapURLToRepository(mirror, canonicalURL)
+		if err != nil {
+			return nil, err
+		}
+		mirrorURLs = append(mirrorURLs, mirrorURL)
+	}
+	mirrorURLs = append(mirrorURLs, canonicalURL)
+
+	return mirrorURLs, nil
+}
+
+func remapURLToRepository(repository string, canonicalURL *url.URL) (*url.URL, error) {
+	fileRepo, err := url.Parse(repository)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse assetsLocation.fileRepository %q: %v", f, err)
+		return nil, fmt.Errorf("unable to parse file repository %q: %v", repository, err)
 	}
 
 	fileRepo.Path = path.Join(fileRepo.Path, canonicalURL.Path)
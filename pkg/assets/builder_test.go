@@ -17,8 +17,11 @@ limitations under the License.
 package assets
 
 import (
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -180,3 +183,48 @@ func TestRemapEmptySection(t *testing.T) {
 
 	golden.AssertMatchesFile(t, string(actual), expectedPath)
 }
+
+func TestRemapFileAndSHA_FileRepositoryMirrors(t *testing.T) {
+	fileRepository := t.TempDir()
+	mirror := t.TempDir()
+
+	canonicalURL, err := url.Parse("https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kubelet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is only staged in the mirror, not in the file repository
+	sha := strings.Repeat("a", 64)
+	mirrorFile := filepath.Join(mirror, canonicalURL.Path)
+	if err := os.MkdirAll(filepath.Dir(mirrorFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mirrorFile+".sha256", []byte(sha+"  kubelet\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.FileRepository = &fileRepository
+	builder.AssetsLocation.FileRepositoryMirrors = []string{mirror}
+
+	downloadURL, hash, err := builder.RemapFileAndSHA(canonicalURL)
+	if err != nil {
+		t.Fatalf("error remapping file: %v", err)
+	}
+	if downloadURL.String() != filepath.Join(fileRepository, canonicalURL.Path) {
+		t.Errorf("unexpected download url %q", downloadURL)
+	}
+	if hash.Hex() != sha {
+		t.Errorf("unexpected hash %q", hash.Hex())
+	}
+
+	asset := builder.BuildMirroredAsset(downloadURL, hash)
+	expected := []string{
+		filepath.Join(fileRepository, canonicalURL.Path),
+		mirrorFile,
+		canonicalURL.String(),
+	}
+	if !reflect.DeepEqual(asset.Locations, expected) {
+		t.Errorf("unexpected locations %v, expected %v", asset.Locations, expected)
+	}
+}
@@ -0,0 +1,494 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// BundleManifestFile is the name of the file listing the assets in a bundle
+const BundleManifestFile = "kops-assets.json"
+
+// annotationRefName is the OCI annotation naming the image of an index entry
+const annotationRefName = "org.opencontainers.image.ref.name"
+
+// BundleManifest lists the assets in a bundle.
+// A bundle is a tarball of an OCI image layout, holding the images and the
+// contents of the files as blobs, with this manifest at its root.
+type BundleManifest struct {
+	Images []*BundleImage `json:"images,omitempty"`
+	Files  []*BundleFile  `json:"files,omitempty"`
+}
+
+// BundleImage is an image in a bundle
+type BundleImage struct {
+	// Canonical is the source location of the image
+	Canonical string `json:"canonical"`
+	// Digest is the digest of the image manifest, or of the index for multi-arch images
+	Digest string `json:"digest"`
+	// MediaType is the media type of the image manifest or index
+	MediaType string `json:"mediaType"`
+	// SignatureDigest is the digest of the cosign signature of the image, if it is signed
+	SignatureDigest string `json:"signatureDigest,omitempty"`
+}
+
+// BundleFile is a file in a bundle
+type BundleFile struct {
+	// Canonical is the source location of the file
+	Canonical string `json:"canonical"`
+	// SHA is the hash of the file, as expected by the cluster
+	SHA string `json:"sha"`
+	// Digest is the digest of the blob holding the file
+	Digest string `json:"digest"`
+	// Size is the size of the file in bytes
+	Size int64 `json:"size"`
+}
+
+// WriteBundle downloads the images and files from their canonical locations,
+// and writes them to out as a bundle, for copying into disconnected environments.
+func WriteBundle(ctx context.Context, out io.Writer, imageAssets []*ImageAsset, fileAssets []*FileAsset) error {
+	dir, err := os.MkdirTemp("", "kops-assets-bundle")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	layoutPath, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		return fmt.Errorf("error creating image layout: %w", err)
+	}
+
+	manifest := &BundleManifest{}
+
+	seen := map[string]bool{}
+	for _, imageAsset := range imageAssets {
+		if seen[imageAsset.CanonicalLocation] {
+			continue
+		}
+		seen[imageAsset.CanonicalLocation] = true
+
+		image, err := bundleImage(ctx, layoutPath, imageAsset.CanonicalLocation)
+		if err != nil {
+			return err
+		}
+		manifest.Images = append(manifest.Images, image)
+	}
+
+	seen = map[string]bool{}
+	for _, fileAsset := range fileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		file, err := bundleFile(layoutPath, canonical, fileAsset.SHAValue)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	sort.Slice(manifest.Images, func(i, j int) bool {
+		return manifest.Images[i].Canonical < manifest.Images[j].Canonical
+	})
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Canonical < manifest.Files[j].Canonical
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling bundle manifest: %w", err)
+	}
+	if err := layoutPath.WriteFile(BundleManifestFile, data, 0o644); err != nil {
+		return fmt.Errorf("error writing bundle manifest: %w", err)
+	}
+
+	return writeTar(out, dir)
+}
+
+func bundleImage(ctx context.Context, layoutPath layout.Path, source string) (*BundleImage, error) {
+	sourceRef, err := name.ParseReference(source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reference %q: %v", source, err)
+	}
+
+	desc, err := remote.Get(sourceRef, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %q: %v", source, err)
+	}
+
+	annotations := layout.WithAnnotations(map[string]string{annotationRefName: source})
+
+	klog.Infof("adding image %v to bundle", sourceRef)
+	switch desc.MediaType {
+	case types.OCIImageIndex, types.DockerManifestList:
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return nil, err
+		}
+		if err := layoutPath.AppendIndex(idx, annotations); err != nil {
+			return nil, fmt.Errorf("error adding image index %q to bundle: %w", source, err)
+		}
+	default:
+		// Assume anything else is an image, since some registries don't set mediaTypes properly.
+		img, err := desc.Image()
+		if err != nil {
+			return nil, err
+		}
+		if err := layoutPath.AppendImage(img, annotations); err != nil {
+			return nil, fmt.Errorf("error adding image %q to bundle: %w", source, err)
+		}
+	}
+
+	image := &BundleImage{
+		Canonical: source,
+		Digest:    desc.Digest.String(),
+		MediaType: string(desc.MediaType),
+	}
+
+	// Signatures are bundled with the images, so that the imported images can be verified
+	sigRef := signatureRef(sourceRef, desc.Digest)
+	sigImage, err := remote.Image(sigRef, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
+	if err != nil {
+		klog.V(2).Infof("not adding signature of %v to bundle: %v", sourceRef, err)
+		return image, nil
+	}
+	sigDigest, err := sigImage.Digest()
+	if err != nil {
+		return nil, err
+	}
+	if err := layoutPath.AppendImage(sigImage, layout.WithAnnotations(map[string]string{annotationRefName: sigRef.String()})); err != nil {
+		return nil, fmt.Errorf("error adding signature of %q to bundle: %w", source, err)
+	}
+	image.SignatureDigest = sigDigest.String()
+
+	return image, nil
+}
+
+func bundleFile(layoutPath layout.Path, source string, sha string) (*BundleFile, error) {
+	data, err := vfs.Context.ReadFile(source)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file not found %q: %v", source, err)
+		}
+		return nil, fmt.Errorf("error downloading file %q: %v", source, err)
+	}
+
+	if err := verifySHA(data, source, sha); err != nil {
+		return nil, err
+	}
+
+	digest, size, err := v1.SHA256(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Infof("adding file %q to bundle", source)
+	if err := layoutPath.WriteBlob(digest, io.NopCloser(bytes.NewReader(data))); err != nil {
+		return nil, fmt.Errorf("error adding file %q to bundle: %w", source, err)
+	}
+
+	return &BundleFile{
+		Canonical: source,
+		SHA:       sha,
+		Digest:    digest.String(),
+		Size:      size,
+	}, nil
+}
+
+// ImportBundle uploads the images and files of a bundle written by WriteBundle
+// to the container registry and file repository configured in the cluster spec.
+func ImportBundle(ctx context.Context, in io.Reader, cluster *kops.Cluster) error {
+	dir, err := os.MkdirTemp("", "kops-assets-bundle")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractTar(in, dir); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, BundleManifestFile))
+	if err != nil {
+		return fmt.Errorf("error reading bundle manifest: %w", err)
+	}
+	manifest := &BundleManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return fmt.Errorf("error parsing bundle manifest: %w", err)
+	}
+
+	layoutPath, err := layout.FromPath(dir)
+	if err != nil {
+		return fmt.Errorf("error reading image layout: %w", err)
+	}
+
+	assetsSpec := cluster.Spec.Assets
+	if len(manifest.Images) != 0 && (assetsSpec == nil || assetsSpec.ContainerRegistry == nil) {
+		return fmt.Errorf("assets.containerRegistry must be set to import the images of a bundle")
+	}
+	if len(manifest.Files) != 0 && (assetsSpec == nil || assetsSpec.FileRepository == nil) {
+		return fmt.Errorf("assets.fileRepository must be set to import the files of a bundle")
+	}
+
+	assetBuilder := NewAssetBuilder(assetsSpec, cluster.Spec.KubernetesVersion, false)
+
+	for _, image := range manifest.Images {
+		if _, err := assetBuilder.RemapImage(image.Canonical); err != nil {
+			return err
+		}
+		target := assetBuilder.ImageAssets[len(assetBuilder.ImageAssets)-1].DownloadLocation
+		if err := importImage(layoutPath, image, target); err != nil {
+			return fmt.Errorf("%s: %w", image.Canonical, err)
+		}
+	}
+
+	for _, file := range manifest.Files {
+		canonicalURL, err := url.Parse(file.Canonical)
+		if err != nil {
+			return fmt.Errorf("unable to parse %q: %w", file.Canonical, err)
+		}
+		target, err := assetBuilder.RemapFileAndSHAValue(canonicalURL, file.SHA)
+		if err != nil {
+			return err
+		}
+		if err := importFile(ctx, cluster, layoutPath, file, target.String()); err != nil {
+			return fmt.Errorf("%s: %w", file.Canonical, err)
+		}
+	}
+
+	return nil
+}
+
+func importImage(layoutPath layout.Path, image *BundleImage, target string) error {
+	h, err := v1.NewHash(image.Digest)
+	if err != nil {
+		return fmt.Errorf("parsing digest %q: %v", image.Digest, err)
+	}
+
+	targetRef, err := name.ParseReference(target)
+	if err != nil {
+		return fmt.Errorf("parsing reference for %q: %v", target, err)
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+
+	targetDesc, err := remote.Get(targetRef, options...)
+	if err == nil && targetDesc.Digest == h {
+		klog.Infof("no need to import image %v", targetRef)
+		return nil
+	}
+
+	idx, err := layoutPath.ImageIndex()
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("importing image %s to %v", image.Canonical, targetRef)
+	switch types.MediaType(image.MediaType) {
+	case types.OCIImageIndex, types.DockerManifestList:
+		imageIndex, err := idx.ImageIndex(h)
+		if err != nil {
+			return err
+		}
+		if err := remote.WriteIndex(targetRef, imageIndex, options...); err != nil {
+			return err
+		}
+	default:
+		img, err := idx.Image(h)
+		if err != nil {
+			return err
+		}
+		if err := remote.Write(targetRef, img, options...); err != nil {
+			return err
+		}
+	}
+
+	if image.SignatureDigest != "" {
+		sigHash, err := v1.NewHash(image.SignatureDigest)
+		if err != nil {
+			return fmt.Errorf("parsing digest %q: %v", image.SignatureDigest, err)
+		}
+		sigImage, err := idx.Image(sigHash)
+		if err != nil {
+			return err
+		}
+		sigRef := signatureRef(targetRef, h)
+		klog.Infof("importing signature of %s to %v", image.Canonical, sigRef)
+		if err := remote.Write(sigRef, sigImage, options...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importFile(ctx context.Context, cluster *kops.Cluster, layoutPath layout.Path, file *BundleFile, target string) error {
+	if targetFileMatches(target, file.SHA) {
+		klog.Infof("no need to import file %q", target)
+		return nil
+	}
+
+	h, err := v1.NewHash(file.Digest)
+	if err != nil {
+		return fmt.Errorf("parsing digest %q: %v", file.Digest, err)
+	}
+
+	data, err := layoutPath.Bytes(h)
+	if err != nil {
+		return fmt.Errorf("error reading file from bundle: %w", err)
+	}
+	digest, _, err := v1.SHA256(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if digest != h {
+		return fmt.Errorf("bundle is corrupt: digest of file is %s, expected %s", digest, h)
+	}
+
+	return uploadFile(ctx, cluster, data, file.Canonical, target, file.SHA)
+}
+
+// writeTar writes the contents of dir to out as a tarball
+func writeTar(out io.Writer, dir string) error {
+	tw := tar.NewWriter(out)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	return nil
+}
+
+// extractTar extracts the regular files and directories of a tarball into dir
+func extractTar(in io.Reader, dir string) error {
+	tr := tar.NewReader(in)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading bundle: %w", err)
+		}
+
+		rel := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in bundle", header.Name)
+		}
+		p := filepath.Join(dir, rel)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return fmt.Errorf("error extracting %q from bundle: %w", header.Name, err)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		default:
+			klog.Warningf("ignoring %q in bundle, of type %v", header.Name, header.Typeflag)
+		}
+	}
+}
+
+// verifySHA checks that the data of the file from source matches the sha
+func verifySHA(data []byte, source string, sha string) error {
+	shaHash, err := hashing.FromString(strings.TrimSpace(sha))
+	if err != nil {
+		return fmt.Errorf("unable to parse sha: %q, %v", sha, err)
+	}
+	dataHash, err := shaHash.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to hash file %q downloaded: %v", source, err)
+	}
+	if !shaHash.Equal(dataHash) {
+		return fmt.Errorf("the sha value %q does not match %q calculated value %q", sha, source, dataHash.String())
+	}
+	return nil
+}
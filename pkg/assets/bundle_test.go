@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func TestBundle_Files(t *testing.T) {
+	ctx := context.TODO()
+
+	sourceDir := t.TempDir()
+	repoDir := t.TempDir()
+
+	data := []byte("kubelet binary")
+	source := filepath.Join(sourceDir, "release", "v1.26.0", "kubelet")
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sha, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileAssets := []*FileAsset{
+		{CanonicalURL: sourceURL, DownloadURL: sourceURL, SHAValue: sha.Hex()},
+		{CanonicalURL: sourceURL, DownloadURL: sourceURL, SHAValue: sha.Hex()},
+	}
+
+	var bundle bytes.Buffer
+	if err := WriteBundle(ctx, &bundle, nil, fileAssets); err != nil {
+		t.Fatalf("error writing bundle: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.KubernetesVersion = "1.26.0"
+	cluster.Spec.Assets = &kops.AssetsSpec{FileRepository: &repoDir}
+
+	if err := ImportBundle(ctx, bytes.NewReader(bundle.Bytes()), cluster); err != nil {
+		t.Fatalf("error importing bundle: %v", err)
+	}
+
+	target := filepath.Join(repoDir, source)
+	actual, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("error reading imported file: %v", err)
+	}
+	if !bytes.Equal(actual, data) {
+		t.Errorf("unexpected imported file %q", actual)
+	}
+	actualSHA, err := os.ReadFile(target + ".sha256")
+	if err != nil {
+		t.Fatalf("error reading imported sha: %v", err)
+	}
+	if string(actualSHA) != sha.Hex() {
+		t.Errorf("unexpected imported sha %q", actualSHA)
+	}
+
+	// Importing again skips the files that are already present
+	if err := ImportBundle(ctx, bytes.NewReader(bundle.Bytes()), cluster); err != nil {
+		t.Fatalf("error importing bundle again: %v", err)
+	}
+
+	cluster.Spec.Assets = nil
+	err = ImportBundle(ctx, bytes.NewReader(bundle.Bytes()), cluster)
+	if err == nil || !strings.Contains(err.Error(), "assets.fileRepository must be set") {
+		t.Errorf("expected error without a file repository, got %v", err)
+	}
+}
+
+func TestBundle_WrongSHA(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "kubelet")
+	if err := os.WriteFile(source, []byte("kubelet binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileAssets := []*FileAsset{
+		{CanonicalURL: sourceURL, DownloadURL: sourceURL, SHAValue: strings.Repeat("0", 64)},
+	}
+
+	var bundle bytes.Buffer
+	err = WriteBundle(context.TODO(), &bundle, nil, fileAssets)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected sha mismatch error, got %v", err)
+	}
+}
+
+func TestExtractTar_InvalidPath(t *testing.T) {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape", Typeflag: tar.TypeReg, Size: 1, Mode: 0o644}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := extractTar(&b, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+}
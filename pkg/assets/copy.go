@@ -31,12 +31,22 @@ type assetTask interface {
 func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, cluster *kops.Cluster) error {
 	tasks := map[string]assetTask{}
 
+	var verifier *ImageVerifier
+	if cluster.Spec.Assets != nil {
+		v, err := NewImageVerifier(cluster.Spec.Assets.ImageVerification)
+		if err != nil {
+			return err
+		}
+		verifier = v
+	}
+
 	for _, imageAsset := range imageAssets {
 		if imageAsset.DownloadLocation != imageAsset.CanonicalLocation {
 			copyImageTask := &CopyImage{
 				Name:        imageAsset.DownloadLocation,
 				SourceImage: imageAsset.CanonicalLocation,
 				TargetImage: imageAsset.DownloadLocation,
+				Verifier:    verifier,
 			}
 
 			if existing, ok := tasks[copyImageTask.Name]; ok {
@@ -57,6 +67,7 @@ func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, cluster *kops.Clus
 				SourceFile: fileAsset.CanonicalURL.String(),
 				SHA:        fileAsset.SHAValue,
 				Cluster:    cluster,
+				Signature:  verifier.MatchesTarball(fileAsset.CanonicalURL.String()),
 			}
 
 			if existing, ok := tasks[copyFileTask.Name]; ok {
@@ -39,6 +39,8 @@ type CopyFile struct {
 	TargetFile string
 	SHA        string
 	Cluster    *kops.Cluster
+	// Signature is true if the cosign sign-blob signature next to the source file is copied too, when there is one
+	Signature bool
 }
 
 // fileExtensionForSHA returns the expected extension for the given hash
@@ -57,14 +59,68 @@ func fileExtensionForSHA(sha string) (string, error) {
 func (e *CopyFile) Run() error {
 	ctx := context.TODO()
 
-	expectedSHA := strings.TrimSpace(e.SHA)
+	if _, err := fileExtensionForSHA(strings.TrimSpace(e.SHA)); err != nil {
+		return err
+	}
 
-	shaExtension, err := fileExtensionForSHA(expectedSHA)
+	if e.Signature {
+		if err := copyBlobSignature(ctx, e.Cluster, e.SourceFile, e.TargetFile); err != nil {
+			return err
+		}
+	}
+
+	if targetFileMatches(e.TargetFile, e.SHA) {
+		return nil
+	}
+
+	source := e.SourceFile
+	target := e.TargetFile
+	sourceSha := e.SHA
+
+	klog.V(2).Infof("copying bits from %q to %q", source, target)
+
+	if err := transferFile(ctx, e.Cluster, source, target, sourceSha); err != nil {
+		return fmt.Errorf("unable to transfer %q to %q: %v", source, target, err)
+	}
+
+	return nil
+}
+
+// copyBlobSignature copies the cosign sign-blob signature of the source file next to the target file, so that nodes can verify the copy.
+// Only image tarballs need a signature, so files without one are skipped.
+func copyBlobSignature(ctx context.Context, cluster *kops.Cluster, source string, target string) error {
+	signature, err := vfs.Context.ReadFile(source + blobSignatureSuffix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			klog.V(2).Infof("no signature found for %q", source)
+			return nil
+		}
+		return fmt.Errorf("error downloading signature of %q: %v", source, err)
+	}
+
+	objectStore, err := buildVFSPath(target + blobSignatureSuffix)
 	if err != nil {
 		return err
 	}
+	p, err := vfs.Context.BuildVfsPath(objectStore)
+	if err != nil {
+		return fmt.Errorf("error building path %q: %v", objectStore, err)
+	}
 
-	targetSHAFile := e.TargetFile + shaExtension
+	klog.Infof("uploading signature of %q to %q", source, objectStore)
+	return writeFile(ctx, cluster, p, signature)
+}
+
+// targetFileMatches returns true if the SHA file next to the target file matches the expected SHA
+func targetFileMatches(targetFile string, sha string) bool {
+	expectedSHA := strings.TrimSpace(sha)
+
+	shaExtension, err := fileExtensionForSHA(expectedSHA)
+	if err != nil {
+		return false
+	}
+
+	targetSHAFile := targetFile + shaExtension
 
 	targetSHABytes, err := vfs.Context.ReadFile(targetSHAFile)
 	if err != nil {
@@ -74,28 +130,18 @@ func (e *CopyFile) Run() error {
 		} else {
 			klog.V(4).Infof("unable to download: %q, %v", targetSHAFile, err)
 		}
-	} else {
-		targetSHA := string(targetSHABytes)
-
-		if strings.TrimSpace(targetSHA) == expectedSHA {
-			klog.V(8).Infof("found matching target sha for file: %q", e.TargetFile)
-			return nil
-		}
-
-		klog.V(8).Infof("did not find same file, found mismatching target sha1 for file: %q", e.TargetFile)
+		return false
 	}
 
-	source := e.SourceFile
-	target := e.TargetFile
-	sourceSha := e.SHA
+	targetSHA := string(targetSHABytes)
 
-	klog.V(2).Infof("copying bits from %q to %q", source, target)
-
-	if err := transferFile(ctx, e.Cluster, source, target, sourceSha); err != nil {
-		return fmt.Errorf("unable to transfer %q to %q: %v", source, target, err)
+	if strings.TrimSpace(targetSHA) == expectedSHA {
+		klog.V(8).Infof("found matching target sha for file: %q", targetFile)
+		return true
 	}
 
-	return nil
+	klog.V(8).Infof("did not find same file, found mismatching target sha1 for file: %q", targetFile)
+	return false
 }
 
 // transferFile downloads a file from the source location, validates the file matches the SHA,
@@ -113,6 +159,11 @@ func transferFile(ctx context.Context, cluster *kops.Cluster, source string, tar
 		return fmt.Errorf("error downloading file %q: %v", source, err)
 	}
 
+	return uploadFile(ctx, cluster, data, source, target, sha)
+}
+
+// uploadFile validates the file matches the SHA, and uploads the file and its SHA to the target location.
+func uploadFile(ctx context.Context, cluster *kops.Cluster, data []byte, source string, target string, sha string) error {
 	objectStore, err := buildVFSPath(target)
 	if err != nil {
 		return err
@@ -17,7 +17,15 @@ limitations under the License.
 package assets
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
 	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 func Test_BuildVFSPath(t *testing.T) {
@@ -80,3 +88,54 @@ func Test_BuildVFSPath(t *testing.T) {
 		}
 	}
 }
+
+func TestCopyFileCopiesSignature(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	writeFile := func(location string, data []byte) {
+		p, err := vfs.Context.BuildVfsPath(location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+			t.Fatalf("error writing %s: %v", location, err)
+		}
+	}
+
+	data := []byte("image tarball")
+	hash := sha256.Sum256(data)
+	writeFile("memfs://source/kube-apiserver.tar", data)
+	writeFile("memfs://source/kube-apiserver.tar.sig", []byte("signature"))
+	writeFile("memfs://source/kube-proxy.tar", data)
+
+	for _, name := range []string{"kube-apiserver.tar", "kube-proxy.tar"} {
+		copyFile := &CopyFile{
+			Name:       name,
+			SourceFile: "memfs://source/" + name,
+			TargetFile: "memfs://target/" + name,
+			SHA:        hex.EncodeToString(hash[:]),
+			Cluster:    &kops.Cluster{},
+			Signature:  true,
+		}
+		if err := copyFile.Run(); err != nil {
+			t.Fatalf("error copying %s: %v", name, err)
+		}
+	}
+
+	signature, err := vfs.Context.ReadFile("memfs://target/kube-apiserver.tar.sig")
+	if err != nil {
+		t.Fatalf("error reading copied signature: %v", err)
+	}
+	if string(signature) != "signature" {
+		t.Errorf("unexpected signature %q", signature)
+	}
+
+	// Files without a signature are copied without one
+	if _, err := vfs.Context.ReadFile("memfs://target/kube-proxy.tar.sig"); !os.IsNotExist(err) {
+		t.Errorf("expected no signature for kube-proxy.tar, got %v", err)
+	}
+	if _, err := vfs.Context.ReadFile("memfs://target/kube-proxy.tar"); err != nil {
+		t.Errorf("error reading copied file: %v", err)
+	}
+}
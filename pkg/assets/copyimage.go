@@ -17,6 +17,7 @@ limitations under the License.
 package assets
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -32,6 +33,8 @@ type CopyImage struct {
 	Name        string
 	SourceImage string
 	TargetImage string
+	// Verifier verifies the signature of the source image, and copies it with the image, when set
+	Verifier *ImageVerifier
 }
 
 func (e *CopyImage) Run() error {
@@ -55,22 +58,38 @@ func (e *CopyImage) Run() error {
 		return fmt.Errorf("fetching %q: %v", source, err)
 	}
 
+	verify := e.Verifier.Matches(source)
+	if verify {
+		digest, err := e.Verifier.VerifyImage(context.TODO(), source)
+		if err != nil {
+			return err
+		}
+		if digest != desc.Digest {
+			return fmt.Errorf("image %q changed from %s to %s while being verified", source, digest, desc.Digest)
+		}
+	}
+
 	targetDesc, err := remote.Get(targetRef, options...)
 	if err == nil && desc.Digest.String() == targetDesc.Digest.String() {
 		klog.Infof("no need to copy image from %v to %v", sourceRef, targetRef)
-		return nil
+	} else {
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			// Handle indexes separately.
+			if err := copyIndex(desc, sourceRef, targetRef, options...); err != nil {
+				return fmt.Errorf("failed to copy index: %v", err)
+			}
+		default:
+			// Assume anything else is an image, since some registries don't set mediaTypes properly.
+			if err := copyImage(desc, sourceRef, targetRef, options...); err != nil {
+				return fmt.Errorf("failed to copy image: %v", err)
+			}
+		}
 	}
 
-	switch desc.MediaType {
-	case types.OCIImageIndex, types.DockerManifestList:
-		// Handle indexes separately.
-		if err := copyIndex(desc, sourceRef, targetRef, options...); err != nil {
-			return fmt.Errorf("failed to copy index: %v", err)
-		}
-	default:
-		// Assume anything else is an image, since some registries don't set mediaTypes properly.
-		if err := copyImage(desc, sourceRef, targetRef, options...); err != nil {
-			return fmt.Errorf("failed to copy image: %v", err)
+	if verify {
+		if err := copySignature(sourceRef, targetRef, desc.Digest, options...); err != nil {
+			return fmt.Errorf("failed to copy signature: %v", err)
 		}
 	}
 
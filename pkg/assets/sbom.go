@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/sbom"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+// sbomComment describes the split between the assets document and the documents that nodeup writes on the nodes
+const sbomComment = "Lists the file and image assets of the cluster, and the operating system packages that nodeup installs on the nodes of each instance group. " +
+	"The versions of the packages are resolved from the package repositories of the distribution when they are installed; " +
+	"run nodeup --sbom on a node of an instance group to list them with their installed versions."
+
+// BuildSBOM builds a software bill of materials listing the image and file assets of a cluster,
+// and the operating system packages that nodeup installs on the nodes of its instance groups.
+// If resolveDigests is true, the digests of images that are not pinned by digest are looked up in their registries.
+func BuildSBOM(ctx context.Context, name string, imageAssets []*ImageAsset, fileAssets []*FileAsset, instanceGroups []*kops.InstanceGroup, resolveDigests bool) *sbom.Document {
+	doc := sbom.NewDocument(name)
+	doc.Comment = sbomComment
+
+	seen := map[string]bool{}
+	for _, fileAsset := range fileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		doc.Components = append(doc.Components, sbom.FileComponent(canonical, fileAsset.DownloadURL.String(), fileAsset.SHAValue))
+	}
+
+	seen = map[string]bool{}
+	for _, imageAsset := range imageAssets {
+		if seen[imageAsset.CanonicalLocation] {
+			continue
+		}
+		seen[imageAsset.CanonicalLocation] = true
+
+		var digest string
+		if resolveDigests && !strings.Contains(imageAsset.CanonicalLocation, "@") {
+			d, err := crane.Digest(imageAsset.CanonicalLocation, crane.WithAuthFromKeychain(authn.DefaultKeychain), crane.WithContext(ctx))
+			if err != nil {
+				klog.Warningf("unable to determine digest of image %q: %v", imageAsset.CanonicalLocation, err)
+			} else {
+				digest = d
+			}
+		}
+
+		doc.Components = append(doc.Components, sbom.ImageComponent(imageAsset.CanonicalLocation, imageAsset.DownloadLocation, digest))
+	}
+
+	instanceGroups = append([]*kops.InstanceGroup(nil), instanceGroups...)
+	sort.Slice(instanceGroups, func(i, j int) bool {
+		return instanceGroups[i].ObjectMeta.Name < instanceGroups[j].ObjectMeta.Name
+	})
+	for _, ig := range instanceGroups {
+		comment := fmt.Sprintf("Installed by nodeup on the nodes of instance group %q", ig.ObjectMeta.Name)
+
+		distribution, found := distributions.FindDistributionForImage(ig.Spec.Image)
+		if !found || distribution.PackageFormat() == "" {
+			// The distribution of the image is only known on the node, so the additional packages are listed by name
+			for _, p := range ig.Spec.Packages {
+				doc.Components = append(doc.Components, &sbom.Component{
+					Type:    sbom.ComponentTypePackage,
+					Name:    p,
+					PURL:    "pkg:generic/" + url.PathEscape(p),
+					Comment: comment + "; the distribution of the image is not known",
+				})
+			}
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, p := range append(distribution.Packages(), ig.Spec.Packages...) {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+
+			c := sbom.PackageComponent(distribution.PackageFormat(), distribution.Project(), p, "")
+			c.Version = sbom.VersionDistroResolved
+			c.Comment = comment + "; the version is resolved from the package repositories of " + distribution.Project() + " when it is installed"
+			doc.Components = append(doc.Components, c)
+		}
+	}
+
+	return doc
+}
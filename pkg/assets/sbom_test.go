@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/sbom"
+)
+
+func TestBuildSBOM(t *testing.T) {
+	kubelet, _ := url.Parse("https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kubelet")
+	fileAssets := []*FileAsset{
+		{DownloadURL: kubelet, CanonicalURL: kubelet, SHAValue: strings.Repeat("a", 64)},
+		{DownloadURL: kubelet, CanonicalURL: kubelet, SHAValue: strings.Repeat("a", 64)},
+	}
+	imageAssets := []*ImageAsset{
+		{DownloadLocation: "registry.k8s.io/pause:3.9", CanonicalLocation: "registry.k8s.io/pause:3.9"},
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+			Spec:       kops.InstanceGroupSpec{Packages: []string{"nfs-common"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "control-plane"},
+			Spec: kops.InstanceGroupSpec{
+				Image:    "099720109477/ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20240207",
+				Packages: []string{"htop", "nfs-common", "socat"},
+			},
+		},
+	}
+
+	doc := BuildSBOM(context.Background(), "minimal.example.com", imageAssets, fileAssets, instanceGroups, false)
+
+	if !strings.Contains(doc.Comment, "nodeup --sbom") {
+		t.Errorf("expected the document to refer to nodeup --sbom, got %q", doc.Comment)
+	}
+
+	var actual []string
+	for _, c := range doc.Components {
+		actual = append(actual, string(c.Type)+" "+c.Name+" "+c.Version+" "+c.PURL)
+	}
+	expected := []string{
+		"file kubelet v1.26.0 pkg:generic/kubelet@v1.26.0?download_url=https%3A%2F%2Fdl.k8s.io%2Frelease%2Fv1.26.0%2Fbin%2Flinux%2Famd64%2Fkubelet",
+		"container registry.k8s.io/pause 3.9 pkg:oci/pause?repository_url=registry.k8s.io%2Fpause&tag=3.9",
+	}
+	for _, p := range []string{"bridge-utils", "cgroupfs-mount", "conntrack", "ebtables", "ethtool", "iptables", "libapparmor1", "libseccomp2", "libltdl7", "pigz", "socat", "util-linux", "htop", "nfs-common"} {
+		expected = append(expected, "package "+p+" distro-resolved pkg:deb/ubuntu/"+p)
+	}
+	expected = append(expected, "package nfs-common  pkg:generic/nfs-common")
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected components:\n%s\nexpected:\n%s", strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+	}
+	if !strings.Contains(doc.Components[2].Comment, `instance group "control-plane"`) {
+		t.Errorf("unexpected package component comment %q", doc.Components[2].Comment)
+	}
+	if last := doc.Components[len(doc.Components)-1]; last.Type != sbom.ComponentTypePackage || !strings.Contains(last.Comment, `instance group "nodes"`) {
+		t.Errorf("unexpected package component %+v", last)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// cosignSignatureAnnotation is the annotation holding the signature of a cosign signature layer
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// blobSignatureSuffix is the suffix of the file holding the cosign sign-blob signature of a file
+const blobSignatureSuffix = ".sig"
+
+// ImageVerifier verifies the cosign signatures of images, with offline public keys.
+// Signatures are not checked against a transparency log.
+type ImageVerifier struct {
+	publicKeys []crypto.PublicKey
+	images     []string
+	tarballs   []string
+}
+
+// NewImageVerifier builds a verifier for the spec; it returns nil if the spec is nil.
+func NewImageVerifier(spec *kops.ImageVerificationSpec) (*ImageVerifier, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	v := &ImageVerifier{
+		images:   spec.Images,
+		tarballs: spec.Tarballs,
+	}
+	for _, data := range spec.PublicKeys {
+		key, err := pki.ParsePEMPublicKey([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing image verification public key: %w", err)
+		}
+		switch key.Key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported image verification public key type %T", key.Key)
+		}
+		v.publicKeys = append(v.publicKeys, key.Key)
+	}
+	if len(v.publicKeys) == 0 {
+		return nil, fmt.Errorf("no public keys to verify images with")
+	}
+
+	return v, nil
+}
+
+// Matches returns true if the image with the canonical location must be verified
+func (v *ImageVerifier) Matches(canonical string) bool {
+	if v == nil {
+		return false
+	}
+	if len(v.images) == 0 {
+		return true
+	}
+	for _, prefix := range v.images {
+		if strings.HasPrefix(canonical, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesTarball returns true if the image tarball at the URL must be verified with its cosign sign-blob signature.
+// Unlike images, tarballs are only verified when they are listed: their signature is a separate artifact,
+// and the published signatures of the Kubernetes release tarballs are keyless, so they cannot be verified with the public keys.
+func (v *ImageVerifier) MatchesTarball(tarballURL string) bool {
+	if v == nil {
+		return false
+	}
+	for _, prefix := range v.tarballs {
+		if strings.HasPrefix(tarballURL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyImage checks that the image has a cosign signature by one of the public keys, and returns its digest.
+func (v *ImageVerifier) VerifyImage(ctx context.Context, image string) (v1.Hash, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("parsing reference %q: %v", image, err)
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx)}
+
+	desc, err := remote.Head(ref, options...)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("fetching %q: %v", image, err)
+	}
+
+	sigRef := signatureRef(ref, desc.Digest)
+	sigImage, err := remote.Image(sigRef, options...)
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("fetching signature of %q from %v: %v", image, sigRef, err)
+	}
+
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return v1.Hash{}, fmt.Errorf("reading signature of %q: %v", image, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, found := layer.Annotations[cosignSignatureAnnotation]
+		if !found {
+			continue
+		}
+
+		l, err := sigImage.LayerByDigest(layer.Digest)
+		if err != nil {
+			return v1.Hash{}, err
+		}
+		payload, err := readLayer(l)
+		if err != nil {
+			return v1.Hash{}, fmt.Errorf("reading signature payload of %q: %v", image, err)
+		}
+
+		if err := v.verifySignature(payload, signature); err != nil {
+			klog.V(2).Infof("ignoring signature of %q: %v", image, err)
+			continue
+		}
+
+		signedDigest, err := payloadDigest(payload)
+		if err != nil {
+			klog.V(2).Infof("ignoring signature of %q: %v", image, err)
+			continue
+		}
+		if signedDigest != desc.Digest.String() {
+			klog.V(2).Infof("ignoring signature of %q for digest %s, expected %s", image, signedDigest, desc.Digest)
+			continue
+		}
+
+		klog.V(2).Infof("verified signature of %q with digest %s", image, desc.Digest)
+		return desc.Digest, nil
+	}
+
+	return v1.Hash{}, fmt.Errorf("image %q with digest %s has no signature by the configured public keys", image, desc.Digest)
+}
+
+// ReadBlobSignature reads the signature that cosign sign-blob wrote for the file, stored next to it with a .sig suffix
+func ReadBlobSignature(fileURL string) (string, error) {
+	signature, err := vfs.Context.ReadFile(fileURL + blobSignatureSuffix)
+	if err != nil {
+		return "", fmt.Errorf("error reading signature of %s: %w", fileURL, err)
+	}
+	return strings.TrimSpace(string(signature)), nil
+}
+
+// VerifyBlob checks the signature of a file, as written by cosign sign-blob
+func (v *ImageVerifier) VerifyBlob(data []byte, signature []byte) error {
+	return v.verifySignature(data, string(signature))
+}
+
+// verifySignature checks that the base64-encoded signature of the payload was made by one of the public keys
+func (v *ImageVerifier) verifySignature(payload []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %w", err)
+	}
+
+	digest := sha256.Sum256(payload)
+	for _, key := range v.publicKeys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, digest[:], sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, sig) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("signature does not match any of the public keys")
+}
+
+// payloadDigest returns the image digest from a cosign simple signing payload
+func payloadDigest(payload []byte) (string, error) {
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return "", fmt.Errorf("error parsing signature payload: %w", err)
+	}
+	return simpleSigning.Critical.Image.DockerManifestDigest, nil
+}
+
+// signatureRef returns the tag of the cosign signature of the image with the digest
+func signatureRef(ref name.Reference, digest v1.Hash) name.Tag {
+	return ref.Context().Tag(digest.Algorithm + "-" + digest.Hex + ".sig")
+}
+
+// copySignature copies the cosign signature of the image with the digest, so that the copy can be verified
+func copySignature(sourceRef name.Reference, targetRef name.Reference, digest v1.Hash, options ...remote.Option) error {
+	sourceSig := signatureRef(sourceRef, digest)
+	targetSig := signatureRef(targetRef, digest)
+
+	sigImage, err := remote.Image(sourceSig, options...)
+	if err != nil {
+		return fmt.Errorf("fetching signature %v: %v", sourceSig, err)
+	}
+
+	klog.Infof("copying signature from %v to %v", sourceSig, targetSig)
+	return remote.Write(targetSig, sigImage, options...)
+}
+
+func readLayer(l v1.Layer) ([]byte, error) {
+	rc, err := l.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
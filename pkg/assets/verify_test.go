@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func encodePublicKey(t *testing.T, key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("error marshaling public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestImageVerifier_VerifyBlob(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519Public, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("image tarball")
+	digest := sha256.Sum256(data)
+
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519Signature := ed25519.Sign(ed25519Key, data)
+	otherSignature, err := ecdsa.SignASN1(rand.Reader, otherKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewImageVerifier(&kops.ImageVerificationSpec{
+		PublicKeys: []string{encodePublicKey(t, &ecdsaKey.PublicKey), encodePublicKey(t, ed25519Public)},
+	})
+	if err != nil {
+		t.Fatalf("error building verifier: %v", err)
+	}
+
+	grid := []struct {
+		name      string
+		data      []byte
+		signature []byte
+		valid     bool
+	}{
+		{
+			name:      "ecdsa",
+			data:      data,
+			signature: ecdsaSignature,
+			valid:     true,
+		},
+		{
+			name:      "ed25519",
+			data:      data,
+			signature: ed25519Signature,
+			valid:     true,
+		},
+		{
+			name:      "other key",
+			data:      data,
+			signature: otherSignature,
+		},
+		{
+			name:      "changed data",
+			data:      []byte("other image tarball"),
+			signature: ecdsaSignature,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			signature := []byte(base64.StdEncoding.EncodeToString(g.signature) + "\n")
+			err := verifier.VerifyBlob(g.data, signature)
+			if g.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !g.valid && err == nil {
+				t.Errorf("expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestImageVerifier_Matches(t *testing.T) {
+	var nilVerifier *ImageVerifier
+	if nilVerifier.Matches("registry.k8s.io/pause:3.9") {
+		t.Errorf("nil verifier should not match any image")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := encodePublicKey(t, &key.PublicKey)
+
+	all, err := NewImageVerifier(&kops.ImageVerificationSpec{PublicKeys: []string{publicKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !all.Matches("registry.k8s.io/pause:3.9") {
+		t.Errorf("verifier without prefixes should match all images")
+	}
+
+	prefixed, err := NewImageVerifier(&kops.ImageVerificationSpec{
+		PublicKeys: []string{publicKey},
+		Images:     []string{"registry.k8s.io/kops/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prefixed.Matches("registry.k8s.io/kops/kops-controller:1.26.0") {
+		t.Errorf("expected image with prefix to match")
+	}
+	if prefixed.Matches("registry.k8s.io/pause:3.9") {
+		t.Errorf("expected image without prefix not to match")
+	}
+
+	if all.MatchesTarball("https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar") {
+		t.Errorf("verifier without tarball prefixes should not match any tarball")
+	}
+
+	tarballs, err := NewImageVerifier(&kops.ImageVerificationSpec{
+		PublicKeys: []string{publicKey},
+		Tarballs:   []string{"https://artifacts.example.com/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tarballs.MatchesTarball("https://artifacts.example.com/kube-apiserver.tar") {
+		t.Errorf("expected tarball with prefix to match")
+	}
+	if tarballs.MatchesTarball("https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar") {
+		t.Errorf("expected tarball without prefix not to match")
+	}
+
+	if _, err := NewImageVerifier(&kops.ImageVerificationSpec{}); err == nil {
+		t.Errorf("expected error without public keys")
+	}
+	if _, err := NewImageVerifier(&kops.ImageVerificationSpec{PublicKeys: []string{"not a key"}}); err == nil {
+		t.Errorf("expected error for invalid public key")
+	}
+}
+
+func TestPayloadDigest(t *testing.T) {
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"registry.example.com/pause"},"image":{"docker-manifest-digest":"sha256:abcd"},"type":"cosign container image signature"},"optional":null}`)
+	digest, err := payloadDigest(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if digest != "sha256:abcd" {
+		t.Errorf("unexpected digest %q", digest)
+	}
+}
+
+func TestRemapImagePinsVerifiedImages(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An image with a cosign signature by the key, and an image without a signature
+	signed := u.Host + "/kops/kops-controller:1.26.0"
+	unsigned := u.Host + "/pause:3.9"
+	var signedDigest string
+	for _, image := range []string{signed, unsigned} {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := random.Image(64, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(ref, img); err != nil {
+			t.Fatalf("error pushing %s: %v", image, err)
+		}
+		if image != signed {
+			continue
+		}
+
+		digest, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedDigest = digest.String()
+
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, ref.Context().String(), digest))
+		payloadDigest := sha256.Sum256(payload)
+		signature, err := ecdsa.SignASN1(rand.Reader, key, payloadDigest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigImage, err := mutate.Append(empty.Image, mutate.Addendum{
+			Layer: static.NewLayer(payload, types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json")),
+			Annotations: map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := remote.Write(signatureRef(ref, digest), sigImage); err != nil {
+			t.Fatalf("error pushing signature of %s: %v", image, err)
+		}
+	}
+
+	publicKey := encodePublicKey(t, &key.PublicKey)
+
+	grid := []struct {
+		name     string
+		images   []string
+		image    string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "signed image is pinned",
+			image:    signed,
+			expected: signed + "@" + signedDigest,
+		},
+		{
+			name:     "pinned image is kept",
+			image:    signed + "@" + signedDigest,
+			expected: signed + "@" + signedDigest,
+		},
+		{
+			name:  "unsigned image is rejected",
+			image: unsigned,
+			err:   true,
+		},
+		{
+			// The image does not exist, so it would fail verification
+			name:     "unverified image is not checked",
+			images:   []string{u.Host + "/kops/"},
+			image:    u.Host + "/missing:1.0",
+			expected: u.Host + "/missing:1.0",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier, err := NewImageVerifier(&kops.ImageVerificationSpec{
+				PublicKeys: []string{publicKey},
+				Images:     g.images,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			builder := &AssetBuilder{ImageVerifier: verifier}
+
+			actual, err := builder.RemapImage(g.image)
+			if g.err {
+				if err == nil {
+					t.Errorf("expected error remapping %s", g.image)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != g.expected {
+				t.Errorf("expected %s, got %s", g.expected, actual)
+			}
+		})
+	}
+}
@@ -55,6 +55,12 @@ func (c *RESTClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return nil
 }
 
+// HistoryFor fetches the HistoryClient for the cluster
+func (c *RESTClientset) HistoryFor(cluster *kops.Cluster) (simple.HistoryClient, error) {
+	// Revision history is only recorded by the VFS state store
+	return nil, fmt.Errorf("configuration history is not supported by the kubernetes-API state store")
+}
+
 // CreateCluster implements the CreateCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
 	namespace := restNamespaceForClusterName(cluster.Name)
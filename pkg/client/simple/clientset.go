@@ -18,6 +18,7 @@ package simple
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
@@ -60,6 +61,9 @@ type Clientset interface {
 
 	// DeleteCluster deletes all the state for the specified cluster
 	DeleteCluster(ctx context.Context, cluster *kops.Cluster) error
+
+	// HistoryFor returns the client for the revision history of a particular Cluster's configuration
+	HistoryFor(cluster *kops.Cluster) (HistoryClient, error)
 }
 
 // AddonsClient is a client for manipulating cluster addons
@@ -71,3 +75,45 @@ type AddonsClient interface {
 	// List returns all the addon objects
 	List(ctx context.Context) (kubemanifest.ObjectList, error)
 }
+
+// HistoryClient is a client for the revision history of a cluster's configuration.
+// A revision is recorded every time the Cluster, its InstanceGroups or its addons are written.
+type HistoryClient interface {
+	// List returns the recorded revisions, oldest first
+	List(ctx context.Context) ([]*ClusterRevision, error)
+
+	// Get returns the configuration recorded at the specified revision
+	Get(ctx context.Context, revision int) (*ClusterSnapshot, error)
+
+	// Record records the current configuration as a new revision, if it changed since the latest revision
+	Record(ctx context.Context, reason string) error
+
+	// Batch runs fn, recording the writes it makes as a single revision instead of a revision per write.
+	// If fn fails, the configuration it left behind is recorded with the reason marked as incomplete.
+	Batch(ctx context.Context, reason string, fn func() error) error
+}
+
+// ClusterRevision describes a recorded revision of a cluster's configuration
+type ClusterRevision struct {
+	// Revision is the sequence number of the revision, starting at 1
+	Revision int `json:"revision"`
+	// Timestamp is when the revision was recorded
+	Timestamp time.Time `json:"timestamp"`
+	// User is the local user and host that made the change, if known
+	User string `json:"user,omitempty"`
+	// Reason describes the write that caused the revision, e.g. "update InstanceGroup nodes"
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClusterSnapshot is the configuration of a cluster at a particular revision
+type ClusterSnapshot struct {
+	ClusterRevision
+
+	Cluster           *kops.Cluster
+	InstanceGroups    []*kops.InstanceGroup
+	AdditionalObjects kubemanifest.ObjectList
+
+	// CompletedCluster is the completed cluster spec last written by kops update cluster, if any.
+	// It is derived from the other objects, so it is recorded for reference but never restored.
+	CompletedCluster *kops.Cluster
+}
@@ -37,6 +37,7 @@ type vfsAddonsClient struct {
 
 	clusterName string
 	cluster     *kops.Cluster
+	history     *vfsHistoryClient
 }
 
 var _ simple.AddonsClient = &vfsAddonsClient{}
@@ -51,6 +52,7 @@ func newAddonsVFS(c *VFSClientset, cluster *kops.Cluster) *vfsAddonsClient {
 	r := &vfsAddonsClient{
 		cluster:     cluster,
 		clusterName: clusterName,
+		history:     newHistoryVFS(c, cluster),
 	}
 	r.basePath = c.basePath.Join(clusterName, "clusteraddons")
 
@@ -88,11 +90,15 @@ func (c *vfsAddonsClient) Replace(addons kubemanifest.ObjectList) error {
 		return err
 	}
 
+	c.history.ensureBaseline(ctx)
+
 	rs := bytes.NewReader(b)
 	if err := configPath.WriteFile(ctx, rs, acl); err != nil {
 		return fmt.Errorf("error writing addons file %s: %v", configPath, err)
 	}
 
+	c.history.record(ctx, "replace addons")
+
 	return nil
 }
 
@@ -80,6 +80,11 @@ func (c *VFSClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsVFS(c, cluster)
 }
 
+// HistoryFor implements the HistoryFor method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) HistoryFor(cluster *kops.Cluster) (simple.HistoryClient, error) {
+	return newHistoryVFS(c, cluster), nil
+}
+
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	if cluster.Spec.SecretStore == "" {
 		configBase, err := registry.ConfigBase(cluster)
@@ -143,7 +148,7 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		}
 
 		// "cluster.spec" was written by kOps 1.21 and earlier.
-		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated {
+		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated || relativePath == registry.PathApplyLock {
 			continue
 		}
 		if strings.HasPrefix(relativePath, "addons/") {
@@ -167,6 +172,12 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
+		if strings.HasPrefix(relativePath, registry.PathPolicies+"/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, historyDir+"/") {
+			continue
+		}
 		// TODO: offer an option _not_ to delete backups?
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
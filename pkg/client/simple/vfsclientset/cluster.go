@@ -123,6 +123,8 @@ func (r *ClusterVFS) Create(c *api.Cluster) (*api.Cluster, error) {
 		return nil, fmt.Errorf("error writing Cluster %q: %v", c.ObjectMeta.Name, err)
 	}
 
+	newClusterHistory(r.basePath, c).record(ctx, "create Cluster")
+
 	return c, nil
 }
 
@@ -134,7 +136,7 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		return nil, field.Required(field.NewPath("objectMeta", "name"), "clusterName is required")
 	}
 
-	old, err := r.Get(clusterName, metav1.GetOptions{})
+	old, version, err := r.findVersion(ctx, clusterName)
 	if err != nil {
 		return nil, err
 	}
@@ -143,20 +145,32 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		return nil, errors.NewNotFound(schema.GroupResource{Group: api.GroupName, Resource: "Cluster"}, clusterName)
 	}
 
+	if err := r.checkGeneration(old, c); err != nil {
+		return nil, err
+	}
+
 	if err := validation.ValidateClusterUpdate(c, status, old).ToAggregate(); err != nil {
 		return nil, err
 	}
 
+	// The generation is bumped on a copy, so the caller's object is only changed once the write succeeds
+	updated := c.DeepCopy()
 	if !apiequality.Semantic.DeepEqual(old.Spec, c.Spec) {
-		c.SetGeneration(old.GetGeneration() + 1)
+		updated.SetGeneration(old.GetGeneration() + 1)
 	}
 
-	if err := r.writeConfig(ctx, c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+	history := newClusterHistory(r.basePath, c)
+	history.ensureBaseline(ctx)
+
+	if err := r.updateConfig(ctx, updated, r.basePath.Join(clusterName, registry.PathCluster), clusterName, updated, version); err != nil {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
 	}
+	*c = *updated
+
+	history.record(ctx, "update Cluster")
 
 	return c, nil
 }
@@ -185,17 +199,24 @@ func (r *ClusterVFS) listNames() ([]string, error) {
 }
 
 func (r *ClusterVFS) find(ctx context.Context, clusterName string) (*api.Cluster, error) {
+	c, _, err := r.findVersion(ctx, clusterName)
+	return c, err
+}
+
+// findVersion is like find, but also returns the version of the stored configuration
+// if the backing store supports conditional writes.
+func (r *ClusterVFS) findVersion(ctx context.Context, clusterName string) (*api.Cluster, string, error) {
 	if clusterName == "" {
-		return nil, fmt.Errorf("clusterName is required")
+		return nil, "", fmt.Errorf("clusterName is required")
 	}
 	configPath := r.basePath.Join(clusterName, registry.PathCluster)
 
-	o, err := r.readConfig(ctx, configPath)
+	o, version, err := r.readConfigVersion(ctx, configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return nil, "", nil
 		}
-		return nil, fmt.Errorf("error reading cluster configuration %q: %v", clusterName, err)
+		return nil, "", fmt.Errorf("error reading cluster configuration %q: %v", clusterName, err)
 	}
 
 	c := o.(*api.Cluster)
@@ -211,12 +232,12 @@ func (r *ClusterVFS) find(ctx context.Context, clusterName string) (*api.Cluster
 	if c.Spec.ConfigBase == "" {
 		configBase, err := r.configBase(clusterName)
 		if err != nil {
-			return nil, fmt.Errorf("error building ConfigBase for cluster: %v", err)
+			return nil, "", fmt.Errorf("error building ConfigBase for cluster: %v", err)
 		}
 		c.Spec.ConfigBase = configBase.Path()
 	}
 
-	return c, nil
+	return c, version, nil
 }
 
 func (r *ClusterVFS) Delete(name string, options *metav1.DeleteOptions) error {
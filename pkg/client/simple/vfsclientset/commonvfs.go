@@ -25,9 +25,11 @@ import (
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
@@ -114,19 +116,33 @@ func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
 }
 
 func (c *commonVFS) readConfig(ctx context.Context, configPath vfs.Path) (runtime.Object, error) {
-	data, err := configPath.ReadFile(ctx)
+	object, _, err := c.readConfigVersion(ctx, configPath)
+	return object, err
+}
+
+// readConfigVersion reads the object stored at configPath, along with the version of the file.
+// The version is empty if the backing store does not support conditional writes.
+func (c *commonVFS) readConfigVersion(ctx context.Context, configPath vfs.Path) (runtime.Object, string, error) {
+	var data []byte
+	var version string
+	var err error
+	if versioned, ok := configPath.(vfs.HasVersion); ok {
+		data, version, err = versioned.ReadFileVersion(ctx)
+	} else {
+		data, err = configPath.ReadFile(ctx)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, err
+			return nil, "", err
 		}
-		return nil, fmt.Errorf("error reading %s: %v", configPath, err)
+		return nil, "", fmt.Errorf("error reading %s: %v", configPath, err)
 	}
 
 	object, _, err := kopscodecs.Decode(data, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
+		return nil, "", fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
-	return object, nil
+	return object, version, nil
 }
 
 func (c *commonVFS) writeConfig(ctx context.Context, cluster *kops.Cluster, configPath vfs.Path, o runtime.Object, writeOptions ...vfs.WriteOption) error {
@@ -174,7 +190,49 @@ func (c *commonVFS) writeConfig(ctx context.Context, cluster *kops.Cluster, conf
 	return nil
 }
 
-func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime.Object) error {
+// updateConfig overwrites the existing configuration file at configPath.
+// If version is not empty, the write only succeeds if the file has not been modified since that version was read;
+// otherwise a Conflict error is returned.
+func (c *commonVFS) updateConfig(ctx context.Context, cluster *kops.Cluster, configPath vfs.Path, name string, o runtime.Object, version string) error {
+	versioned, ok := configPath.(vfs.HasVersion)
+	if !ok || version == "" {
+		return c.writeConfig(ctx, cluster, configPath, o, vfs.WriteOptionOnlyIfExists)
+	}
+
+	data, err := c.serialize(o)
+	if err != nil {
+		return fmt.Errorf("error marshaling object: %v", err)
+	}
+
+	acl, err := acls.GetACL(ctx, configPath, cluster)
+	if err != nil {
+		return err
+	}
+
+	if _, err := versioned.WriteFileIfVersion(ctx, bytes.NewReader(data), acl, version); err != nil {
+		if err == vfs.ErrVersionMismatch {
+			return c.newConflict(name, "the configuration file was modified while it was being updated")
+		}
+		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
+	}
+	return nil
+}
+
+// checkGeneration implements generation-based optimistic concurrency.
+// If the updated object carries a generation, it must match the generation in the state store,
+// otherwise the object was based on a stale read and the update is rejected.
+func (c *commonVFS) checkGeneration(old metav1.Object, updated metav1.Object) error {
+	if updated.GetGeneration() == 0 || updated.GetGeneration() == old.GetGeneration() {
+		return nil
+	}
+	return c.newConflict(updated.GetName(), fmt.Sprintf("the object is at generation %d in the state store, but the update was based on generation %d", old.GetGeneration(), updated.GetGeneration()))
+}
+
+func (c *commonVFS) newConflict(name string, reason string) error {
+	return errors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: c.kind}, name, fmt.Errorf("%s; please apply your changes to the latest version and try again", reason))
+}
+
+func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime.Object, version string) error {
 	objectMeta, err := meta.Accessor(i)
 	if err != nil {
 		return err
@@ -192,8 +250,11 @@ func (c *commonVFS) update(ctx context.Context, cluster *kops.Cluster, i runtime
 		objectMeta.SetCreationTimestamp(metav1.NewTime(time.Now().UTC()))
 	}
 
-	err = c.writeConfig(ctx, cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
+	err = c.updateConfig(ctx, cluster, c.basePath.Join(objectMeta.GetName()), objectMeta.GetName(), i, version)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
 
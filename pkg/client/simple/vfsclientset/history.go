@@ -0,0 +1,369 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/mutexes"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// historyDir is the directory, relative to the cluster's state, in which revisions are recorded
+	historyDir = "history"
+	// maxHistoryRevisions is the number of revisions we keep; older revisions are pruned
+	maxHistoryRevisions = 50
+
+	pathInstanceGroups = "instancegroup"
+	pathAddons         = "clusteraddons/default"
+)
+
+// batches holds the state paths of the clusters whose writes are being recorded as a single revision by Batch
+var batches sync.Map
+
+// historyRecord is the format in which a revision is stored.
+// Files holds the contents of each configuration file, keyed by its path relative to the cluster's state.
+type historyRecord struct {
+	simple.ClusterRevision
+	Files map[string]string `json:"files"`
+}
+
+type vfsHistoryClient struct {
+	clusterBase vfs.Path
+	cluster     *kops.Cluster
+}
+
+var _ simple.HistoryClient = &vfsHistoryClient{}
+
+func newHistoryVFS(c *VFSClientset, cluster *kops.Cluster) *vfsHistoryClient {
+	if cluster == nil || cluster.Name == "" {
+		klog.Fatalf("cluster / cluster.Name is required")
+	}
+
+	return newClusterHistory(c.basePath, cluster)
+}
+
+func newClusterHistory(basePath vfs.Path, cluster *kops.Cluster) *vfsHistoryClient {
+	return &vfsHistoryClient{
+		clusterBase: basePath.Join(cluster.Name),
+		cluster:     cluster,
+	}
+}
+
+// List implements HistoryClient::List
+func (h *vfsHistoryClient) List(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	revisions, err := h.listRevisions()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*simple.ClusterRevision
+	for _, revision := range revisions {
+		record, err := h.readRecord(ctx, revision)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Pruned concurrently
+				continue
+			}
+			return nil, err
+		}
+		list = append(list, &record.ClusterRevision)
+	}
+	return list, nil
+}
+
+// Get implements HistoryClient::Get
+func (h *vfsHistoryClient) Get(ctx context.Context, revision int) (*simple.ClusterSnapshot, error) {
+	record, err := h.readRecord(ctx, revision)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("revision %d not found in the history of cluster %q", revision, h.cluster.Name)
+		}
+		return nil, err
+	}
+
+	snapshot := &simple.ClusterSnapshot{
+		ClusterRevision: record.ClusterRevision,
+	}
+
+	var paths []string
+	for p := range record.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		data := []byte(record.Files[p])
+		switch {
+		case p == registry.PathClusterCompleted:
+			o, _, err := kopscodecs.Decode(data, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s in revision %d: %v", p, revision, err)
+			}
+			completed, ok := o.(*kops.Cluster)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object %T in %s in revision %d", o, p, revision)
+			}
+			snapshot.CompletedCluster = completed
+
+		case p == pathAddons:
+			objects, err := kubemanifest.LoadObjectsFrom(data)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing addons in revision %d: %v", revision, err)
+			}
+			snapshot.AdditionalObjects = objects
+
+		default:
+			o, _, err := kopscodecs.Decode(data, nil)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s in revision %d: %v", p, revision, err)
+			}
+			switch v := o.(type) {
+			case *kops.Cluster:
+				if v.Spec.ConfigBase == "" {
+					v.Spec.ConfigBase = h.clusterBase.Path()
+				}
+				snapshot.Cluster = v
+			case *kops.InstanceGroup:
+				if v.ObjectMeta.Labels == nil {
+					v.ObjectMeta.Labels = make(map[string]string)
+				}
+				v.ObjectMeta.Labels[kops.LabelClusterName] = h.cluster.Name
+				snapshot.InstanceGroups = append(snapshot.InstanceGroups, v)
+			default:
+				return nil, fmt.Errorf("unexpected object %T in %s in revision %d", o, p, revision)
+			}
+		}
+	}
+
+	if snapshot.Cluster == nil {
+		return nil, fmt.Errorf("revision %d does not contain a cluster configuration", revision)
+	}
+	return snapshot, nil
+}
+
+// ensureBaseline records the current configuration if no history has been recorded yet,
+// so that the state from before the first recorded write can be restored.
+// It should be called before writing to the state store.
+func (h *vfsHistoryClient) ensureBaseline(ctx context.Context) {
+	revisions, err := h.listRevisions()
+	if err != nil {
+		klog.Warningf("unable to read revision history for cluster %q: %v", h.cluster.Name, err)
+		return
+	}
+	if len(revisions) != 0 {
+		return
+	}
+	h.record(ctx, "initial revision")
+}
+
+// Record implements HistoryClient::Record
+func (h *vfsHistoryClient) Record(ctx context.Context, reason string) error {
+	return h.recordRevision(ctx, reason)
+}
+
+// Batch implements HistoryClient::Batch
+func (h *vfsHistoryClient) Batch(ctx context.Context, reason string, fn func() error) error {
+	h.ensureBaseline(ctx)
+
+	key := h.clusterBase.Path()
+	if _, found := batches.LoadOrStore(key, true); found {
+		return fmt.Errorf("the configuration of cluster %q is already being changed", h.cluster.Name)
+	}
+	err := fn()
+	batches.Delete(key)
+
+	if err != nil {
+		h.record(ctx, reason+" (incomplete)")
+		return err
+	}
+	return h.recordRevision(ctx, reason)
+}
+
+// record stores the current configuration of the cluster as a new revision.
+// It should be called after writing to the state store.
+// Failing to record history is not fatal to the write that triggered it.
+// Writes made within Batch are not recorded individually.
+func (h *vfsHistoryClient) record(ctx context.Context, reason string) {
+	if _, found := batches.Load(h.clusterBase.Path()); found {
+		return
+	}
+	if err := h.recordRevision(ctx, reason); err != nil {
+		klog.Warningf("unable to record revision history for cluster %q: %v", h.cluster.Name, err)
+	}
+}
+
+func (h *vfsHistoryClient) recordRevision(ctx context.Context, reason string) error {
+	files, err := h.readConfigFiles(ctx)
+	if err != nil {
+		return err
+	}
+	if _, found := files[registry.PathCluster]; !found {
+		// Nothing to record until the cluster has been created
+		return nil
+	}
+
+	revisions, err := h.listRevisions()
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	if len(revisions) != 0 {
+		latest := revisions[len(revisions)-1]
+		previous, err := h.readRecord(ctx, latest)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(previous.Files, files) {
+			klog.V(4).Infof("configuration unchanged since revision %d, not recording a new revision", latest)
+			return nil
+		}
+		next = latest + 1
+	}
+
+	record := &historyRecord{
+		ClusterRevision: simple.ClusterRevision{
+			Revision:  next,
+			Timestamp: time.Now().UTC(),
+			User:      mutexes.LeaseHolder(),
+			Reason:    reason,
+		},
+		Files: files,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error serializing revision: %v", err)
+	}
+
+	p := h.revisionPath(next)
+	acl, err := acls.GetACL(ctx, p, h.cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.CreateFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("error writing revision %d: %v", next, err)
+	}
+
+	revisions = append(revisions, next)
+	for len(revisions) > maxHistoryRevisions {
+		if err := h.revisionPath(revisions[0]).Remove(); err != nil {
+			klog.Warningf("unable to prune revision %d: %v", revisions[0], err)
+		}
+		revisions = revisions[1:]
+	}
+
+	return nil
+}
+
+// readConfigFiles reads the configuration files of the cluster, keyed by their path relative to the cluster's state.
+// The completed cluster spec is included so that revisions show what kops update cluster applied,
+// but it is derived from the other files and is never restored.
+func (h *vfsHistoryClient) readConfigFiles(ctx context.Context) (map[string]string, error) {
+	files := make(map[string]string)
+
+	readFile := func(relativePath string) error {
+		data, err := h.clusterBase.Join(relativePath).ReadFile(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("error reading %s: %v", relativePath, err)
+		}
+		files[relativePath] = string(data)
+		return nil
+	}
+
+	if err := readFile(registry.PathCluster); err != nil {
+		return nil, err
+	}
+
+	names, err := listChildNames(ctx, h.clusterBase.Join(pathInstanceGroups))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := readFile(pathInstanceGroups + "/" + name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := readFile(pathAddons); err != nil {
+		return nil, err
+	}
+
+	if err := readFile(registry.PathClusterCompleted); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// listRevisions returns the recorded revision numbers, in ascending order
+func (h *vfsHistoryClient) listRevisions() ([]int, error) {
+	names, err := listChildNames(context.TODO(), h.clusterBase.Join(historyDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []int
+	for _, name := range names {
+		revision, err := strconv.Atoi(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			klog.Warningf("ignoring unexpected file %q in revision history", name)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Ints(revisions)
+	return revisions, nil
+}
+
+func (h *vfsHistoryClient) readRecord(ctx context.Context, revision int) (*historyRecord, error) {
+	data, err := h.revisionPath(revision).ReadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &historyRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("error parsing revision %d: %v", revision, err)
+	}
+	return record, nil
+}
+
+func (h *vfsHistoryClient) revisionPath(revision int) vfs.Path {
+	return h.clusterBase.Join(historyDir, fmt.Sprintf("%06d.json", revision))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(basePath)
+
+	cluster := testutils.BuildMinimalCluster("test.k8s.io")
+	cluster.Spec.ConfigBase = "memfs://tests/test.k8s.io"
+	if _, err := clientset.CreateCluster(ctx, cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	nodes.Spec.MaxSize = fi.PtrTo[int32](2)
+	igClient := clientset.InstanceGroupsFor(cluster)
+	if _, err := igClient.Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	nodes.Spec.MaxSize = fi.PtrTo[int32](5)
+	if _, err := igClient.Update(ctx, &nodes, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+
+	// An update that doesn't change anything should not record a revision
+	if _, err := igClient.Update(ctx, &nodes, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+
+	history, err := clientset.HistoryFor(cluster)
+	if err != nil {
+		t.Fatalf("error getting history client: %v", err)
+	}
+	revisions, err := history.List(ctx)
+	if err != nil {
+		t.Fatalf("error listing history: %v", err)
+	}
+
+	var reasons []string
+	for _, r := range revisions {
+		reasons = append(reasons, r.Reason)
+	}
+	expected := []string{"create Cluster", "create InstanceGroup nodes", "update InstanceGroup nodes"}
+	if len(reasons) != len(expected) {
+		t.Fatalf("expected revisions %v, got %v", expected, reasons)
+	}
+	for i := range expected {
+		if reasons[i] != expected[i] || revisions[i].Revision != i+1 {
+			t.Errorf("expected revision %d to be %q, got %d %q", i+1, expected[i], revisions[i].Revision, reasons[i])
+		}
+	}
+
+	snapshot, err := history.Get(ctx, 2)
+	if err != nil {
+		t.Fatalf("error getting revision: %v", err)
+	}
+	if snapshot.Cluster.Name != cluster.Name {
+		t.Errorf("expected cluster %q in revision, got %q", cluster.Name, snapshot.Cluster.Name)
+	}
+	if len(snapshot.InstanceGroups) != 1 || fi.ValueOf(snapshot.InstanceGroups[0].Spec.MaxSize) != 2 {
+		t.Errorf("expected instance group with maxSize 2 in revision 2, got %+v", snapshot.InstanceGroups)
+	}
+
+	// Writes within a batch are recorded as a single revision
+	err = history.Batch(ctx, "rollback to revision 2", func() error {
+		nodes.Spec.MaxSize = fi.PtrTo[int32](2)
+		if _, err := igClient.Update(ctx, &nodes, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		return igClient.Delete(ctx, "nodes", metav1.DeleteOptions{})
+	})
+	if err != nil {
+		t.Fatalf("error in batch: %v", err)
+	}
+	revisions, err = history.List(ctx)
+	if err != nil {
+		t.Fatalf("error listing history: %v", err)
+	}
+	if len(revisions) != 4 || revisions[3].Reason != "rollback to revision 2" {
+		t.Errorf("expected a single revision for the batch, got %d revisions", len(revisions))
+	}
+
+	// A batch that fails partway is recorded as incomplete
+	err = history.Batch(ctx, "rollback to revision 3", func() error {
+		if _, err := igClient.Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed")
+	})
+	if err == nil {
+		t.Fatalf("expected error from batch")
+	}
+	revisions, err = history.List(ctx)
+	if err != nil {
+		t.Fatalf("error listing history: %v", err)
+	}
+	if len(revisions) != 5 || revisions[4].Reason != "rollback to revision 3 (incomplete)" {
+		t.Errorf("expected an incomplete revision for the failed batch, got %+v", revisions[len(revisions)-1])
+	}
+
+	if _, err := history.Get(ctx, 10); err == nil {
+		t.Errorf("expected error getting unknown revision")
+	}
+}
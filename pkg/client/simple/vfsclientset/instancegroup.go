@@ -19,6 +19,7 @@ package vfsclientset
 import (
 	"context"
 	"fmt"
+	"os"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +39,7 @@ type InstanceGroupVFS struct {
 
 	clusterName string
 	cluster     *kopsapi.Cluster
+	history     *vfsHistoryClient
 }
 
 func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGroupVFS {
@@ -51,6 +53,7 @@ func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGro
 	r := &InstanceGroupVFS{
 		cluster:     cluster,
 		clusterName: clusterName,
+		history:     newHistoryVFS(c, cluster),
 	}
 	r.init(kind, c.basePath.Join(clusterName, "instancegroup"), StoreVersion)
 	r.validate = func(o runtime.Object) error {
@@ -101,33 +104,53 @@ func (c *InstanceGroupVFS) List(ctx context.Context, options metav1.ListOptions)
 
 func (c *InstanceGroupVFS) Create(ctx context.Context, g *kopsapi.InstanceGroup, opts metav1.CreateOptions) (*kopsapi.InstanceGroup, error) {
 	validation.ValidateInstanceGroup(g, nil, true)
+	c.history.ensureBaseline(ctx)
 	err := c.create(ctx, c.cluster, g)
 	if err != nil {
 		return nil, err
 	}
+	c.history.record(ctx, "create InstanceGroup "+g.Name)
 	return g, nil
 }
 
 func (c *InstanceGroupVFS) Update(ctx context.Context, g *kopsapi.InstanceGroup, opts metav1.UpdateOptions) (*kopsapi.InstanceGroup, error) {
-	old, err := c.Get(ctx, g.Name, metav1.GetOptions{})
+	o, version, err := c.readConfigVersion(ctx, c.basePath.Join(g.Name))
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NewNotFound(schema.GroupResource{Group: kopsapi.GroupName, Resource: "InstanceGroup"}, g.Name)
+		}
+		return nil, fmt.Errorf("error reading InstanceGroup %q: %v", g.Name, err)
+	}
+	old := o.(*kopsapi.InstanceGroup)
+
+	if err := c.checkGeneration(old, g); err != nil {
 		return nil, err
 	}
 
+	// The generation is bumped on a copy, so the caller's object is only changed once the write succeeds
+	updated := g.DeepCopy()
 	if !apiequality.Semantic.DeepEqual(old.Spec, g.Spec) {
-		g.SetGeneration(old.GetGeneration() + 1)
+		updated.SetGeneration(old.GetGeneration() + 1)
 	}
 
-	validation.ValidateInstanceGroup(g, nil, true)
-	err = c.update(ctx, c.cluster, g)
+	validation.ValidateInstanceGroup(updated, nil, true)
+	c.history.ensureBaseline(ctx)
+	err = c.update(ctx, c.cluster, updated, version)
 	if err != nil {
 		return nil, err
 	}
+	*g = *updated
+	c.history.record(ctx, "update InstanceGroup "+g.Name)
 	return g, nil
 }
 
 func (c *InstanceGroupVFS) Delete(ctx context.Context, name string, options metav1.DeleteOptions) error {
-	return c.delete(ctx, name, options)
+	c.history.ensureBaseline(ctx)
+	if err := c.delete(ctx, name, options); err != nil {
+		return err
+	}
+	c.history.record(ctx, "delete InstanceGroup "+name)
+	return nil
 }
 
 func (r *InstanceGroupVFS) DeleteCollection(ctx context.Context, options metav1.DeleteOptions, listOptions metav1.ListOptions) error {
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestInstanceGroupUpdateConflict(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	clientset := NewVFSClientset(basePath)
+
+	cluster := testutils.BuildMinimalCluster("test.k8s.io")
+	nodes := testutils.BuildMinimalNodeInstanceGroup("nodes", "subnet-us-test-1a")
+	igClient := clientset.InstanceGroupsFor(cluster)
+	if _, err := igClient.Create(ctx, &nodes, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+	nodes.Spec.MaxSize = fi.PtrTo[int32](3)
+	if _, err := igClient.Update(ctx, &nodes, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+
+	first, err := igClient.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	second, err := igClient.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+
+	first.Spec.MaxSize = fi.PtrTo[int32](5)
+	if _, err := igClient.Update(ctx, first, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+
+	// A second spec change based on the same stale read must be rejected
+	second.Spec.MaxSize = fi.PtrTo[int32](10)
+	secondGeneration := second.GetGeneration()
+	_, err = igClient.Update(ctx, second, metav1.UpdateOptions{})
+	if !errors.IsConflict(err) {
+		t.Fatalf("expected conflict updating stale instance group, got %v", err)
+	}
+	if second.GetGeneration() != secondGeneration {
+		t.Errorf("expected the rejected instance group to keep generation %d, got %d", secondGeneration, second.GetGeneration())
+	}
+
+	// Updating from the latest version succeeds
+	latest, err := igClient.Get(ctx, "nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting instance group: %v", err)
+	}
+	latest.Spec.MaxSize = fi.PtrTo[int32](10)
+	if _, err := igClient.Update(ctx, latest, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diskimage mounts a partition of a raw or qcow2 VM disk image on the local machine.
+package diskimage
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// FormatRaw is a raw disk image
+	FormatRaw = "raw"
+	// FormatQcow2 is a qcow2 disk image
+	FormatQcow2 = "qcow2"
+)
+
+// SupportedFormats are the supported disk image formats
+var SupportedFormats = []string{FormatRaw, FormatQcow2}
+
+// FormatFromPath returns the format of the disk image from its file extension
+func FormatFromPath(p string) string {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".qcow2", ".qcow":
+		return FormatQcow2
+	default:
+		return FormatRaw
+	}
+}
+
+// PartitionDevice returns the device of a partition of a disk device, e.g. /dev/loop0p1 or /dev/nbd0p1
+func PartitionDevice(device string, partition int) string {
+	return fmt.Sprintf("%sp%d", device, partition)
+}
+
+// Mount is a partition of a disk image mounted on the local machine
+type Mount struct {
+	// Dir is the directory the partition is mounted on
+	Dir string
+
+	image  string
+	format string
+	device string
+}
+
+// MountImage attaches the disk image to a loop or network block device, and mounts the partition on a temporary directory.
+// Mounting requires root privileges, and qemu-nbd for qcow2 images.
+func MountImage(image string, partition int) (*Mount, error) {
+	m := &Mount{
+		image:  image,
+		format: FormatFromPath(image),
+	}
+
+	if err := m.attach(); err != nil {
+		return nil, err
+	}
+
+	partitionDevice := m.device
+	if partition > 0 {
+		partitionDevice = PartitionDevice(m.device, partition)
+	}
+	if err := waitForDevice(partitionDevice); err != nil {
+		m.detach()
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "kops-bake-image")
+	if err != nil {
+		m.detach()
+		return nil, err
+	}
+	if err := run("mount", partitionDevice, dir); err != nil {
+		os.Remove(dir)
+		m.detach()
+		return nil, err
+	}
+	m.Dir = dir
+
+	return m, nil
+}
+
+// Close unmounts the partition and detaches the disk image
+func (m *Mount) Close() error {
+	if m.Dir != "" {
+		if err := run("umount", m.Dir); err != nil {
+			return err
+		}
+		if err := os.Remove(m.Dir); err != nil {
+			klog.Warningf("error removing %q: %v", m.Dir, err)
+		}
+		m.Dir = ""
+	}
+	return m.detach()
+}
+
+func (m *Mount) attach() error {
+	switch m.format {
+	case FormatRaw:
+		out, err := exec.Command("losetup", "--find", "--show", "--partscan", m.image).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error attaching %q to a loop device: %v: %s", m.image, err, string(out))
+		}
+		m.device = strings.TrimSpace(string(out))
+		return nil
+
+	case FormatQcow2:
+		if err := run("modprobe", "nbd", "max_part=16"); err != nil {
+			return err
+		}
+		device, err := findFreeNBDDevice()
+		if err != nil {
+			return err
+		}
+		if err := run("qemu-nbd", "--connect="+device, "--format="+FormatQcow2, m.image); err != nil {
+			return err
+		}
+		m.device = device
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported disk image format %q", m.format)
+	}
+}
+
+func (m *Mount) detach() error {
+	if m.device == "" {
+		return nil
+	}
+	var err error
+	switch m.format {
+	case FormatRaw:
+		err = run("losetup", "--detach", m.device)
+	case FormatQcow2:
+		err = run("qemu-nbd", "--disconnect", m.device)
+	}
+	if err == nil {
+		m.device = ""
+	}
+	return err
+}
+
+// Convert copies the disk image to the output, converting it to the format of the output file extension
+func Convert(image string, output string) error {
+	return run("qemu-img", "convert", "-f", FormatFromPath(image), "-O", FormatFromPath(output), image, output)
+}
+
+// findFreeNBDDevice returns the first network block device that is not connected
+func findFreeNBDDevice() (string, error) {
+	for i := 0; i < 16; i++ {
+		b, err := os.ReadFile(fmt.Sprintf("/sys/block/nbd%d/size", i))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(b)) == "0" {
+			return fmt.Sprintf("/dev/nbd%d", i), nil
+		}
+	}
+	return "", fmt.Errorf("no free nbd device found")
+}
+
+func waitForDevice(device string) error {
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(device); err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("device %q was not found", device)
+}
+
+func run(name string, args ...string) error {
+	klog.V(2).Infof("running %s %s", name, strings.Join(args, " "))
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running %s %s: %v: %s", name, strings.Join(args, " "), err, string(out))
+	}
+	return nil
+}
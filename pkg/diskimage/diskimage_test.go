@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskimage
+
+import "testing"
+
+func TestFormatFromPath(t *testing.T) {
+	grid := map[string]string{
+		"ubuntu.qcow2":     FormatQcow2,
+		"/tmp/ubuntu.QCOW": FormatQcow2,
+		"ubuntu.img":       FormatRaw,
+		"ubuntu.raw":       FormatRaw,
+		"ubuntu":           FormatRaw,
+	}
+	for p, expected := range grid {
+		if actual := FormatFromPath(p); actual != expected {
+			t.Errorf("FormatFromPath(%q) was %q, expected %q", p, actual, expected)
+		}
+	}
+}
+
+func TestPartitionDevice(t *testing.T) {
+	if actual := PartitionDevice("/dev/nbd0", 1); actual != "/dev/nbd0p1" {
+		t.Errorf("unexpected partition device %q", actual)
+	}
+}
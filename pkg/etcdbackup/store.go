@@ -0,0 +1,331 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etcdbackup reads and writes the backup stores managed by etcd-manager.
+//
+// The layout of a backup store and the format of its files must match what etcd-manager
+// (and etcd-manager-ctl) read and write:
+//
+//	<store>/<backup name>/_etcd_backup.meta   backup metadata
+//	<store>/<backup name>/etcd.backup.gz      gzip compressed etcd snapshot
+//	<store>/control/etcd-cluster-spec         expected member count and etcd version
+//	<store>/control/<timestamp>/_command.json commands for etcd-manager, such as restoring a backup
+package etcdbackup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/urls"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// MetaFilename is the name of the file holding the metadata of a backup
+	MetaFilename = "_etcd_backup.meta"
+	// DataFilename is the name of the file holding the compressed etcd snapshot of a backup
+	DataFilename = "etcd.backup.gz"
+
+	controlDir          = "control"
+	commandFilename     = "_command.json"
+	clusterSpecFilename = "etcd-cluster-spec"
+)
+
+// DefaultBackupStore returns the backup store used for an etcd cluster when none is configured.
+func DefaultBackupStore(configBase string, etcdClusterName string) string {
+	return urls.Join(configBase, "backups", "etcd", etcdClusterName)
+}
+
+// ClusterSpec is the etcd-manager representation of the desired etcd cluster.
+type ClusterSpec struct {
+	MemberCount int32  `json:"memberCount,omitempty"`
+	EtcdVersion string `json:"etcdVersion,omitempty"`
+}
+
+// backupInfo is the content of the metadata file of a backup.
+type backupInfo struct {
+	EtcdVersion string       `json:"etcdVersion,omitempty"`
+	Timestamp   int64String  `json:"timestamp,omitempty"`
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+}
+
+// command is a command for etcd-manager, stored in the control directory of the backup store.
+type command struct {
+	Timestamp     int64String           `json:"timestamp,omitempty"`
+	RestoreBackup *restoreBackupCommand `json:"restoreBackup,omitempty"`
+}
+
+type restoreBackupCommand struct {
+	ClusterSpec *ClusterSpec `json:"clusterSpec,omitempty"`
+	Backup      string       `json:"backup,omitempty"`
+}
+
+// int64String is an int64 encoded as a JSON string, as done by the protobuf JSON mapping etcd-manager uses.
+type int64String int64
+
+func (i int64String) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+func (i *int64String) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid int64 value %q: %w", s, err)
+	}
+	*i = int64String(v)
+	return nil
+}
+
+// Backup describes a backup in a backup store.
+type Backup struct {
+	Name        string    `json:"name"`
+	Timestamp   time.Time `json:"timestamp"`
+	EtcdVersion string    `json:"etcdVersion,omitempty"`
+}
+
+// Store is the backup store of an etcd cluster.
+type Store struct {
+	cluster     *kops.Cluster
+	etcdCluster *kops.EtcdClusterSpec
+	basePath    vfs.Path
+}
+
+// NewStore returns the backup store of the named etcd cluster.
+func NewStore(cluster *kops.Cluster, etcdClusterName string) (*Store, error) {
+	var etcdCluster *kops.EtcdClusterSpec
+	for i := range cluster.Spec.EtcdClusters {
+		if cluster.Spec.EtcdClusters[i].Name == etcdClusterName {
+			etcdCluster = &cluster.Spec.EtcdClusters[i]
+		}
+	}
+	if etcdCluster == nil {
+		return nil, fmt.Errorf("etcd cluster %q not found in cluster %q", etcdClusterName, cluster.Name)
+	}
+
+	location := ""
+	if etcdCluster.Backups != nil {
+		location = etcdCluster.Backups.BackupStore
+	}
+	if location == "" {
+		if cluster.Spec.ConfigBase == "" {
+			return nil, fmt.Errorf("configBase is not set for cluster %q", cluster.Name)
+		}
+		location = DefaultBackupStore(cluster.Spec.ConfigBase, etcdCluster.Name)
+	}
+
+	basePath, err := vfs.Context.BuildVfsPath(location)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing backup store %q for etcd cluster %q: %w", location, etcdCluster.Name, err)
+	}
+
+	return &Store{
+		cluster:     cluster,
+		etcdCluster: etcdCluster,
+		basePath:    basePath,
+	}, nil
+}
+
+// Path returns the location of the backup store.
+func (s *Store) Path() vfs.Path {
+	return s.basePath
+}
+
+// ListBackups returns the backups in the store, oldest first.
+func (s *Store) ListBackups(ctx context.Context) ([]*Backup, error) {
+	files, err := s.basePath.ReadTree()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing backups in %s: %w", s.basePath, err)
+	}
+
+	prefix := strings.TrimSuffix(s.basePath.Path(), "/") + "/"
+	var backups []*Backup
+	for _, file := range files {
+		relativePath := strings.TrimPrefix(file.Path(), prefix)
+		tokens := strings.Split(relativePath, "/")
+		if len(tokens) != 2 || tokens[1] != MetaFilename || tokens[0] == controlDir {
+			continue
+		}
+
+		backup, err := s.readBackup(ctx, tokens[0])
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name < backups[j].Name
+	})
+	return backups, nil
+}
+
+func (s *Store) readBackup(ctx context.Context, name string) (*Backup, error) {
+	data, err := s.basePath.Join(name, MetaFilename).ReadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &backupInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("error parsing metadata of backup %q: %w", name, err)
+	}
+
+	backup := &Backup{
+		Name:        name,
+		EtcdVersion: info.EtcdVersion,
+	}
+	// Backup names start with the time they were taken
+	if t, err := time.Parse(time.RFC3339, name[:strings.Index(name, "Z")+1]); err == nil {
+		backup.Timestamp = t
+	} else if info.Timestamp != 0 {
+		backup.Timestamp = time.Unix(int64(info.Timestamp), 0).UTC()
+	}
+	return backup, nil
+}
+
+// ExpectedClusterSpec returns the member count and etcd version that etcd-manager is maintaining.
+// If etcd-manager has not recorded them yet, they are taken from the cluster configuration.
+func (s *Store) ExpectedClusterSpec(ctx context.Context) (*ClusterSpec, error) {
+	data, err := s.basePath.Join(controlDir, clusterSpecFilename).ReadFile(ctx)
+	if err == nil {
+		spec := &ClusterSpec{}
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", clusterSpecFilename, err)
+		}
+		return spec, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %w", clusterSpecFilename, err)
+	}
+
+	klog.V(2).Infof("%s not found in %s, using the cluster configuration", clusterSpecFilename, s.basePath)
+	if s.etcdCluster.Version == "" {
+		return nil, fmt.Errorf("etcd version of etcd cluster %q is not known", s.etcdCluster.Name)
+	}
+	return &ClusterSpec{
+		MemberCount: int32(len(s.etcdCluster.Members)),
+		EtcdVersion: s.etcdCluster.Version,
+	}, nil
+}
+
+// AddRestoreCommand asks etcd-manager to restore the named backup.
+// etcd-manager executes the command the next time it starts.
+func (s *Store) AddRestoreCommand(ctx context.Context, backupName string) error {
+	if _, err := s.readBackup(ctx, backupName); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("backup %q not found in %s", backupName, s.basePath)
+		}
+		return fmt.Errorf("error reading backup %q: %w", backupName, err)
+	}
+
+	spec, err := s.ExpectedClusterSpec(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	cmd := &command{
+		Timestamp: int64String(now.UnixNano()),
+		RestoreBackup: &restoreBackupCommand{
+			ClusterSpec: spec,
+			Backup:      backupName,
+		},
+	}
+	data, err := json.MarshalIndent(cmd, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing restore command: %w", err)
+	}
+
+	return s.writeFile(ctx, s.basePath.Join(controlDir, now.Format(time.RFC3339Nano), commandFilename), bytes.NewReader(data))
+}
+
+// AddBackup stores an etcd snapshot as a new backup, and returns the name of the backup.
+// The snapshot is compressed to a temporary file rather than in memory, as it can be as large as the etcd database.
+func (s *Store) AddBackup(ctx context.Context, etcdVersion string, snapshot io.Reader) (string, error) {
+	spec, err := s.ExpectedClusterSpec(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	compressed, err := os.CreateTemp("", "etcd-backup")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer func() {
+		compressed.Close()
+		os.Remove(compressed.Name())
+	}()
+
+	w := gzip.NewWriter(compressed)
+	if _, err := io.Copy(w, snapshot); err != nil {
+		return "", fmt.Errorf("error compressing snapshot: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error compressing snapshot: %w", err)
+	}
+	if _, err := compressed.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error reading compressed snapshot: %w", err)
+	}
+
+	now := time.Now().UTC()
+	// Use the naming scheme of etcd-manager, which relies on it to apply the retention policy
+	name := fmt.Sprintf("%s-%06d", now.Format(time.RFC3339), now.Nanosecond()/1000)
+	info := &backupInfo{
+		EtcdVersion: etcdVersion,
+		Timestamp:   int64String(now.Unix()),
+		ClusterSpec: spec,
+	}
+	meta, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializing backup metadata: %w", err)
+	}
+
+	// The metadata is written last, so that incomplete backups are not listed
+	if err := s.writeFile(ctx, s.basePath.Join(name, DataFilename), compressed); err != nil {
+		return "", err
+	}
+	if err := s.writeFile(ctx, s.basePath.Join(name, MetaFilename), bytes.NewReader(meta)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (s *Store) writeFile(ctx context.Context, p vfs.Path, data io.ReadSeeker) error {
+	acl, err := acls.GetACL(ctx, p, s.cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(ctx, data, acl); err != nil {
+		return fmt.Errorf("error writing %s: %w", p, err)
+	}
+	return nil
+}
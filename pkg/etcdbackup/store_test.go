@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestStore(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	vfs.Context.ResetMemfsContext(true)
+
+	cluster := testutils.BuildMinimalCluster("test.k8s.io")
+	cluster.Spec.ConfigBase = "memfs://tests/test.k8s.io"
+	cluster.Spec.EtcdClusters[0].Version = "3.5.7"
+
+	store, err := NewStore(cluster, "main")
+	if err != nil {
+		t.Fatalf("error building store: %v", err)
+	}
+	if store.Path().Path() != "memfs://tests/test.k8s.io/backups/etcd/main" {
+		t.Errorf("unexpected backup store %q", store.Path().Path())
+	}
+
+	if _, err := NewStore(cluster, "unknown"); err == nil {
+		t.Errorf("expected error building store for unknown etcd cluster")
+	}
+
+	// Backups written by etcd-manager
+	for _, name := range []string{"2023-03-02T10:00:00Z-000002", "2023-03-01T10:00:00Z-000001"} {
+		meta := `{"etcdVersion": "3.5.7", "timestamp": "1677664800", "clusterSpec": {"memberCount": 3, "etcdVersion": "3.5.7"}}`
+		if err := store.Path().Join(name, MetaFilename).WriteFile(ctx, strings.NewReader(meta), nil); err != nil {
+			t.Fatalf("error writing backup: %v", err)
+		}
+	}
+	spec := `{"memberCount": 3, "etcdVersion": "3.5.6"}`
+	if err := store.Path().Join("control", "etcd-cluster-spec").WriteFile(ctx, strings.NewReader(spec), nil); err != nil {
+		t.Fatalf("error writing cluster spec: %v", err)
+	}
+
+	name, err := store.AddBackup(ctx, "3.5.7", strings.NewReader("snapshot"))
+	if err != nil {
+		t.Fatalf("error adding backup: %v", err)
+	}
+
+	backups, err := store.ListBackups(ctx)
+	if err != nil {
+		t.Fatalf("error listing backups: %v", err)
+	}
+	var names []string
+	for _, backup := range backups {
+		names = append(names, backup.Name)
+	}
+	if len(names) != 3 || names[0] != "2023-03-01T10:00:00Z-000001" || names[1] != "2023-03-02T10:00:00Z-000002" || names[2] != name {
+		t.Fatalf("unexpected backups %v", names)
+	}
+	if backups[0].Timestamp.Format("2006-01-02T15:04:05Z") != "2023-03-01T10:00:00Z" || backups[0].EtcdVersion != "3.5.7" {
+		t.Errorf("unexpected backup %+v", backups[0])
+	}
+
+	data, err := store.Path().Join(name, DataFilename).ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading backup: %v", err)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error decompressing backup: %v", err)
+	}
+	snapshot, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error decompressing backup: %v", err)
+	}
+	if string(snapshot) != "snapshot" {
+		t.Errorf("unexpected snapshot %q", snapshot)
+	}
+
+	if err := store.AddRestoreCommand(ctx, "2023-03-01T10:00:00Z-000003"); err == nil {
+		t.Errorf("expected error restoring unknown backup")
+	}
+	if err := store.AddRestoreCommand(ctx, "2023-03-01T10:00:00Z-000001"); err != nil {
+		t.Fatalf("error adding restore command: %v", err)
+	}
+
+	controlFiles, err := store.Path().Join("control").ReadTree()
+	if err != nil {
+		t.Fatalf("error listing commands: %v", err)
+	}
+	var commands []*command
+	for _, f := range controlFiles {
+		if f.Base() != "_command.json" {
+			continue
+		}
+		data, err := f.ReadFile(ctx)
+		if err != nil {
+			t.Fatalf("error reading command: %v", err)
+		}
+		cmd := &command{}
+		if err := json.Unmarshal(data, cmd); err != nil {
+			t.Fatalf("error parsing command: %v", err)
+		}
+		commands = append(commands, cmd)
+	}
+	if len(commands) != 1 || commands[0].RestoreBackup == nil {
+		t.Fatalf("expected a single restore command, got %+v", commands)
+	}
+	restore := commands[0].RestoreBackup
+	if restore.Backup != "2023-03-01T10:00:00Z-000001" || restore.ClusterSpec.MemberCount != 3 || restore.ClusterSpec.EtcdVersion != "3.5.6" {
+		t.Errorf("unexpected restore command %+v", restore)
+	}
+}
@@ -77,6 +77,8 @@ var (
 	Azure = new("Azure", Bool(false))
 	// APIServerNodes enables ability to provision nodes that only run the kube-apiserver.
 	APIServerNodes = new("APIServerNodes", Bool(false))
+	// EtcdNodes enables ability to provision nodes that only run etcd members.
+	EtcdNodes = new("EtcdNodes", Bool(false))
 	// UseAddonOperators activates experimental addon operator support
 	UseAddonOperators = new("UseAddonOperators", Bool(false))
 	// TerraformManagedFiles enables rendering managed files into the Terraform configuration.
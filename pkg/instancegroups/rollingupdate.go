@@ -116,6 +116,7 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 	results := make(map[string]error)
 
 	masterGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	etcdGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
 	apiServerGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
 	nodeGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
 	bastionGroups := make(map[string]*cloudinstances.CloudInstanceGroup)
@@ -127,6 +128,8 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 			apiServerGroups[k] = group
 		case api.InstanceGroupRoleControlPlane:
 			masterGroups[k] = group
+		case api.InstanceGroupRoleEtcd:
+			etcdGroups[k] = group
 		case api.InstanceGroupRoleBastion:
 			bastionGroups[k] = group
 		default:
@@ -165,6 +168,16 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		}
 	}
 
+	// Upgrade etcd nodes next, in series, so that etcd keeps quorum.
+	{
+		for _, k := range sortGroups(etcdGroups) {
+			err := c.rollingUpdateInstanceGroup(etcdGroups[k], c.MasterInterval)
+			if err != nil {
+				return fmt.Errorf("etcd node not healthy after update, stopping rolling-update: %q", err)
+			}
+		}
+	}
+
 	// Upgrade control plane next.
 	{
 		// We run control-plane nodes in series, even if they are in separate instance groups
@@ -67,6 +67,13 @@ func (b *BastionModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	if err != nil {
 		return err
 	}
+	var etcdGroups []SecurityGroupInfo
+	if b.HasEtcdNodes() {
+		etcdGroups, err = b.GetSecurityGroups(kops.InstanceGroupRoleEtcd)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Create security group for bastion instances
 	for _, bastionGroup := range bastionGroups {
@@ -154,6 +161,22 @@ func (b *BastionModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		}
 	}
 
+	// Allow bastion nodes to SSH to etcd nodes
+	for _, src := range bastionGroups {
+		for _, dest := range etcdGroups {
+			t := &awstasks.SecurityGroupRule{
+				Name:          fi.PtrTo("bastion-to-etcd-ssh" + JoinSuffixes(src, dest)),
+				Lifecycle:     b.SecurityLifecycle,
+				SecurityGroup: dest.Task,
+				SourceGroup:   src.Task,
+				Protocol:      fi.PtrTo("tcp"),
+				FromPort:      fi.PtrTo(int64(22)),
+				ToPort:        fi.PtrTo(int64(22)),
+			}
+			AddDirectionalGroupRule(c, t)
+		}
+	}
+
 	var sshAllowedCIDRs []string
 	var nlbSubnetMappings []*awstasks.SubnetMapping
 	{
@@ -51,6 +51,13 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.CloudupModelBuilderContext) err
 	if err != nil {
 		return err
 	}
+	var etcdGroups []SecurityGroupInfo
+	if b.HasEtcdNodes() {
+		etcdGroups, err = b.GetSecurityGroups(kops.InstanceGroupRoleEtcd)
+		if err != nil {
+			return err
+		}
+	}
 
 	// SSH is open to AdminCIDR set
 	if b.UsesSSHBastion() {
@@ -87,6 +94,20 @@ func (b *ExternalAccessModelBuilder) Build(c *fi.CloudupModelBuilderContext) err
 				t.SetCidrOrPrefix(sshAccess)
 				AddDirectionalGroupRule(c, t)
 			}
+
+			for _, etcdGroup := range etcdGroups {
+				suffix := etcdGroup.Suffix
+				t := &awstasks.SecurityGroupRule{
+					Name:          fi.PtrTo(fmt.Sprintf("ssh-external-to-etcd-%s%s", sshAccess, suffix)),
+					Lifecycle:     b.Lifecycle,
+					SecurityGroup: etcdGroup.Task,
+					Protocol:      fi.PtrTo("tcp"),
+					FromPort:      fi.PtrTo(int64(22)),
+					ToPort:        fi.PtrTo(int64(22)),
+				}
+				t.SetCidrOrPrefix(sshAccess)
+				AddDirectionalGroupRule(c, t)
+			}
 		}
 	}
 
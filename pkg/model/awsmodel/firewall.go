@@ -21,6 +21,8 @@ import (
 	"strconv"
 
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 
@@ -52,6 +54,10 @@ func (b *FirewallModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 		return err
 	}
 
+	if err := b.buildEtcdRules(c, masterGroups); err != nil {
+		return err
+	}
+
 	// We _should_ block per port... but:
 	// * It causes e2e tests to break
 	// * Users expect to be able to reach pods
@@ -125,19 +131,27 @@ func (b *FirewallModelBuilder) applyNodeToMasterBlockSpecificPorts(c *fi.Cloudup
 	tcpBlocked := make(map[int]bool)
 
 	// Don't allow nodes to access etcd client port
-	tcpBlocked[4001] = true
-	tcpBlocked[4002] = true
+	tcpBlocked[wellknownports.EtcdMainClientPort] = true
+	tcpBlocked[wellknownports.EtcdEventsClientPort] = true
 
 	// Don't allow nodes to access etcd peer port
-	tcpBlocked[2380] = true
-	tcpBlocked[2381] = true
+	tcpBlocked[wellknownports.EtcdMainPeerPort] = true
+	tcpBlocked[wellknownports.EtcdEventsPeerPort] = true
+
+	// Don't allow nodes to access the client and peer ports of additional etcd clusters
+	for _, etcdCluster := range b.Cluster.Spec.EtcdClusters {
+		if index := apimodel.ExtraEtcdClusterPortIndex(&b.Cluster.Spec, etcdCluster.Name); index >= 0 {
+			tcpBlocked[wellknownports.EtcdExtraClientPort+index] = true
+			tcpBlocked[wellknownports.EtcdExtraPeerPort+index] = true
+		}
+	}
 
 	udpRanges := []portRange{{From: 1, To: 65535}}
 	protocols := []Protocol{}
 
 	if b.Cluster.Spec.Networking.Cilium != nil && b.Cluster.Spec.Networking.Cilium.EtcdManaged {
 		// Block the etcd peer port
-		tcpBlocked[2382] = true
+		tcpBlocked[wellknownports.EtcdCiliumPeerPort] = true
 	}
 
 	if b.Cluster.Spec.Networking.Calico != nil {
@@ -295,6 +309,90 @@ func (b *FirewallModelBuilder) buildMasterRules(c *fi.CloudupModelBuilderContext
 	return masterGroups, nil
 }
 
+// buildEtcdRules configures the security groups of InstanceGroups with role Etcd.
+// Etcd nodes only accept traffic from the control plane and from other etcd nodes,
+// they don't need the API server or node ingress that the masters security group allows.
+func (b *FirewallModelBuilder) buildEtcdRules(c *fi.CloudupModelBuilderContext, masterGroups []SecurityGroupInfo) error {
+	if !b.HasEtcdNodes() {
+		return nil
+	}
+
+	etcdGroups, err := b.GetSecurityGroups(kops.InstanceGroupRoleEtcd)
+	if err != nil {
+		return err
+	}
+
+	for _, group := range etcdGroups {
+		group.Task.Lifecycle = b.Lifecycle
+		c.AddTask(group.Task)
+	}
+
+	for _, src := range etcdGroups {
+		// Allow full egress
+		{
+			t := &awstasks.SecurityGroupRule{
+				Name:          fi.PtrTo("ipv4-etcd-egress" + src.Suffix),
+				Lifecycle:     b.Lifecycle,
+				SecurityGroup: src.Task,
+				Egress:        fi.PtrTo(true),
+				CIDR:          fi.PtrTo("0.0.0.0/0"),
+			}
+			AddDirectionalGroupRule(c, t)
+		}
+		{
+			t := &awstasks.SecurityGroupRule{
+				Name:          fi.PtrTo("ipv6-etcd-egress" + src.Suffix),
+				Lifecycle:     b.Lifecycle,
+				SecurityGroup: src.Task,
+				Egress:        fi.PtrTo(true),
+				IPv6CIDR:      fi.PtrTo("::/0"),
+			}
+			AddDirectionalGroupRule(c, t)
+		}
+
+		// Etcd nodes can talk to etcd nodes
+		for _, dest := range etcdGroups {
+			suffix := JoinSuffixes(src, dest)
+
+			t := &awstasks.SecurityGroupRule{
+				Name:          fi.PtrTo("all-etcd-to-etcd" + suffix),
+				Lifecycle:     b.Lifecycle,
+				SecurityGroup: dest.Task,
+				SourceGroup:   src.Task,
+			}
+			AddDirectionalGroupRule(c, t)
+		}
+
+		// Etcd nodes and masters can talk to each other, as etcd clusters can have members in both
+		for _, master := range masterGroups {
+			{
+				suffix := JoinSuffixes(src, master)
+
+				t := &awstasks.SecurityGroupRule{
+					Name:          fi.PtrTo("all-etcd-to-master" + suffix),
+					Lifecycle:     b.Lifecycle,
+					SecurityGroup: master.Task,
+					SourceGroup:   src.Task,
+				}
+				AddDirectionalGroupRule(c, t)
+			}
+			{
+				suffix := JoinSuffixes(master, src)
+
+				t := &awstasks.SecurityGroupRule{
+					Name:          fi.PtrTo("all-master-to-etcd" + suffix),
+					Lifecycle:     b.Lifecycle,
+					SecurityGroup: src.Task,
+					SourceGroup:   master.Task,
+				}
+				AddDirectionalGroupRule(c, t)
+			}
+		}
+	}
+
+	return nil
+}
+
 type SecurityGroupInfo struct {
 	Name   string
 	Suffix string
@@ -334,6 +432,15 @@ func (b *AWSModelContext) GetSecurityGroups(role kops.InstanceGroupRole) ([]Secu
 			RemoveExtraRules: []string{"port=22"},
 		}
 		baseGroup.Tags = b.CloudTags(name, false)
+	} else if role == kops.InstanceGroupRoleEtcd {
+		name := b.SecurityGroupName(role)
+		baseGroup = &awstasks.SecurityGroup{
+			Name:             fi.PtrTo(name),
+			VPC:              b.LinkToVPC(),
+			Description:      fi.PtrTo("Security group for etcd nodes"),
+			RemoveExtraRules: []string{"port=22"},
+		}
+		baseGroup.Tags = b.CloudTags(name, false)
 	} else if role == kops.InstanceGroupRoleBastion {
 		name := b.SecurityGroupName(role)
 		baseGroup = &awstasks.SecurityGroup{
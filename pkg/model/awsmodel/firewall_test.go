@@ -17,7 +17,14 @@ limitations under the License.
 package awsmodel
 
 import (
+	"reflect"
 	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 )
 
 func TestJoinSuffixes(t *testing.T) {
@@ -50,3 +57,55 @@ func TestJoinSuffixes(t *testing.T) {
 		}
 	}
 }
+
+func TestFirewallModelBuilder_EtcdNodes(t *testing.T) {
+	cluster := buildMinimalCluster()
+
+	controlPlane := &kops.InstanceGroup{}
+	controlPlane.ObjectMeta.Name = "control-plane"
+	controlPlane.Spec.Role = kops.InstanceGroupRoleControlPlane
+	etcd := &kops.InstanceGroup{}
+	etcd.ObjectMeta.Name = "etcd"
+	etcd.Spec.Role = kops.InstanceGroupRoleEtcd
+
+	b := FirewallModelBuilder{
+		AWSModelContext: &AWSModelContext{
+			KopsModelContext: &model.KopsModelContext{
+				IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+				InstanceGroups:  []*kops.InstanceGroup{controlPlane, buildNodeInstanceGroup("subnet-us-test-1a"), etcd},
+			},
+		},
+		Lifecycle: fi.LifecycleSync,
+	}
+
+	c := &fi.CloudupModelBuilderContext{
+		Tasks: make(map[string]fi.CloudupTask),
+	}
+	if err := b.Build(c); err != nil {
+		t.Fatalf("error from Build: %v", err)
+	}
+
+	if _, found := c.Tasks["SecurityGroup/etcd.testcluster.test.com"]; !found {
+		t.Fatalf("expected a security group for etcd nodes")
+	}
+
+	ingress := make(map[string]bool)
+	for _, task := range c.Tasks {
+		rule, ok := task.(*awstasks.SecurityGroupRule)
+		if !ok || fi.ValueOf(rule.SecurityGroup.Name) != "etcd.testcluster.test.com" || fi.ValueOf(rule.Egress) {
+			continue
+		}
+		if rule.SourceGroup == nil {
+			t.Errorf("unexpected ingress rule %q to etcd nodes", fi.ValueOf(rule.Name))
+			continue
+		}
+		ingress[fi.ValueOf(rule.SourceGroup.Name)] = true
+	}
+	expected := map[string]bool{
+		"masters.testcluster.test.com": true,
+		"etcd.testcluster.test.com":    true,
+	}
+	if !reflect.DeepEqual(ingress, expected) {
+		t.Errorf("unexpected ingress to etcd nodes from %v, expected %v", ingress, expected)
+	}
+}
@@ -270,6 +270,8 @@ func (b *IAMModelBuilder) roleKey(role iam.Subject) (string, bool) {
 		return "master", false
 	case *iam.NodeRoleAPIServer:
 		return strings.ToLower(string(kops.InstanceGroupRoleAPIServer)), false
+	case *iam.NodeRoleEtcd:
+		return kops.InstanceGroupRoleEtcd.ToLowerString(), false
 	case *iam.NodeRoleNode:
 		return strings.ToLower(string(kops.InstanceGroupRoleNode)), false
 	case *iam.NodeRoleBastion:
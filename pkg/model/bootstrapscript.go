@@ -234,7 +234,7 @@ func (b *BootstrapScriptBuilder) ResourceNodeUp(c *fi.CloudupModelBuilderContext
 	for _, etcdCluster := range b.Cluster.Spec.EtcdClusters {
 		k := etcdCluster.Name
 		keypairs = append(keypairs, "etcd-manager-ca-"+k, "etcd-peers-ca-"+k)
-		if k != "events" && k != "main" {
+		if !model.IsAPIServerEtcdCluster(etcdCluster) {
 			keypairs = append(keypairs, "etcd-clients-ca-"+k)
 		}
 	}
@@ -23,6 +23,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/loader"
 
@@ -137,9 +139,24 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 	for _, etcdCluster := range clusterSpec.EtcdClusters {
 		switch etcdCluster.Name {
 		case "main":
-			c.EtcdServers = append(c.EtcdServers, "https://127.0.0.1:4001")
+			c.EtcdServers = append(c.EtcdServers, fmt.Sprintf("https://127.0.0.1:%d", wellknownports.EtcdMainClientPort))
 		case "events":
-			c.EtcdServersOverrides = append(c.EtcdServersOverrides, "/events#https://127.0.0.1:4002")
+			resources := etcdCluster.Resources
+			if len(resources) == 0 {
+				resources = []string{"/events"}
+			}
+			for _, resource := range resources {
+				c.EtcdServersOverrides = append(c.EtcdServersOverrides, fmt.Sprintf("%s#https://127.0.0.1:%d", resource, wellknownports.EtcdEventsClientPort))
+			}
+		case "cilium":
+			// not used by kube-apiserver
+		default:
+			// The client ports of additional etcd clusters are selected by their PortIndex, as in etcdmanager.PortsForCluster
+			index := apimodel.ExtraEtcdClusterPortIndex(clusterSpec, etcdCluster.Name)
+			clientPort := wellknownports.EtcdExtraClientPort + index
+			for _, resource := range etcdCluster.Resources {
+				c.EtcdServersOverrides = append(c.EtcdServersOverrides, fmt.Sprintf("%s#https://127.0.0.1:%d", resource, clientPort))
+			}
 		}
 	}
 
@@ -165,6 +182,11 @@ func (b *KubeAPIServerOptionsBuilder) BuildOptions(o interface{}) error {
 	// We make sure to disable AnonymousAuth
 	c.AnonymousAuth = fi.PtrTo(false)
 
+	// The hardening profile disables profiling, as recommended by the CIS benchmarks
+	if c.EnableProfiling == nil && clusterSpec.Hardening != nil && clusterSpec.Hardening.Profile != "" {
+		c.EnableProfiling = fi.PtrTo(false)
+	}
+
 	// We query via the kube-apiserver-healthcheck proxy, which listens on port 3990
 	c.InsecureBindAddress = ""
 	c.InsecurePort = nil
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/flagbuilder"
@@ -299,9 +300,11 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec, instance
 		container.Image = remapped
 	}
 
+	etcdNodes := apimodel.UsesEtcdNodes(&etcdCluster, b.InstanceGroups)
+
 	var clientHost string
 
-	if featureflag.APIServerNodes.Enabled() {
+	if featureflag.APIServerNodes.Enabled() || etcdNodes {
 		clientHost = etcdCluster.Name + ".etcd.internal." + b.ClusterName()
 	} else {
 		clientHost = "__name__"
@@ -319,7 +322,7 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec, instance
 		pod.Annotations = make(map[string]string)
 	}
 
-	if featureflag.APIServerNodes.Enabled() {
+	if featureflag.APIServerNodes.Enabled() && !etcdNodes {
 		pod.Annotations["dns.alpha.kubernetes.io/internal"] = clientHost
 	}
 
@@ -334,7 +337,7 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec, instance
 	// (etcd makes it difficult to change peer urls, treating it as a cluster event, for reasons unknown)
 	dnsInternalSuffix := ".internal." + b.Cluster.Name
 
-	ports, err := PortsForCluster(etcdCluster)
+	ports, err := PortsForCluster(&b.Cluster.Spec, etcdCluster)
 	if err != nil {
 		return nil, err
 	}
@@ -350,8 +353,9 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec, instance
 		if !featureflag.APIServerNodes.Enabled() {
 			clientHost = b.Cluster.APIInternalName()
 		}
+
 	default:
-		return nil, fmt.Errorf("unknown etcd cluster key %q", etcdCluster.Name)
+		// additional etcd clusters for kube-apiserver; PortsForCluster has already checked the name
 	}
 
 	if backupStore == "" {
@@ -424,6 +428,9 @@ func (b *EtcdManagerBuilder) buildPod(etcdCluster kops.EtcdClusterSpec, instance
 				awsup.TagNameEtcdClusterPrefix + etcdCluster.Name,
 				awsup.TagNameRolePrefix + "control-plane=1",
 			}
+			if etcdNodes {
+				config.VolumeTag[2] = awsup.TagNameRolePrefix + "etcd=1"
+			}
 			config.VolumeNameTag = awsup.TagNameEtcdClusterPrefix + etcdCluster.Name
 
 		case kops.CloudProviderAzure:
@@ -606,33 +613,46 @@ type Ports struct {
 }
 
 // PortsForCluster returns the ports that the cluster users.
-func PortsForCluster(etcdCluster kops.EtcdClusterSpec) (Ports, error) {
+// Additional etcd clusters use the ports selected by their PortIndex.
+func PortsForCluster(clusterSpec *kops.ClusterSpec, etcdCluster kops.EtcdClusterSpec) (Ports, error) {
 	switch etcdCluster.Name {
 	case "main":
 		return Ports{
 			GRPCPort: wellknownports.EtcdMainGRPC,
 			// TODO: Use a socket file for the quarantine port
 			QuarantinedGRPCPort: wellknownports.EtcdMainQuarantinedClientPort,
-			ClientPort:          4001,
-			PeerPort:            2380,
+			ClientPort:          wellknownports.EtcdMainClientPort,
+			PeerPort:            wellknownports.EtcdMainPeerPort,
 		}, nil
 
 	case "events":
 		return Ports{
 			GRPCPort:            wellknownports.EtcdEventsGRPC,
 			QuarantinedGRPCPort: wellknownports.EtcdEventsQuarantinedClientPort,
-			ClientPort:          4002,
-			PeerPort:            2381,
+			ClientPort:          wellknownports.EtcdEventsClientPort,
+			PeerPort:            wellknownports.EtcdEventsPeerPort,
 		}, nil
 	case "cilium":
 		return Ports{
 			GRPCPort:            wellknownports.EtcdCiliumGRPC,
 			QuarantinedGRPCPort: wellknownports.EtcdCiliumQuarantinedClientPort,
-			ClientPort:          4003,
-			PeerPort:            2382,
+			ClientPort:          wellknownports.EtcdCiliumClientPort,
+			PeerPort:            wellknownports.EtcdCiliumPeerPort,
 		}, nil
 
 	default:
-		return Ports{}, fmt.Errorf("unknown etcd cluster key %q", etcdCluster.Name)
+		index := apimodel.ExtraEtcdClusterPortIndex(clusterSpec, etcdCluster.Name)
+		if index < 0 {
+			return Ports{}, fmt.Errorf("unknown etcd cluster key %q", etcdCluster.Name)
+		}
+		if index >= wellknownports.EtcdExtraClusters {
+			return Ports{}, fmt.Errorf("no ports available for etcd cluster %q, the port index must be less than %d", etcdCluster.Name, wellknownports.EtcdExtraClusters)
+		}
+		return Ports{
+			GRPCPort:            wellknownports.EtcdExtraGRPC + index,
+			QuarantinedGRPCPort: wellknownports.EtcdExtraQuarantinedClientPort + index,
+			ClientPort:          wellknownports.EtcdExtraClientPort + index,
+			PeerPort:            wellknownports.EtcdExtraPeerPort + index,
+		}, nil
 	}
 }
@@ -36,6 +36,7 @@ func Test_RunEtcdManagerBuilder(t *testing.T) {
 		"tests/interval",
 		"tests/proxy",
 		"tests/overwrite_settings",
+		"tests/extra_clusters",
 	}
 	for _, basedir := range tests {
 		basedir := basedir
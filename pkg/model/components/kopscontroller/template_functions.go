@@ -86,7 +86,7 @@ func (t *templateFunctions) GossipServices() ([]*corev1.Service, error) {
 		for _, etcdCluster := range t.Cluster.Spec.EtcdClusters {
 			name := "etcd-" + etcdCluster.Name + "-internal"
 			service := buildHeadlessService(types.NamespacedName{Name: name, Namespace: "kube-system"})
-			ports, err := etcdmanager.PortsForCluster(etcdCluster)
+			ports, err := etcdmanager.PortsForCluster(&t.Cluster.Spec, etcdCluster)
 			if err != nil {
 				return nil, err
 			}
@@ -49,6 +49,11 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o interface{}) error
 	}
 	kcm := clusterSpec.KubeControllerManager
 
+	// The hardening profile disables profiling, as recommended by the CIS benchmarks
+	if kcm.EnableProfiling == nil && clusterSpec.Hardening != nil && clusterSpec.Hardening.Profile != "" {
+		kcm.EnableProfiling = fi.PtrTo(false)
+	}
+
 	// Tune the duration upon which the volume attach detach component is called.
 	// See https://github.com/kubernetes/kubernetes/pull/39551
 	// TLDR; set this too low, and have a few EBS Volumes, and you will spam AWS api
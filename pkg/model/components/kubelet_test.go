@@ -17,6 +17,7 @@ limitations under the License.
 package components
 
 import (
+	"reflect"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -89,3 +90,42 @@ func TestFeatureGatesOverride(t *testing.T) {
 		t.Errorf("ExperimentalCriticalPodAnnotation feature should be disalbled")
 	}
 }
+
+func TestCredentialProvidersNotEnabledByDefault(t *testing.T) {
+	grid := []struct {
+		cloudProvider kops.CloudProviderSpec
+		providers     []kops.KubeletCredentialProviderSpec
+		expected      []string
+	}{
+		{
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+		},
+		{
+			cloudProvider: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+		},
+		{
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			providers:     []kops.KubeletCredentialProviderSpec{{Name: "ecr-credential-provider"}},
+			expected:      []string{"ecr-credential-provider"},
+		},
+	}
+
+	for _, g := range grid {
+		cluster := buildKubeletTestCluster()
+		cluster.Spec.CloudProvider = g.cloudProvider
+		cluster.Spec.KubernetesVersion = "1.28.0"
+		cluster.Spec.Kubelet.CredentialProviders = g.providers
+
+		if err := buildOptions(cluster); err != nil {
+			t.Fatalf("buildOptions failed: %v", err)
+		}
+
+		var actual []string
+		for _, provider := range cluster.Spec.Kubelet.CredentialProviders {
+			actual = append(actual, provider.Name)
+		}
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("unexpected credential providers for %s: expected %v, got %v", cluster.Spec.GetCloudProvider(), g.expected, actual)
+		}
+	}
+}
@@ -42,6 +42,11 @@ func (b *KubeSchedulerOptionsBuilder) BuildOptions(o interface{}) error {
 		config.LogLevel = 2
 	}
 
+	// The hardening profile disables profiling, as recommended by the CIS benchmarks
+	if config.EnableProfiling == nil && clusterSpec.Hardening != nil && clusterSpec.Hardening.Profile != "" {
+		config.EnableProfiling = fi.PtrTo(false)
+	}
+
 	if config.Image == "" {
 		image, err := Image("kube-scheduler", clusterSpec, b.AssetBuilder)
 		if err != nil {
@@ -180,6 +180,10 @@ func (b *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (ma
 		labels[awstasks.CloudTagInstanceGroupRolePrefix+strings.ToLower(string(kops.InstanceGroupRoleAPIServer))] = "1"
 	}
 
+	if ig.Spec.Role == kops.InstanceGroupRoleEtcd {
+		labels[awstasks.CloudTagInstanceGroupRolePrefix+kops.InstanceGroupRoleEtcd.ToLowerString()] = "1"
+	}
+
 	if ig.Spec.Role == kops.InstanceGroupRoleNode {
 		labels[awstasks.CloudTagInstanceGroupRolePrefix+strings.ToLower(string(kops.InstanceGroupRoleNode))] = "1"
 	}
@@ -280,6 +284,17 @@ func (b *KopsModelContext) UsesSSHBastion() bool {
 	return false
 }
 
+// HasEtcdNodes checks if we have InstanceGroups with role Etcd in the cluster
+func (b *KopsModelContext) HasEtcdNodes() bool {
+	for _, ig := range b.InstanceGroups {
+		if ig.IsEtcdOnly() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UseLoadBalancerForAPI checks if we are using a load balancer for the kubeapi
 func (b *KopsModelContext) UseLoadBalancerForAPI() bool {
 	return b.Cluster.Spec.API.LoadBalancer != nil
@@ -38,6 +38,7 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/util/stringorslice"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
@@ -352,6 +353,11 @@ func (r *NodeRoleAPIServer) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 
 	b.addNodeupPermissions(p, r.warmPool)
 
+	if featureflag.EtcdNodes.Enabled() {
+		// Protokube discovers the etcd nodes from their volumes
+		p.unconditionalAction.Insert("ec2:DescribeVolumes")
+	}
+
 	var err error
 	if p, err = b.AddS3Permissions(p); err != nil {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
@@ -386,7 +392,7 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 
 	p := NewPolicy(clusterName, b.Partition)
 
-	addEtcdManagerPermissions(p)
+	addEtcdManagerPermissions(p, "master")
 	b.addNodeupPermissions(p, false)
 
 	if b.Cluster.Spec.IsKopsControllerIPAM() {
@@ -487,6 +493,29 @@ func (r *NodeRoleNode) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	return p, nil
 }
 
+// BuildAWSPolicy generates a custom policy for nodes that only run etcd members.
+func (r *NodeRoleEtcd) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
+	p := NewPolicy(b.Cluster.GetName(), b.Partition)
+
+	addEtcdManagerPermissions(p, kops.InstanceGroupRoleEtcd.ToLowerString())
+	b.addNodeupPermissions(p, false)
+
+	var err error
+	if p, err = b.AddS3Permissions(p); err != nil {
+		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
+	}
+
+	if b.KMSKeys != nil && len(b.KMSKeys) != 0 {
+		addKMSIAMPolicies(p, stringorslice.Slice(b.KMSKeys))
+	}
+
+	if b.Cluster.Spec.IAM != nil && b.Cluster.Spec.IAM.AllowContainerRegistry {
+		addECRPermissions(p)
+	}
+
+	return p, nil
+}
+
 // BuildAWSPolicy generates a custom policy for a bastion host.
 func (r *NodeRoleBastion) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 	p := NewPolicy(b.Cluster.GetName(), b.Partition)
@@ -668,7 +697,7 @@ func WriteableVFSPaths(cluster *kops.Cluster, role Subject) ([]vfs.Path, error)
 
 	// etcd-manager needs write permissions to the backup store
 	switch role.(type) {
-	case *NodeRoleMaster:
+	case *NodeRoleMaster, *NodeRoleEtcd:
 		backupStores := sets.NewString()
 		for _, c := range cluster.Spec.EtcdClusters {
 			if c.Backups == nil || c.Backups.BackupStore == "" || backupStores.Has(c.Backups.BackupStore) {
@@ -698,6 +727,24 @@ func ReadableStatePaths(cluster *kops.Cluster, role Subject) ([]string, error) {
 	case *NodeRoleMaster, *NodeRoleAPIServer:
 		paths = append(paths, "/*")
 
+	case *NodeRoleEtcd:
+		paths = append(paths,
+			"/cluster-completed.spec",
+			"/igconfig/etcd/*",
+			"/manifests/etcd/*",
+			"/pki/private/etcd-clients-ca/*",
+			"/secrets/dockerconfig",
+		)
+		for _, etcdCluster := range cluster.Spec.EtcdClusters {
+			paths = append(paths,
+				"/pki/private/etcd-manager-ca-"+etcdCluster.Name+"/*",
+				"/pki/private/etcd-peers-ca-"+etcdCluster.Name+"/*",
+			)
+			if !model.IsAPIServerEtcdCluster(etcdCluster) {
+				paths = append(paths, "/pki/private/etcd-clients-ca-"+etcdCluster.Name+"/*")
+			}
+		}
+
 	case *NodeRoleNode:
 		// Give access to keys for client certificates as needed.
 		if !model.UseKopsControllerForNodeConfig(cluster) {
@@ -843,7 +890,8 @@ func addKopsControllerIPAMPermissions(p *Policy) {
 	)
 }
 
-func addEtcdManagerPermissions(p *Policy) {
+// addEtcdManagerPermissions allows etcd-manager to attach the volumes tagged for the given instance group role.
+func addEtcdManagerPermissions(p *Policy, role string) {
 	p.unconditionalAction.Insert(
 		"ec2:DescribeVolumes", // aws.go
 	)
@@ -857,8 +905,8 @@ func addEtcdManagerPermissions(p *Policy) {
 			Resource: stringorslice.Slice([]string{"*"}),
 			Condition: Condition{
 				"StringEquals": map[string]string{
-					"aws:ResourceTag/k8s.io/role/master": "1",
-					"aws:ResourceTag/KubernetesCluster":  p.clusterName,
+					"aws:ResourceTag/k8s.io/role/" + role: "1",
+					"aws:ResourceTag/KubernetesCluster":   p.clusterName,
 				},
 			},
 		},
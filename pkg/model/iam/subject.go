@@ -72,6 +72,14 @@ func (_ *NodeRoleBastion) ServiceAccount() (types.NamespacedName, bool) {
 	return types.NamespacedName{}, false
 }
 
+// NodeRoleEtcd represents the role of nodes that only run etcd members, and implements Subject.
+type NodeRoleEtcd struct{}
+
+// ServiceAccount implements Subject.
+func (_ *NodeRoleEtcd) ServiceAccount() (types.NamespacedName, bool) {
+	return types.NamespacedName{}, false
+}
+
 type GenericServiceAccount struct {
 	NamespacedName types.NamespacedName
 	Policy         *Policy
@@ -100,6 +108,8 @@ func BuildNodeRoleSubject(igRole kops.InstanceGroupRole, enableLifecycleHookPerm
 		}, nil
 	case kops.InstanceGroupRoleBastion:
 		return &NodeRoleBastion{}, nil
+	case kops.InstanceGroupRoleEtcd:
+		return &NodeRoleEtcd{}, nil
 	default:
 		return nil, fmt.Errorf("unknown instancegroup role %q", igRole)
 	}
@@ -100,7 +100,7 @@ func (b *MasterVolumeBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 
 			switch b.Cluster.Spec.GetCloudProvider() {
 			case kops.CloudProviderAWS:
-				err = b.addAWSVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+				err = b.addAWSVolume(c, name, volumeSize, zone, etcd, m, allMembers, ig)
 				if err != nil {
 					return err
 				}
@@ -130,7 +130,7 @@ func (b *MasterVolumeBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	return nil
 }
 
-func (b *MasterVolumeBuilder) addAWSVolume(c *fi.CloudupModelBuilderContext, name string, volumeSize int32, zone string, etcd kops.EtcdClusterSpec, m kops.EtcdMemberSpec, allMembers []string) error {
+func (b *MasterVolumeBuilder) addAWSVolume(c *fi.CloudupModelBuilderContext, name string, volumeSize int32, zone string, etcd kops.EtcdClusterSpec, m kops.EtcdMemberSpec, allMembers []string, ig *kops.InstanceGroup) error {
 	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSVolumeTypes.html
 	volumeType := fi.ValueOf(m.VolumeType)
 	if volumeType == "" {
@@ -167,9 +167,14 @@ func (b *MasterVolumeBuilder) addAWSVolume(c *fi.CloudupModelBuilderContext, nam
 	// tags[awsup.TagClusterName] = b.C.cluster.Name
 	// This is the configuration of the etcd cluster
 	tags[awsup.TagNameEtcdClusterPrefix+etcd.Name] = m.Name + "/" + strings.Join(allMembers, ",")
-	// This says "only mount on a control plane node"
-	tags[awsup.TagNameRolePrefix+"control-plane"] = "1"
-	tags[awsup.TagNameRolePrefix+"master"] = "1"
+	if ig.IsEtcdOnly() {
+		// This says "only mount on an etcd node"
+		tags[awsup.TagNameRolePrefix+"etcd"] = "1"
+	} else {
+		// This says "only mount on a control plane node"
+		tags[awsup.TagNameRolePrefix+"control-plane"] = "1"
+		tags[awsup.TagNameRolePrefix+"master"] = "1"
+	}
 
 	// We always add an owned tags (these can't be shared)
 	tags["kubernetes.io/cluster/"+b.Cluster.ObjectMeta.Name] = "owned"
@@ -40,6 +40,8 @@ func (b *KopsModelContext) SecurityGroupName(role kops.InstanceGroupRole) string
 		return "nodes." + b.ClusterName()
 	case kops.InstanceGroupRoleControlPlane, kops.InstanceGroupRoleAPIServer:
 		return "masters." + b.ClusterName()
+	case kops.InstanceGroupRoleEtcd:
+		return "etcd." + b.ClusterName()
 	default:
 		klog.Fatalf("unknown role: %v", role)
 		return ""
@@ -62,6 +64,8 @@ func (b *KopsModelContext) AutoscalingGroupName(ig *kops.InstanceGroup) string {
 		return ig.ObjectMeta.Name + ".masters." + b.ClusterName()
 	case kops.InstanceGroupRoleAPIServer:
 		return ig.ObjectMeta.Name + ".apiservers." + b.ClusterName()
+	case kops.InstanceGroupRoleEtcd:
+		return ig.ObjectMeta.Name + ".etcd." + b.ClusterName()
 	case kops.InstanceGroupRoleNode, kops.InstanceGroupRoleBastion:
 		return ig.ObjectMeta.Name + "." + b.ClusterName()
 
@@ -147,6 +151,8 @@ func (b *KopsModelContext) IAMName(role kops.InstanceGroupRole) string {
 		rolename = "masters." + b.ClusterName()
 	case kops.InstanceGroupRoleAPIServer:
 		rolename = "apiservers." + b.ClusterName()
+	case kops.InstanceGroupRoleEtcd:
+		rolename = "etcd." + b.ClusterName()
 	case kops.InstanceGroupRoleBastion:
 		rolename = "bastions." + b.ClusterName()
 	case kops.InstanceGroupRoleNode:
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstacktasks"
 
@@ -181,6 +182,30 @@ func (b *FirewallModelBuilder) addETCDRules(c *fi.CloudupModelBuilderContext, sg
 		}
 		b.addDirectionalGroupRule(c, masterSG, masterSG, etcdMgmrRule)
 	}
+
+	// Additional etcd clusters
+	for _, etcdCluster := range b.Cluster.Spec.EtcdClusters {
+		index := apimodel.ExtraEtcdClusterPortIndex(&b.Cluster.Spec, etcdCluster.Name)
+		if index < 0 {
+			continue
+		}
+		for _, port := range []int{
+			wellknownports.EtcdExtraClientPort + index,
+			wellknownports.EtcdExtraPeerPort + index,
+			wellknownports.EtcdExtraGRPC + index,
+			wellknownports.EtcdExtraQuarantinedClientPort + index,
+		} {
+			etcdExtraRule := &openstacktasks.SecurityGroupRule{
+				Lifecycle:    b.Lifecycle,
+				Direction:    s(string(rules.DirIngress)),
+				Protocol:     s(string(rules.ProtocolTCP)),
+				EtherType:    s(IPV4),
+				PortRangeMin: i(port),
+				PortRangeMax: i(port),
+			}
+			b.addDirectionalGroupRule(c, masterSG, masterSG, etcdExtraRule)
+		}
+	}
 	return nil
 }
 
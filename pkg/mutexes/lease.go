@@ -0,0 +1,310 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutexes
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// DefaultLeaseDuration is how long a lease remains valid without being renewed.
+	DefaultLeaseDuration = 5 * time.Minute
+	// defaultLeasePollInterval is how often we check whether a held lease has been released.
+	defaultLeasePollInterval = 5 * time.Second
+)
+
+// LeaseInfo is the record stored in the state store for an advisory lease.
+type LeaseInfo struct {
+	// ID uniquely identifies the lease, so that a holder can tell whether it still owns it.
+	ID string `json:"id"`
+	// Holder is the user and host that acquired the lease.
+	Holder string `json:"holder"`
+	// PID is the process ID of the holder.
+	PID int `json:"pid"`
+	// Operation describes what the holder is doing, e.g. "update cluster".
+	Operation string `json:"operation,omitempty"`
+	// AcquiredAt is when the lease was acquired.
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// RenewedAt is when the lease was last renewed by the holder.
+	RenewedAt time.Time `json:"renewedAt"`
+	// DurationSeconds is how long the lease remains valid after it was last renewed.
+	DurationSeconds int64 `json:"durationSeconds"`
+}
+
+// Expired returns true if the holder has not renewed the lease within its duration.
+func (l *LeaseInfo) Expired(now time.Time) bool {
+	return now.After(l.RenewedAt.Add(time.Duration(l.DurationSeconds) * time.Second))
+}
+
+func (l *LeaseInfo) String() string {
+	s := fmt.Sprintf("%s (pid %d)", l.Holder, l.PID)
+	if l.Operation != "" {
+		s += fmt.Sprintf(" running %q", l.Operation)
+	}
+	return s + fmt.Sprintf(" since %s", l.AcquiredAt.Format(time.RFC3339))
+}
+
+// LeaseHeldError is returned when a lease could not be acquired because another holder owns it.
+type LeaseHeldError struct {
+	Path   vfs.Path
+	Holder *LeaseInfo
+}
+
+func (e *LeaseHeldError) Error() string {
+	return fmt.Sprintf("lock %s is held by %s", e.Path, e.Holder)
+}
+
+// LeaseOptions controls how a lease is acquired.
+type LeaseOptions struct {
+	// Operation is recorded in the lease, so that other users can see what the holder is doing.
+	Operation string
+	// Duration is how long the lease remains valid if the holder stops renewing it, e.g. because it crashed.
+	// Defaults to DefaultLeaseDuration.
+	Duration time.Duration
+	// WaitTimeout is how long to wait for a lease held by someone else to be released.
+	// If zero, acquisition fails immediately with a LeaseHeldError.
+	WaitTimeout time.Duration
+	// Break takes over the lease even if it is currently held by someone else.
+	Break bool
+}
+
+// StateStoreLease is an advisory lock held in the state store, used to keep
+// two kOps processes from mutating the same cluster at the same time.
+// The lease is renewed in the background until it is released.
+type StateStoreLease struct {
+	path     vfs.Path
+	info     LeaseInfo
+	duration time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// AcquireStateStoreLease acquires the lease stored at p, blocking for up to options.WaitTimeout if it is held by someone else.
+func AcquireStateStoreLease(ctx context.Context, p vfs.Path, options LeaseOptions) (*StateStoreLease, error) {
+	duration := options.Duration
+	if duration == 0 {
+		duration = DefaultLeaseDuration
+	}
+
+	id, err := newLeaseID()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &StateStoreLease{
+		path:     p,
+		duration: duration,
+		info: LeaseInfo{
+			ID:              id,
+			Holder:          LeaseHolder(),
+			PID:             os.Getpid(),
+			Operation:       options.Operation,
+			DurationSeconds: int64(duration / time.Second),
+		},
+	}
+
+	breakLease := options.Break
+	deadline := time.Now().Add(options.WaitTimeout)
+	for {
+		existing, version, err := ReadStateStoreLease(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now().UTC()
+		l.info.AcquiredAt = now
+		l.info.RenewedAt = now
+
+		switch {
+		case existing == nil:
+			err = l.write(ctx, "", true)
+			if os.IsExist(err) {
+				continue
+			}
+
+		case existing.ID == l.info.ID:
+			// We won the race; fall through to start renewing.
+
+		case breakLease || existing.Expired(now):
+			if breakLease {
+				klog.Warningf("breaking lock %s held by %s", p, existing)
+			} else {
+				klog.Warningf("taking over expired lock %s held by %s", p, existing)
+			}
+			breakLease = false
+			err = l.write(ctx, version, false)
+			if err == vfs.ErrVersionMismatch {
+				continue
+			}
+
+		default:
+			if time.Now().After(deadline) {
+				return nil, &LeaseHeldError{Path: p, Holder: existing}
+			}
+			klog.Infof("waiting for lock %s held by %s", p, existing)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(defaultLeasePollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error writing lock %s: %w", p, err)
+		}
+
+		// Most backends do not offer an atomic create, so read the lease back to check that we hold it.
+		current, _, err := ReadStateStoreLease(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil && current.ID == l.info.ID {
+			break
+		}
+	}
+
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.renewLoop(ctx)
+
+	return l, nil
+}
+
+// ReadStateStoreLease returns the lease currently stored at p, or nil if there is none.
+// The returned version is non-empty if the backing store supports conditional writes.
+func ReadStateStoreLease(ctx context.Context, p vfs.Path) (*LeaseInfo, string, error) {
+	var data []byte
+	var version string
+	var err error
+	if versioned, ok := p.(vfs.HasVersion); ok {
+		data, version, err = versioned.ReadFileVersion(ctx)
+	} else {
+		data, err = p.ReadFile(ctx)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("error reading lock %s: %w", p, err)
+	}
+
+	info := &LeaseInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, "", fmt.Errorf("error parsing lock %s: %w", p, err)
+	}
+	return info, version, nil
+}
+
+// Release stops renewing the lease and removes it from the state store, if we still hold it.
+func (l *StateStoreLease) Release(ctx context.Context) error {
+	close(l.stop)
+	<-l.done
+
+	current, _, err := ReadStateStoreLease(ctx, l.path)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.ID != l.info.ID {
+		klog.Warningf("lock %s was taken over by another process", l.path)
+		return nil
+	}
+	if err := l.path.Remove(); err != nil {
+		return fmt.Errorf("error removing lock %s: %w", l.path, err)
+	}
+	return nil
+}
+
+func (l *StateStoreLease) renewLoop(ctx context.Context) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, version, err := ReadStateStoreLease(ctx, l.path)
+		if err != nil {
+			klog.Warningf("error renewing lock: %v", err)
+			continue
+		}
+		if current == nil || current.ID != l.info.ID {
+			klog.Warningf("lock %s was taken over by another process; no longer renewing", l.path)
+			return
+		}
+		l.info.RenewedAt = time.Now().UTC()
+		if err := l.write(ctx, version, false); err != nil {
+			klog.Warningf("error renewing lock %s: %v", l.path, err)
+		}
+	}
+}
+
+// write stores the lease, conditional on version if it is non-empty.
+func (l *StateStoreLease) write(ctx context.Context, version string, create bool) error {
+	data, err := json.Marshal(&l.info)
+	if err != nil {
+		return fmt.Errorf("error serializing lock: %w", err)
+	}
+
+	if create {
+		return l.path.CreateFile(ctx, bytes.NewReader(data), nil)
+	}
+	if versioned, ok := l.path.(vfs.HasVersion); ok && version != "" {
+		_, err := versioned.WriteFileIfVersion(ctx, bytes.NewReader(data), nil, version)
+		return err
+	}
+	return l.path.WriteFile(ctx, bytes.NewReader(data), nil)
+}
+
+func newLeaseID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating lock id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// LeaseHolder identifies the local user and host, in the user@host form recorded in leases
+func LeaseHolder() string {
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return username + "@" + hostname
+}
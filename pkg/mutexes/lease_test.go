@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutexes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/testutils/testcontext"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestStateStoreLease(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/apply.lock")
+
+	first, err := AcquireStateStoreLease(ctx, p, LeaseOptions{Operation: "update cluster"})
+	if err != nil {
+		t.Fatalf("error acquiring lease: %v", err)
+	}
+
+	_, err = AcquireStateStoreLease(ctx, p, LeaseOptions{})
+	var held *LeaseHeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("expected LeaseHeldError, got %v", err)
+	}
+	if held.Holder.ID != first.info.ID || held.Holder.Operation != "update cluster" {
+		t.Errorf("unexpected lease holder %+v", held.Holder)
+	}
+
+	second, err := AcquireStateStoreLease(ctx, p, LeaseOptions{Break: true})
+	if err != nil {
+		t.Fatalf("error breaking lease: %v", err)
+	}
+
+	// Releasing a lease that was taken over must not remove the new holder's lease
+	if err := first.Release(ctx); err != nil {
+		t.Fatalf("error releasing broken lease: %v", err)
+	}
+	current, _, err := ReadStateStoreLease(ctx, p)
+	if err != nil {
+		t.Fatalf("error reading lease: %v", err)
+	}
+	if current == nil || current.ID != second.info.ID {
+		t.Fatalf("expected lease to be held by %q, got %+v", second.info.ID, current)
+	}
+
+	if err := second.Release(ctx); err != nil {
+		t.Fatalf("error releasing lease: %v", err)
+	}
+	current, _, err = ReadStateStoreLease(ctx, p)
+	if err != nil {
+		t.Fatalf("error reading lease: %v", err)
+	}
+	if current != nil {
+		t.Fatalf("expected lease to be removed, got %+v", current)
+	}
+}
+
+func TestStateStoreLeaseExpired(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	p := vfs.NewMemFSPath(vfs.NewMemFSContext(), "cluster/apply.lock")
+
+	stale := &LeaseInfo{
+		ID:              "stale",
+		Holder:          "someone@somewhere",
+		AcquiredAt:      time.Now().Add(-time.Hour),
+		RenewedAt:       time.Now().Add(-time.Hour),
+		DurationSeconds: 60,
+	}
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatalf("error serializing lease: %v", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lease: %v", err)
+	}
+
+	l, err := AcquireStateStoreLease(ctx, p, LeaseOptions{})
+	if err != nil {
+		t.Fatalf("expected expired lease to be taken over, got %v", err)
+	}
+	if err := l.Release(ctx); err != nil {
+		t.Fatalf("error releasing lease: %v", err)
+	}
+}
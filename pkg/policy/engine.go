@@ -0,0 +1,318 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// ActionNone is the planned action of a task that would not change
+	ActionNone = "none"
+	// ActionUnknown is the planned action of a task when the changes were not computed, e.g. for the terraform target
+	ActionUnknown = "unknown"
+
+	// maxFieldDepth limits how deep nested fields of tasks are converted
+	maxFieldDepth = 8
+)
+
+// Input is what the rules are evaluated against
+type Input struct {
+	// Cluster is the cluster being updated
+	Cluster *kops.Cluster
+	// Tasks is the task graph of the update, keyed by type/name
+	Tasks map[string]fi.CloudupTask
+	// Plan holds the changes computed by a dry run; nil if the changes were not computed
+	Plan *fi.Plan
+}
+
+// Engine evaluates the rules of policies
+type Engine struct {
+	rules []*compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	program cel.Program
+}
+
+// NewEngine compiles the rules of the policies.
+// The expressions can use the variables cluster, task, action and changes.
+func NewEngine(policies []*Policy) (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("cluster", cel.DynType),
+		cel.Variable("task", cel.DynType),
+		cel.Variable("action", cel.StringType),
+		cel.Variable("changes", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error building CEL environment: %w", err)
+	}
+
+	e := &Engine{}
+	for _, policy := range policies {
+		for _, rule := range policy.Rules {
+			ast, issues := env.Compile(rule.Expression)
+			if issues != nil && issues.Err() != nil {
+				return nil, fmt.Errorf("error compiling rule %q: %w", rule.Name, issues.Err())
+			}
+			if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+				return nil, fmt.Errorf("rule %q must evaluate to a bool, not %v", rule.Name, ast.OutputType())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling rule %q: %w", rule.Name, err)
+			}
+			e.rules = append(e.rules, &compiledRule{Rule: rule, program: program})
+		}
+	}
+	return e, nil
+}
+
+// HasRules returns true if the engine has any rules to evaluate
+func (e *Engine) HasRules() bool {
+	return len(e.rules) != 0
+}
+
+// Evaluate returns the violations of the rules by the tasks and deletions of the input.
+// A rule that fails to evaluate, e.g. because it references a missing field, is violated.
+func (e *Engine) Evaluate(input *Input) ([]Violation, error) {
+	cluster, err := toJSONValue(input.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error converting cluster: %w", err)
+	}
+
+	changes := make(map[string]*fi.PlanChange)
+	var deletions []*fi.PlanChange
+	if input.Plan != nil {
+		for i := range input.Plan.Changes {
+			change := &input.Plan.Changes[i]
+			if change.Action == fi.PlanActionDelete {
+				deletions = append(deletions, change)
+			} else {
+				changes[change.Type+"/"+change.Name] = change
+			}
+		}
+	}
+
+	var keys []string
+	for key := range input.Tasks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []Violation
+	for _, key := range keys {
+		task := input.Tasks[key]
+		taskType, taskName := key, ""
+		if i := strings.Index(key, "/"); i != -1 {
+			taskType, taskName = key[:i], key[i+1:]
+		}
+		vars := map[string]interface{}{
+			"cluster": cluster,
+			"task":    taskValue(taskType, taskName, task),
+			"action":  ActionUnknown,
+			"changes": map[string]interface{}{},
+		}
+		if input.Plan != nil {
+			vars["action"] = ActionNone
+			if change := changes[key]; change != nil {
+				vars["action"] = string(change.Action)
+				vars["changes"] = changesValue(change)
+			}
+		}
+		violations = append(violations, e.evaluate(key, taskType, vars)...)
+	}
+
+	for _, deletion := range deletions {
+		vars := map[string]interface{}{
+			"cluster": cluster,
+			"task": map[string]interface{}{
+				"type":   deletion.Type,
+				"name":   deletion.Name,
+				"fields": map[string]interface{}{},
+			},
+			"action":  string(fi.PlanActionDelete),
+			"changes": map[string]interface{}{},
+		}
+		violations = append(violations, e.evaluate(deletion.Type+"/"+deletion.Name, deletion.Type, vars)...)
+	}
+
+	return violations, nil
+}
+
+func (e *Engine) evaluate(key string, taskType string, vars map[string]interface{}) []Violation {
+	var violations []Violation
+	for _, rule := range e.rules {
+		if len(rule.TaskTypes) != 0 && !contains(rule.TaskTypes, taskType) {
+			continue
+		}
+		if len(rule.Actions) != 0 && !contains(rule.Actions, vars["action"].(string)) {
+			continue
+		}
+
+		message := rule.Message
+		if message == "" {
+			message = fmt.Sprintf("expression %q is false", rule.Expression)
+		}
+
+		out, _, err := rule.program.Eval(vars)
+		if err != nil {
+			message = fmt.Sprintf("error evaluating rule: %v", err)
+		} else if allowed, ok := out.Value().(bool); !ok {
+			message = fmt.Sprintf("rule evaluated to %v, not a bool", out.Value())
+		} else if allowed {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Rule:    rule.Name,
+			Action:  rule.Action,
+			Task:    key,
+			Message: message,
+		})
+	}
+	return violations
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// toJSONValue converts an API object to the maps and lists of its JSON form
+func toJSONValue(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func taskValue(taskType string, taskName string, task fi.CloudupTask) map[string]interface{} {
+	v := map[string]interface{}{
+		"type": taskType,
+		"name": taskName,
+	}
+	if hasLifecycle, ok := task.(fi.HasLifecycle); ok {
+		v["lifecycle"] = string(hasLifecycle.GetLifecycle())
+	}
+	fields, _ := toValue(reflect.ValueOf(task), 0).(map[string]interface{})
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	v["fields"] = fields
+	return v
+}
+
+func changesValue(change *fi.PlanChange) map[string]interface{} {
+	v := make(map[string]interface{})
+	for _, field := range change.Fields {
+		v[field.Name] = map[string]interface{}{
+			"old":  field.Old,
+			"new":  field.New,
+			"diff": field.Diff,
+		}
+	}
+	return v
+}
+
+// toValue converts a field of a task to a value that can be used in CEL expressions.
+// Other tasks are replaced by their name, and resources and nil values are omitted.
+func toValue(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() || depth > maxFieldDepth {
+		return nil
+	}
+
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+
+	if depth > 0 && v.CanInterface() {
+		switch t := v.Interface().(type) {
+		case fi.Resource:
+			return nil
+		case fi.CloudupTask:
+			if hasName, ok := t.(fi.HasName); ok {
+				return fi.ValueOf(hasName.GetName())
+			}
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return toValue(v.Elem(), depth)
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		var values []interface{}
+		for i := 0; i < v.Len(); i++ {
+			if value := toValue(v.Index(i), depth+1); value != nil {
+				values = append(values, value)
+			}
+		}
+		return values
+	case reflect.Map:
+		values := make(map[string]interface{})
+		iter := v.MapRange()
+		for iter.Next() {
+			if value := toValue(iter.Value(), depth+1); value != nil {
+				values[fmt.Sprint(iter.Key().Interface())] = value
+			}
+		}
+		return values
+	case reflect.Struct:
+		values := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				// Not exported
+				continue
+			}
+			if value := toValue(v.Field(i), depth+1); value != nil {
+				values[v.Type().Field(i).Name] = value
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates organization policies, written as CEL expressions, against the tasks and changes of a cluster update.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// Action is the outcome of a rule that is not satisfied
+type Action string
+
+const (
+	// ActionDeny fails the update
+	ActionDeny Action = "Deny"
+	// ActionWarn logs a warning
+	ActionWarn Action = "Warn"
+)
+
+// Policy is a set of rules, read from a YAML or JSON file
+type Policy struct {
+	// Rules are the rules of the policy
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a CEL expression that must hold for every task it applies to
+type Rule struct {
+	// Name identifies the rule in violations
+	Name string `json:"name"`
+	// TaskTypes restricts the rule to tasks of these types, e.g. LaunchTemplate; the rule applies to all tasks if empty
+	TaskTypes []string `json:"taskTypes,omitempty"`
+	// Actions restricts the rule to tasks with these planned actions: create, update, delete or none; the rule applies to all tasks if empty
+	Actions []string `json:"actions,omitempty"`
+	// Expression is a CEL expression that evaluates to true if the task complies with the rule
+	Expression string `json:"expression"`
+	// Action is Deny or Warn; defaults to Deny
+	Action Action `json:"action,omitempty"`
+	// Message describes the violation
+	Message string `json:"message,omitempty"`
+}
+
+// Violation is a rule that is not satisfied by a task
+type Violation struct {
+	// Rule is the name of the rule
+	Rule string
+	// Action is the action of the rule
+	Action Action
+	// Task is the type/name of the task
+	Task string
+	// Message describes the violation
+	Message string
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Rule, v.Task, v.Message)
+}
+
+// ParsePolicy parses a policy in YAML or JSON
+func ParsePolicy(b []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(b, policy); err != nil {
+		return nil, err
+	}
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if rule.Expression == "" {
+			return nil, fmt.Errorf("rule %q has no expression", rule.Name)
+		}
+		if rule.Action == "" {
+			rule.Action = ActionDeny
+		}
+		if rule.Action != ActionDeny && rule.Action != ActionWarn {
+			return nil, fmt.Errorf("rule %q has unknown action %q, must be %s or %s", rule.Name, rule.Action, ActionDeny, ActionWarn)
+		}
+	}
+	return policy, nil
+}
+
+// ReadPolicies reads the policies in the .yaml, .yml and .json files of a directory, in name order.
+// A directory that does not exist has no policies.
+func ReadPolicies(ctx context.Context, dir vfs.Path) ([]*Policy, error) {
+	files, err := dir.ReadDir()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing policies in %q: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path() < files[j].Path()
+	})
+
+	var policies []*Policy
+	for _, file := range files {
+		switch strings.ToLower(path.Ext(file.Base())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		b, err := file.ReadFile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading policy %q: %w", file, err)
+		}
+		policy, err := ParsePolicy(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing policy %q: %w", file, err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type testSubnet struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+	Public    *bool
+}
+
+func (*testSubnet) Run(_ *fi.CloudupContext) error {
+	panic("not implemented")
+}
+
+func (t *testSubnet) GetName() *string {
+	return t.Name
+}
+
+type testLaunchTemplate struct {
+	Name                 *string
+	Lifecycle            fi.Lifecycle
+	InstanceType         *string
+	RootVolumeEncryption *bool
+	Subnets              []*testSubnet
+	UserData             fi.Resource
+}
+
+func (*testLaunchTemplate) Run(_ *fi.CloudupContext) error {
+	panic("not implemented")
+}
+
+func TestParsePolicy(t *testing.T) {
+	grid := []struct {
+		Policy        string
+		ExpectedError bool
+	}{
+		{
+			Policy: `
+rules:
+- name: allowed-instance-types
+  taskTypes: [LaunchTemplate]
+  expression: task.fields.InstanceType in ["t3.medium"]
+  action: Warn
+`,
+		},
+		{
+			Policy: `
+rules:
+- expression: "true"
+`,
+			ExpectedError: true,
+		},
+		{
+			Policy: `
+rules:
+- name: missing-expression
+`,
+			ExpectedError: true,
+		},
+		{
+			Policy: `
+rules:
+- name: unknown-action
+  expression: "true"
+  action: Block
+`,
+			ExpectedError: true,
+		},
+		{
+			Policy: `
+rules:
+- name: unknown-field
+  expresion: "true"
+`,
+			ExpectedError: true,
+		},
+	}
+	for _, g := range grid {
+		_, err := ParsePolicy([]byte(g.Policy))
+		if g.ExpectedError && err == nil {
+			t.Errorf("expected error parsing %s", g.Policy)
+		}
+		if !g.ExpectedError && err != nil {
+			t.Errorf("unexpected error parsing %s: %v", g.Policy, err)
+		}
+	}
+}
+
+func TestNewEngineInvalidExpression(t *testing.T) {
+	for _, expression := range []string{"task.fields.", `task.name + "x"`} {
+		_, err := NewEngine([]*Policy{{Rules: []Rule{{Name: "invalid", Expression: expression, Action: ActionDeny}}}})
+		if err == nil {
+			t.Errorf("expected error compiling %q", expression)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`
+rules:
+- name: allowed-instance-types
+  taskTypes: [LaunchTemplate]
+  expression: task.fields.InstanceType in ["t3.medium", "t3.large"]
+  message: instance type is not allowed
+- name: encrypted-control-plane
+  taskTypes: [LaunchTemplate]
+  expression: '!task.name.startsWith("control-plane") || (has(task.fields.RootVolumeEncryption) && task.fields.RootVolumeEncryption)'
+- name: no-public-subnets-in-prod
+  taskTypes: [Subnet]
+  expression: '!cluster.metadata.name.startsWith("prod") || !(has(task.fields.Public) && task.fields.Public)'
+- name: no-deletions
+  actions: [delete]
+  expression: "false"
+  action: Warn
+  message: resources would be deleted
+- name: no-instance-type-changes
+  actions: [update]
+  expression: '!("InstanceType" in changes)'
+  action: Warn
+`))
+	if err != nil {
+		t.Fatalf("unexpected error parsing policy: %v", err)
+	}
+	engine, err := NewEngine([]*Policy{policy})
+	if err != nil {
+		t.Fatalf("unexpected error compiling policy: %v", err)
+	}
+
+	publicSubnet := &testSubnet{Name: fi.PtrTo("utility-a"), Public: fi.PtrTo(true)}
+	privateSubnet := &testSubnet{Name: fi.PtrTo("a")}
+	tasks := map[string]fi.CloudupTask{
+		"Subnet/utility-a": publicSubnet,
+		"Subnet/a":         privateSubnet,
+		"LaunchTemplate/control-plane-a": &testLaunchTemplate{
+			Name:         fi.PtrTo("control-plane-a"),
+			InstanceType: fi.PtrTo("t3.medium"),
+			Subnets:      []*testSubnet{privateSubnet},
+			UserData:     fi.NewStringResource("#!/bin/bash"),
+		},
+		"LaunchTemplate/nodes-a": &testLaunchTemplate{
+			Name:         fi.PtrTo("nodes-a"),
+			InstanceType: fi.PtrTo("m5.24xlarge"),
+			Subnets:      []*testSubnet{privateSubnet},
+		},
+	}
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "prod.example.com"}}
+	plan := &fi.Plan{
+		SchemaVersion: fi.PlanSchemaVersion,
+		Changes: []fi.PlanChange{
+			{
+				Action: fi.PlanActionUpdate,
+				Type:   "LaunchTemplate",
+				Name:   "nodes-a",
+				Fields: []fi.PlanField{{Name: "InstanceType", Old: "t3.medium", New: "m5.24xlarge"}},
+			},
+			{
+				Action: fi.PlanActionDelete,
+				Type:   "Subnet",
+				Name:   "subnet-1234",
+			},
+		},
+	}
+
+	violations, err := engine.Evaluate(&Input{Cluster: cluster, Tasks: tasks, Plan: plan})
+	if err != nil {
+		t.Fatalf("unexpected error evaluating policy: %v", err)
+	}
+
+	expected := []Violation{
+		{
+			Rule:    "encrypted-control-plane",
+			Action:  ActionDeny,
+			Task:    "LaunchTemplate/control-plane-a",
+			Message: `expression "!task.name.startsWith(\"control-plane\") || (has(task.fields.RootVolumeEncryption) && task.fields.RootVolumeEncryption)" is false`,
+		},
+		{
+			Rule:    "allowed-instance-types",
+			Action:  ActionDeny,
+			Task:    "LaunchTemplate/nodes-a",
+			Message: "instance type is not allowed",
+		},
+		{
+			Rule:    "no-instance-type-changes",
+			Action:  ActionWarn,
+			Task:    "LaunchTemplate/nodes-a",
+			Message: `expression "!(\"InstanceType\" in changes)" is false`,
+		},
+		{
+			Rule:    "no-public-subnets-in-prod",
+			Action:  ActionDeny,
+			Task:    "Subnet/utility-a",
+			Message: `expression "!cluster.metadata.name.startsWith(\"prod\") || !(has(task.fields.Public) && task.fields.Public)" is false`,
+		},
+		{
+			Rule:    "no-deletions",
+			Action:  ActionWarn,
+			Task:    "Subnet/subnet-1234",
+			Message: "resources would be deleted",
+		},
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("unexpected violations\nactual:   %+v\nexpected: %+v", violations, expected)
+	}
+}
+
+func TestToValue(t *testing.T) {
+	subnet := &testSubnet{Name: fi.PtrTo("a")}
+	actual := toValue(reflect.ValueOf(&testLaunchTemplate{
+		Name:         fi.PtrTo("nodes"),
+		Lifecycle:    fi.LifecycleSync,
+		InstanceType: fi.PtrTo("t3.medium"),
+		Subnets:      []*testSubnet{subnet},
+		UserData:     fi.NewStringResource("#!/bin/bash"),
+	}), 0)
+	expected := map[string]interface{}{
+		"Name":         "nodes",
+		"Lifecycle":    "Sync",
+		"InstanceType": "t3.medium",
+		"Subnets":      []interface{}{"a"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected value\nactual:   %+v\nexpected: %+v", actual, expected)
+	}
+}
@@ -454,6 +454,8 @@ func guessSSHUser(image *ec2.Image) string {
 		return "admin"
 	case awsup.WellKnownAccountUbuntu:
 		return "ubuntu"
+	case awsup.WellKnownAccountRocky:
+		return "rocky"
 	case awsup.WellKnownAccountFlatcar:
 		return "core"
 	}
@@ -0,0 +1,473 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/kops"
+)
+
+// Format is the format in which a software bill of materials is written
+type Format string
+
+const (
+	FormatSPDX      Format = "spdx"
+	FormatCycloneDX Format = "cyclonedx"
+)
+
+// Formats is the list of supported software bill of materials formats
+var Formats = []string{string(FormatSPDX), string(FormatCycloneDX)}
+
+// ComponentType is the kind of software a component is
+type ComponentType string
+
+const (
+	// ComponentTypeFile is a file asset, such as a binary or an archive
+	ComponentTypeFile ComponentType = "file"
+	// ComponentTypeImage is a container image
+	ComponentTypeImage ComponentType = "container"
+	// ComponentTypePackage is an operating system package
+	ComponentTypePackage ComponentType = "package"
+)
+
+// NoAssertion is the SPDX value for information that was not determined
+const NoAssertion = "NOASSERTION"
+
+// VersionDistroResolved is the version of the operating system packages whose version is only resolved
+// from the package repositories of the distribution when they are installed on a node
+const VersionDistroResolved = "distro-resolved"
+
+// Component is a piece of software that a cluster installs
+type Component struct {
+	Type    ComponentType
+	Name    string
+	Version string
+	// SHA256 is the hex-encoded SHA256 hash of the file, or the digest of the image
+	SHA256 string
+	// SHA1 is the hex-encoded SHA1 hash of the file, for files that only publish a SHA1 hash
+	SHA1 string
+	// SourceURL is the canonical location the component is published at
+	SourceURL string
+	// DownloadURL is the location the cluster downloads the component from, when it differs from SourceURL
+	DownloadURL string
+	// License is the SPDX license expression declared for the component, if known
+	License string
+	// PURL is the package URL of the component
+	PURL string
+	// Comment describes how the component is installed, when it is not apparent from its locations
+	Comment string
+}
+
+// Document is a software bill of materials
+type Document struct {
+	// Name is the name of the document, usually the name of the cluster
+	Name string
+	// ID uniquely identifies the document
+	ID string
+	// Created is when the document was created
+	Created time.Time
+	// Comment describes what the document lists, and where the components it does not list are described
+	Comment string
+	// Components are the components listed in the document
+	Components []*Component
+}
+
+// NewDocument creates an empty document with a new ID
+func NewDocument(name string) *Document {
+	return &Document{
+		Name:    name,
+		ID:      uuid.NewString(),
+		Created: time.Now().UTC(),
+	}
+}
+
+// Write writes the document in the format
+func (d *Document) Write(out io.Writer, format Format) error {
+	var doc interface{}
+	switch format {
+	case FormatSPDX:
+		doc = d.spdx()
+	case FormatCycloneDX:
+		doc = d.cycloneDX()
+	default:
+		return fmt.Errorf("unknown sbom format %q, available formats: %s", format, strings.Join(Formats, ", "))
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// See https://spdx.github.io/spdx-spec/v2.3/
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func (d *Document) spdx() *spdxDocument {
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              d.Name,
+		DocumentNamespace: "https://kops.sigs.k8s.io/spdx/" + url.PathEscape(d.Name) + "-" + d.ID,
+		CreationInfo: spdxCreationInfo{
+			Created:  d.Created.Format(time.RFC3339),
+			Creators: []string{"Tool: kops-" + kops.Version},
+			Comment:  d.Comment,
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, c := range d.Components {
+		p := spdxPackage{
+			Name:             c.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      c.Version,
+			DownloadLocation: NoAssertion,
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  NoAssertion,
+			CopyrightText:    NoAssertion,
+			Comment:          c.Comment,
+		}
+		if c.SourceURL != "" {
+			p.DownloadLocation = c.SourceURL
+		}
+		if c.License != "" {
+			p.LicenseDeclared = c.License
+		}
+		if c.SHA256 != "" {
+			p.Checksums = append(p.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: c.SHA256})
+		}
+		if c.SHA1 != "" {
+			p.Checksums = append(p.Checksums, spdxChecksum{Algorithm: "SHA1", ChecksumValue: c.SHA1})
+		}
+		switch c.Type {
+		case ComponentTypeFile:
+			p.PrimaryPackagePurpose = "FILE"
+		case ComponentTypeImage:
+			p.PrimaryPackagePurpose = "CONTAINER"
+		default:
+			p.PrimaryPackagePurpose = "LIBRARY"
+		}
+		if c.PURL != "" {
+			p.ExternalRefs = append(p.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  c.PURL,
+			})
+		}
+
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: p.SPDXID,
+		})
+	}
+
+	return doc
+}
+
+// See https://cyclonedx.org/docs/1.5/json/
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Description        string                       `json:"description,omitempty"`
+	Hashes             []cycloneDXHash              `json:"hashes,omitempty"`
+	Licenses           []cycloneDXLicenseChoice     `json:"licenses,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicenseChoice struct {
+	Expression string `json:"expression"`
+}
+
+type cycloneDXExternalReference struct {
+	Type    string `json:"type"`
+	URL     string `json:"url"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (d *Document) cycloneDX() *cycloneDXDocument {
+	doc := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + d.ID,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: d.Created.Format(time.RFC3339),
+			Tools: cycloneDXTools{
+				Components: []cycloneDXComponent{
+					{Type: "application", Name: "kops", Version: kops.Version},
+				},
+			},
+			Component: cycloneDXComponent{Type: "platform", Name: d.Name, Description: d.Comment},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for i, c := range d.Components {
+		component := cycloneDXComponent{
+			BOMRef:      fmt.Sprintf("component-%d", i+1),
+			Name:        c.Name,
+			Version:     c.Version,
+			Description: c.Comment,
+			PURL:        c.PURL,
+		}
+		switch c.Type {
+		case ComponentTypeFile:
+			component.Type = "file"
+		case ComponentTypeImage:
+			component.Type = "container"
+		default:
+			component.Type = "library"
+		}
+		if c.SHA256 != "" {
+			component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: "SHA-256", Content: c.SHA256})
+		}
+		if c.SHA1 != "" {
+			component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: "SHA-1", Content: c.SHA1})
+		}
+		if c.License != "" {
+			component.Licenses = append(component.Licenses, cycloneDXLicenseChoice{Expression: c.License})
+		}
+		if c.SourceURL != "" {
+			component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{
+				Type: "distribution",
+				URL:  c.SourceURL,
+			})
+		}
+		if c.DownloadURL != "" && c.DownloadURL != c.SourceURL {
+			component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{
+				Type:    "distribution",
+				URL:     c.DownloadURL,
+				Comment: "download location",
+			})
+		}
+
+		doc.Components = append(doc.Components, component)
+	}
+
+	return doc
+}
+
+// versionRegexp matches versions like v1.26.0, 1.6.18 or v1.27.0-rc.1 in paths
+var versionRegexp = regexp.MustCompile(`v?[0-9]+\.[0-9]+\.[0-9]+(-(alpha|beta|rc)[.0-9]*)?`)
+
+// FileComponent builds the component for a file asset, from its canonical location and its hex-encoded hash
+func FileComponent(sourceURL string, downloadURL string, hash string) *Component {
+	c := &Component{
+		Type:        ComponentTypeFile,
+		Name:        sourceURL,
+		SourceURL:   sourceURL,
+		DownloadURL: downloadURL,
+		License:     knownLicense(sourceURL),
+	}
+	if u, err := url.Parse(sourceURL); err == nil {
+		c.Name = path.Base(u.Path)
+		// The version is usually a path segment, or part of the file name
+		if version := versionRegexp.FindAllString(u.Path, -1); len(version) != 0 {
+			c.Version = version[len(version)-1]
+		}
+	}
+
+	switch len(hash) {
+	case 64:
+		c.SHA256 = hash
+	case 40:
+		c.SHA1 = hash
+	}
+
+	purl := "pkg:generic/" + url.PathEscape(c.Name)
+	if c.Version != "" {
+		purl += "@" + url.PathEscape(c.Version)
+	}
+	c.PURL = purl + "?download_url=" + url.QueryEscape(sourceURL)
+
+	return c
+}
+
+// ImageComponent builds the component for a container image, from its canonical location and its digest, if known
+func ImageComponent(sourceImage string, downloadImage string, digest string) *Component {
+	repository, tag := sourceImage, ""
+	if i := strings.Index(repository, "@"); i != -1 {
+		if digest == "" {
+			digest = repository[i+1:]
+		}
+		repository = repository[:i]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		tag = repository[i+1:]
+		repository = repository[:i]
+	}
+
+	c := &Component{
+		Type:      ComponentTypeImage,
+		Name:      repository,
+		Version:   tag,
+		SourceURL: sourceImage,
+		License:   knownLicense(sourceImage),
+	}
+	if downloadImage != sourceImage {
+		c.DownloadURL = downloadImage
+	}
+	if strings.HasPrefix(digest, "sha256:") {
+		c.SHA256 = strings.TrimPrefix(digest, "sha256:")
+	}
+
+	name := path.Base(repository)
+	purl := "pkg:oci/" + url.PathEscape(name)
+	if digest != "" {
+		purl += "@" + url.PathEscape(digest)
+	}
+	purl += "?repository_url=" + url.QueryEscape(repository)
+	if tag != "" {
+		purl += "&tag=" + url.QueryEscape(tag)
+	}
+	c.PURL = purl
+
+	return c
+}
+
+// PackageComponent builds the component for an operating system package of the distribution
+func PackageComponent(packageType string, distribution string, name string, version string) *Component {
+	c := &Component{
+		Type:    ComponentTypePackage,
+		Name:    name,
+		Version: version,
+	}
+
+	purl := "pkg:" + packageType + "/" + url.PathEscape(distribution) + "/" + url.PathEscape(name)
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	c.PURL = purl
+
+	return c
+}
+
+// knownLicenses are the licenses of the projects published at these location prefixes
+var knownLicenses = []struct {
+	prefix  string
+	license string
+}{
+	// The projects of the Kubernetes organizations are licensed under Apache-2.0
+	{prefix: "https://dl.k8s.io/", license: "Apache-2.0"},
+	{prefix: "https://cdn.dl.k8s.io/", license: "Apache-2.0"},
+	{prefix: "https://storage.googleapis.com/kubernetes-release/", license: "Apache-2.0"},
+	{prefix: "https://artifacts.k8s.io/binaries/kops/", license: "Apache-2.0"},
+	{prefix: "https://github.com/kubernetes/kops/", license: "Apache-2.0"},
+	{prefix: "registry.k8s.io/", license: "Apache-2.0"},
+	{prefix: "k8s.gcr.io/", license: "Apache-2.0"},
+	{prefix: "https://github.com/containerd/containerd/", license: "Apache-2.0"},
+	{prefix: "https://github.com/opencontainers/runc/", license: "Apache-2.0"},
+	{prefix: "https://github.com/containernetworking/plugins/", license: "Apache-2.0"},
+	{prefix: "https://storage.googleapis.com/k8s-artifacts-cni/", license: "Apache-2.0"},
+	{prefix: "https://download.docker.com/", license: "Apache-2.0"},
+}
+
+// knownLicense returns the license declared by the project publishing the location, or "" if it is not known
+func knownLicense(location string) string {
+	for _, l := range knownLicenses {
+		if strings.HasPrefix(location, l.prefix) {
+			return l.license
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileComponent(t *testing.T) {
+	sha256 := strings.Repeat("a", 64)
+	sha1 := strings.Repeat("b", 40)
+
+	grid := []struct {
+		url      string
+		hash     string
+		expected Component
+	}{
+		{
+			url:  "https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kubelet",
+			hash: sha256,
+			expected: Component{
+				Type:      ComponentTypeFile,
+				Name:      "kubelet",
+				Version:   "v1.26.0",
+				SHA256:    sha256,
+				SourceURL: "https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kubelet",
+				License:   "Apache-2.0",
+				PURL:      "pkg:generic/kubelet@v1.26.0?download_url=https%3A%2F%2Fdl.k8s.io%2Frelease%2Fv1.26.0%2Fbin%2Flinux%2Famd64%2Fkubelet",
+			},
+		},
+		{
+			url:  "https://github.com/containerd/containerd/releases/download/v1.6.18/containerd-1.6.18-linux-amd64.tar.gz",
+			hash: sha1,
+			expected: Component{
+				Type:      ComponentTypeFile,
+				Name:      "containerd-1.6.18-linux-amd64.tar.gz",
+				Version:   "1.6.18",
+				SHA1:      sha1,
+				SourceURL: "https://github.com/containerd/containerd/releases/download/v1.6.18/containerd-1.6.18-linux-amd64.tar.gz",
+				License:   "Apache-2.0",
+				PURL:      "pkg:generic/containerd-1.6.18-linux-amd64.tar.gz@1.6.18?download_url=https%3A%2F%2Fgithub.com%2Fcontainerd%2Fcontainerd%2Freleases%2Fdownload%2Fv1.6.18%2Fcontainerd-1.6.18-linux-amd64.tar.gz",
+			},
+		},
+		{
+			url: "https://example.com/files/tool",
+			expected: Component{
+				Type:      ComponentTypeFile,
+				Name:      "tool",
+				SourceURL: "https://example.com/files/tool",
+				PURL:      "pkg:generic/tool?download_url=https%3A%2F%2Fexample.com%2Ffiles%2Ftool",
+			},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.url, func(t *testing.T) {
+			actual := FileComponent(g.url, "", g.hash)
+			if !reflect.DeepEqual(*actual, g.expected) {
+				t.Errorf("unexpected component\nactual:   %+v\nexpected: %+v", *actual, g.expected)
+			}
+		})
+	}
+}
+
+func TestImageComponent(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("c", 64)
+
+	grid := []struct {
+		image    string
+		download string
+		digest   string
+		expected Component
+	}{
+		{
+			image:    "registry.k8s.io/kube-apiserver:v1.26.0",
+			download: "registry.example.com/kube-apiserver:v1.26.0",
+			digest:   digest,
+			expected: Component{
+				Type:        ComponentTypeImage,
+				Name:        "registry.k8s.io/kube-apiserver",
+				Version:     "v1.26.0",
+				SHA256:      strings.Repeat("c", 64),
+				SourceURL:   "registry.k8s.io/kube-apiserver:v1.26.0",
+				DownloadURL: "registry.example.com/kube-apiserver:v1.26.0",
+				License:     "Apache-2.0",
+				PURL:        "pkg:oci/kube-apiserver@sha256:" + strings.Repeat("c", 64) + "?repository_url=registry.k8s.io%2Fkube-apiserver&tag=v1.26.0",
+			},
+		},
+		{
+			image:    "localhost:5000/cilium/cilium:v1.13.0@" + digest,
+			download: "localhost:5000/cilium/cilium:v1.13.0@" + digest,
+			expected: Component{
+				Type:      ComponentTypeImage,
+				Name:      "localhost:5000/cilium/cilium",
+				Version:   "v1.13.0",
+				SHA256:    strings.Repeat("c", 64),
+				SourceURL: "localhost:5000/cilium/cilium:v1.13.0@" + digest,
+				PURL:      "pkg:oci/cilium@sha256:" + strings.Repeat("c", 64) + "?repository_url=localhost%3A5000%2Fcilium%2Fcilium&tag=v1.13.0",
+			},
+		},
+		{
+			image:    "localhost:5000/pause",
+			download: "localhost:5000/pause",
+			expected: Component{
+				Type:      ComponentTypeImage,
+				Name:      "localhost:5000/pause",
+				SourceURL: "localhost:5000/pause",
+				PURL:      "pkg:oci/pause?repository_url=localhost%3A5000%2Fpause",
+			},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.image, func(t *testing.T) {
+			actual := ImageComponent(g.image, g.download, g.digest)
+			if !reflect.DeepEqual(*actual, g.expected) {
+				t.Errorf("unexpected component\nactual:   %+v\nexpected: %+v", *actual, g.expected)
+			}
+		})
+	}
+}
+
+func TestDocument_Write(t *testing.T) {
+	doc := &Document{
+		Name:    "k8s-cluster.example.com",
+		ID:      "5b0e2cd8-1e3b-4b0f-8a4f-3f6f1c1a2b3c",
+		Created: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		Comment: "Lists the assets of the cluster",
+		Components: []*Component{
+			FileComponent("https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kubelet", "https://files.example.com/release/v1.26.0/bin/linux/amd64/kubelet", strings.Repeat("a", 64)),
+			ImageComponent("registry.k8s.io/pause:3.9", "registry.k8s.io/pause:3.9", ""),
+			PackageComponent("deb", "ubuntu", "conntrack", "1:1.4.6-2build2"),
+		},
+	}
+	doc.Components[2].Comment = "Installed by nodeup"
+
+	var spdx bytes.Buffer
+	if err := doc.Write(&spdx, FormatSPDX); err != nil {
+		t.Fatalf("error writing spdx: %v", err)
+	}
+	var spdxDoc spdxDocument
+	if err := json.Unmarshal(spdx.Bytes(), &spdxDoc); err != nil {
+		t.Fatalf("error parsing spdx: %v", err)
+	}
+	if spdxDoc.SPDXVersion != "SPDX-2.3" || spdxDoc.CreationInfo.Comment != doc.Comment || len(spdxDoc.Packages) != 3 || len(spdxDoc.Relationships) != 3 {
+		t.Errorf("unexpected spdx document:\n%s", spdx.String())
+	}
+	if p := spdxDoc.Packages[0]; p.LicenseDeclared != "Apache-2.0" || p.Checksums[0].Algorithm != "SHA256" || p.PrimaryPackagePurpose != "FILE" {
+		t.Errorf("unexpected spdx file package %+v", p)
+	}
+	if p := spdxDoc.Packages[2]; p.LicenseDeclared != NoAssertion || p.DownloadLocation != NoAssertion || p.ExternalRefs[0].ReferenceLocator != "pkg:deb/ubuntu/conntrack@1:1.4.6-2build2" || p.Comment != "Installed by nodeup" {
+		t.Errorf("unexpected spdx os package %+v", p)
+	}
+
+	var cycloneDX bytes.Buffer
+	if err := doc.Write(&cycloneDX, FormatCycloneDX); err != nil {
+		t.Fatalf("error writing cyclonedx: %v", err)
+	}
+	var cycloneDXDoc cycloneDXDocument
+	if err := json.Unmarshal(cycloneDX.Bytes(), &cycloneDXDoc); err != nil {
+		t.Fatalf("error parsing cyclonedx: %v", err)
+	}
+	if cycloneDXDoc.SerialNumber != "urn:uuid:"+doc.ID || cycloneDXDoc.Metadata.Component.Description != doc.Comment || len(cycloneDXDoc.Components) != 3 {
+		t.Errorf("unexpected cyclonedx document:\n%s", cycloneDX.String())
+	}
+	if c := cycloneDXDoc.Components[0]; c.Type != "file" || len(c.ExternalReferences) != 2 || c.Hashes[0].Algorithm != "SHA-256" {
+		t.Errorf("unexpected cyclonedx file component %+v", c)
+	}
+	if c := cycloneDXDoc.Components[1]; c.Type != "container" || len(c.ExternalReferences) != 1 {
+		t.Errorf("unexpected cyclonedx image component %+v", c)
+	}
+	if c := cycloneDXDoc.Components[2]; c.Type != "library" || c.Description != "Installed by nodeup" {
+		t.Errorf("unexpected cyclonedx os package component %+v", c)
+	}
+
+	if err := doc.Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
@@ -312,6 +312,10 @@ func (v *ValidationCluster) validateNodes(cloudGroups map[string]*cloudinstances
 					// bastion nodes don't join the cluster
 					nodeExpectedToJoin = false
 				}
+				if cloudGroup.InstanceGroup.Spec.Role == kops.InstanceGroupRoleEtcd {
+					// etcd nodes only run static pods and don't join the cluster
+					nodeExpectedToJoin = false
+				}
 				if member.State == cloudinstances.WarmPool {
 					nodeExpectedToJoin = false
 				}
@@ -17,6 +17,15 @@ limitations under the License.
 package wellknownports
 
 const (
+	// SSH is the port where sshd listens.
+	SSH = 22
+
+	// DNS is the port where DNS servers listen.
+	DNS = 53
+
+	// BGP is the port used by Calico and kube-router to exchange routes.
+	BGP = 179
+
 	// KubeAPIServer is the port where kube-apiserver listens.
 	KubeAPIServer = 443
 
@@ -56,11 +65,55 @@ const (
 	// ProtokubeGossipMemberlist is the port where protokube listens for the memberlist-backed gossip
 	ProtokubeGossipMemberlist = 4000
 
-	// 4001 is etcd main, 4002 is etcd events
+	// EtcdMainClientPort is the client port, for the main etcd
+	EtcdMainClientPort = 4001
+
+	// EtcdEventsClientPort is the client port, for the events etcd
+	EtcdEventsClientPort = 4002
 
 	// EtcdCiliumClientPort is the port were the Cilium etcd cluster listens
 	EtcdCiliumClientPort = 4003
 
+	// EtcdMainPeerPort is the peer port, for the main etcd
+	EtcdMainPeerPort = 2380
+
+	// EtcdEventsPeerPort is the peer port, for the events etcd
+	EtcdEventsPeerPort = 2381
+
+	// EtcdCiliumPeerPort is the peer port, for the Cilium etcd
+	EtcdCiliumPeerPort = 2382
+
+	// EtcdExtraClusters is the number of additional etcd clusters (beyond main, events and cilium) we reserve ports for.
+	// Each additional etcd cluster uses the ports at its index in the ranges below.
+	EtcdExtraClusters = 8
+
+	// EtcdExtraGRPC is the first GRPC port used by etcd-manager, for the additional etcd clusters (3970-3977)
+	EtcdExtraGRPC = 3970
+
+	// EtcdExtraQuarantinedClientPort is the first port used by etcd when quarantined, for the additional etcd clusters (3978-3985)
+	EtcdExtraQuarantinedClientPort = 3978
+
+	// EtcdExtraClientPort is the first client port, for the additional etcd clusters (4004-4011)
+	EtcdExtraClientPort = 4004
+
+	// EtcdExtraPeerPort is the first peer port, for the additional etcd clusters (2383-2390)
+	EtcdExtraPeerPort = 2383
+
+	// CiliumHealth is the port where cilium-health listens
+	CiliumHealth = 4240
+
+	// HubbleServer is the port where the Hubble server of Cilium listens
+	HubbleServer = 4244
+
+	// CalicoTypha is the port where Calico Typha listens
+	CalicoTypha = 5473
+
+	// WeaveMesh is the port used by Weave for control and data traffic
+	WeaveMesh = 6783
+
+	// WeaveFastDatapath is the port used by Weave for fast datapath traffic
+	WeaveFastDatapath = 6784
+
 	// CiliumOperatorPrometheusPort is the port the Cilium Operator exposes metrics
 	CiliumPrometheusOperatorPort = 6942
 
@@ -73,6 +126,12 @@ const (
 	// VxlanUDP is the port used by VXLAN tunneling over UDP
 	VxlanUDP = 8472
 
+	// VxlanIANAUDP is the IANA-assigned port of VXLAN, used by Calico and Kopeio
+	VxlanIANAUDP = 4789
+
+	// FlannelUDP is the port used by the udp backend of Flannel
+	FlannelUDP = 8285
+
 	// AWSLBCMetricsPort is reserved for the AWS Load Balancer Controller's metrics.
 	AWSLBCMetricsPort = 9442
 
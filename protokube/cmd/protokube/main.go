@@ -57,6 +57,7 @@ func run() error {
 	var containerized, master, gossip bool
 	var cloud, clusterID, dnsInternalSuffix, gossipSecret, gossipListen, gossipProtocol, gossipSecretSecondary, gossipListenSecondary, gossipProtocolSecondary string
 	var flagChannels string
+	var etcdNodesClusters []string
 	var dnsUpdateInterval int
 
 	flag.BoolVar(&containerized, "containerized", containerized, "Set if we are running containerized")
@@ -74,6 +75,7 @@ func run() error {
 	flag.StringVar(&gossipListenSecondary, "gossip-listen-secondary", fmt.Sprintf("0.0.0.0:%d", wellknownports.ProtokubeGossipMemberlist), "address:port on which to bind for gossip")
 	flags.StringVar(&gossipSecretSecondary, "gossip-secret-secondary", gossipSecret, "Secret to use to secure gossip")
 	flags.StringSliceVarP(&zones, "zone", "z", []string{}, "Configure permitted zones and their mappings")
+	flags.StringSliceVar(&etcdNodesClusters, "etcd-nodes-clusters", etcdNodesClusters, "Names of the etcd clusters running on etcd nodes, whose addresses are maintained in /etc/hosts")
 
 	bootstrapMasterNodeLabels := false
 	flag.BoolVar(&bootstrapMasterNodeLabels, "bootstrap-master-node-labels", bootstrapMasterNodeLabels, "Bootstrap the labels for master nodes (required in k8s 1.16)")
@@ -234,6 +236,24 @@ func run() error {
 		}()
 	}
 
+	if len(etcdNodesClusters) != 0 {
+		provider, ok := cloudProvider.(protokube.EtcdNodesProvider)
+		if !ok {
+			return fmt.Errorf("etcd nodes are not supported on cloud %q", cloud)
+		}
+		if clusterID == "" {
+			return fmt.Errorf("cluster-id is required when etcd-nodes-clusters is set")
+		}
+
+		etcdHosts := &protokube.EtcdHosts{
+			Provider:     provider,
+			HostsPath:    path.Join(rootfs, "etc/hosts"),
+			ClusterName:  clusterID,
+			EtcdClusters: etcdNodesClusters,
+		}
+		go etcdHosts.RunSyncLoop()
+	}
+
 	var channels []string
 	if flagChannels != "" {
 		channels = strings.Split(flagChannels, ",")
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/protokube/pkg/gossip/dns/hosts"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// EtcdNodesProvider finds the addresses of the nodes running the members of an etcd cluster,
+// for etcd clusters that run on instance groups with the Etcd role.
+type EtcdNodesProvider interface {
+	EtcdNodeAddresses(etcdClusterName string) ([]string, error)
+}
+
+var _ EtcdNodesProvider = &AWSCloudProvider{}
+
+// EtcdNodeAddresses returns the addresses of the instances that have a volume of the etcd cluster attached.
+func (a *AWSCloudProvider) EtcdNodeAddresses(etcdClusterName string) ([]string, error) {
+	request := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			awsup.NewEC2Filter("tag:kubernetes.io/cluster/"+a.clusterTag, "owned"),
+			awsup.NewEC2Filter("tag:"+awsup.TagNameRolePrefix+"etcd", "1"),
+			awsup.NewEC2Filter("tag-key", awsup.TagNameEtcdClusterPrefix+etcdClusterName),
+			awsup.NewEC2Filter("attachment.status", "attached"),
+		},
+	}
+
+	var instanceIDs []*string
+	err := a.ec2.DescribeVolumesPages(request, func(p *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		for _, volume := range p.Volumes {
+			for _, attachment := range volume.Attachments {
+				instanceIDs = append(instanceIDs, attachment.InstanceId)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying for volumes of etcd cluster %q: %w", etcdClusterName, err)
+	}
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+
+	var addresses []string
+	err = a.ec2.DescribeInstancesPages(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs}, func(p *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, r := range p.Reservations {
+			for _, i := range r.Instances {
+				address := aws.StringValue(i.Ipv6Address)
+				if address == "" {
+					address = aws.StringValue(i.PrivateIpAddress)
+				}
+				if address != "" {
+					addresses = append(addresses, address)
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying for instances of etcd cluster %q: %w", etcdClusterName, err)
+	}
+
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// EtcdHosts maintains /etc/hosts records for the client names of etcd clusters running on etcd nodes.
+// DNS records for these names cannot be published by dns-controller, because the etcd nodes
+// do not join the cluster, and kube-apiserver needs them before it can start.
+type EtcdHosts struct {
+	// Provider finds the addresses of the etcd nodes
+	Provider EtcdNodesProvider
+	// HostsPath is the path of the hosts file to update
+	HostsPath string
+	// ClusterName is the name of the cluster, used to build the client names
+	ClusterName string
+	// EtcdClusters are the names of the etcd clusters running on etcd nodes
+	EtcdClusters []string
+}
+
+// RunSyncLoop keeps the hosts file in sync with the etcd nodes.
+func (e *EtcdHosts) RunSyncLoop() {
+	for {
+		if err := e.syncOnce(); err != nil {
+			klog.Warningf("error updating etcd hosts (will sleep and retry): %v", err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+func (e *EtcdHosts) syncOnce() error {
+	records := make(map[string][]string)
+	for _, etcdCluster := range e.EtcdClusters {
+		addresses, err := e.Provider.EtcdNodeAddresses(etcdCluster)
+		if err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			return fmt.Errorf("no etcd nodes found for etcd cluster %q", etcdCluster)
+		}
+		records[etcdCluster+".etcd.internal."+e.ClusterName] = addresses
+	}
+
+	mutator := func(existing []string) (*hosts.HostMap, error) {
+		hostMap := &hosts.HostMap{}
+		badLines := hostMap.Parse(existing)
+		if len(badLines) != 0 {
+			klog.Warningf("ignoring unexpected lines in /etc/hosts: %v", badLines)
+		}
+
+		for hostname, addresses := range records {
+			hostMap.ReplaceRecords(hostname, addresses)
+		}
+
+		return hostMap, nil
+	}
+
+	return hosts.UpdateHostsFileWithRecords(e.HostsPath, mutator)
+}
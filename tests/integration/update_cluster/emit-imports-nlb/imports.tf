@@ -0,0 +1,289 @@
+import {
+  to = aws_autoscaling_group.master-us-test-1a-masters-minimal-example-com
+  id = "master-us-test-1a.masters.minimal.example.com"
+}
+
+import {
+  to = aws_autoscaling_group.nodes-minimal-example-com
+  id = "nodes.minimal.example.com"
+}
+
+import {
+  to = aws_autoscaling_lifecycle_hook.master-us-test-1a-NTHLifecycleHook
+  id = "master-us-test-1a.masters.minimal.example.com/master-us-test-1a-NTHLifecycleHook"
+}
+
+import {
+  to = aws_autoscaling_lifecycle_hook.nodes-NTHLifecycleHook
+  id = "nodes.minimal.example.com/nodes-NTHLifecycleHook"
+}
+
+import {
+  to = aws_cloudwatch_event_rule.minimal-example-com-ASGLifecycle
+  id = "minimal.example.com-ASGLifecycle"
+}
+
+import {
+  to = aws_cloudwatch_event_rule.minimal-example-com-InstanceScheduledChange
+  id = "minimal.example.com-InstanceScheduledChange"
+}
+
+import {
+  to = aws_cloudwatch_event_rule.minimal-example-com-InstanceStateChange
+  id = "minimal.example.com-InstanceStateChange"
+}
+
+import {
+  to = aws_cloudwatch_event_rule.minimal-example-com-SpotInterruption
+  id = "minimal.example.com-SpotInterruption"
+}
+
+import {
+  to = aws_cloudwatch_event_target.minimal-example-com-ASGLifecycle-Target
+  id = "minimal.example.com-ASGLifecycle/1"
+}
+
+import {
+  to = aws_cloudwatch_event_target.minimal-example-com-InstanceScheduledChange-Target
+  id = "minimal.example.com-InstanceScheduledChange/1"
+}
+
+import {
+  to = aws_cloudwatch_event_target.minimal-example-com-InstanceStateChange-Target
+  id = "minimal.example.com-InstanceStateChange/1"
+}
+
+import {
+  to = aws_cloudwatch_event_target.minimal-example-com-SpotInterruption-Target
+  id = "minimal.example.com-SpotInterruption/1"
+}
+
+import {
+  to = aws_ebs_volume.us-test-1a-etcd-events-minimal-example-com
+  id = "vol-N"
+}
+
+import {
+  to = aws_ebs_volume.us-test-1a-etcd-main-minimal-example-com
+  id = "vol-N"
+}
+
+import {
+  to = aws_iam_instance_profile.masters-minimal-example-com
+  id = "masters.minimal.example.com"
+}
+
+import {
+  to = aws_iam_instance_profile.nodes-minimal-example-com
+  id = "nodes.minimal.example.com"
+}
+
+import {
+  to = aws_iam_role.masters-minimal-example-com
+  id = "masters.minimal.example.com"
+}
+
+import {
+  to = aws_iam_role.nodes-minimal-example-com
+  id = "nodes.minimal.example.com"
+}
+
+import {
+  to = aws_iam_role_policy.masters-minimal-example-com
+  id = "masters.minimal.example.com:masters.minimal.example.com"
+}
+
+import {
+  to = aws_iam_role_policy.nodes-minimal-example-com
+  id = "nodes.minimal.example.com:nodes.minimal.example.com"
+}
+
+import {
+  to = aws_internet_gateway.minimal-example-com
+  id = "igw-2"
+}
+
+import {
+  to = aws_key_pair.kubernetes-minimal-example-com-c4a6ed9aa889b9e2c39cd663eb9c7157
+  id = "kubernetes.minimal.example.com-c4:a6:ed:9a:a8:89:b9:e2:c3:9c:d6:63:eb:9c:71:57"
+}
+
+import {
+  to = aws_launch_template.master-us-test-1a-masters-minimal-example-com
+  id = "lt-N"
+}
+
+import {
+  to = aws_launch_template.nodes-minimal-example-com
+  id = "lt-N"
+}
+
+import {
+  to = aws_lb.api-minimal-example-com
+  id = "arn:aws-test:elasticloadbalancing:us-test-1:000000000000:loadbalancer/net/api-minimal-example-com-gecgf7/1"
+}
+
+import {
+  to = aws_lb_listener.api-minimal-example-com-443
+  id = "arn:aws-test:elasticloadbalancing:us-test-1:000000000000:listener/net/api-minimal-example-com-gecgf7/1/1"
+}
+
+import {
+  to = aws_lb_target_group.tcp-minimal-example-com-5905t8
+  id = "arn:aws-test:elasticloadbalancing:us-test-1:000000000000:targetgroup/tcp-minimal-example-com-5905t8/4"
+}
+
+import {
+  to = aws_route.route-0-0-0-0--0
+  id = "rtb-1_0.0.0.0/0"
+}
+
+import {
+  to = aws_route.route-__--0
+  id = "rtb-1_::/0"
+}
+
+import {
+  to = aws_route53_record.api-minimal-example-com
+  id = "Z1AFAKE1ZON3YO_api.minimal.example.com_A"
+}
+
+import {
+  to = aws_route_table.minimal-example-com
+  id = "rtb-1"
+}
+
+import {
+  to = aws_route_table_association.us-test-1a-minimal-example-com
+  id = "subnet-1/rtb-1"
+}
+
+import {
+  to = aws_security_group.api-elb-minimal-example-com
+  id = "sg-N"
+}
+
+import {
+  to = aws_security_group.masters-minimal-example-com
+  id = "sg-N"
+}
+
+import {
+  to = aws_security_group.nodes-minimal-example-com
+  id = "sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-22to22-masters-minimal-example-com
+  id = "sg-N_ingress_tcp_22_22_0.0.0.0/0"
+}
+
+import {
+  to = aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-22to22-nodes-minimal-example-com
+  id = "sg-N_ingress_tcp_22_22_0.0.0.0/0"
+}
+
+import {
+  to = aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-443to443-masters-minimal-example-com
+  id = "sg-N_ingress_tcp_443_443_0.0.0.0/0"
+}
+
+import {
+  to = aws_security_group_rule.from-masters-minimal-example-com-egress-all-0to0-0-0-0-0--0
+  id = "sg-N_egress_all_0_65536_0.0.0.0/0"
+}
+
+import {
+  to = aws_security_group_rule.from-masters-minimal-example-com-egress-all-0to0-__--0
+  id = "sg-N_egress_all_0_65536_::/0"
+}
+
+import {
+  to = aws_security_group_rule.from-masters-minimal-example-com-ingress-all-0to0-masters-minimal-example-com
+  id = "sg-N_ingress_all_0_65536_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-masters-minimal-example-com-ingress-all-0to0-nodes-minimal-example-com
+  id = "sg-N_ingress_all_0_65536_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-egress-all-0to0-0-0-0-0--0
+  id = "sg-N_egress_all_0_65536_0.0.0.0/0"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-egress-all-0to0-__--0
+  id = "sg-N_egress_all_0_65536_::/0"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-ingress-all-0to0-nodes-minimal-example-com
+  id = "sg-N_ingress_all_0_65536_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-1to2379-masters-minimal-example-com
+  id = "sg-N_ingress_tcp_1_2379_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-2382to4000-masters-minimal-example-com
+  id = "sg-N_ingress_tcp_2382_4000_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-ingress-tcp-4003to65535-masters-minimal-example-com
+  id = "sg-N_ingress_tcp_4003_65535_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.from-nodes-minimal-example-com-ingress-udp-1to65535-masters-minimal-example-com
+  id = "sg-N_ingress_udp_1_65535_sg-N"
+}
+
+import {
+  to = aws_security_group_rule.https-elb-to-master
+  id = "sg-N_ingress_tcp_443_443_172.20.0.0/16"
+}
+
+import {
+  to = aws_security_group_rule.https-lb-to-master-10-1-0-0--16
+  id = "sg-N_ingress_tcp_443_443_10.1.0.0/16"
+}
+
+import {
+  to = aws_security_group_rule.icmp-pmtu-api-elb-0-0-0-0--0
+  id = "sg-N_ingress_icmp_3_4_0.0.0.0/0"
+}
+
+import {
+  to = aws_sqs_queue.minimal-example-com-nth
+  id = "https://sqs.us-east-1.amazonaws.com/123456789123/minimal-example-com-nth"
+}
+
+import {
+  to = aws_subnet.us-test-1a-minimal-example-com
+  id = "subnet-1"
+}
+
+import {
+  to = aws_vpc.minimal-example-com
+  id = "vpc-1"
+}
+
+import {
+  to = aws_vpc_dhcp_options.minimal-example-com
+  id = "dopt-1"
+}
+
+import {
+  to = aws_vpc_dhcp_options_association.minimal-example-com
+  id = "vpc-1"
+}
+
+import {
+  to = aws_vpc_ipv4_cidr_block_association.cidr-10-1-0-0--16
+  id = "vpc-1-0"
+}
@@ -25,6 +25,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"regexp/syntax"
 	"strconv"
 	"strings"
 	"time"
@@ -93,6 +94,8 @@ func (r *assetResource) GetSource() *Source {
 type AssetStore struct {
 	cacheDir string
 	assets   []*asset
+	// placeholders is set when lookups that match no asset resolve to an empty placeholder
+	placeholders bool
 }
 
 func NewAssetStore(cacheDir string) *AssetStore {
@@ -102,6 +105,56 @@ func NewAssetStore(cacheDir string) *AssetStore {
 	return a
 }
 
+// NewPlaceholderAssetStore returns an asset store that resolves every lookup to an empty placeholder,
+// so that the nodeup tasks can be built without downloading the assets, e.g. to print the task graph.
+// A lookup by expression resolves to a single placeholder, named after the first path matching the expression.
+func NewPlaceholderAssetStore() *AssetStore {
+	return &AssetStore{placeholders: true}
+}
+
+// placeholderAsset returns an empty asset for the path
+func placeholderAsset(assetPath string) *asset {
+	return &asset{
+		Key:       path.Base(assetPath),
+		AssetPath: assetPath,
+		resource:  NewStringResource(""),
+	}
+}
+
+// placeholderPath returns the first path matching the expression, taking the first alternative of every choice
+func placeholderPath(expr *regexp.Regexp) string {
+	re, err := syntax.Parse(expr.String(), syntax.Perl)
+	if err != nil {
+		return expr.String()
+	}
+	var sb strings.Builder
+	var walk func(re *syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			sb.WriteString(string(re.Rune))
+		case syntax.OpCharClass:
+			if len(re.Rune) != 0 {
+				sb.WriteRune(re.Rune[0])
+			}
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			sb.WriteRune('x')
+		case syntax.OpCapture, syntax.OpPlus, syntax.OpAlternate:
+			walk(re.Sub[0])
+		case syntax.OpRepeat:
+			for i := 0; i < re.Min; i++ {
+				walk(re.Sub[0])
+			}
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				walk(sub)
+			}
+		}
+	}
+	walk(re)
+	return sb.String()
+}
+
 func (a *AssetStore) FindMatches(expr *regexp.Regexp) map[string]Resource {
 	matches := make(map[string]Resource)
 
@@ -112,6 +165,33 @@ func (a *AssetStore) FindMatches(expr *regexp.Regexp) map[string]Resource {
 			matches[a.Key] = &assetResource{Asset: a}
 		}
 	}
+	if len(matches) == 0 && a.placeholders {
+		placeholder := placeholderAsset(placeholderPath(expr))
+		matches[placeholder.Key] = &assetResource{Asset: placeholder}
+	}
+
+	return matches
+}
+
+// FindMatchesInArchive returns the files extracted from the archive with the given file name
+// whose path matches the expression, keyed by their path in the archive.
+func (a *AssetStore) FindMatchesInArchive(archiveName string, expr *regexp.Regexp) map[string]Resource {
+	matches := make(map[string]Resource)
+
+	klog.Infof("Matching assets of archive %q for %q:", archiveName, expr.String())
+	for _, a := range a.assets {
+		if a.source == nil || a.source.Parent == nil || path.Base(a.source.Parent.URL) != archiveName {
+			continue
+		}
+		if expr.MatchString(a.AssetPath) {
+			klog.Infof("    %s", a.AssetPath)
+			matches[a.AssetPath] = &assetResource{Asset: a}
+		}
+	}
+	if len(matches) == 0 && a.placeholders {
+		placeholder := placeholderAsset(placeholderPath(expr))
+		matches[placeholder.AssetPath] = &assetResource{Asset: placeholder}
+	}
 
 	return matches
 }
@@ -153,6 +233,14 @@ func (a *AssetStore) Find(key string, assetPath string) (Resource, error) {
 	}
 
 	if len(matches) == 0 {
+		if a.placeholders {
+			if assetPath == "" {
+				assetPath = key
+			}
+			placeholder := placeholderAsset(assetPath)
+			placeholder.Key = key
+			return &assetResource{Asset: placeholder}, nil
+		}
 		return nil, nil
 	}
 	if len(matches) == 1 {
@@ -245,18 +333,26 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 	key := path.Base(primaryURL)
 	localFile := path.Join(a.cacheDir, hash.String()+"_"+utils.SanitizeString(key))
 
+	// Try each location in order; DownloadURL verifies the hash of the file it downloads
+	var servedURL string
 	for _, url := range urls {
 		_, err = DownloadURL(url, localFile, hash)
 		if err != nil {
 			klog.Warningf("error downloading url %q: %v", url, err)
 			continue
 		} else {
+			servedURL = url
 			break
 		}
 	}
 	if err != nil {
 		return err
 	}
+	if servedURL != primaryURL {
+		klog.Infof("asset %q was served by mirror %q", key, servedURL)
+	} else {
+		klog.V(2).Infof("asset %q was served by %q", key, servedURL)
+	}
 
 	assetPath := primaryURL
 	r := NewFileResource(localFile)
@@ -274,8 +370,8 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 
 	// normalize filename suffix
 	file := strings.ToLower(assetPath)
-	// pickup both tar.gz and tgz files
-	if strings.HasSuffix(file, ".tar.gz") || strings.HasSuffix(file, ".tgz") {
+	// pickup tar.gz, tgz, tar.xz and txz files
+	if isArchive(file) {
 		err = a.addArchive(source, localFile)
 		if err != nil {
 			return err
@@ -297,7 +393,11 @@ func (a *AssetStore) addArchive(archiveSource *Source, archiveFile string) error
 			return fmt.Errorf("error creating directories %q: %v", path.Dir(extractedTemp), err)
 		}
 
-		args := []string{"tar", "zxf", archiveFile, "-C", extractedTemp}
+		flags := "zxf"
+		if isXZArchive(strings.ToLower(archiveSource.URL)) {
+			flags = "Jxf"
+		}
+		args := []string{"tar", flags, archiveFile, "-C", extractedTemp}
 		klog.Infof("running extract command %s", args)
 		cmd := exec.Command(args[0], args[1:]...)
 		output, err := cmd.CombinedOutput()
@@ -349,3 +449,13 @@ func (a *AssetStore) addArchive(archiveSource *Source, archiveFile string) error
 	}
 	return nil
 }
+
+// isArchive returns true if the (lower case) file name is a tar archive that we know how to extract
+func isArchive(file string) bool {
+	return strings.HasSuffix(file, ".tar.gz") || strings.HasSuffix(file, ".tgz") || isXZArchive(file)
+}
+
+// isXZArchive returns true if the (lower case) file name is a xz compressed tar archive
+func isXZArchive(file string) bool {
+	return strings.HasSuffix(file, ".tar.xz") || strings.HasSuffix(file, ".txz")
+}
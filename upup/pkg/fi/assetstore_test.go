@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestPlaceholderAssetStore(t *testing.T) {
+	store := NewPlaceholderAssetStore()
+
+	if res, err := store.Find("kubelet", ""); err != nil || res == nil {
+		t.Errorf("expected a placeholder for kubelet, got %v, %v", res, err)
+	}
+
+	grid := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `^bin/(containerd|ctr)`, expected: "bin/containerd"},
+		{expr: `/runc\.(amd64|arm64)$`, expected: "/runc.amd64"},
+		{expr: `^bridge$`, expected: "bridge"},
+		{expr: `(^|/)(runsc|containerd-shim-runsc-v1)$`, expected: "runsc"},
+	}
+	for _, g := range grid {
+		t.Run(g.expr, func(t *testing.T) {
+			matches := store.FindMatchesInArchive("archive.tar.gz", regexp.MustCompile(g.expr))
+			if len(matches) != 1 || matches[g.expected] == nil {
+				t.Errorf("expected a single placeholder %q, got %v", g.expected, matches)
+			}
+		})
+	}
+}
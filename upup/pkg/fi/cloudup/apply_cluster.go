@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -29,6 +30,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,6 +59,7 @@ import (
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/pkg/model/openstackmodel"
 	"k8s.io/kops/pkg/model/scalewaymodel"
+	"k8s.io/kops/pkg/mutexes"
 	"k8s.io/kops/pkg/templates"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/models"
@@ -122,9 +125,27 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOutput is the format of the report of a dry run; text if empty
+	DryRunOutput fi.DryRunOutputFormat
+
+	// PolicyDir is a local directory of policies to evaluate before applying changes, in addition to the policies in the state store.
+	PolicyDir string
+
+	// EmitImports writes Terraform import blocks for the cloud resources that already exist; only valid with the terraform target
+	EmitImports bool
+
+	// TerraformModule writes the Terraform output as a reusable module; only valid with the terraform target
+	TerraformModule bool
+
 	// AllowKopsDowngrade permits applying with a kops version older than what was last used to apply to the cluster.
 	AllowKopsDowngrade bool
 
+	// LockTimeout is how long to wait for the state store lock when another apply is holding it.
+	LockTimeout time.Duration
+
+	// BreakLock takes over the state store lock, even if another apply is holding it.
+	BreakLock bool
+
 	// RunTasksOptions defines parameters for task execution, e.g. retry interval
 	RunTasksOptions *fi.RunTasksOptions
 
@@ -142,8 +163,13 @@ type ApplyClusterCmd struct {
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// BuildTasksOnly stops after the tasks are built, without running them
+	BuildTasksOnly bool
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.CloudupTask
+	// TaskBuilders is the model builder that created each task in TaskMap, by task key (output)
+	TaskBuilders map[string]string
 
 	// ImageAssets are the image assets we use (output).
 	ImageAssets []*assets.ImageAsset
@@ -245,6 +271,13 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	}
 
 	assetBuilder := assets.NewAssetBuilder(c.Cluster.Spec.Assets, c.Cluster.Spec.KubernetesVersion, c.GetAssets)
+	if c.Cluster.Spec.Assets != nil && !c.GetAssets {
+		// Verify the images before the cluster uses them; kops get assets --copy verifies them before copying instead
+		assetBuilder.ImageVerifier, err = assets.NewImageVerifier(c.Cluster.Spec.Assets.ImageVerification)
+		if err != nil {
+			return err
+		}
+	}
 	err = c.upgradeSpecs(ctx, assetBuilder)
 	if err != nil {
 		return err
@@ -267,6 +300,28 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("error parsing config base %q: %v", cluster.Spec.ConfigBase, err)
 	}
 
+	if c.TargetName != TargetDryRun && !c.GetAssets {
+		lease, err := mutexes.AcquireStateStoreLease(ctx, configBase.Join(registry.PathApplyLock), mutexes.LeaseOptions{
+			Operation:   "update cluster",
+			WaitTimeout: c.LockTimeout,
+			Break:       c.BreakLock,
+		})
+		if err != nil {
+			var held *mutexes.LeaseHeldError
+			if errors.As(err, &held) {
+				return fmt.Errorf("another update is in progress: %w (use --break-lock to take over the lock)", err)
+			}
+			return fmt.Errorf("error acquiring lock: %w", err)
+		}
+		defer func() {
+			if err := lease.Release(ctx); err != nil {
+				klog.Warningf("error releasing lock: %v", err)
+			}
+		}()
+	}
+
+	banner := c.bannerOut()
+
 	if !c.AllowKopsDowngrade {
 		kopsVersionUpdatedBytes, err := configBase.Join(registry.PathKopsVersionUpdated).ReadFile(ctx)
 		if err == nil {
@@ -276,14 +331,14 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				return fmt.Errorf("error parsing last kops version updated: %v", err)
 			}
 			if version.GT(semver.MustParse(kopsbase.Version)) {
-				fmt.Printf("\n")
-				fmt.Printf("%s\n", starline)
-				fmt.Printf("\n")
-				fmt.Printf("The cluster was last updated by kops version %s\n", kopsVersionUpdated)
-				fmt.Printf("To permit updating by the older version %s, run with the --allow-kops-downgrade flag\n", kopsbase.Version)
-				fmt.Printf("\n")
-				fmt.Printf("%s\n", starline)
-				fmt.Printf("\n")
+				fmt.Fprintf(banner, "\n")
+				fmt.Fprintf(banner, "%s\n", starline)
+				fmt.Fprintf(banner, "\n")
+				fmt.Fprintf(banner, "The cluster was last updated by kops version %s\n", kopsVersionUpdated)
+				fmt.Fprintf(banner, "To permit updating by the older version %s, run with the --allow-kops-downgrade flag\n", kopsbase.Version)
+				fmt.Fprintf(banner, "\n")
+				fmt.Fprintf(banner, "%s\n", starline)
+				fmt.Fprintf(banner, "\n")
 				return fmt.Errorf("kops version older than last used to update the cluster")
 			}
 		} else if err != os.ErrNotExist {
@@ -348,16 +403,16 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 
 		if warn {
-			fmt.Println("")
-			fmt.Printf("%s\n", starline)
-			fmt.Println("")
-			fmt.Println("Kubelet anonymousAuth is currently turned on. This allows RBAC escalation and remote code execution possibilities.")
-			fmt.Println("It is highly recommended you turn it off by setting 'spec.kubelet.anonymousAuth' to 'false' via 'kops edit cluster'")
-			fmt.Println("")
-			fmt.Println("See https://kops.sigs.k8s.io/security/#kubelet-api")
-			fmt.Println("")
-			fmt.Printf("%s\n", starline)
-			fmt.Println("")
+			fmt.Fprintln(banner, "")
+			fmt.Fprintf(banner, "%s\n", starline)
+			fmt.Fprintln(banner, "")
+			fmt.Fprintln(banner, "Kubelet anonymousAuth is currently turned on. This allows RBAC escalation and remote code execution possibilities.")
+			fmt.Fprintln(banner, "It is highly recommended you turn it off by setting 'spec.kubelet.anonymousAuth' to 'false' via 'kops edit cluster'")
+			fmt.Fprintln(banner, "")
+			fmt.Fprintln(banner, "See https://kops.sigs.k8s.io/security/#kubelet-api")
+			fmt.Fprintln(banner, "")
+			fmt.Fprintf(banner, "%s\n", starline)
+			fmt.Fprintln(banner, "")
 		}
 	}
 
@@ -368,9 +423,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			return fmt.Errorf("could not load encryptionconfig secret: %v", err)
 		}
 		if secret == nil {
-			fmt.Println("")
-			fmt.Println("You have encryptionConfig enabled, but no encryptionconfig secret has been set.")
-			fmt.Println("See `kops create secret encryptionconfig -h` and https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/")
+			fmt.Fprintln(banner, "")
+			fmt.Fprintln(banner, "You have encryptionConfig enabled, but no encryptionconfig secret has been set.")
+			fmt.Fprintln(banner, "See `kops create secret encryptionconfig -h` and https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/")
 			return fmt.Errorf("could not find encryptionconfig secret")
 		}
 		hashBytes := sha256.Sum256(secret.Data)
@@ -384,9 +439,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			return fmt.Errorf("could not load the ciliumpassword secret: %w", err)
 		}
 		if secret == nil {
-			fmt.Println("")
-			fmt.Println("You have cilium encryption enabled, but no ciliumpassword secret has been set.")
-			fmt.Println("See `kops create secret ciliumpassword -h`")
+			fmt.Fprintln(banner, "")
+			fmt.Fprintln(banner, "You have cilium encryption enabled, but no ciliumpassword secret has been set.")
+			fmt.Fprintln(banner, "See `kops create secret ciliumpassword -h`")
 			return fmt.Errorf("could not find ciliumpassword secret")
 		}
 	}
@@ -508,7 +563,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		cloud:            cloud,
 	}
 
-	configBuilder, err := newNodeUpConfigBuilder(cluster, assetBuilder, c.Assets, encryptionConfigSecretHash)
+	configBuilder, err := newNodeUpConfigBuilder(cluster, c.InstanceGroups, assetBuilder, c.Assets, encryptionConfigSecretHash)
 	if err != nil {
 		return err
 	}
@@ -701,6 +756,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("error building tasks: %v", err)
 	}
+	c.TaskBuilders = l.TaskBuilders()
+	if c.BuildTasksOnly {
+		return nil
+	}
 
 	var target fi.CloudupTarget
 	shouldPrecreateDNS := true
@@ -729,6 +788,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	case TargetTerraform:
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, project, outDir, cluster.Spec.Target)
+		tf.EmitImports = c.EmitImports
+		tf.Module = c.TerraformModule
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraformWriter.LiteralFromStringValue(cloud.Region())); err != nil {
@@ -755,7 +816,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		if c.GetAssets {
 			out = io.Discard
 		}
-		target = fi.NewCloudupDryRunTarget(assetBuilder, out)
+		dryRunTarget := fi.NewCloudupDryRunTarget(assetBuilder, out)
+		dryRunTarget.SetOutputFormat(c.DryRunOutput)
+		target = dryRunTarget
 
 		// Avoid making changes on a dry-run
 		shouldPrecreateDNS = false
@@ -765,7 +828,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	}
 	c.Target = target
 
-	if target.DefaultCheckExisting() {
+	if target.DefaultCheckExisting() && target.ProcessDeletions() {
 		c.TaskMap, err = l.FindDeletions(cloud, c.LifecycleOverrides)
 		if err != nil {
 			return fmt.Errorf("error finding deletions: %w", err)
@@ -783,12 +846,46 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	} else {
 		options.InitDefaults()
 	}
+	if rateLimitedCloud, ok := cloud.(fi.RateLimitedCloud); ok && options.RateLimiter == nil && target.DefaultCheckExisting() {
+		// Only targets that check the existing resources call the cloud APIs
+		options.RateLimiter = rateLimitedCloud.TaskRateLimiter()
+	}
+
+	policyEngine, err := c.buildPolicyEngine(ctx, configBase)
+	if err != nil {
+		return err
+	}
+	if policyEngine.HasRules() && c.TargetName != TargetDryRun {
+		// Evaluate the policies before making any changes; the changes are only known if the target checks the existing resources
+		var plan *fi.Plan
+		if target.DefaultCheckExisting() {
+			plan, err = c.planChanges(ctx, assetBuilder, cluster, keyStore, secretStore, configBase, options)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := c.evaluatePolicies(policyEngine, cluster, plan); err != nil {
+			return err
+		}
+	}
 
 	err = context.RunTasks(options)
 	if err != nil {
 		return fmt.Errorf("error running tasks: %v", err)
 	}
 
+	var policyErr error
+	if policyEngine.HasRules() && c.TargetName == TargetDryRun {
+		dryRunTarget := target.(*fi.CloudupDryRunTarget)
+		plan, err := dryRunTarget.BuildPlan(c.TaskMap)
+		if err != nil {
+			return err
+		}
+		var violations []fi.PlanViolation
+		violations, policyErr = c.evaluatePolicies(policyEngine, cluster, plan)
+		dryRunTarget.SetViolations(violations)
+	}
+
 	if cluster.IsGossip() || cluster.UsesNoneDNS() {
 		shouldPrecreateDNS = false
 	}
@@ -803,6 +900,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("error closing target: %v", err)
 	}
+	if policyErr != nil {
+		return policyErr
+	}
 
 	c.ImageAssets = assetBuilder.ImageAssets
 	c.FileAssets = assetBuilder.FileAssets
@@ -829,8 +929,18 @@ func (c *ApplyClusterCmd) upgradeSpecs(ctx context.Context, assetBuilder *assets
 	return nil
 }
 
+// bannerOut returns the writer of the messages for humans, which go to stderr when the output of the dry run is machine-readable
+func (c *ApplyClusterCmd) bannerOut() io.Writer {
+	if c.DryRunOutput != "" && c.DryRunOutput != fi.DryRunOutputText {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 // validateKopsVersion ensures that kops meet the version requirements / recommendations in the channel
 func (c *ApplyClusterCmd) validateKopsVersion() error {
+	banner := c.bannerOut()
+
 	kopsVersion, err := semver.ParseTolerant(kopsbase.Version)
 	if err != nil {
 		klog.Warningf("unable to parse kops version %q", kopsbase.Version)
@@ -861,31 +971,31 @@ func (c *ApplyClusterCmd) validateKopsVersion() error {
 	}
 
 	if recommended != nil && !required && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("A new kops version is available: %s", recommended)
-		fmt.Printf("\n")
-		fmt.Printf("Upgrading is recommended\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "A new kops version is available: %s", recommended)
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "Upgrading is recommended\n")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 	} else if required {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 		if recommended != nil {
-			fmt.Printf("a new kops version is available: %s\n", recommended)
+			fmt.Fprintf(banner, "a new kops version is available: %s\n", recommended)
 		}
-		fmt.Println("")
-		fmt.Printf("This version of kops (%s) is no longer supported; upgrading is required\n", kopsbase.Version)
-		fmt.Printf("(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
-		fmt.Println("")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintln(banner, "")
+		fmt.Fprintf(banner, "This version of kops (%s) is no longer supported; upgrading is required\n", kopsbase.Version)
+		fmt.Fprintf(banner, "(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
+		fmt.Fprintln(banner, "")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 	}
 
 	if required {
@@ -899,6 +1009,8 @@ func (c *ApplyClusterCmd) validateKopsVersion() error {
 
 // validateKubernetesVersion ensures that kubernetes meet the version requirements / recommendations in the channel
 func (c *ApplyClusterCmd) validateKubernetesVersion() error {
+	banner := c.bannerOut()
+
 	parsed, err := util.ParseKubernetesVersion(c.Cluster.Spec.KubernetesVersion)
 	if err != nil {
 		klog.Warningf("unable to parse kubernetes version %q", c.Cluster.Spec.KubernetesVersion)
@@ -915,14 +1027,14 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 		tooNewVersion.Pre = nil
 		tooNewVersion.Build = nil
 		if util.IsKubernetesGTE(tooNewVersion.String(), *parsed) {
-			fmt.Printf("\n")
-			fmt.Printf("%s\n", starline)
-			fmt.Printf("\n")
-			fmt.Printf("This version of kubernetes is not yet supported; upgrading kops is required\n")
-			fmt.Printf("(you can bypass this check by exporting KOPS_RUN_TOO_NEW_VERSION)\n")
-			fmt.Printf("\n")
-			fmt.Printf("%s\n", starline)
-			fmt.Printf("\n")
+			fmt.Fprintf(banner, "\n")
+			fmt.Fprintf(banner, "%s\n", starline)
+			fmt.Fprintf(banner, "\n")
+			fmt.Fprintf(banner, "This version of kubernetes is not yet supported; upgrading kops is required\n")
+			fmt.Fprintf(banner, "(you can bypass this check by exporting KOPS_RUN_TOO_NEW_VERSION)\n")
+			fmt.Fprintf(banner, "\n")
+			fmt.Fprintf(banner, "%s\n", starline)
+			fmt.Fprintf(banner, "\n")
 			if os.Getenv("KOPS_RUN_TOO_NEW_VERSION") == "" {
 				return fmt.Errorf("kops upgrade is required")
 			}
@@ -930,25 +1042,25 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 	}
 
 	if !util.IsKubernetesGTE(OldestSupportedKubernetesVersion, *parsed) {
-		fmt.Printf("This version of Kubernetes is no longer supported; upgrading Kubernetes is required\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "This version of Kubernetes is no longer supported; upgrading Kubernetes is required\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 		return fmt.Errorf("kubernetes upgrade is required")
 	}
 	if !util.IsKubernetesGTE(OldestRecommendedKubernetesVersion, *parsed) && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("Kops support for this Kubernetes version is deprecated and will be removed in a future release.\n")
-		fmt.Printf("\n")
-		fmt.Printf("Upgrading Kubernetes is recommended\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "Kops support for this Kubernetes version is deprecated and will be removed in a future release.\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "Upgrading Kubernetes is recommended\n")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 
 	}
 
@@ -978,31 +1090,31 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 	}
 
 	if recommended != nil && !required && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("A new kubernetes version is available: %s\n", recommended)
-		fmt.Printf("Upgrading is recommended (try kops upgrade cluster)\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "A new kubernetes version is available: %s\n", recommended)
+		fmt.Fprintf(banner, "Upgrading is recommended (try kops upgrade cluster)\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 	} else if required {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 		if recommended != nil {
-			fmt.Printf("A new kubernetes version is available: %s\n", recommended)
+			fmt.Fprintf(banner, "A new kubernetes version is available: %s\n", recommended)
 		}
-		fmt.Printf("\n")
-		fmt.Printf("This version of kubernetes is no longer supported; upgrading is required\n")
-		fmt.Printf("(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "This version of kubernetes is no longer supported; upgrading is required\n")
+		fmt.Fprintf(banner, "(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
+		fmt.Fprintf(banner, "\n")
+		fmt.Fprintf(banner, "%s\n", starline)
+		fmt.Fprintf(banner, "\n")
 	}
 
 	if required {
@@ -1048,14 +1160,14 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 			if err != nil {
 				return err
 			}
-			c.Assets[arch] = append(c.Assets[arch], mirrors.BuildMirroredAsset(u, hash))
+			c.Assets[arch] = append(c.Assets[arch], assetBuilder.BuildMirroredAsset(u, hash))
 		}
 
 		cniAsset, cniAssetHash, err := findCNIAssets(c.Cluster, assetBuilder, arch)
 		if err != nil {
 			return err
 		}
-		c.Assets[arch] = append(c.Assets[arch], mirrors.BuildMirroredAsset(cniAsset, cniAssetHash))
+		c.Assets[arch] = append(c.Assets[arch], assetBuilder.BuildMirroredAsset(cniAsset, cniAssetHash))
 
 		var containerRuntimeAssetUrl *url.URL
 		var containerRuntimeAssetHash *hashing.Hash
@@ -1070,7 +1182,7 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 		if err != nil {
 			return err
 		}
-		c.Assets[arch] = append(c.Assets[arch], mirrors.BuildMirroredAsset(containerRuntimeAssetUrl, containerRuntimeAssetHash))
+		c.Assets[arch] = append(c.Assets[arch], assetBuilder.BuildMirroredAsset(containerRuntimeAssetUrl, containerRuntimeAssetHash))
 
 		if c.Cluster.Spec.ContainerRuntime == "containerd" {
 			var runcAssetUrl *url.URL
@@ -1080,9 +1192,21 @@ func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error
 				return err
 			}
 			if runcAssetUrl != nil && runcAssetHash != nil {
-				c.Assets[arch] = append(c.Assets[arch], mirrors.BuildMirroredAsset(runcAssetUrl, runcAssetHash))
+				c.Assets[arch] = append(c.Assets[arch], assetBuilder.BuildMirroredAsset(runcAssetUrl, runcAssetHash))
 			}
+
+			runtimeAssets, err := findContainerdRuntimeAssets(c.Cluster, c.InstanceGroups, assetBuilder, arch)
+			if err != nil {
+				return err
+			}
+			c.Assets[arch] = append(c.Assets[arch], runtimeAssets...)
+		}
+
+		credentialProviderAssets, err := findCredentialProviderAssets(c.Cluster, c.InstanceGroups, assetBuilder, arch)
+		if err != nil {
+			return err
 		}
+		c.Assets[arch] = append(c.Assets[arch], credentialProviderAssets...)
 
 		asset, err := NodeUpAsset(assetBuilder, arch)
 		if err != nil {
@@ -1135,13 +1259,14 @@ type nodeUpConfigBuilder struct {
 	configBase                 vfs.Path
 	cluster                    *kops.Cluster
 	etcdManifests              map[string][]string
+	etcdNodesClusters          []string
 	images                     map[kops.InstanceGroupRole]map[architectures.Architecture][]*nodeup.Image
 	protokubeAsset             map[architectures.Architecture][]*mirrors.MirroredAsset
 	channelsAsset              map[architectures.Architecture][]*mirrors.MirroredAsset
 	encryptionConfigSecretHash string
 }
 
-func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset, encryptionConfigSecretHash string) (model.NodeUpConfigBuilder, error) {
+func newNodeUpConfigBuilder(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, assets map[architectures.Architecture][]*mirrors.MirroredAsset, encryptionConfigSecretHash string) (model.NodeUpConfigBuilder, error) {
 	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigBase)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config base %q: %v", cluster.Spec.ConfigBase, err)
@@ -1200,15 +1325,10 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 
 					baseURL.Path = path.Join(baseURL.Path, "/bin/linux", string(arch), component+".tar")
 
-					u, hash, err := assetBuilder.RemapFileAndSHA(baseURL)
+					image, err := buildPreloadImage(assetBuilder, baseURL)
 					if err != nil {
 						return nil, err
 					}
-
-					image := &nodeup.Image{
-						Sources: []string{u.String()},
-						Hash:    hash.Hex(),
-					}
 					images[role][arch] = append(images[role][arch], image)
 				}
 			}
@@ -1226,15 +1346,10 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 
 					baseURL.Path = path.Join(baseURL.Path, "/images/"+name+"-"+string(arch)+".tar.gz")
 
-					u, hash, err := assetBuilder.RemapFileAndSHA(baseURL)
+					image, err := buildPreloadImage(assetBuilder, baseURL)
 					if err != nil {
 						return nil, err
 					}
-
-					image := &nodeup.Image{
-						Sources: []string{u.String()},
-						Hash:    hash.Hex(),
-					}
 					images[role][arch] = append(images[role][arch], image)
 				}
 			}
@@ -1249,15 +1364,10 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 
 					baseURL.Path = path.Join(baseURL.Path, "/images/"+name+"-"+string(arch)+".tar.gz")
 
-					u, hash, err := assetBuilder.RemapFileAndSHA(baseURL)
+					image, err := buildPreloadImage(assetBuilder, baseURL)
 					if err != nil {
 						return nil, err
 					}
-
-					image := &nodeup.Image{
-						Sources: []string{u.String()},
-						Hash:    hash.Hex(),
-					}
 					images[role][arch] = append(images[role][arch], image)
 				}
 			}
@@ -1281,6 +1391,7 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 		configBase:                 configBase,
 		cluster:                    cluster,
 		etcdManifests:              etcdManifests,
+		etcdNodesClusters:          apiModel.FindEtcdNodesClusters(cluster, instanceGroups),
 		images:                     images,
 		protokubeAsset:             protokubeAsset,
 		channelsAsset:              channelsAsset,
@@ -1291,6 +1402,32 @@ func newNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 }
 
 // BuildConfig returns the NodeUp config and auxiliary config.
+// buildPreloadImage builds the image that nodeup loads from the tarball at the url, falling back to the mirrors of the file repository.
+// The signature of the tarball is carried with the image, so that nodeup verifies it wherever the tarball is downloaded from.
+func buildPreloadImage(assetBuilder *assets.AssetBuilder, tarballURL *url.URL) (*nodeup.Image, error) {
+	canonicalSource := tarballURL.String()
+
+	u, hash, err := assetBuilder.RemapFileAndSHA(tarballURL)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &nodeup.Image{
+		Sources:         assetBuilder.BuildMirroredAsset(u, hash).Locations,
+		Hash:            hash.Hex(),
+		CanonicalSource: canonicalSource,
+	}
+
+	if assetBuilder.ImageVerifier.MatchesTarball(canonicalSource) {
+		image.Signature, err = assets.ReadBlobSignature(u.String())
+		if err != nil {
+			return nil, fmt.Errorf("image %s must be verified: %w", canonicalSource, err)
+		}
+	}
+
+	return image, nil
+}
+
 func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAdditionalIPs []string, keysets map[string]*fi.Keyset) (*nodeup.Config, *nodeup.BootConfig, error) {
 	cluster := n.cluster
 
@@ -1306,6 +1443,7 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 	useGossip := cluster.IsGossip()
 	isMaster := role == kops.InstanceGroupRoleControlPlane
 	hasAPIServer := isMaster || role == kops.InstanceGroupRoleAPIServer
+	isEtcdNode := role == kops.InstanceGroupRoleEtcd
 
 	config, bootConfig := nodeup.NewConfig(cluster, ig)
 
@@ -1327,7 +1465,7 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 			}
 		}
 
-		if isMaster {
+		if isMaster || isEtcdNode {
 			if err := loadCertificates(keysets, "etcd-clients-ca", config, true); err != nil {
 				return nil, nil, err
 			}
@@ -1339,12 +1477,15 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 				if err := loadCertificates(keysets, "etcd-peers-ca-"+k, config, true); err != nil {
 					return nil, nil, err
 				}
-				if k != "events" && k != "main" {
+				if !apiModel.IsAPIServerEtcdCluster(etcdCluster) {
 					if err := loadCertificates(keysets, "etcd-clients-ca-"+k, config, true); err != nil {
 						return nil, nil, err
 					}
 				}
 			}
+		}
+
+		if isMaster {
 			config.KeypairIDs["service-account"] = keysets["service-account"].Primary.Id
 		} else {
 			if keysets["etcd-client-cilium"] != nil {
@@ -1369,6 +1510,7 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 				return nil, nil, fmt.Errorf("encoding service-account keys: %w", err)
 			}
 			config.APIServerConfig.ServiceAccountPublicKeys = serviceAccountPublicKeys
+			config.APIServerConfig.EtcdNodesClusters = n.etcdNodesClusters
 		} else {
 			for _, key := range []string{"kubelet", "kube-proxy", "kube-router"} {
 				if keysets[key] != nil {
@@ -1377,7 +1519,7 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 			}
 		}
 
-		if isMaster || useGossip {
+		if isMaster || useGossip || (hasAPIServer && len(n.etcdNodesClusters) != 0) {
 			for _, arch := range architectures.GetSupported() {
 				for _, a := range n.protokubeAsset[arch] {
 					config.Assets[arch] = append(config.Assets[arch], a.CompactString())
@@ -1423,7 +1565,8 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 		}
 	}
 
-	useConfigServer := apiModel.UseKopsControllerForNodeConfig(cluster) && !ig.HasAPIServer()
+	// Etcd nodes can't use kops-controller, which needs kube-apiserver and thus etcd
+	useConfigServer := apiModel.UseKopsControllerForNodeConfig(cluster) && !ig.HasAPIServer() && !ig.IsEtcdOnly()
 	if useConfigServer {
 		hosts := []string{"kops-controller.internal." + cluster.ObjectMeta.Name}
 		if cluster.UsesNoneDNS() && len(bootConfig.APIServerIPs) > 0 {
@@ -1483,6 +1626,14 @@ func (n *nodeUpConfigBuilder) BuildConfig(ig *kops.InstanceGroup, apiserverAddit
 	}
 
 	config.Images = n.images[role]
+
+	for _, image := range ig.Spec.PrePullImages {
+		remapped, err := n.assetBuilder.RemapImage(image)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to remap image %q: %w", image, err)
+		}
+		config.PrePullImages = append(config.PrePullImages, remapped)
+	}
 	config.Channels = n.channels
 	config.EtcdManifests = n.etcdManifests[ig.Name]
 
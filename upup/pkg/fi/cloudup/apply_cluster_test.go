@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// captureOutput returns what fn writes to stdout and stderr
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	capture := func(f **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := *f
+		*f = w
+		done := make(chan string)
+		go func() {
+			b, _ := io.ReadAll(r)
+			done <- string(b)
+		}()
+		return func() string {
+			*f = original
+			w.Close()
+			return <-done
+		}
+	}
+
+	stdout := capture(&os.Stdout)
+	stderr := capture(&os.Stderr)
+	fn()
+	return stdout(), stderr()
+}
+
+func TestVersionBannersKeepDryRunOutputMachineReadable(t *testing.T) {
+	channel := &kops.Channel{
+		Spec: kops.ChannelSpec{
+			KopsVersions: []kops.KopsVersionSpec{
+				{Range: ">=0.0.0", RecommendedVersion: "99.0.0"},
+			},
+			KubernetesVersions: []kops.KubernetesVersionSpec{
+				{Range: ">=0.0.0", RecommendedVersion: "99.0.0"},
+			},
+		},
+	}
+
+	for _, format := range []fi.DryRunOutputFormat{"", fi.DryRunOutputText, fi.DryRunOutputJSON, fi.DryRunOutputYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			c := &ApplyClusterCmd{
+				Cluster: &kops.Cluster{
+					Spec: kops.ClusterSpec{
+						// Deprecated, so a banner recommends upgrading
+						KubernetesVersion: "1.23.0",
+					},
+				},
+				DryRunOutput: format,
+				channel:      channel,
+			}
+
+			var kopsErr, kubernetesErr error
+			stdout, stderr := captureOutput(t, func() {
+				kopsErr = c.validateKopsVersion()
+				kubernetesErr = c.validateKubernetesVersion()
+			})
+			if kopsErr != nil || kubernetesErr != nil {
+				t.Fatalf("unexpected errors: %v, %v", kopsErr, kubernetesErr)
+			}
+
+			banners, other := stdout, stderr
+			if format == fi.DryRunOutputJSON || format == fi.DryRunOutputYAML {
+				banners, other = stderr, stdout
+			}
+			for _, expected := range []string{
+				"A new kops version is available: 99.0.0",
+				"Kops support for this Kubernetes version is deprecated",
+				"A new kubernetes version is available: 99.0.0",
+			} {
+				if !strings.Contains(banners, expected) {
+					t.Errorf("expected %q in banners, got %q", expected, banners)
+				}
+			}
+			if strings.Contains(other, starline) {
+				t.Errorf("unexpected banners in %q", other)
+			}
+		})
+	}
+}
+
+func TestBuildPreloadImageUsesFileRepositoryMirrors(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	hash := "0000000000000000000000000000000000000000000000000000000000000000"
+	hashFile := "memfs://files/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar.sha256"
+	p, err := vfs.Context.BuildVfsPath(hashFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte(hash)), nil); err != nil {
+		t.Fatalf("error writing %s: %v", hashFile, err)
+	}
+
+	assetBuilder := assets.NewAssetBuilder(&kops.AssetsSpec{
+		FileRepository:        fi.PtrTo("memfs://files/"),
+		FileRepositoryMirrors: []string{"memfs://mirror/"},
+	}, "1.26.0", false)
+
+	canonical := "https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar"
+	u, err := url.Parse(canonical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image, err := buildPreloadImage(assetBuilder, u)
+	if err != nil {
+		t.Fatalf("error building image: %v", err)
+	}
+
+	expectedSources := []string{
+		"memfs://files/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar",
+		"memfs://mirror/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar",
+		canonical,
+	}
+	if !reflect.DeepEqual(image.Sources, expectedSources) {
+		t.Errorf("expected sources %v, got %v", expectedSources, image.Sources)
+	}
+	if image.Hash != hash {
+		t.Errorf("unexpected hash %q", image.Hash)
+	}
+	if image.CanonicalSource != canonical {
+		t.Errorf("unexpected canonical source %q", image.CanonicalSource)
+	}
+}
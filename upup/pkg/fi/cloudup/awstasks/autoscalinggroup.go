@@ -26,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"k8s.io/klog/v2"
 
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
@@ -940,8 +941,8 @@ type terraformAutoscalingGroup struct {
 	Name                    *string                                          `cty:"name"`
 	LaunchConfigurationName *terraformWriter.Literal                         `cty:"launch_configuration"`
 	LaunchTemplate          *terraformAutoscalingLaunchTemplateSpecification `cty:"launch_template"`
-	MaxSize                 *int64                                           `cty:"max_size"`
-	MinSize                 *int64                                           `cty:"min_size"`
+	MaxSize                 *terraformWriter.Literal                         `cty:"max_size"`
+	MinSize                 *terraformWriter.Literal                         `cty:"min_size"`
 	MixedInstancesPolicy    []*terraformMixedInstancesPolicy                 `cty:"mixed_instances_policy"`
 	VPCZoneIdentifier       []*terraformWriter.Literal                       `cty:"vpc_zone_identifier"`
 	Tags                    []*terraformASGTag                               `cty:"tag"`
@@ -960,8 +961,6 @@ type terraformAutoscalingGroup struct {
 func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *AutoscalingGroup) error {
 	tf := &terraformAutoscalingGroup{
 		Name:                e.Name,
-		MinSize:             e.MinSize,
-		MaxSize:             e.MaxSize,
 		MetricsGranularity:  e.Granularity,
 		EnabledMetrics:      aws.StringSlice(e.Metrics),
 		InstanceProtection:  e.InstanceProtection,
@@ -969,6 +968,34 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 		CapacityRebalance:   e.CapacityRebalance,
 	}
 
+	// The instance counts can be overridden by users of the module
+	igName := e.Tags[nodeidentityaws.CloudTagInstanceGroupName]
+	if igName == "" {
+		igName = fi.ValueOf(e.Name)
+	}
+	if e.MinSize != nil {
+		minSize, err := t.AddModuleVariable(igName+"_min_size", &terraformWriter.Variable{
+			Type:        "number",
+			Description: "Minimum number of instances in " + igName,
+			Default:     terraformWriter.LiteralFromIntValue(*e.MinSize),
+		})
+		if err != nil {
+			return err
+		}
+		tf.MinSize = minSize
+	}
+	if e.MaxSize != nil {
+		maxSize, err := t.AddModuleVariable(igName+"_max_size", &terraformWriter.Variable{
+			Type:        "number",
+			Description: "Maximum number of instances in " + igName,
+			Default:     terraformWriter.LiteralFromIntValue(*e.MaxSize),
+		})
+		if err != nil {
+			return err
+		}
+		tf.MaxSize = maxSize
+	}
+
 	for _, s := range e.Subnets {
 		tf.VPCZoneIdentifier = append(tf.VPCZoneIdentifier, s.TerraformLink())
 	}
@@ -1087,6 +1114,19 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	return t.RenderResource("aws_autoscaling_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *AutoscalingGroup) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*AutoscalingGroup)
+	if a.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_autoscaling_group",
+		ResourceName: *e.Name,
+		ID:           *a.Name,
+	}}
+}
+
 // TerraformLink fills in the property
 func (e *AutoscalingGroup) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_autoscaling_group", fi.ValueOf(e.Name), "id")
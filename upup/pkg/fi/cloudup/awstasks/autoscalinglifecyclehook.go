@@ -160,6 +160,20 @@ func (_ *AutoscalingLifecycleHook) RenderTerraform(t *terraform.TerraformTarget,
 	return t.RenderResource("aws_autoscaling_lifecycle_hook", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *AutoscalingLifecycleHook) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*AutoscalingLifecycleHook)
+	// Find returns the expected hook when a disabled hook does not exist
+	if !fi.ValueOf(e.Enabled) || !fi.ValueOf(a.Enabled) || a.AutoscalingGroup == nil || a.AutoscalingGroup.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_autoscaling_lifecycle_hook",
+		ResourceName: *e.Name,
+		ID:           *a.AutoscalingGroup.Name + "/" + fi.ValueOf(a.GetHookName()),
+	}}
+}
+
 func (h *AutoscalingLifecycleHook) GetHookName() *string {
 	if h.HookName != nil {
 		return h.HookName
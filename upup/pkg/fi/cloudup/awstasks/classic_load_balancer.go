@@ -741,6 +741,19 @@ func (_ *ClassicLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return t.RenderResource("aws_elb", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *ClassicLoadBalancer) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*ClassicLoadBalancer)
+	if fi.ValueOf(e.Shared) || a.LoadBalancerName == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_elb",
+		ResourceName: *e.Name,
+		ID:           *a.LoadBalancerName,
+	}}
+}
+
 func (e *ClassicLoadBalancer) TerraformLink(params ...string) *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
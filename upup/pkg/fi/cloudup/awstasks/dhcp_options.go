@@ -188,6 +188,19 @@ func (_ *DHCPOptions) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_vpc_dhcp_options", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *DHCPOptions) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*DHCPOptions)
+	if a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_vpc_dhcp_options",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *DHCPOptions) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_vpc_dhcp_options", *e.Name, "id")
 }
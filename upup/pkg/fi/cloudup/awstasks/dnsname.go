@@ -282,6 +282,22 @@ func (_ *DNSName) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 	return t.RenderResource("aws_route53_record", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *DNSName) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*DNSName)
+	if a.Zone == nil || a.Zone.ZoneID == nil || a.ResourceName == nil || a.ResourceType == nil {
+		return nil
+	}
+	// The import ID has the form <zone>_<name>_<type>
+	zoneID := strings.TrimPrefix(*a.Zone.ZoneID, "/hostedzone/")
+	name := strings.TrimSuffix(*a.ResourceName, ".")
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_route53_record",
+		ResourceName: *e.Name,
+		ID:           zoneID + "_" + name + "_" + *a.ResourceType,
+	}}
+}
+
 func (e *DNSName) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route53_record", *e.Name)
 }
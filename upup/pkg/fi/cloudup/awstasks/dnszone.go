@@ -230,6 +230,11 @@ type terraformRoute53ZoneAssociation struct {
 	Lifecycle *terraform.Lifecycle     `cty:"lifecycle"`
 }
 
+// TerraformImport implements terraform.Importable; RenderTerraform reuses an existing zone instead of importing it
+func (e *DNSZone) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	return nil
+}
+
 func (_ *DNSZone) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *DNSZone) error {
 	cloud := t.Cloud.(awsup.AWSCloud)
 
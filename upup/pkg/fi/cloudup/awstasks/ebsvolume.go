@@ -225,6 +225,20 @@ func (_ *EBSVolume) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_ebs_volume", tfName, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *EBSVolume) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*EBSVolume)
+	if a.ID == nil {
+		return nil
+	}
+	tfName, _ := e.TerraformName()
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_ebs_volume",
+		ResourceName: tfName,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *EBSVolume) TerraformLink() *terraformWriter.Literal {
 	tfName, _ := e.TerraformName()
 	return terraformWriter.LiteralSelfLink("aws_ebs_volume", tfName)
@@ -205,6 +205,19 @@ func (_ *EgressOnlyInternetGateway) RenderTerraform(t *terraform.TerraformTarget
 	return t.RenderResource("aws_egress_only_internet_gateway", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *EgressOnlyInternetGateway) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*EgressOnlyInternetGateway)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_egress_only_internet_gateway",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *EgressOnlyInternetGateway) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
@@ -292,6 +292,19 @@ func (_ *ElasticIP) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_eip", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *ElasticIP) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*ElasticIP)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_eip",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *ElasticIP) TerraformLink() *terraformWriter.Literal {
 	if fi.ValueOf(e.Shared) {
 		if e.ID == nil {
@@ -150,6 +150,19 @@ func (_ *EventBridgeRule) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_cloudwatch_event_rule", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *EventBridgeRule) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*EventBridgeRule)
+	if a.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_cloudwatch_event_rule",
+		ResourceName: *e.Name,
+		ID:           *a.Name,
+	}}
+}
+
 func (eb *EventBridgeRule) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_cloudwatch_event_rule", fi.ValueOf(eb.Name), "id")
 }
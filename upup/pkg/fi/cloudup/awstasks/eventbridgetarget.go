@@ -139,3 +139,16 @@ func (_ *EventBridgeTarget) RenderTerraform(t *terraform.TerraformTarget, a, e,
 
 	return t.RenderResource("aws_cloudwatch_event_target", *e.Name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *EventBridgeTarget) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*EventBridgeTarget)
+	if a.ID == nil || a.Rule == nil || a.Rule.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_cloudwatch_event_target",
+		ResourceName: *e.Name,
+		ID:           *a.Rule.Name + "/" + *a.ID,
+	}}
+}
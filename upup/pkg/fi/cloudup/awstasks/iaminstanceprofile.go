@@ -180,6 +180,11 @@ func (_ *IAMInstanceProfile) RenderTerraform(t *terraform.TerraformTarget, a, e,
 	return nil
 }
 
+// TerraformImport implements terraform.Importable; the instance profile is imported by IAMInstanceProfileRole
+func (e *IAMInstanceProfile) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	return nil
+}
+
 func (e *IAMInstanceProfile) TerraformLink() *terraformWriter.Literal {
 	if fi.ValueOf(e.Shared) {
 		return terraformWriter.LiteralFromStringValue(fi.ValueOf(e.Name))
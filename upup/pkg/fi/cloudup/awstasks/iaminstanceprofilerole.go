@@ -124,3 +124,16 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *IAMInstanceProfileRole) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*IAMInstanceProfileRole)
+	if a.InstanceProfile == nil || a.InstanceProfile.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_iam_instance_profile",
+		ResourceName: *e.InstanceProfile.Name,
+		ID:           *a.InstanceProfile.Name,
+	}}
+}
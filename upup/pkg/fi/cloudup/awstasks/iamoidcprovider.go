@@ -242,6 +242,19 @@ func (p *IAMOIDCProvider) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_iam_openid_connect_provider", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *IAMOIDCProvider) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*IAMOIDCProvider)
+	if a.arn == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_iam_openid_connect_provider",
+		ResourceName: *e.Name,
+		ID:           *a.arn,
+	}}
+}
+
 func (e *IAMOIDCProvider) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_openid_connect_provider", *e.Name, "arn")
 }
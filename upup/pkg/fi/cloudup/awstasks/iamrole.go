@@ -367,6 +367,19 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 	return t.RenderResource("aws_iam_role", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *IAMRole) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*IAMRole)
+	if a.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_iam_role",
+		ResourceName: *e.Name,
+		ID:           *a.Name,
+	}}
+}
+
 func (e *IAMRole) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_iam_role", *e.Name, "name")
 }
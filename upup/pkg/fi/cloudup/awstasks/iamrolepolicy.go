@@ -355,6 +355,45 @@ func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_iam_role_policy", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable; the attachments of the external policies are imported with the inline policy
+func (e *IAMRolePolicy) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*IAMRolePolicy)
+	roleName := fi.ValueOf(e.Role.Name)
+	if roleName == "" {
+		return nil
+	}
+
+	var imports []*terraformWriter.Import
+	if a.ExternalPolicies != nil && e.ExternalPolicies != nil {
+		attached := make(map[string]bool)
+		for _, policy := range *a.ExternalPolicies {
+			attached[policy] = true
+		}
+		for _, policy := range *e.ExternalPolicies {
+			if !attached[policy] {
+				continue
+			}
+			h := fnv.New32a()
+			h.Write([]byte(policy))
+			imports = append(imports, &terraformWriter.Import{
+				ResourceType: "aws_iam_role_policy_attachment",
+				ResourceName: fmt.Sprintf("%s-%d", *e.Name, h.Sum32()),
+				ID:           roleName + "/" + policy,
+			})
+		}
+	}
+
+	// Find only returns the inline policy when the task has no external policies
+	if e.ExternalPolicies == nil && a.PolicyDocument != nil {
+		imports = append(imports, &terraformWriter.Import{
+			ResourceType: "aws_iam_role_policy",
+			ResourceName: *e.Name,
+			ID:           roleName + ":" + fi.ValueOf(e.Name),
+		})
+	}
+	return imports
+}
+
 func (e *IAMRolePolicy) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_iam_role_policy", *e.Name)
 }
@@ -218,6 +218,19 @@ func (_ *InternetGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_internet_gateway", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *InternetGateway) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*InternetGateway)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_internet_gateway",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *InternetGateway) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
@@ -329,3 +329,16 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 
 	return target.RenderResource("aws_launch_template", fi.ValueOf(e.Name), tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *LaunchTemplate) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*LaunchTemplate)
+	if a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_launch_template",
+		ResourceName: fi.ValueOf(e.Name),
+		ID:           *a.ID,
+	}}
+}
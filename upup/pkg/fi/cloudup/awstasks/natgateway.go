@@ -386,6 +386,19 @@ func (_ *NatGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_nat_gateway", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *NatGateway) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*NatGateway)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_nat_gateway",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *NatGateway) TerraformLink() *terraformWriter.Literal {
 	if fi.ValueOf(e.Shared) {
 		if e.ID == nil {
@@ -50,6 +50,12 @@ type NetworkLoadBalancer struct {
 	LoadBalancerName *string
 	CLBName          *string
 
+	// ARN is the Amazon Resource Name of the NLB, set when it exists
+	ARN *string
+	// listenerARNs are the ARNs of the existing listeners, by port.
+	// They are not fields of the listeners, which are compared in full.
+	listenerARNs map[int]string
+
 	DNSName      *string
 	HostedZoneId *string
 
@@ -258,6 +264,7 @@ func (e *NetworkLoadBalancer) Find(c *fi.CloudupContext) (*NetworkLoadBalancer,
 	actual.Name = e.Name
 	actual.CLBName = e.CLBName
 	actual.LoadBalancerName = lb.LoadBalancerName
+	actual.ARN = loadBalancerArn
 	actual.DNSName = lb.DNSName
 	actual.HostedZoneId = lb.CanonicalHostedZoneId // CanonicalHostedZoneNameID
 	actual.Scheme = lb.Scheme
@@ -309,9 +316,11 @@ func (e *NetworkLoadBalancer) Find(c *fi.CloudupContext) (*NetworkLoadBalancer,
 
 		actual.Listeners = []*NetworkLoadBalancerListener{}
 		actual.TargetGroups = []*TargetGroup{}
+		actual.listenerARNs = make(map[int]string)
 		for _, l := range response.Listeners {
 			actualListener := &NetworkLoadBalancerListener{}
 			actualListener.Port = int(aws.Int64Value(l.Port))
+			actual.listenerARNs[actualListener.Port] = aws.StringValue(l.ListenerArn)
 			if len(l.Certificates) != 0 {
 				actualListener.SSLCertificateID = aws.StringValue(l.Certificates[0].CertificateArn) // What if there is more then one certificate, can we just grab the default certificate? we don't set it as default, we only set the one.
 				if l.SslPolicy != nil {
@@ -830,6 +839,31 @@ func (_ *NetworkLoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return nil
 }
 
+// TerraformImport implements terraform.Importable; the listeners are imported with the NLB
+func (e *NetworkLoadBalancer) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*NetworkLoadBalancer)
+	if a.ARN == nil {
+		return nil
+	}
+	imports := []*terraformWriter.Import{{
+		ResourceType: "aws_lb",
+		ResourceName: *e.Name,
+		ID:           *a.ARN,
+	}}
+	for _, listener := range e.Listeners {
+		arn := a.listenerARNs[listener.Port]
+		if arn == "" {
+			continue
+		}
+		imports = append(imports, &terraformWriter.Import{
+			ResourceType: "aws_lb_listener",
+			ResourceName: fmt.Sprintf("%v-%v", *e.Name, listener.Port),
+			ID:           arn,
+		})
+	}
+	return imports
+}
+
 func (e *NetworkLoadBalancer) TerraformLink(params ...string) *terraformWriter.Literal {
 	prop := "id"
 	if len(params) > 0 {
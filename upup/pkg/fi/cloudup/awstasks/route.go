@@ -322,3 +322,20 @@ func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Rou
 	name := fmt.Sprintf("route-%v", *e.Name)
 	return t.RenderResource("aws_route", name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *Route) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*Route)
+	if a.RouteTable == nil || a.RouteTable.ID == nil {
+		return nil
+	}
+	destination := fi.ValueOf(a.CIDR)
+	if destination == "" {
+		destination = fi.ValueOf(a.IPv6CIDR)
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_route",
+		ResourceName: fmt.Sprintf("route-%v", *e.Name),
+		ID:           *a.RouteTable.ID + "_" + destination,
+	}}
+}
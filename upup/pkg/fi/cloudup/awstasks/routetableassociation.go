@@ -200,6 +200,19 @@ func (_ *RouteTableAssociation) RenderTerraform(t *terraform.TerraformTarget, a,
 	return t.RenderResource("aws_route_table_association", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *RouteTableAssociation) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*RouteTableAssociation)
+	if a.Subnet == nil || a.Subnet.ID == nil || a.RouteTable == nil || a.RouteTable.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_route_table_association",
+		ResourceName: *e.Name,
+		ID:           *a.Subnet.ID + "/" + *a.RouteTable.ID,
+	}}
+}
+
 func (e *RouteTableAssociation) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralSelfLink("aws_route_table_association", *e.Name)
 }
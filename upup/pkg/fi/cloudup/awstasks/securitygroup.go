@@ -208,6 +208,19 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_security_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SecurityGroup) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*SecurityGroup)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_security_group",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *SecurityGroup) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
@@ -396,3 +396,48 @@ func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e,
 
 	return t.RenderResource("aws_security_group_rule", *e.Name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *SecurityGroupRule) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*SecurityGroupRule)
+	if a.SecurityGroup == nil || a.SecurityGroup.ID == nil {
+		return nil
+	}
+
+	// The import ID has the form <sg>_<type>_<protocol>_<from>_<to>_<source>
+	ruleType := "ingress"
+	if fi.ValueOf(e.Egress) {
+		ruleType = "egress"
+	}
+	protocol := fi.ValueOf(e.Protocol)
+	fromPort := fi.ValueOf(e.FromPort)
+	toPort := fi.ValueOf(e.ToPort)
+	if e.ToPort == nil {
+		toPort = 65535
+	}
+	if protocol == "" || protocol == "-1" {
+		protocol = "all"
+		fromPort = 0
+		toPort = 65536
+	}
+
+	var source string
+	switch {
+	case e.SourceGroup != nil && e.SourceGroup.ID != nil:
+		source = *e.SourceGroup.ID
+	case e.CIDR != nil:
+		source = *e.CIDR
+	case e.IPv6CIDR != nil:
+		source = *e.IPv6CIDR
+	case e.PrefixList != nil:
+		source = *e.PrefixList
+	default:
+		return nil
+	}
+
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_security_group_rule",
+		ResourceName: *e.Name,
+		ID:           fmt.Sprintf("%s_%s_%s_%d_%d_%s", *a.SecurityGroup.ID, ruleType, protocol, fromPort, toPort, source),
+	}}
+}
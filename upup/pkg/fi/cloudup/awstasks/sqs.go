@@ -211,6 +211,19 @@ func (_ *SQS) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SQS)
 	return t.RenderResource("aws_sqs_queue", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SQS) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*SQS)
+	if a.URL == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_sqs_queue",
+		ResourceName: *e.Name,
+		ID:           *a.URL,
+	}}
+}
+
 func (e *SQS) TerraformLink() *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("aws_sqs_queue", *e.Name, "arn")
 }
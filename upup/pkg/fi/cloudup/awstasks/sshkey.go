@@ -215,6 +215,19 @@ func (_ *SSHKey) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SS
 	return t.RenderResource("aws_key_pair", tfName, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *SSHKey) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*SSHKey)
+	if e.IsExistingKey() || a.Name == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_key_pair",
+		ResourceName: strings.Replace(*e.Name, ":", "", -1),
+		ID:           *a.Name,
+	}}
+}
+
 // IsExistingKey will be true if the task has been initialized without using a public key
 // this is when we want to use a key that is already present in AWS.
 func (e *SSHKey) IsExistingKey() bool {
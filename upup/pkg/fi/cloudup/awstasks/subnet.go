@@ -53,6 +53,9 @@ type Subnet struct {
 	Shared                      *bool
 
 	Tags map[string]string
+
+	// terraformID refers to the ID of a shared subnet in the terraform output, e.g. a module input variable
+	terraformID *terraformWriter.Literal
 }
 
 var _ fi.CompareWithID = &Subnet{}
@@ -384,6 +387,22 @@ type terraformSubnet struct {
 }
 
 func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Subnet) error {
+	if fi.ValueOf(e.Shared) && e.ID != nil {
+		name := fi.ValueOf(e.ShortName)
+		if name == "" {
+			name = fi.ValueOf(e.Name)
+		}
+		id, err := t.AddModuleVariable("subnet_"+name+"_id", &terraformWriter.Variable{
+			Type:        "string",
+			Description: "ID of the existing subnet " + name,
+			Default:     terraformWriter.LiteralFromStringValue(*e.ID),
+		})
+		if err != nil {
+			return err
+		}
+		e.terraformID = id
+	}
+
 	if fi.ValueOf(e.ShortName) != "" {
 		name := fi.ValueOf(e.ShortName)
 		if err := t.AddOutputVariable("subnet_"+name+"_id", e.TerraformLink()); err != nil {
@@ -399,7 +418,7 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 		// We probably shouldn't output subnet_ids only in this case - we normally output them by role,
 		// but removing it now might break people.  We could always output subnet_ids though, if we
 		// ever get a request for that.
-		return t.AddOutputVariableArray("subnet_ids", terraformWriter.LiteralFromStringValue(*e.ID))
+		return t.AddOutputVariableArray("subnet_ids", e.TerraformLink())
 	}
 
 	var ipv6CIDR *terraformWriter.Literal
@@ -454,6 +473,19 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 	return t.RenderResource("aws_subnet", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *Subnet) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*Subnet)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_subnet",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *Subnet) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
@@ -462,6 +494,9 @@ func (e *Subnet) TerraformLink() *terraformWriter.Literal {
 		}
 
 		klog.V(4).Infof("reusing existing subnet with id %q", *e.ID)
+		if e.terraformID != nil {
+			return e.terraformID
+		}
 		return terraformWriter.LiteralFromStringValue(*e.ID)
 	}
 
@@ -291,6 +291,19 @@ func (_ *TargetGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_lb_target_group", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *TargetGroup) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*TargetGroup)
+	if fi.ValueOf(e.Shared) || a.ARN == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_lb_target_group",
+		ResourceName: *e.Name,
+		ID:           *a.ARN,
+	}}
+}
+
 func (e *TargetGroup) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
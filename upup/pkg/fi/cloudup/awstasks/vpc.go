@@ -51,6 +51,9 @@ type VPC struct {
 
 	Tags map[string]string
 
+	// terraformID refers to the ID of a shared VPC in the terraform output, e.g. a module input variable
+	terraformID *terraformWriter.Literal
+
 	// AssociateExtraCIDRBlocks contains a list of cidr blocks that should be
 	// associated with the VPC; any other CIDR blocks should be disassociated.
 	// The associations themselves are created through the VPCCIDRBlock awstask.
@@ -280,7 +283,7 @@ func (e *VPC) FindDeletions(c *fi.CloudupContext) ([]fi.CloudupDeletion, error)
 }
 
 type terraformVPCData struct {
-	ID *string `cty:"id"`
+	ID *terraformWriter.Literal `cty:"id"`
 }
 
 type terraformVPC struct {
@@ -292,6 +295,18 @@ type terraformVPC struct {
 }
 
 func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC) error {
+	if fi.ValueOf(e.Shared) && e.ID != nil {
+		id, err := t.AddModuleVariable("vpc_id", &terraformWriter.Variable{
+			Type:        "string",
+			Description: "ID of the existing VPC",
+			Default:     terraformWriter.LiteralFromStringValue(*e.ID),
+		})
+		if err != nil {
+			return err
+		}
+		e.terraformID = id
+	}
+
 	if err := t.AddOutputVariable("vpc_id", e.TerraformLink()); err != nil {
 		return err
 	}
@@ -325,7 +340,7 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 		}
 
 		tf := terraformVPCData{
-			ID: e.ID,
+			ID: e.TerraformLink(),
 		}
 
 		return t.RenderDataSource("aws_vpc", *e.Name, tf)
@@ -350,6 +365,19 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 	return t.RenderResource("aws_vpc", *e.Name, tf)
 }
 
+// TerraformImport implements terraform.Importable
+func (e *VPC) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*VPC)
+	if fi.ValueOf(e.Shared) || a.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_vpc",
+		ResourceName: *e.Name,
+		ID:           *a.ID,
+	}}
+}
+
 func (e *VPC) TerraformLink() *terraformWriter.Literal {
 	shared := fi.ValueOf(e.Shared)
 	if shared {
@@ -358,6 +386,9 @@ func (e *VPC) TerraformLink() *terraformWriter.Literal {
 		}
 
 		klog.V(4).Infof("reusing existing VPC with id %q", *e.ID)
+		if e.terraformID != nil {
+			return e.terraformID
+		}
 		return terraformWriter.LiteralFromStringValue(*e.ID)
 	}
 
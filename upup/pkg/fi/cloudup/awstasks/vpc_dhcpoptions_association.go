@@ -114,3 +114,16 @@ func (_ *VPCDHCPOptionsAssociation) RenderTerraform(t *terraform.TerraformTarget
 
 	return t.RenderResource("aws_vpc_dhcp_options_association", *e.Name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *VPCDHCPOptionsAssociation) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*VPCDHCPOptionsAssociation)
+	if a.VPC == nil || a.VPC.ID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_vpc_dhcp_options_association",
+		ResourceName: *e.Name,
+		ID:           *a.VPC.ID,
+	}}
+}
@@ -35,6 +35,9 @@ type VPCCIDRBlock struct {
 	VPC       *VPC
 	CIDRBlock *string
 
+	// AssociationID is the ID of the association of the CIDR block with the VPC, set when it exists
+	AssociationID *string
+
 	// Shared is set if this is a shared VPC
 	Shared *bool
 }
@@ -54,7 +57,7 @@ func (e *VPCCIDRBlock) Find(c *fi.CloudupContext) (*VPCCIDRBlock, error) {
 		return nil, err
 	}
 
-	found := false
+	var found *ec2.VpcCidrBlockAssociation
 	if e.CIDRBlock != nil {
 		for _, cba := range vpc.CidrBlockAssociationSet {
 			if cba == nil || cba.CidrBlockState == nil {
@@ -67,18 +70,19 @@ func (e *VPCCIDRBlock) Find(c *fi.CloudupContext) (*VPCCIDRBlock, error) {
 			}
 
 			if aws.StringValue(cba.CidrBlock) == aws.StringValue(e.CIDRBlock) {
-				found = true
+				found = cba
 				break
 			}
 		}
 	}
-	if !found {
+	if found == nil {
 		return nil, nil
 	}
 
 	actual := &VPCCIDRBlock{
-		VPC:       &VPC{ID: vpc.VpcId},
-		CIDRBlock: e.CIDRBlock,
+		VPC:           &VPC{ID: vpc.VpcId},
+		CIDRBlock:     e.CIDRBlock,
+		AssociationID: found.AssociationId,
 	}
 
 	// Prevent spurious changes
@@ -162,3 +166,16 @@ func (_ *VPCCIDRBlock) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 	name := fmt.Sprintf("cidr-%v", *e.Name)
 	return t.RenderResource("aws_vpc_ipv4_cidr_block_association", name, tf)
 }
+
+// TerraformImport implements terraform.Importable
+func (e *VPCCIDRBlock) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	a := actual.(*VPCCIDRBlock)
+	if aws.BoolValue(e.Shared) || a.AssociationID == nil {
+		return nil
+	}
+	return []*terraformWriter.Import{{
+		ResourceType: "aws_vpc_ipv4_cidr_block_association",
+		ResourceName: fmt.Sprintf("cidr-%v", *e.Name),
+		ID:           *a.AssociationID,
+	}}
+}
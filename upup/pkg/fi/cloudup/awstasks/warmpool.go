@@ -23,6 +23,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
 // WarmPool provdes the definition for an ASG warm pool in aws.
@@ -121,3 +122,8 @@ func (*WarmPool) RenderAWS(t *awsup.AWSAPITarget, a, e, changes *WarmPool) error
 func (_ *WarmPool) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *WarmPool) error {
 	return nil
 }
+
+// TerraformImport implements terraform.Importable; the warm pool is rendered and imported with the AutoscalingGroup
+func (e *WarmPool) TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import {
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
@@ -73,6 +74,13 @@ import (
 // backoff along the way.
 const ClientMaxRetries = 13
 
+const (
+	// TaskRateLimit is the rate at which tasks may start calling the AWS APIs, per second
+	TaskRateLimit = 20
+	// TaskRateBurst is the number of tasks that may start calling the AWS APIs at once
+	TaskRateBurst = 50
+)
+
 const (
 	DescribeTagsMaxAttempts   = 120
 	DescribeTagsRetryInterval = 2 * time.Second
@@ -113,6 +121,7 @@ const (
 	WellKnownAccountDebian       = "136693071363"
 	WellKnownAccountFlatcar      = "075585003325"
 	WellKnownAccountRedhat       = "309956199498"
+	WellKnownAccountRocky        = "792107900819"
 	WellKnownAccountUbuntu       = "099720109477"
 )
 
@@ -212,6 +221,9 @@ type awsCloudImplementation struct {
 	regionDelayers *RegionDelayers
 
 	instanceTypes *instanceTypes
+
+	// taskRateLimiter is shared by all the tasks that call the APIs in the region
+	taskRateLimiter *rate.Limiter
 }
 
 type RegionDelayers struct {
@@ -247,6 +259,7 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 			instanceTypes: &instanceTypes{
 				typeMap: make(map[string]*ec2.InstanceTypeInfo),
 			},
+			taskRateLimiter: rate.NewLimiter(TaskRateLimit, TaskRateBurst),
 		}
 
 		config := aws.NewConfig().WithRegion(region)
@@ -384,6 +397,12 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 	return i, nil
 }
 
+var _ fi.RateLimitedCloud = &awsCloudImplementation{}
+
+func (c *awsCloudImplementation) TaskRateLimiter() *rate.Limiter {
+	return c.taskRateLimiter
+}
+
 func (c *awsCloudImplementation) Session() (*session.Session, error) {
 	config := aws.NewConfig().WithRegion(c.region)
 	config = config.WithCredentialsChainVerboseErrors(true)
@@ -2069,10 +2088,14 @@ func resolveImage(ssmClient ssmiface.SSMAPI, ec2Client ec2iface.EC2API, name str
 				owner = WellKnownAccountDebian
 			case "debian11":
 				owner = WellKnownAccountDebian
+			case "debian12":
+				owner = WellKnownAccountDebian
 			case "flatcar":
 				owner = WellKnownAccountFlatcar
 			case "redhat", "redhat.com":
 				owner = WellKnownAccountRedhat
+			case "rocky", "rockylinux.org":
+				owner = WellKnownAccountRocky
 			case "ubuntu":
 				owner = WellKnownAccountUbuntu
 			}
@@ -2292,7 +2315,7 @@ func (c *awsCloudImplementation) DefaultInstanceType(cluster *kops.Cluster, ig *
 	var candidates []string
 
 	switch ig.Spec.Role {
-	case kops.InstanceGroupRoleControlPlane, kops.InstanceGroupRoleNode, kops.InstanceGroupRoleAPIServer:
+	case kops.InstanceGroupRoleControlPlane, kops.InstanceGroupRoleNode, kops.InstanceGroupRoleAPIServer, kops.InstanceGroupRoleEtcd:
 		// t3.medium is the cheapest instance with 4GB of mem, unlimited by default, fast and has decent network
 		// c5.large and c4.large are a good second option in case t3.medium is not available in the AZ
 		candidates = []string{"t3.medium", "c5.large", "c4.large", "t4g.medium"}
@@ -33,6 +33,8 @@ func matchInstanceGroup(name string, clusterName string, instancegroups []*kops.
 			groupName = g.ObjectMeta.Name + ".masters." + clusterName
 		case kops.InstanceGroupRoleAPIServer:
 			groupName = g.ObjectMeta.Name + ".apiservers." + clusterName
+		case kops.InstanceGroupRoleEtcd:
+			groupName = g.ObjectMeta.Name + ".etcd." + clusterName
 		case kops.InstanceGroupRoleNode:
 			groupName = g.ObjectMeta.Name + "." + clusterName
 		case kops.InstanceGroupRoleBastion:
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/mirrors"
 )
 
 const (
@@ -280,3 +281,46 @@ func findAllContainerdDockerMappings() map[string]string {
 
 	return versions
 }
+
+// findContainerdRuntimeAssets returns the packages of the additional runtime handlers
+// configured for the cluster or for any of its instance groups.
+func findContainerdRuntimeAssets(c *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, arch architectures.Architecture) ([]*mirrors.MirroredAsset, error) {
+	var runtimes []kops.ContainerdRuntimeSpec
+	if c.Spec.Containerd != nil {
+		runtimes = append(runtimes, c.Spec.Containerd.Runtimes...)
+	}
+	for _, ig := range instanceGroups {
+		if ig.Spec.Containerd != nil {
+			runtimes = append(runtimes, ig.Spec.Containerd.Runtimes...)
+		}
+	}
+
+	var result []*mirrors.MirroredAsset
+	seen := make(map[string]bool)
+	for _, runtime := range runtimes {
+		if runtime.Packages == nil {
+			continue
+		}
+		var assetUrl, assetHash string
+		switch arch {
+		case architectures.ArchitectureAmd64:
+			assetUrl = fi.ValueOf(runtime.Packages.UrlAmd64)
+			assetHash = fi.ValueOf(runtime.Packages.HashAmd64)
+		case architectures.ArchitectureArm64:
+			assetUrl = fi.ValueOf(runtime.Packages.UrlArm64)
+			assetHash = fi.ValueOf(runtime.Packages.HashArm64)
+		}
+		if assetUrl == "" || seen[assetUrl] {
+			continue
+		}
+		seen[assetUrl] = true
+
+		u, h, err := findAssetsUrlHash(assetBuilder, assetUrl, assetHash)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, assetBuilder.BuildMirroredAsset(u, h))
+	}
+
+	return result, nil
+}
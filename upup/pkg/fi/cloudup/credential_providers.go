@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+	"net/url"
+
+	"k8s.io/kops/pkg/apis/kops"
+	apimodel "k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/mirrors"
+)
+
+// findCredentialProviderAssets returns the binaries of the kubelet image credential providers
+// configured for the cluster or for any of its instance groups.
+func findCredentialProviderAssets(c *kops.Cluster, instanceGroups []*kops.InstanceGroup, assetBuilder *assets.AssetBuilder, arch architectures.Architecture) ([]*mirrors.MirroredAsset, error) {
+	kubeletConfigs := []*kops.KubeletConfigSpec{c.Spec.Kubelet, c.Spec.ControlPlaneKubelet}
+	for _, ig := range instanceGroups {
+		kubeletConfigs = append(kubeletConfigs, ig.Spec.Kubelet)
+	}
+
+	kubernetesVersion, err := util.ParseKubernetesVersion(c.Spec.KubernetesVersion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse kubernetes version %q: %v", c.Spec.KubernetesVersion, err)
+	}
+
+	var result []*mirrors.MirroredAsset
+	seen := make(map[string]bool)
+	for _, kubeletConfig := range kubeletConfigs {
+		if kubeletConfig == nil {
+			continue
+		}
+		for i := range kubeletConfig.CredentialProviders {
+			provider := &kubeletConfig.CredentialProviders[i]
+
+			assetURL, assetHash, err := apimodel.CredentialProviderURL(provider, *kubernetesVersion, arch)
+			if err != nil {
+				return nil, err
+			}
+			if seen[assetURL] {
+				continue
+			}
+			seen[assetURL] = true
+
+			var u *url.URL
+			var h *hashing.Hash
+			if assetHash != "" {
+				u, h, err = findAssetsUrlHash(assetBuilder, assetURL, assetHash)
+			} else {
+				u, err = url.Parse(assetURL)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse asset URL %q: %v", assetURL, err)
+				}
+				u, h, err = assetBuilder.RemapFileAndSHA(u)
+			}
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, assetBuilder.BuildMirroredAsset(u, h))
+		}
+	}
+
+	return result, nil
+}
@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
@@ -51,6 +52,9 @@ func PerformAssignments(c *kops.Cluster, cloud fi.Cloud) error {
 		etcdCluster.Manager.BackupRetentionDays = fi.PtrTo[uint32](90)
 	}
 
+	// The ports of additional etcd clusters must not change once they are running
+	model.AssignExtraEtcdClusterPortIndexes(&c.Spec)
+
 	// Topology support
 	// TODO Kris: Unsure if this needs to be here, or if the API conversion code will handle it
 	if c.Spec.Networking.Topology == nil {
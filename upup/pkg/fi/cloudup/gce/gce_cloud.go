@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	"golang.org/x/oauth2/google"
+	"golang.org/x/time/rate"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
 	oauth2 "google.golang.org/api/oauth2/v2"
@@ -77,9 +78,22 @@ type gceCloudImplementation struct {
 	projectInfo *compute.Project
 
 	labels map[string]string
+
+	// taskRateLimiter is shared by all the tasks that call the APIs of the project
+	taskRateLimiter *rate.Limiter
 }
 
-var _ fi.Cloud = &gceCloudImplementation{}
+var (
+	_ fi.Cloud            = &gceCloudImplementation{}
+	_ fi.RateLimitedCloud = &gceCloudImplementation{}
+)
+
+const (
+	// TaskRateLimit is the rate at which tasks may start calling the GCE APIs, per second
+	TaskRateLimit = 20
+	// TaskRateBurst is the number of tasks that may start calling the GCE APIs at once
+	TaskRateBurst = 50
+)
 
 func (c *gceCloudImplementation) ProviderID() kops.CloudProviderID {
 	return kops.CloudProviderGCE
@@ -124,7 +138,11 @@ func NewGCECloud(region string, project string, labels map[string]string) (GCECl
 		return i.(gceCloudInternal).WithLabels(labels), nil
 	}
 
-	c := &gceCloudImplementation{region: region, project: project}
+	c := &gceCloudImplementation{
+		region:          region,
+		project:         project,
+		taskRateLimiter: rate.NewLimiter(TaskRateLimit, TaskRateBurst),
+	}
 
 	ctx := context.Background()
 
@@ -196,6 +214,10 @@ func (c *gceCloudImplementation) WithLabels(labels map[string]string) GCECloud {
 	return i
 }
 
+func (c *gceCloudImplementation) TaskRateLimiter() *rate.Limiter {
+	return c.taskRateLimiter
+}
+
 // Compute returns private struct element compute.
 func (c *gceCloudImplementation) Compute() ComputeClient {
 	return c.compute
@@ -364,7 +364,11 @@ func BuildMachineTypeURL(project, zone, name string) string {
 func BuildImageURL(defaultProject, nameSpec string) string {
 	tokens := strings.Split(nameSpec, "/")
 	var project, name string
-	if len(tokens) == 2 {
+	if len(tokens) == 3 && tokens[1] == "family" {
+		// project/family/name refers to the latest image of the family
+		project = tokens[0]
+		name = "family/" + tokens[2]
+	} else if len(tokens) == 2 {
 		project = tokens[0]
 		name = tokens[1]
 	} else if len(tokens) == 1 {
@@ -380,6 +384,16 @@ func BuildImageURL(defaultProject, nameSpec string) string {
 }
 
 func ShortenImageURL(defaultProject string, imageURL string) (string, error) {
+	if strings.Contains(imageURL, "/global/images/family/") {
+		// Image families are always qualified with their project, as family/name would be read as project/name
+		u, err := gce.ParseGoogleCloudURL(strings.Replace(imageURL, "/global/images/family/", "/global/images/", 1))
+		if err != nil {
+			return "", err
+		}
+		klog.V(4).Infof("Resolved image %q -> %q", imageURL, u.Project+"/family/"+u.Name)
+		return u.Project + "/family/" + u.Name, nil
+	}
+
 	u, err := gce.ParseGoogleCloudURL(imageURL)
 	if err != nil {
 		return "", err
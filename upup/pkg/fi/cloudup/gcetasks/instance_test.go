@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"testing"
+)
+
+func TestImageURL(t *testing.T) {
+	grid := []struct {
+		image string
+		url   string
+	}{
+		{
+			image: "my-image",
+			url:   "https://www.googleapis.com/compute/v1/projects/my-project/global/images/my-image",
+		},
+		{
+			image: "ubuntu-os-cloud/ubuntu-2004-focal-v20221018",
+			url:   "https://www.googleapis.com/compute/v1/projects/ubuntu-os-cloud/global/images/ubuntu-2004-focal-v20221018",
+		},
+		{
+			image: "debian-cloud/family/debian-12",
+			url:   "https://www.googleapis.com/compute/v1/projects/debian-cloud/global/images/family/debian-12",
+		},
+		{
+			image: "my-project/family/my-family",
+			url:   "https://www.googleapis.com/compute/v1/projects/my-project/global/images/family/my-family",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.image, func(t *testing.T) {
+			url := BuildImageURL("my-project", g.image)
+			if url != g.url {
+				t.Errorf("unexpected URL for image %q: expected %q, got %q", g.image, g.url, url)
+			}
+
+			image, err := ShortenImageURL("my-project", url)
+			if err != nil {
+				t.Fatalf("unexpected error shortening %q: %v", url, err)
+			}
+			if image != g.image {
+				t.Errorf("unexpected image for URL %q: expected %q, got %q", url, g.image, image)
+			}
+		})
+	}
+}
@@ -31,10 +31,18 @@ type Loader struct {
 	Builders []fi.CloudupModelBuilder
 
 	tasks map[string]fi.CloudupTask
+	// builders maps the key of each task to the model builder that created it
+	builders map[string]string
 }
 
 func (l *Loader) Init() {
 	l.tasks = make(map[string]fi.CloudupTask)
+	l.builders = make(map[string]string)
+}
+
+// TaskBuilders returns the model builder that created each task, by task key
+func (l *Loader) TaskBuilders() map[string]string {
+	return l.builders
 }
 
 func (l *Loader) BuildTasks(ctx context.Context, lifecycleOverrides map[string]fi.Lifecycle) (map[string]fi.CloudupTask, error) {
@@ -49,6 +57,11 @@ func (l *Loader) BuildTasks(ctx context.Context, lifecycleOverrides map[string]f
 			return nil, err
 		}
 		l.tasks = context.Tasks
+		for key := range l.tasks {
+			if _, found := l.builders[key]; !found {
+				l.builders[key] = fi.ModelBuilderName(builder)
+			}
+		}
 	}
 
 	err := l.processDeferrals()
@@ -154,7 +154,11 @@ type NewClusterOptions struct {
 	// InstanceManager specifies which manager to use for managing instances.
 	InstanceManager string
 
-	Image             string
+	Image string
+	// ImageDistribution selects the default image of a distribution supported by nodeup, e.g. "debian12" or "ubuntu2404".
+	// It is used for the instance groups without an image that are created with the cluster; it is not stored,
+	// so instance groups created later use the default image of the channel.
+	ImageDistribution string
 	NodeImage         string
 	ControlPlaneImage string
 	BastionImage      string
@@ -435,7 +439,7 @@ func NewCluster(opt *NewClusterOptions, clientset simple.Clientset) (*NewCluster
 				if err != nil {
 					return nil, err
 				}
-				instanceGroup.Spec.Image, err = defaultImage(cluster, channel, architecture)
+				instanceGroup.Spec.Image, err = defaultImage(cluster, channel, architecture, opt.ImageDistribution)
 				if err != nil {
 					return nil, err
 				}
@@ -1520,8 +1524,159 @@ func addCiliumNetwork(cluster *api.Cluster) {
 	cluster.Spec.KubeProxy.Enabled = &enabled
 }
 
-// defaultImage returns the default Image, based on the cloudprovider
-func defaultImage(cluster *api.Cluster, channel *api.Channel, architecture architectures.Architecture) (string, error) {
+// distributionImages are the images of the distributions supported by nodeup, by cloudprovider and architecture.
+// Only the clouds where an image name always refers to the latest release of the distribution are listed,
+// e.g. AWS SSM parameters, GCE image families and Azure URNs with the "latest" version.
+var distributionImages = map[api.CloudProviderID]map[string]map[architectures.Architecture]string{
+	api.CloudProviderAWS: {
+		"amazonlinux2023": {
+			architectures.ArchitectureAmd64: "ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-x86_64",
+			architectures.ArchitectureArm64: "ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-arm64",
+		},
+		"debian11": {
+			architectures.ArchitectureAmd64: "ssm:/aws/service/debian/release/11/latest/amd64",
+			architectures.ArchitectureArm64: "ssm:/aws/service/debian/release/11/latest/arm64",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "ssm:/aws/service/debian/release/12/latest/amd64",
+			architectures.ArchitectureArm64: "ssm:/aws/service/debian/release/12/latest/arm64",
+		},
+		"rhel9": {
+			architectures.ArchitectureAmd64: "redhat.com/RHEL-9.*_HVM-*-x86_64-*-Hourly2-GP*",
+			architectures.ArchitectureArm64: "redhat.com/RHEL-9.*_HVM-*-arm64-*-Hourly2-GP*",
+		},
+		"rocky9": {
+			architectures.ArchitectureAmd64: "rockylinux.org/Rocky-9-EC2-Base-9.*.x86_64",
+			architectures.ArchitectureArm64: "rockylinux.org/Rocky-9-EC2-Base-9.*.aarch64",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "ssm:/aws/service/canonical/ubuntu/server/22.04/stable/current/amd64/hvm/ebs-gp2/ami-id",
+			architectures.ArchitectureArm64: "ssm:/aws/service/canonical/ubuntu/server/22.04/stable/current/arm64/hvm/ebs-gp2/ami-id",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "ssm:/aws/service/canonical/ubuntu/server/24.04/stable/current/amd64/hvm/ebs-gp3/ami-id",
+			architectures.ArchitectureArm64: "ssm:/aws/service/canonical/ubuntu/server/24.04/stable/current/arm64/hvm/ebs-gp3/ami-id",
+		},
+	},
+	api.CloudProviderAzure: {
+		"debian11": {
+			architectures.ArchitectureAmd64: "Debian:debian-11:11-gen2:latest",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "Debian:debian-12:12-gen2:latest",
+			architectures.ArchitectureArm64: "Debian:debian-12:12-arm64:latest",
+		},
+		"rhel9": {
+			architectures.ArchitectureAmd64: "RedHat:RHEL:9-lvm-gen2:latest",
+			architectures.ArchitectureArm64: "RedHat:rhel-arm64:9_2-arm64:latest",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "Canonical:0001-com-ubuntu-server-jammy:22_04-lts-gen2:latest",
+			architectures.ArchitectureArm64: "Canonical:0001-com-ubuntu-server-jammy:22_04-lts-arm64:latest",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "Canonical:ubuntu-24_04-lts:server:latest",
+			architectures.ArchitectureArm64: "Canonical:ubuntu-24_04-lts:server-arm64:latest",
+		},
+	},
+	api.CloudProviderDO: {
+		"debian11": {
+			architectures.ArchitectureAmd64: "debian-11-x64",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "debian-12-x64",
+		},
+		"rocky9": {
+			architectures.ArchitectureAmd64: "rockylinux-9-x64",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "ubuntu-22-04-x64",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "ubuntu-24-04-x64",
+		},
+	},
+	api.CloudProviderGCE: {
+		"debian11": {
+			architectures.ArchitectureAmd64: "debian-cloud/family/debian-11",
+			architectures.ArchitectureArm64: "debian-cloud/family/debian-11-arm64",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "debian-cloud/family/debian-12",
+			architectures.ArchitectureArm64: "debian-cloud/family/debian-12-arm64",
+		},
+		"rhel9": {
+			architectures.ArchitectureAmd64: "rhel-cloud/family/rhel-9",
+			architectures.ArchitectureArm64: "rhel-cloud/family/rhel-9-arm64",
+		},
+		"rocky9": {
+			architectures.ArchitectureAmd64: "rocky-linux-cloud/family/rocky-linux-9",
+			architectures.ArchitectureArm64: "rocky-linux-cloud/family/rocky-linux-9-arm64",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "ubuntu-os-cloud/family/ubuntu-2204-lts",
+			architectures.ArchitectureArm64: "ubuntu-os-cloud/family/ubuntu-2204-lts-arm64",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "ubuntu-os-cloud/family/ubuntu-2404-lts-amd64",
+			architectures.ArchitectureArm64: "ubuntu-os-cloud/family/ubuntu-2404-lts-arm64",
+		},
+	},
+	api.CloudProviderHetzner: {
+		"debian11": {
+			architectures.ArchitectureAmd64: "debian-11",
+			architectures.ArchitectureArm64: "debian-11",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "debian-12",
+			architectures.ArchitectureArm64: "debian-12",
+		},
+		"rocky9": {
+			architectures.ArchitectureAmd64: "rocky-9",
+			architectures.ArchitectureArm64: "rocky-9",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "ubuntu-22.04",
+			architectures.ArchitectureArm64: "ubuntu-22.04",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "ubuntu-24.04",
+			architectures.ArchitectureArm64: "ubuntu-24.04",
+		},
+	},
+	api.CloudProviderScaleway: {
+		"debian11": {
+			architectures.ArchitectureAmd64: "debian_bullseye",
+			architectures.ArchitectureArm64: "debian_bullseye",
+		},
+		"debian12": {
+			architectures.ArchitectureAmd64: "debian_bookworm",
+			architectures.ArchitectureArm64: "debian_bookworm",
+		},
+		"rocky9": {
+			architectures.ArchitectureAmd64: "rockylinux_9",
+		},
+		"ubuntu2204": {
+			architectures.ArchitectureAmd64: "ubuntu_jammy",
+			architectures.ArchitectureArm64: "ubuntu_jammy",
+		},
+		"ubuntu2404": {
+			architectures.ArchitectureAmd64: "ubuntu_noble",
+			architectures.ArchitectureArm64: "ubuntu_noble",
+		},
+	},
+}
+
+// defaultImage returns the default Image, based on the cloudprovider, or on the distribution when one is requested
+func defaultImage(cluster *api.Cluster, channel *api.Channel, architecture architectures.Architecture, distribution string) (string, error) {
+	if distribution != "" {
+		image := distributionImages[cluster.Spec.GetCloudProvider()][distribution][architecture]
+		if image == "" {
+			return "", fmt.Errorf("unable to determine image of distribution %q for cloud provider %q and architecture %q; please specify the image", distribution, cluster.Spec.GetCloudProvider(), architecture)
+		}
+		return image, nil
+	}
+
 	if channel != nil {
 		var kubernetesVersion *semver.Version
 		if cluster.Spec.KubernetesVersion != "" {
@@ -434,7 +434,7 @@ func TestDefaultImage(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actual, err := defaultImage(test.cluster, channel, test.architecture)
+		actual, err := defaultImage(test.cluster, channel, test.architecture, "")
 		if err != nil {
 			t.Error(err)
 			continue
@@ -444,3 +444,79 @@ func TestDefaultImage(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultImageForDistribution(t *testing.T) {
+	tests := []struct {
+		cloud        api.CloudProviderSpec
+		architecture architectures.Architecture
+		distribution string
+		expected     string
+		expectError  bool
+	}{
+		{
+			cloud:        api.CloudProviderSpec{AWS: &api.AWSSpec{}},
+			architecture: architectures.ArchitectureArm64,
+			distribution: "amazonlinux2023",
+			expected:     "ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-arm64",
+		},
+		{
+			cloud:        api.CloudProviderSpec{Hetzner: &api.HetznerSpec{}},
+			architecture: architectures.ArchitectureAmd64,
+			distribution: "debian12",
+			expected:     "debian-12",
+		},
+		{
+			cloud:        api.CloudProviderSpec{GCE: &api.GCESpec{}},
+			architecture: architectures.ArchitectureArm64,
+			distribution: "ubuntu2404",
+			expected:     "ubuntu-os-cloud/family/ubuntu-2404-lts-arm64",
+		},
+		{
+			cloud:        api.CloudProviderSpec{Azure: &api.AzureSpec{}},
+			architecture: architectures.ArchitectureAmd64,
+			distribution: "debian12",
+			expected:     "Debian:debian-12:12-gen2:latest",
+		},
+		{
+			cloud:        api.CloudProviderSpec{Azure: &api.AzureSpec{}},
+			architecture: architectures.ArchitectureAmd64,
+			distribution: "rocky9",
+			expectError:  true,
+		},
+		{
+			cloud:        api.CloudProviderSpec{DO: &api.DOSpec{}},
+			architecture: architectures.ArchitectureArm64,
+			distribution: "ubuntu2404",
+			expectError:  true,
+		},
+		{
+			cloud:        api.CloudProviderSpec{Scaleway: &api.ScalewaySpec{}},
+			architecture: architectures.ArchitectureAmd64,
+			distribution: "amazonlinux2023",
+			expectError:  true,
+		},
+	}
+
+	for _, test := range tests {
+		cluster := &api.Cluster{
+			Spec: api.ClusterSpec{
+				KubernetesVersion: "v1.25.0",
+				CloudProvider:     test.cloud,
+			},
+		}
+		actual, err := defaultImage(cluster, nil, test.architecture, test.distribution)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("expected error for distribution %q on %q, got image %q", test.distribution, cluster.Spec.GetCloudProvider(), actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("unexpected default image for distribution %q on %q: expected=%q, actual=%q", test.distribution, cluster.Spec.GetCloudProvider(), test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/policy"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// buildPolicyEngine compiles the policies in the state store, and in the local PolicyDir if set
+func (c *ApplyClusterCmd) buildPolicyEngine(ctx context.Context, configBase vfs.Path) (*policy.Engine, error) {
+	if c.GetAssets {
+		// Listing the assets does not change the cluster
+		return policy.NewEngine(nil)
+	}
+
+	policies, err := policy.ReadPolicies(ctx, configBase.Join(registry.PathPolicies))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.PolicyDir != "" {
+		dir, err := vfs.Context.BuildVfsPath(c.PolicyDir)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing policy directory %q: %w", c.PolicyDir, err)
+		}
+		local, err := policy.ReadPolicies(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(local) == 0 {
+			return nil, fmt.Errorf("no policies found in %q", c.PolicyDir)
+		}
+		policies = append(policies, local...)
+	}
+
+	return policy.NewEngine(policies)
+}
+
+// planChanges runs the tasks against a dry-run target, to compute the changes before they are applied
+func (c *ApplyClusterCmd) planChanges(ctx context.Context, assetBuilder *assets.AssetBuilder, cluster *kops.Cluster, keyStore fi.CAStore, secretStore fi.SecretStore, configBase vfs.Path, options fi.RunTasksOptions) (*fi.Plan, error) {
+	target := fi.NewCloudupDryRunTarget(assetBuilder, io.Discard)
+	context, err := fi.NewCloudupContext(ctx, target, cluster, c.Cloud, keyStore, secretStore, configBase, c.TaskMap)
+	if err != nil {
+		return nil, fmt.Errorf("error building context: %v", err)
+	}
+	if err := context.RunTasks(options); err != nil {
+		return nil, fmt.Errorf("error planning changes: %v", err)
+	}
+	return target.BuildPlan(c.TaskMap)
+}
+
+// evaluatePolicies logs the violations of the policies, and returns an error if any rule that denies the changes is violated
+func (c *ApplyClusterCmd) evaluatePolicies(engine *policy.Engine, cluster *kops.Cluster, plan *fi.Plan) ([]fi.PlanViolation, error) {
+	violations, err := engine.Evaluate(&policy.Input{
+		Cluster: cluster,
+		Tasks:   c.TaskMap,
+		Plan:    plan,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating policies: %w", err)
+	}
+
+	var planViolations []fi.PlanViolation
+	var denied []string
+	for _, v := range violations {
+		planViolations = append(planViolations, fi.PlanViolation{
+			Rule:    v.Rule,
+			Action:  string(v.Action),
+			Task:    v.Task,
+			Message: v.Message,
+		})
+		if v.Action == policy.ActionDeny {
+			denied = append(denied, v.String())
+		} else {
+			klog.Warningf("policy violation: %s", v.String())
+		}
+	}
+
+	if len(denied) != 0 {
+		return planViolations, fmt.Errorf("changes denied by policy:\n  %s", strings.Join(denied, "\n  "))
+	}
+	return planViolations, nil
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/nodelabels"
@@ -97,6 +98,22 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		if ig.Spec.MaxSize == nil {
 			ig.Spec.MaxSize = fi.PtrTo(int32(1))
 		}
+	} else if ig.IsEtcdOnly() {
+		if !featureflag.EtcdNodes.Enabled() {
+			return nil, fmt.Errorf("etcd nodes requires the EtcdNodes feature flag to be enabled")
+		}
+		if ig.Spec.MachineType == "" {
+			ig.Spec.MachineType, err = defaultMachineType(cloud, cluster, ig)
+			if err != nil {
+				return nil, fmt.Errorf("error assigning default machine type for etcd nodes: %v", err)
+			}
+		}
+		if ig.Spec.MinSize == nil {
+			ig.Spec.MinSize = fi.PtrTo(int32(1))
+		}
+		if ig.Spec.MaxSize == nil {
+			ig.Spec.MaxSize = fi.PtrTo(int32(1))
+		}
 	} else if ig.Spec.Role == kops.InstanceGroupRoleBastion {
 		if ig.Spec.MachineType == "" {
 			ig.Spec.MachineType, err = defaultMachineType(cloud, cluster, ig)
@@ -133,7 +150,7 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine machine architecture for InstanceGroup %q: %v", ig.ObjectMeta.Name, err)
 		}
-		ig.Spec.Image, err = defaultImage(cluster, channel, architecture)
+		ig.Spec.Image, err = defaultImage(cluster, channel, architecture, "")
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine default image for instance group %q: %v", ig.ObjectMeta.Name, err)
 		}
@@ -225,6 +242,21 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		}
 	}
 
+	// Label the nodes with the sandboxed runtimes they provide, to be used by the scheduling of the RuntimeClasses
+	var runtimes []kops.ContainerdRuntimeSpec
+	if cluster.Spec.Containerd != nil {
+		runtimes = cluster.Spec.Containerd.Runtimes
+	}
+	if ig.Spec.Containerd != nil && ig.Spec.Containerd.Runtimes != nil {
+		runtimes = ig.Spec.Containerd.Runtimes
+	}
+	for _, runtime := range runtimes {
+		if ig.Spec.NodeLabels == nil {
+			ig.Spec.NodeLabels = make(map[string]string)
+		}
+		ig.Spec.NodeLabels[kops.ContainerdRuntimeNodeLabelPrefix+runtime.HandlerName()] = "true"
+	}
+
 	if ig.Spec.Manager == "" {
 		ig.Spec.Manager = kops.InstanceManagerCloudGroup
 	}
@@ -272,6 +304,24 @@ func PopulateInstanceGroupSpec(cluster *kops.Cluster, input *kops.InstanceGroup,
 		reflectutils.JSONMergeStruct(igKubeletConfig, ig.Spec.Kubelet)
 	}
 
+	if hardening := model.InstanceGroupHardening(cluster, ig); hardening != nil {
+		model.ApplyHardeningKubeletDefaults(hardening.Profile, igKubeletConfig)
+	}
+
+	if ig.Spec.Swap != nil {
+		// The kubelet refuses to start on a node with swap, unless it is allowed to use it
+		igKubeletConfig.FailSwapOn = fi.PtrTo(false)
+		if igKubeletConfig.MemorySwapBehavior == "" {
+			igKubeletConfig.MemorySwapBehavior = "LimitedSwap"
+		}
+		if igKubeletConfig.FeatureGates == nil {
+			igKubeletConfig.FeatureGates = make(map[string]string)
+		}
+		if _, found := igKubeletConfig.FeatureGates["NodeSwap"]; !found {
+			igKubeletConfig.FeatureGates["NodeSwap"] = "true"
+		}
+	}
+
 	{
 		if ig.IsControlPlane() {
 			// (Even though the value is empty, we still expect <Key>=<Value>:<Effect>)
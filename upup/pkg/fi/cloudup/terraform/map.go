@@ -79,6 +79,107 @@ func (m *mapStringLiteral) Write(buffer *bytes.Buffer, indent int, key string) {
 	buffer.WriteString("}\n")
 }
 
+// mergedMapLiteral is a map that is merged into another map expression, e.g. an input variable
+type mergedMapLiteral struct {
+	base    *terraformWriter.Literal
+	members *mapStringLiteral
+}
+
+func (m *mergedMapLiteral) IsSingleValue() bool {
+	return false
+}
+
+// write writes the merge of the base expression with a map's key-value pairs.
+// Example:
+//
+//	key = merge(var.tags, {
+//	  "key1" = "value1"
+//	  "key2" = "value2"
+//	})
+func (m *mergedMapLiteral) Write(buffer *bytes.Buffer, indent int, key string) {
+	if len(m.members.members) == 0 {
+		writeIndent(buffer, indent)
+		buffer.WriteString(key)
+		buffer.WriteString(" = ")
+		buffer.WriteString(m.base.String)
+		buffer.WriteRune('\n')
+		return
+	}
+	members := &bytes.Buffer{}
+	m.members.Write(members, indent, key)
+	writeIndent(buffer, indent)
+	buffer.WriteString(key)
+	buffer.WriteString(" = merge(")
+	buffer.WriteString(m.base.String)
+	buffer.WriteString(", ")
+	body := strings.TrimSuffix(strings.TrimPrefix(members.String(), strings.Repeat(" ", indent)+key+" = "), "\n")
+	buffer.WriteString(body)
+	buffer.WriteString(")\n")
+}
+
+// mergedTagBlocks are the tag blocks of an autoscaling group, merged into another map expression, e.g. an input variable.
+// The tags are propagated to the instances that the autoscaling group launches.
+type mergedTagBlocks struct {
+	tags *mergedMapLiteral
+}
+
+func (m *mergedTagBlocks) IsSingleValue() bool {
+	return false
+}
+
+// write writes a dynamic block over the merge of the base expression with the tags.
+// Example:
+//
+//	dynamic "key" {
+//	  for_each = merge(var.tags, {
+//	    "key1" = "value1"
+//	  })
+//	  content {
+//	    key                 = key.key
+//	    propagate_at_launch = true
+//	    value               = key.value
+//	  }
+//	}
+func (m *mergedTagBlocks) Write(buffer *bytes.Buffer, indent int, key string) {
+	writeIndent(buffer, indent)
+	fmt.Fprintf(buffer, "dynamic %q {\n", key)
+	m.tags.Write(buffer, indent+2, "for_each")
+	content := &object{field: map[string]element{
+		"key":                 terraformWriter.LiteralTokens(key, "key"),
+		"propagate_at_launch": terraformWriter.LiteralTokens("true"),
+		"value":               terraformWriter.LiteralTokens(key, "value"),
+	}}
+	content.Write(buffer, indent+2, "content")
+	writeIndent(buffer, indent)
+	buffer.WriteString("}\n")
+}
+
+// toMergedTagBlocks converts the tag blocks of an autoscaling group into a mergedTagBlocks.
+// It returns nil unless every block is a key and value propagated at launch.
+func toMergedTagBlocks(blocks *sliceObject, base *terraformWriter.Literal) *mergedTagBlocks {
+	members := &mapStringLiteral{members: make(map[string]*terraformWriter.Literal, len(blocks.members))}
+	for _, member := range blocks.members {
+		o, ok := member.(*object)
+		if !ok || len(o.field) != 3 {
+			return nil
+		}
+		key, ok := o.field["key"].(*terraformWriter.Literal)
+		if !ok || !strings.HasPrefix(key.String, `"`) {
+			return nil
+		}
+		value, ok := o.field["value"].(*terraformWriter.Literal)
+		if !ok {
+			return nil
+		}
+		propagate, ok := o.field["propagate_at_launch"].(*terraformWriter.Literal)
+		if !ok || propagate.String != "true" {
+			return nil
+		}
+		members.members[strings.TrimSuffix(strings.TrimPrefix(key.String, `"`), `"`)] = value
+	}
+	return &mergedTagBlocks{tags: &mergedMapLiteral{base: base, members: members}}
+}
+
 func mapToElement(item interface{}) *mapStringLiteral {
 	v := reflect.ValueOf(item)
 	if v.Kind() != reflect.Map {
@@ -34,6 +34,12 @@ type TerraformTarget struct {
 
 	ClusterName string
 
+	// EmitImports enables finding the existing cloud resources, and writing import blocks for them
+	EmitImports bool
+
+	// Module writes a reusable module, without provider configuration and with input variables
+	Module bool
+
 	outDir string
 	// extra config to add to the provider block
 	clusterSpecTarget *kops.TargetSpec
@@ -51,7 +57,26 @@ func NewTerraformTarget(cloud fi.Cloud, project string, outDir string, clusterSp
 	return &target
 }
 
-var _ fi.CloudupTarget = &TerraformTarget{}
+var (
+	_ fi.CloudupTarget                         = &TerraformTarget{}
+	_ fi.ImportingTarget[fi.CloudupSubContext] = &TerraformTarget{}
+)
+
+// AddModuleVariable adds an input variable when writing a module, returning the literal that refers to it.
+// Otherwise it returns the default value.
+func (t *TerraformTarget) AddModuleVariable(key string, v *terraformWriter.Variable) (*terraformWriter.Literal, error) {
+	if !t.Module {
+		return v.Default, nil
+	}
+	return t.AddVariable(key, v)
+}
+
+// Importable is implemented by tasks whose existing resources can be imported into the TF state
+type Importable interface {
+	// TerraformImport returns the imports for the existing resources of the task, or nil if they should not be imported.
+	// Tasks that render several TF resources, such as a load balancer and its listeners, return an import for each.
+	TerraformImport(actual fi.CloudupTask) []*terraformWriter.Import
+}
 
 func (t *TerraformTarget) AddFileResource(resourceType string, resourceName string, key string, r fi.Resource, base64 bool) (*terraformWriter.Literal, error) {
 	d, err := fi.ResourceAsBytes(r)
@@ -69,7 +94,23 @@ func (t *TerraformTarget) ProcessDeletions() bool {
 }
 
 func (t *TerraformTarget) DefaultCheckExisting() bool {
-	return false
+	return t.EmitImports
+}
+
+func (t *TerraformTarget) ImportExisting(actual, expected fi.CloudupTask) error {
+	importable, ok := expected.(Importable)
+	if !ok {
+		name := ""
+		if hasName, ok := expected.(fi.HasName); ok {
+			name = fi.ValueOf(hasName.GetName())
+		}
+		klog.Warningf("existing resource for %s %q cannot be imported into terraform", fi.TypeNameForTask(expected), name)
+		return nil
+	}
+	for _, imp := range importable.TerraformImport(actual) {
+		t.AddImport(imp)
+	}
+	return nil
 }
 
 // tfGetProviderExtraConfig is a helper function to get extra config with safety checks on the pointers.
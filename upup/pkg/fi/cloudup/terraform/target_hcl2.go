@@ -30,20 +30,37 @@ import (
 func (t *TerraformTarget) finishHCL2() error {
 	buf := &bytes.Buffer{}
 
+	var tags *terraformWriter.Literal
+	if t.Module {
+		var err error
+		tags, err = t.AddVariable("tags", &terraformWriter.Variable{
+			Type:        "map(string)",
+			Description: "Additional tags to add to all resources",
+			Default:     terraformWriter.LiteralTokens("{}"),
+		})
+		if err != nil {
+			return err
+		}
+		writeVariables(buf, t.GetVariables())
+	}
+
 	outputs, err := t.GetOutputs()
 	if err != nil {
 		return err
 	}
 	writeLocalsOutputs(buf, outputs)
 
-	t.writeProviders(buf)
+	if !t.Module {
+		// The provider configuration of a module is passed by the calling module
+		t.writeProviders(buf)
+	}
 
 	resourcesByType, err := t.GetResourcesByType()
 	if err != nil {
 		return err
 	}
 
-	t.writeResources(buf, resourcesByType)
+	t.writeResources(buf, resourcesByType, tags)
 
 	dataSourcesByType, err := t.GetDataSourcesByType()
 	if err != nil {
@@ -52,7 +69,14 @@ func (t *TerraformTarget) finishHCL2() error {
 
 	t.writeDataSources(buf, dataSourcesByType)
 
-	t.writeTerraform(buf)
+	imports, err := t.GetImports()
+	if err != nil {
+		return err
+	}
+
+	writeImports(buf, imports)
+
+	t.writeTerraform(buf, len(imports) > 0)
 
 	t.Files["kubernetes.tf"] = buf.Bytes()
 
@@ -161,7 +185,58 @@ func sortedKeysForMap[K ~string, V any](m map[K]V) []K {
 	return keys
 }
 
-func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[string]map[string]interface{}) {
+// writeVariables creates the variable blocks for all input variables
+// Example:
+//
+//	variable "key1" {
+//	  default     = "value1"
+//	  description = "The key"
+//	  type        = string
+//	}
+func writeVariables(buf *bytes.Buffer, variables map[string]*terraformWriter.Variable) {
+	for _, name := range sortedKeysForMap(variables) {
+		v := variables[name]
+		body := map[string]*terraformWriter.Literal{
+			"type":        terraformWriter.LiteralTokens(v.Type),
+			"description": terraformWriter.LiteralFromStringValue(v.Description),
+		}
+		if v.Default != nil {
+			body["default"] = v.Default
+		}
+		mapToElement(body).
+			ToObject().
+			Write(buf, 0, fmt.Sprintf("variable %q", name))
+		buf.WriteString("\n")
+	}
+}
+
+// mergeTags merges the tags of a resource, including those of its nested blocks, into the tags variable
+func mergeTags(e element, tags *terraformWriter.Literal) element {
+	switch e := e.(type) {
+	case *object:
+		for key, field := range e.field {
+			if m, ok := field.(*mapStringLiteral); ok && key == "tags" {
+				e.field[key] = &mergedMapLiteral{base: tags, members: m}
+				continue
+			}
+			// Autoscaling groups have a tag block for each tag
+			if blocks, ok := field.(*sliceObject); ok && key == "tag" {
+				if merged := toMergedTagBlocks(blocks, tags); merged != nil {
+					e.field[key] = merged
+					continue
+				}
+			}
+			e.field[key] = mergeTags(field, tags)
+		}
+	case *sliceObject:
+		for i, member := range e.members {
+			e.members[i] = mergeTags(member, tags)
+		}
+	}
+	return e
+}
+
+func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[string]map[string]interface{}, tags *terraformWriter.Literal) {
 	resourceTypes := make([]string, 0, len(resourcesByType))
 	for resourceType := range resourcesByType {
 		resourceTypes = append(resourceTypes, resourceType)
@@ -175,8 +250,11 @@ func (t *TerraformTarget) writeResources(buf *bytes.Buffer, resourcesByType map[
 		}
 		sort.Strings(resourceNames)
 		for _, resourceName := range resourceNames {
-			toElement(resources[resourceName]).
-				Write(buf, 0, fmt.Sprintf("resource %q %q", resourceType, resourceName))
+			e := toElement(resources[resourceName])
+			if tags != nil {
+				e = mergeTags(e, tags)
+			}
+			e.Write(buf, 0, fmt.Sprintf("resource %q %q", resourceType, resourceName))
 			buf.WriteString("\n")
 		}
 	}
@@ -203,9 +281,30 @@ func (t *TerraformTarget) writeDataSources(buf *bytes.Buffer, dataSourcesByType
 	}
 }
 
-func (t *TerraformTarget) writeTerraform(buf *bytes.Buffer) {
+// writeImports creates an import block for each existing resource
+// Example:
+//
+//	import {
+//	  to = aws_vpc.example-com
+//	  id = "vpc-12345678"
+//	}
+func writeImports(buf *bytes.Buffer, imports map[string]string) {
+	for _, address := range sortedKeysForMap(imports) {
+		buf.WriteString("import {\n")
+		buf.WriteString(fmt.Sprintf("  to = %s\n", address))
+		buf.WriteString(fmt.Sprintf("  id = %q\n", imports[address]))
+		buf.WriteString("}\n\n")
+	}
+}
+
+func (t *TerraformTarget) writeTerraform(buf *bytes.Buffer, hasImports bool) {
 	buf.WriteString("terraform {\n")
-	buf.WriteString("  required_version = \">= 0.15.0\"\n")
+	if hasImports {
+		// import blocks were introduced in Terraform 1.5
+		buf.WriteString("  required_version = \">= 1.5.0\"\n")
+	} else {
+		buf.WriteString("  required_version = \">= 0.15.0\"\n")
+	}
 	buf.WriteString("  required_providers {\n")
 
 	providers := make(map[string]bool)
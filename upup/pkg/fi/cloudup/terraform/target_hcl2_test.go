@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
@@ -85,3 +86,192 @@ output "key1" {
 		})
 	}
 }
+
+func TestWriteImports(t *testing.T) {
+	cases := []struct {
+		name     string
+		imports  []*terraformWriter.Import
+		expected string
+	}{
+		{
+			name:     "no imports",
+			expected: "",
+		},
+		{
+			name: "sorted imports",
+			imports: []*terraformWriter.Import{
+				{
+					ResourceType: "aws_vpc",
+					ResourceName: "minimal.example.com",
+					ID:           "vpc-12345678",
+				},
+				{
+					ResourceType: "aws_security_group_rule",
+					ResourceName: "from-0.0.0.0/0-ingress-tcp-22to22-masters-minimal-example-com",
+					ID:           "sg-12345678_ingress_tcp_22_22_0.0.0.0/0",
+				},
+			},
+			expected: `
+import {
+  to = aws_security_group_rule.from-0-0-0-0--0-ingress-tcp-22to22-masters-minimal-example-com
+  id = "sg-12345678_ingress_tcp_22_22_0.0.0.0/0"
+}
+
+import {
+  to = aws_vpc.minimal-example-com
+  id = "vpc-12345678"
+}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &terraformWriter.TerraformWriter{}
+			w.InitTerraformWriter()
+			for _, imp := range tc.imports {
+				w.AddImport(imp)
+			}
+			imports, err := w.GetImports()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			writeImports(buf, imports)
+			actual := strings.TrimSpace(buf.String())
+			expected := strings.TrimSpace(tc.expected)
+			if actual != expected {
+				diffString := diff.FormatDiff(expected, string(actual))
+				t.Logf("diff:\n%s\n", diffString)
+				t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+			}
+		})
+	}
+}
+
+func TestWriteVariables(t *testing.T) {
+	variables := map[string]*terraformWriter.Variable{
+		"vpc_id": {
+			Type:        "string",
+			Description: "ID of the existing VPC",
+			Default:     terraformWriter.LiteralFromStringValue("vpc-12345678"),
+		},
+		"nodes_min_size": {
+			Type:        "number",
+			Description: "Minimum number of instances in nodes",
+			Default:     terraformWriter.LiteralFromIntValue(2),
+		},
+	}
+	expected := `
+variable "nodes_min_size" {
+  default     = 2
+  description = "Minimum number of instances in nodes"
+  type        = number
+}
+
+variable "vpc_id" {
+  default     = "vpc-12345678"
+  description = "ID of the existing VPC"
+  type        = string
+}`
+
+	buf := &bytes.Buffer{}
+	writeVariables(buf, variables)
+	actual := strings.TrimSpace(buf.String())
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	type tagSpecification struct {
+		ResourceType *string           `cty:"resource_type"`
+		Tags         map[string]string `cty:"tags"`
+	}
+	type resource struct {
+		Name              *string             `cty:"name"`
+		Tags              map[string]string   `cty:"tags"`
+		TagSpecifications []*tagSpecification `cty:"tag_specifications"`
+	}
+	item := &resource{
+		Name: fi.PtrTo("nodes"),
+		Tags: map[string]string{"KubernetesCluster": "minimal.example.com"},
+		TagSpecifications: []*tagSpecification{
+			{
+				ResourceType: fi.PtrTo("instance"),
+				Tags:         map[string]string{"Name": "nodes.minimal.example.com"},
+			},
+		},
+	}
+	expected := `
+resource "aws_launch_template" "nodes" {
+  name = "nodes"
+  tag_specifications {
+    resource_type = "instance"
+    tags = merge(var.tags, {
+      "Name" = "nodes.minimal.example.com"
+    })
+  }
+  tags = merge(var.tags, {
+    "KubernetesCluster" = "minimal.example.com"
+  })
+}`
+
+	buf := &bytes.Buffer{}
+	mergeTags(toElement(item), terraformWriter.LiteralTokens("var", "tags")).
+		Write(buf, 0, `resource "aws_launch_template" "nodes"`)
+	actual := strings.TrimSpace(buf.String())
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
+
+func TestMergeTagsAutoscalingGroup(t *testing.T) {
+	type tag struct {
+		Key               *string `cty:"key"`
+		Value             *string `cty:"value"`
+		PropagateAtLaunch *bool   `cty:"propagate_at_launch"`
+	}
+	type resource struct {
+		Name *string `cty:"name"`
+		Tags []*tag  `cty:"tag"`
+	}
+	item := &resource{
+		Name: fi.PtrTo("nodes.minimal.example.com"),
+		Tags: []*tag{
+			{Key: fi.PtrTo("KubernetesCluster"), Value: fi.PtrTo("minimal.example.com"), PropagateAtLaunch: fi.PtrTo(true)},
+			{Key: fi.PtrTo("Name"), Value: fi.PtrTo("nodes.minimal.example.com"), PropagateAtLaunch: fi.PtrTo(true)},
+		},
+	}
+	expected := `
+resource "aws_autoscaling_group" "nodes-minimal-example-com" {
+  name = "nodes.minimal.example.com"
+  dynamic "tag" {
+    for_each = merge(var.tags, {
+      "KubernetesCluster" = "minimal.example.com"
+      "Name"              = "nodes.minimal.example.com"
+    })
+    content {
+      key                 = tag.key
+      propagate_at_launch = true
+      value               = tag.value
+    }
+  }
+}`
+
+	buf := &bytes.Buffer{}
+	mergeTags(toElement(item), terraformWriter.LiteralTokens("var", "tags")).
+		Write(buf, 0, `resource "aws_autoscaling_group" "nodes-minimal-example-com"`)
+	actual := strings.TrimSpace(buf.String())
+	expected = strings.TrimSpace(expected)
+	if actual != expected {
+		diffString := diff.FormatDiff(expected, actual)
+		t.Logf("diff:\n%s\n", diffString)
+		t.Errorf("expected: '%s', got: '%s'\n", expected, actual)
+	}
+}
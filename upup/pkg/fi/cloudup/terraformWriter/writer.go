@@ -36,6 +36,10 @@ type TerraformWriter struct {
 	resources []*terraformResource
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
+	// imports is a list of existing resources that should be imported into the TF state
+	imports []*Import
+	// variables is a list of our TF input variables
+	variables map[string]*Variable
 
 	// Providers is a list of TF Providers we need for writing files
 	Providers map[string]*TerraformProvider
@@ -61,6 +65,26 @@ type terraformResource struct {
 	Item         interface{}
 }
 
+// Import is an existing cloud resource to import into the TF state, as an import block
+type Import struct {
+	// ResourceType is the type of the TF resource, e.g. aws_vpc
+	ResourceType string
+	// ResourceName is the name of the TF resource, before it is sanitized
+	ResourceName string
+	// ID identifies the existing resource, in the format expected by the TF provider for the resource type
+	ID string
+}
+
+// Variable is an input variable of the TF module
+type Variable struct {
+	// Type is the TF type constraint of the variable, e.g. string or list(string)
+	Type string
+	// Description documents the variable for users of the module
+	Description string
+	// Default is the value of the variable if it is not set
+	Default *Literal
+}
+
 type terraformOutputVariable struct {
 	Key        string
 	Value      *Literal
@@ -79,6 +103,7 @@ func sanitizeName(name string) string {
 func (t *TerraformWriter) InitTerraformWriter() {
 	t.Files = make(map[string][]byte)
 	t.outputs = make(map[string]*terraformOutputVariable)
+	t.variables = make(map[string]*Variable)
 }
 
 func (t *TerraformWriter) AddFileBytes(resourceType string, resourceName string, key string, data []byte, base64 bool) (*Literal, error) {
@@ -165,6 +190,28 @@ func (t *TerraformWriter) RenderResource(resourceType string, resourceName strin
 	return nil
 }
 
+func (t *TerraformWriter) AddImport(imp *Import) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.imports = append(t.imports, imp)
+}
+
+// AddVariable adds an input variable, returning the literal that refers to it
+func (t *TerraformWriter) AddVariable(key string, v *Variable) (*Literal, error) {
+	tfName := sanitizeName(key)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if existing := t.variables[tfName]; existing != nil && !reflect.DeepEqual(existing, v) {
+		return nil, fmt.Errorf("duplicate variable: %q", tfName)
+	}
+	t.variables[tfName] = v
+
+	return LiteralTokens("var", tfName), nil
+}
+
 func (t *TerraformWriter) AddOutputVariable(key string, literal *Literal) error {
 	v := &terraformOutputVariable{
 		Key:   key,
@@ -263,3 +310,20 @@ func (t *TerraformWriter) GetOutputs() (map[string]OutputValue, error) {
 	}
 	return values, nil
 }
+
+func (t *TerraformWriter) GetVariables() map[string]*Variable {
+	return t.variables
+}
+
+// GetImports returns the imports keyed by TF resource address, e.g. aws_vpc.name
+func (t *TerraformWriter) GetImports() (map[string]string, error) {
+	imports := make(map[string]string)
+	for _, imp := range t.imports {
+		address := imp.ResourceType + "." + sanitizeName(imp.ResourceName)
+		if existing, found := imports[address]; found && existing != imp.ID {
+			return nil, fmt.Errorf("conflicting imports found for %s: %q and %q", address, existing, imp.ID)
+		}
+		imports[address] = imp.ID
+	}
+	return imports, nil
+}
@@ -98,7 +98,7 @@ func NodeUpAsset(assetsBuilder *assets.AssetBuilder, arch architectures.Architec
 	if err != nil {
 		return nil, err
 	}
-	nodeUpAsset[arch] = mirrors.BuildMirroredAsset(u, hash)
+	nodeUpAsset[arch] = assetsBuilder.BuildMirroredAsset(u, hash)
 	klog.V(8).Infof("Using default nodeup location for %s: %q", arch, u.String())
 
 	return nodeUpAsset[arch], nil
@@ -118,7 +118,7 @@ func ProtokubeAsset(assetsBuilder *assets.AssetBuilder, arch architectures.Archi
 	if err != nil {
 		return nil, err
 	}
-	protokubeAsset[arch] = mirrors.BuildMirroredAsset(u, hash)
+	protokubeAsset[arch] = assetsBuilder.BuildMirroredAsset(u, hash)
 	klog.V(8).Infof("Using default protokube location for %s: %q", arch, u.String())
 
 	return protokubeAsset[arch], nil
@@ -138,7 +138,7 @@ func ChannelsAsset(assetsBuilder *assets.AssetBuilder, arch architectures.Archit
 	if err != nil {
 		return nil, err
 	}
-	channelsAsset[arch] = mirrors.BuildMirroredAsset(u, hash)
+	channelsAsset[arch] = assetsBuilder.BuildMirroredAsset(u, hash)
 	klog.V(8).Infof("Using default channels location for %s: %q", arch, u.String())
 
 	return channelsAsset[arch], nil
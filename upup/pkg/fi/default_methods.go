@@ -77,6 +77,16 @@ func defaultDeltaRunMethod[T SubContext](e Task[T], c *Context[T]) error {
 		}
 	}
 
+	if importer, ok := c.Target.(ImportingTarget[T]); ok {
+		if a != nil {
+			if err := importer.ImportExisting(a, e); err != nil {
+				return err
+			}
+		}
+		// The task is rendered in full, as the imported resource is then managed by the target
+		a = nil
+	}
+
 	if a == nil {
 		// This is kind of subtle.  We want an interface pointer to a struct of the correct type...
 		a = reflect.New(reflect.TypeOf(e)).Elem().Interface().(Task[T])
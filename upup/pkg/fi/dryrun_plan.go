@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// PlanSchemaVersion is the version of the schema of Plan.
+// Fields may be added to the schema without changing the version, but not renamed or removed.
+const PlanSchemaVersion = "v1"
+
+// DryRunOutputFormat is the format of the report of a DryRunTarget
+type DryRunOutputFormat string
+
+const (
+	// DryRunOutputText is a report for humans
+	DryRunOutputText DryRunOutputFormat = "text"
+	// DryRunOutputJSON is a Plan serialized as JSON
+	DryRunOutputJSON DryRunOutputFormat = "json"
+	// DryRunOutputYAML is a Plan serialized as YAML
+	DryRunOutputYAML DryRunOutputFormat = "yaml"
+)
+
+// DryRunOutputFormats are the supported formats of the report of a DryRunTarget
+var DryRunOutputFormats = []string{string(DryRunOutputText), string(DryRunOutputJSON), string(DryRunOutputYAML)}
+
+// PlanAction is the action a plan would take for a task
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionDelete PlanAction = "delete"
+)
+
+// Plan is the machine-readable result of a dry-run.
+type Plan struct {
+	// SchemaVersion is the version of the schema of the plan, PlanSchemaVersion.
+	SchemaVersion string `json:"schemaVersion"`
+	// Changes are the tasks that would be created, updated or deleted, sorted by action, type and name.
+	Changes []PlanChange `json:"changes"`
+	// Violations are the policy rules that the changes do not satisfy.
+	Violations []PlanViolation `json:"violations,omitempty"`
+}
+
+// PlanChange is a task that would be created, updated or deleted.
+type PlanChange struct {
+	// Action is create, update or delete.
+	Action PlanAction `json:"action"`
+	// Type is the type of the task, e.g. InstanceTemplate, or the task name of a deletion.
+	Type string `json:"type"`
+	// Name is the name of the task, or the item that would be deleted.
+	Name string `json:"name"`
+	// Lifecycle is the lifecycle of the task, if it has one.
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Fields are the fields of a created task, or the changed fields of an updated task.
+	Fields []PlanField `json:"fields,omitempty"`
+}
+
+// PlanField is a field of a created or updated task.
+type PlanField struct {
+	// Name is the name of the field.
+	Name string `json:"name"`
+	// Old is the current value of the field of an updated task.
+	Old string `json:"old,omitempty"`
+	// New is the value the field would be set to.
+	New string `json:"new,omitempty"`
+	// Diff is a unified diff of the current and new contents, for resources such as files.
+	Diff string `json:"diff,omitempty"`
+}
+
+// PlanViolation is a policy rule that is not satisfied by a task.
+type PlanViolation struct {
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Action is Deny or Warn.
+	Action string `json:"action"`
+	// Task is the type/name of the task.
+	Task string `json:"task"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// SetViolations records the policy violations of the changes, to be reported on Finish()
+func (t *DryRunTarget[T]) SetViolations(violations []PlanViolation) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.violations = violations
+}
+
+// BuildPlan returns the changes recorded by the dry-run as a Plan
+func (t *DryRunTarget[T]) BuildPlan(taskMap map[string]Task[T]) (*Plan, error) {
+	plan := &Plan{
+		SchemaVersion: PlanSchemaVersion,
+		Changes:       []PlanChange{},
+		Violations:    t.violations,
+	}
+
+	changes := append([]*render[T]{}, t.changes...)
+	sort.Sort(ByTaskKey[T](changes))
+
+	var creates, updates []PlanChange
+	for _, r := range changes {
+		c := PlanChange{
+			Type: getTaskName(r.changes),
+			Name: idForTask(taskMap, r.e),
+		}
+		if hasLifecycle, ok := any(r.e).(HasLifecycle); ok {
+			c.Lifecycle = hasLifecycle.GetLifecycle()
+		}
+
+		if r.aIsNil {
+			c.Action = PlanActionCreate
+			for _, field := range buildCreateList(r.changes) {
+				c.Fields = append(c.Fields, PlanField{Name: field.FieldName, New: field.Description})
+			}
+			creates = append(creates, c)
+		} else {
+			c.Action = PlanActionUpdate
+			changeList, err := buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range changeList {
+				if field.Diff {
+					c.Fields = append(c.Fields, PlanField{Name: field.FieldName, Diff: field.Description})
+				} else {
+					c.Fields = append(c.Fields, PlanField{Name: field.FieldName, Old: field.OldValue, New: field.NewValue})
+				}
+			}
+			updates = append(updates, c)
+		}
+	}
+	plan.Changes = append(plan.Changes, creates...)
+	plan.Changes = append(plan.Changes, updates...)
+
+	deletions := append([]Deletion[T]{}, t.deletions...)
+	sort.Sort(DeletionByTaskName[T](deletions))
+	for _, d := range deletions {
+		plan.Changes = append(plan.Changes, PlanChange{
+			Action: PlanActionDelete,
+			Type:   d.TaskName(),
+			Name:   d.Item(),
+		})
+	}
+
+	return plan, nil
+}
+
+// printPlan writes the changes recorded by the dry-run as a Plan in the given format
+func (t *DryRunTarget[T]) printPlan(taskMap map[string]Task[T], format DryRunOutputFormat, out io.Writer) error {
+	plan, err := t.BuildPlan(taskMap)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	switch format {
+	case DryRunOutputJSON:
+		b, err = json.MarshalIndent(plan, "", "  ")
+		b = append(b, '\n')
+	case DryRunOutputYAML:
+		b, err = yaml.Marshal(plan)
+	default:
+		return fmt.Errorf("unsupported dry-run output format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("error serializing plan: %w", err)
+	}
+
+	_, err = out.Write(b)
+	return err
+}
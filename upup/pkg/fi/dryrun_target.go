@@ -37,11 +37,14 @@ import (
 type DryRunTarget[T SubContext] struct {
 	mutex sync.Mutex
 
-	changes   []*render[T]
-	deletions []Deletion[T]
+	changes    []*render[T]
+	deletions  []Deletion[T]
+	violations []PlanViolation
 
 	// The destination to which the final report will be printed on Finish()
 	out io.Writer
+	// format is the format of the final report; text if empty
+	format DryRunOutputFormat
 
 	// assetBuilder records all assets used
 	assetBuilder *assets.AssetBuilder
@@ -92,6 +95,11 @@ func NewNodeupDryRunTarget(assetBuilder *assets.AssetBuilder, out io.Writer) *No
 	return newDryRunTarget[NodeupSubContext](assetBuilder, out)
 }
 
+// SetOutputFormat sets the format of the report printed on Finish()
+func (t *DryRunTarget[T]) SetOutputFormat(format DryRunOutputFormat) {
+	t.format = format
+}
+
 func (t *DryRunTarget[T]) ProcessDeletions() bool {
 	// We display deletions
 	return true
@@ -142,6 +150,10 @@ func idForTask[T SubContext](taskMap map[string]Task[T], t Task[T]) string {
 }
 
 func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer) error {
+	if t.format != "" && t.format != DryRunOutputText {
+		return t.printPlan(taskMap, t.format, out)
+	}
+
 	b := &bytes.Buffer{}
 
 	if len(t.changes) != 0 {
@@ -166,56 +178,8 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = ValueOf(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
-					}
+				for _, field := range buildCreateList(r.changes) {
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", field.FieldName, field.Description)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -267,6 +231,16 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 		}
 	}
 
+	if len(t.violations) != 0 {
+		if len(t.changes)+len(t.deletions) != 0 {
+			fmt.Fprintf(b, "\n")
+		}
+		fmt.Fprintf(b, "Policy violations:\n")
+		for _, v := range t.violations {
+			fmt.Fprintf(b, "  %-5s %s %s: %s\n", v.Action, v.Rule, v.Task, v.Message)
+		}
+	}
+
 	if len(t.assetBuilder.ImageAssets) != 0 {
 		klog.V(4).Infof("ImageAssets:")
 		for _, a := range t.assetBuilder.ImageAssets {
@@ -290,6 +264,70 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 type change struct {
 	FieldName   string
 	Description string
+	// OldValue and NewValue are the values of a changed field, unless Diff is set
+	OldValue string
+	NewValue string
+	// Diff is true if the Description is a diff of the contents of a resource
+	Diff bool
+}
+
+// buildCreateList returns the informative fields of a task that will be created, with their value as the Description
+func buildCreateList[T SubContext](changes Task[T]) []change {
+	var changeList []change
+
+	valC := reflect.ValueOf(changes)
+	if valC.Kind() == reflect.Ptr && !valC.IsNil() {
+		valC = valC.Elem()
+	}
+	if valC.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < valC.NumField(); i++ {
+		field := valC.Field(i)
+
+		fieldName := valC.Type().Field(i).Name
+		if valC.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		fieldValue := reflectutils.ValueAsString(field)
+
+		shouldPrint := true
+		if fieldName == "Name" {
+			// The field name is already printed above, no need to repeat it.
+			shouldPrint = false
+		}
+		if fieldName == "Lifecycle" {
+			// Lifecycle is a "system" field; no need to show it
+			shouldPrint = false
+		}
+		if fieldValue == "<nil>" || fieldValue == "<resource>" {
+			// Uninformative
+			shouldPrint = false
+		}
+		if fieldValue == "id:<nil>" {
+			// Uninformative, but we can often print the name instead
+			name := ""
+			if field.CanInterface() {
+				hasName, ok := field.Interface().(HasName)
+				if ok {
+					name = ValueOf(hasName.GetName())
+				}
+			}
+			if name != "" {
+				fieldValue = "name:" + name
+			} else {
+				shouldPrint = false
+			}
+		}
+		if shouldPrint {
+			changeList = append(changeList, change{FieldName: fieldName, Description: fieldValue})
+		}
+	}
+
+	return changeList
 }
 
 func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
@@ -334,6 +372,9 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 			}
 
 			description := ""
+			oldValue := ""
+			newValue := ""
+			isDiff := false
 			ignored := false
 			if fieldValE.CanInterface() {
 
@@ -345,17 +386,26 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 					resE, okE := tryResourceAsString(fieldValE)
 					if okA && okE {
 						description = diff.FormatDiff(resA, resE)
+						isDiff = true
 					}
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					oldValue = reflectutils.ValueAsString(fieldValA)
+					newValue = reflectutils.ValueAsString(fieldValE)
+					description = fmt.Sprintf(" %v -> %v", oldValue, newValue)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			changeList = append(changeList, change{
+				FieldName:   valC.Type().Field(i).Name,
+				Description: description,
+				OldValue:    oldValue,
+				NewValue:    newValue,
+				Diff:        isDiff,
+			})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
@@ -429,6 +479,22 @@ func (t *DryRunTarget[T]) Changes() (map[string]Task[T], map[string]Task[T]) {
 	return creates, updates
 }
 
+// ChangedTasks returns the tasks of the task map which are going to be created or updated, keyed by their name in the task map
+func (t *DryRunTarget[T]) ChangedTasks(taskMap map[string]Task[T]) map[string]Task[T] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	changed := make(map[string]Task[T])
+	for _, r := range t.changes {
+		for k, v := range taskMap {
+			if v == r.e {
+				changed[k] = v
+			}
+		}
+	}
+	return changed
+}
+
 // HasChanges returns true iff any changes would have been made
 func (t *DryRunTarget[T]) HasChanges() bool {
 	return len(t.changes)+len(t.deletions) != 0
@@ -65,6 +65,10 @@ func (*testTask) Run(_ *CloudupContext) error {
 	panic("not implemented")
 }
 
+func (t *testTask) GetName() *string {
+	return t.Name
+}
+
 func Test_DryrunTarget_PrintReport(t *testing.T) {
 	builder := assets.NewAssetBuilder(nil, "1.17.3", false)
 	var stdout bytes.Buffer
@@ -90,3 +94,84 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+func Test_DryrunTarget_ChangedTasks(t *testing.T) {
+	builder := assets.NewAssetBuilder(nil, "1.17.3", false)
+	var stdout bytes.Buffer
+	target := newDryRunTarget[CloudupSubContext](builder, &stdout)
+	changed := &testTask{
+		Name:      PtrTo("changed"),
+		Lifecycle: LifecycleSync,
+	}
+	unchanged := &testTask{
+		Name:      PtrTo("unchanged"),
+		Lifecycle: LifecycleSync,
+	}
+	tasks := map[string]CloudupTask{
+		"testTask/changed":   changed,
+		"testTask/unchanged": unchanged,
+	}
+
+	var a *testTask
+	err := target.Render(a, changed, changed)
+	assert.NoError(t, err, "target.Render()")
+
+	actual := target.ChangedTasks(tasks)
+	assert.Equal(t, map[string]CloudupTask{"testTask/changed": changed}, actual)
+}
+
+func Test_DryrunTarget_BuildPlan(t *testing.T) {
+	builder := assets.NewAssetBuilder(nil, "1.17.3", false)
+	var stdout bytes.Buffer
+	target := newDryRunTarget[CloudupSubContext](builder, &stdout)
+	created := &testTask{
+		Name:      PtrTo("created"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "value"},
+	}
+	a := &testTask{
+		Name:      PtrTo("updated"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "old"},
+	}
+	updated := &testTask{
+		Name:      PtrTo("updated"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "new"},
+	}
+	tasks := map[string]CloudupTask{
+		"testTask/created": created,
+		"testTask/updated": updated,
+	}
+
+	var nilTask *testTask
+	assert.NoError(t, target.Render(nilTask, created, created), "target.Render()")
+	changes := &testTask{}
+	_ = BuildChanges(a, updated, changes)
+	assert.NoError(t, target.Render(a, updated, changes), "target.Render()")
+
+	plan, err := target.BuildPlan(tasks)
+	assert.NoError(t, err, "target.BuildPlan()")
+	assert.Equal(t, &Plan{
+		SchemaVersion: PlanSchemaVersion,
+		Changes: []PlanChange{
+			{
+				Action: PlanActionCreate,
+				Type:   "testTask",
+				Name:   "created",
+				Fields: []PlanField{{Name: "Tags", New: "{key: value}"}},
+			},
+			{
+				Action: PlanActionUpdate,
+				Type:   "testTask",
+				Name:   "updated",
+				Fields: []PlanField{{Name: "Tags", Old: "{key: old}", New: "{key: new}"}},
+			},
+		},
+	}, plan)
+
+	target.SetOutputFormat(DryRunOutputJSON)
+	var out bytes.Buffer
+	assert.NoError(t, target.PrintReport(tasks, &out), "target.PrintReport()")
+	assert.Contains(t, out.String(), `"schemaVersion": "v1"`)
+}
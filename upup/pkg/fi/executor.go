@@ -17,11 +17,15 @@ limitations under the License.
 package fi
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
 	"k8s.io/klog/v2"
 )
 
@@ -43,11 +47,27 @@ type taskState[T SubContext] struct {
 type RunTasksOptions struct {
 	MaxTaskDuration         time.Duration
 	WaitAfterAllTasksFailed time.Duration
+
+	// MaxConcurrency is the maximum total weight of the tasks that run at the same time; unlimited if zero
+	MaxConcurrency int
+	// TaskWeights is the weight of tasks by type name, e.g. AutoscalingGroup; the default weight is 1
+	TaskWeights map[string]int
+	// RateLimiter limits the rate at which tasks start, taking a token per unit of weight; unlimited if nil
+	RateLimiter *rate.Limiter
+	// ProgressInterval is how often progress is logged while tasks run; never if zero
+	ProgressInterval time.Duration
 }
 
 func (o *RunTasksOptions) InitDefaults() {
 	o.MaxTaskDuration = 10 * time.Minute
 	o.WaitAfterAllTasksFailed = 10 * time.Second
+	o.ProgressInterval = 30 * time.Second
+}
+
+// RateLimitedCloud is implemented by clouds that limit the rate at which tasks call their APIs
+type RateLimitedCloud interface {
+	// TaskRateLimiter returns the rate limiter shared by all the tasks that use the cloud
+	TaskRateLimiter() *rate.Limiter
 }
 
 // RunTasks executes all the tasks, considering their dependencies
@@ -118,7 +138,7 @@ func (e *executor[T]) RunTasks(taskMap map[string]Task[T]) error {
 		var tasks []*taskState[T]
 		tasks = append(tasks, canRun...)
 
-		taskErrors := e.forkJoin(tasks)
+		taskErrors := e.forkJoin(tasks, doneCount, len(taskStates))
 		var errors []error
 		for i, err := range taskErrors {
 			ts := tasks[i]
@@ -171,14 +191,40 @@ func (e *executor[T]) RunTasks(taskMap map[string]Task[T]) error {
 	return nil
 }
 
-func (e *executor[T]) forkJoin(tasks []*taskState[T]) []error {
+// taskWeight returns the weight of the task, capped by the limit
+func (e *executor[T]) taskWeight(ts *taskState[T], limit int) int {
+	weight := 1
+	if w, found := e.options.TaskWeights[TypeNameForTask(ts.task)]; found && w > 0 {
+		weight = w
+	}
+	if limit > 0 && weight > limit {
+		weight = limit
+	}
+	return weight
+}
+
+// forkJoin runs the tasks, limiting their concurrency and rate, and returns their errors.
+// The counts of the done and total tasks are used to report progress.
+func (e *executor[T]) forkJoin(tasks []*taskState[T], doneCount int, totalCount int) []error {
 	if len(tasks) == 0 {
 		return nil
 	}
 
+	ctx := e.context.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var sem *semaphore.Weighted
+	if e.options.MaxConcurrency > 0 {
+		sem = semaphore.NewWeighted(int64(e.options.MaxConcurrency))
+	}
+
 	results := make([]error, len(tasks))
 	var resultsMutex sync.Mutex
 
+	var started, running, succeeded atomic.Int32
+
 	var wg sync.WaitGroup
 	for i := 0; i < len(tasks); i++ {
 		wg.Add(1)
@@ -189,16 +235,44 @@ func (e *executor[T]) forkJoin(tasks []*taskState[T]) []error {
 			results[index] = fmt.Errorf("function panic")
 			resultsMutex.Unlock()
 
+			if sem != nil {
+				weight := int64(e.taskWeight(ts, e.options.MaxConcurrency))
+				if err := sem.Acquire(ctx, weight); err != nil {
+					resultsMutex.Lock()
+					results[index] = err
+					resultsMutex.Unlock()
+					return
+				}
+				defer sem.Release(weight)
+			}
+			if limiter := e.options.RateLimiter; limiter != nil {
+				if err := limiter.WaitN(ctx, e.taskWeight(ts, limiter.Burst())); err != nil {
+					resultsMutex.Lock()
+					results[index] = err
+					resultsMutex.Unlock()
+					return
+				}
+			}
+
+			started.Add(1)
+			running.Add(1)
+			defer running.Add(-1)
+
 			klog.V(2).Infof("Executing task %q: %v\n", ts.key, ts.task)
 
 			if taskNormalize, ok := ts.task.(TaskNormalize[T]); ok {
 				if err := taskNormalize.Normalize(e.context); err != nil {
+					resultsMutex.Lock()
 					results[index] = err
+					resultsMutex.Unlock()
 					return
 				}
 			}
 
 			result := ts.task.Run(e.context)
+			if result == nil {
+				succeeded.Add(1)
+			}
 
 			resultsMutex.Lock()
 			results[index] = result
@@ -206,6 +280,24 @@ func (e *executor[T]) forkJoin(tasks []*taskState[T]) []error {
 		}(tasks[i], i)
 	}
 
+	if e.options.ProgressInterval > 0 {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			ticker := time.NewTicker(e.options.ProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-finished:
+					return
+				case <-ticker.C:
+					waiting := len(tasks) - int(started.Load())
+					klog.Infof("Tasks: %d done / %d total; %d running, %d waiting to start", doneCount+int(succeeded.Load()), totalCount, running.Load(), waiting)
+				}
+			}
+		}()
+	}
+
 	wg.Wait()
 
 	return results
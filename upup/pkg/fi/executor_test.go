@@ -0,0 +1,115 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type concurrencyTracker struct {
+	mutex   sync.Mutex
+	running int
+	max     int
+}
+
+type concurrencyTask struct {
+	Name    *string
+	tracker *concurrencyTracker
+}
+
+var _ InstallTask = &concurrencyTask{}
+
+func (t *concurrencyTask) Run(_ *InstallContext) error {
+	t.tracker.mutex.Lock()
+	t.tracker.running++
+	if t.tracker.running > t.tracker.max {
+		t.tracker.max = t.tracker.running
+	}
+	t.tracker.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	t.tracker.mutex.Lock()
+	t.tracker.running--
+	t.tracker.mutex.Unlock()
+	return nil
+}
+
+func TestRunTasks_MaxConcurrency(t *testing.T) {
+	grid := []struct {
+		name           string
+		maxConcurrency int
+		taskWeights    map[string]int
+		expectedMax    int
+	}{
+		{
+			name:           "unlimited",
+			maxConcurrency: 0,
+		},
+		{
+			name:           "limited",
+			maxConcurrency: 4,
+			expectedMax:    4,
+		},
+		{
+			name:           "weighted",
+			maxConcurrency: 4,
+			taskWeights:    map[string]int{"concurrencyTask": 2},
+			expectedMax:    2,
+		},
+		{
+			name:           "weight above limit",
+			maxConcurrency: 4,
+			taskWeights:    map[string]int{"concurrencyTask": 10},
+			expectedMax:    1,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			tracker := &concurrencyTracker{}
+			tasks := make(map[string]InstallTask)
+			for i := 0; i < 20; i++ {
+				name := fmt.Sprintf("task-%d", i)
+				tasks[name] = &concurrencyTask{Name: PtrTo(name), tracker: tracker}
+			}
+
+			c, err := NewInstallContext(context.Background(), nil, tasks)
+			if err != nil {
+				t.Fatalf("error building context: %v", err)
+			}
+			var options RunTasksOptions
+			options.InitDefaults()
+			options.MaxConcurrency = g.maxConcurrency
+			options.TaskWeights = g.taskWeights
+			if err := c.RunTasks(options); err != nil {
+				t.Fatalf("error running tasks: %v", err)
+			}
+
+			if g.maxConcurrency == 0 {
+				if tracker.max < 2 {
+					t.Errorf("expected tasks to run concurrently, ran %d at most", tracker.max)
+				}
+			} else if tracker.max > g.expectedMax {
+				t.Errorf("expected at most %d concurrent tasks, ran %d", g.expectedMax, tracker.max)
+			}
+		})
+	}
+}
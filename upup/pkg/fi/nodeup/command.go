@@ -40,7 +40,6 @@ import (
 	"go.uber.org/multierr"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/nodeup/pkg/model"
-	"k8s.io/kops/nodeup/pkg/model/networking"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/nodeup"
@@ -50,6 +49,7 @@ import (
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/kopscontrollerclient"
 	"k8s.io/kops/pkg/resolver"
+	"k8s.io/kops/pkg/sbom"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce/gcediscovery"
@@ -73,8 +73,25 @@ type NodeUpCommand struct {
 	CacheDir       string
 	ConfigLocation string
 	Target         string
+	// DryRunOutput is the format of the report of the dryrun target; text if empty
+	DryRunOutput fi.DryRunOutputFormat
+	// TaskGraph is the format in which to print the task graph, instead of running the tasks, when set
+	TaskGraph fi.TaskGraphFormat
+	// Explain is the key of a task to explain, instead of running the tasks, when set
+	Explain string
+	// SBOM is the format in which to print a software bill of materials of the node, instead of running the tasks, when set
+	SBOM sbom.Format
 	// Deprecated: Fields should be accessed from NodeupConfig or BootConfig.
 	cluster *api.Cluster
+
+	// reconcile is set when checking an already configured node for drift, rather than configuring it
+	reconcile bool
+	// taskFilter restricts the tasks that are run to the ones it accepts, when set
+	taskFilter func(name string, task fi.NodeupTask) bool
+	// changedTasks are the tasks that would change the node, after running against the dryrun target
+	changedTasks map[string]fi.NodeupTask
+	// nodeName is the name of the kubernetes node
+	nodeName string
 }
 
 // Run is responsible for perform the nodeup process
@@ -190,6 +207,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
+	// The node name is determined before the rest of the model, so that failures can be reported on the node
+	c.nodeName, err = (&model.NodeupModelContext{NodeupConfig: &nodeupConfig}).NodeName()
+	if err != nil {
+		return err
+	}
+
 	architecture, err := architectures.FindArchitecture()
 	if err != nil {
 		return fmt.Errorf("error determining OS architecture: %v", err)
@@ -310,43 +333,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
-	loader := &Loader{}
-	loader.Builders = append(loader.Builders, &model.EtcHostsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.NTPBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.MiscUtilsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.DirectoryBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.NvidiaBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.WarmPoolBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &model.PrefixBuilder{NodeupModelContext: modelContext})
-
-	loader.Builders = append(loader.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.CiliumBuilder{NodeupModelContext: modelContext})
-	loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
-
-	loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
+	loader := NewLoader(modelContext)
 	taskMap, err := loader.Build()
 	if err != nil {
 		return fmt.Errorf("error building loader: %v", err)
@@ -357,10 +344,50 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			Sources: image.Sources,
 			Hash:    image.Hash,
 			Runtime: nodeupConfig.ContainerRuntime,
+
+			CanonicalSource: image.CanonicalSource,
+			Signature:       image.Signature,
+			PublicKeys:      nodeupConfig.ImageVerificationPublicKeys,
+			VerifyTarballs:  nodeupConfig.ImageVerificationTarballs,
 		}
 	}
 	// Protokube load image task is in ProtokubeBuilder
 
+	if c.taskFilter != nil {
+		for name, task := range taskMap {
+			if !c.taskFilter(name, task) {
+				delete(taskMap, name)
+			}
+		}
+	}
+
+	if c.SBOM != "" {
+		doc, err := buildSBOM(nodeupConfig.ClusterName, taskMap, &distribution, configAssets, nodeupConfig.Images[architecture])
+		if err != nil {
+			return err
+		}
+		return doc.Write(out, c.SBOM)
+	}
+
+	if c.TaskGraph != "" || c.Explain != "" {
+		graph := fi.BuildTaskGraph(taskMap, loader.TaskBuilders())
+		if c.Explain == "" {
+			return graph.Write(out, c.TaskGraph)
+		}
+		task := taskMap[c.Explain]
+		if task == nil {
+			return fmt.Errorf("task %q not found", c.Explain)
+		}
+		explanation, err := graph.ExplainTask(task, c.Explain, []fi.SpecSource{
+			{Name: "NodeupConfig", Object: &nodeupConfig},
+			{Name: "BootConfig", Object: &bootConfig},
+		})
+		if err != nil {
+			return err
+		}
+		return explanation.Write(out, c.TaskGraph)
+	}
+
 	var target fi.NodeupTarget
 
 	switch c.Target {
@@ -371,30 +398,49 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		}
 	case "dryrun":
 		assetBuilder := assets.NewAssetBuilder(c.cluster.Spec.Assets, c.cluster.Spec.KubernetesVersion, false)
-		target = fi.NewNodeupDryRunTarget(assetBuilder, out)
+		dryRunTarget := fi.NewNodeupDryRunTarget(assetBuilder, out)
+		dryRunTarget.SetOutputFormat(c.DryRunOutput)
+		target = dryRunTarget
 	default:
 		return fmt.Errorf("unsupported target type %q", c.Target)
 	}
 
 	context, err := fi.NewNodeupContext(ctx, target, keyStore, &bootConfig, &nodeupConfig, taskMap)
 	if err != nil {
+		if c.reconcile {
+			return fmt.Errorf("error building context: %v", err)
+		}
 		klog.Exitf("error building context: %v", err)
 	}
 
 	var options fi.RunTasksOptions
 	options.InitDefaults()
+	if c.reconcile {
+		// Don't keep retrying until the next reconciliation
+		options.MaxTaskDuration = time.Minute
+	}
 
 	err = context.RunTasks(options)
 	if err != nil {
+		if c.reconcile {
+			return fmt.Errorf("error running tasks: %v", err)
+		}
 		klog.Exitf("error running tasks: %v", err)
 	}
 
+	if dryRunTarget, ok := target.(*fi.NodeupDryRunTarget); ok {
+		c.changedTasks = dryRunTarget.ChangedTasks(taskMap)
+	}
+
 	err = target.Finish(taskMap)
 	if err != nil {
+		if c.reconcile {
+			return fmt.Errorf("error closing target: %v", err)
+		}
 		klog.Exitf("error closing target: %v", err)
 	}
 
-	if nodeupConfig.EnableLifecycleHook {
+	if nodeupConfig.EnableLifecycleHook && !c.reconcile {
 		if bootConfig.CloudProvider == api.CloudProviderAWS {
 			err := completeWarmingLifecycleAction(cloud.(awsup.AWSCloud), modelContext)
 			if err != nil {
@@ -21,17 +21,73 @@ import (
 	"reflect"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/nodeup/pkg/model"
+	"k8s.io/kops/nodeup/pkg/model/networking"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
 type Loader struct {
 	Builders []fi.NodeupModelBuilder
+
+	// builders maps the key of each task to the model builder that created it
+	builders map[string]string
+}
+
+// NewLoader returns a loader with the model builders of nodeup
+func NewLoader(modelContext *model.NodeupModelContext) *Loader {
+	l := &Loader{}
+	l.Builders = append(l.Builders, &model.EtcHostsBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.NTPBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.MiscUtilsBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.DirectoryBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.SwapBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.NvidiaBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.HardeningBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.KopsControllerBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.PrePullImagesBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.WarmPoolBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &model.PrefixBuilder{NodeupModelContext: modelContext})
+
+	l.Builders = append(l.Builders, &networking.CommonBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &networking.CalicoBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &networking.CiliumBuilder{NodeupModelContext: modelContext})
+	l.Builders = append(l.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
+
+	l.Builders = append(l.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
+	return l
+}
+
+// TaskBuilders returns the model builder that created each task, by task key
+func (l *Loader) TaskBuilders() map[string]string {
+	return l.builders
 }
 
 // Build is responsible for running the build tasks for nodeup
 func (l *Loader) Build() (map[string]fi.NodeupTask, error) {
 	tasks := make(map[string]fi.NodeupTask)
+	l.builders = make(map[string]string)
 	for _, builder := range l.Builders {
 		context := &fi.NodeupModelBuilderContext{
 			Tasks: tasks,
@@ -41,6 +97,11 @@ func (l *Loader) Build() (map[string]fi.NodeupTask, error) {
 			return nil, fmt.Errorf("building %s: %v", reflect.TypeOf(builder), err)
 		}
 		tasks = context.Tasks
+		for key := range tasks {
+			if _, found := l.builders[key]; !found {
+				l.builders[key] = fi.ModelBuilderName(builder)
+			}
+		}
 	}
 
 	// If there is a package task, we need an update packages task
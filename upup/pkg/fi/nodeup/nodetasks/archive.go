@@ -41,6 +41,8 @@ type Archive struct {
 	Source string `json:"source,omitempty"`
 	// Hash is the source tar
 	Hash string `json:"hash,omitempty"`
+	// Contents is the already downloaded archive, used instead of downloading Source when set
+	Contents fi.Resource `json:"-"`
 
 	// TargetDir is the directory for extraction
 	TargetDir string `json:"target,omitempty"`
@@ -150,7 +152,11 @@ func (_ *Archive) RenderLocal(t *local.LocalTarget, a, e, changes *Archive) erro
 			}
 			hash = parsed
 		}
-		if _, err := fi.DownloadURL(e.Source, localFile, hash); err != nil {
+		if e.Contents != nil {
+			if err := fi.WriteFile(localFile, e.Contents, 0o644, 0o755, "", ""); err != nil {
+				return fmt.Errorf("error writing archive %q: %v", e.Name, err)
+			}
+		} else if _, err := fi.DownloadURL(e.Source, localFile, hash); err != nil {
 			return err
 		}
 
@@ -25,6 +25,8 @@ import (
 	"strings"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/backoff"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/local"
@@ -38,6 +40,15 @@ type LoadImageTask struct {
 	Sources []string
 	Hash    string
 	Runtime string
+
+	// CanonicalSource is the original URL of the image, which is matched against VerifyTarballs
+	CanonicalSource string
+	// Signature is the signature that cosign sign-blob wrote for the image, as verified by kops
+	Signature string
+	// PublicKeys are the public keys that the signature of the image must be made with, when set
+	PublicKeys []string
+	// VerifyTarballs are the prefixes of the canonical sources of the images that are verified; no image is verified if empty
+	VerifyTarballs []string
 }
 
 var (
@@ -108,12 +119,14 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 	key := path.Base(primaryURL)
 	localFile := filepath.Join(t.CacheDir, hash.String()+"_"+utils.SanitizeString(key))
 
+	var sourceURL string
 	for _, url := range urls {
 		_, err = fi.DownloadURL(url, localFile, hash)
 		if err != nil {
 			klog.Warningf("error downloading url %q: %v", url, err)
 			continue
 		} else {
+			sourceURL = url
 			break
 		}
 	}
@@ -123,6 +136,12 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 		return err
 	}
 
+	if len(e.PublicKeys) != 0 {
+		if err := e.verifySignature(sourceURL, localFile); err != nil {
+			return err
+		}
+	}
+
 	// containerd can't import gzipped container images, if the image is gzipped extract it to tmp dir
 	// TODO: Improve the naive gzip format detection by checking the content type bytes "\x1F\x8B\x08"
 	var tarFile string
@@ -167,3 +186,33 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 
 	return nil
 }
+
+// verifySignature verifies the signature of a downloaded image before it is imported, if the canonical source of the image is verified
+func (e *LoadImageTask) verifySignature(sourceURL string, localFile string) error {
+	verifier, err := assets.NewImageVerifier(&kops.ImageVerificationSpec{PublicKeys: e.PublicKeys, Tarballs: e.VerifyTarballs})
+	if err != nil {
+		return err
+	}
+
+	canonicalSource := e.CanonicalSource
+	if canonicalSource == "" {
+		canonicalSource = e.Sources[0]
+	}
+	if !verifier.MatchesTarball(canonicalSource) {
+		return nil
+	}
+	if e.Signature == "" {
+		return fmt.Errorf("image %s has no signature to verify", canonicalSource)
+	}
+
+	data, err := os.ReadFile(localFile)
+	if err != nil {
+		return fmt.Errorf("error reading image %s: %v", localFile, err)
+	}
+
+	if err := verifier.VerifyBlob(data, []byte(e.Signature)); err != nil {
+		return fmt.Errorf("error verifying signature of image %s: %v", sourceURL, err)
+	}
+	klog.Infof("verified signature of image %s", sourceURL)
+	return nil
+}
@@ -17,6 +17,15 @@ limitations under the License.
 package nodetasks
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -38,3 +47,78 @@ func TestLoadImageTask_Deps(t *testing.T) {
 		t.Fatalf("unexpected deps.  expected=%v, actual=%v", expected, deps)
 	}
 }
+
+func TestLoadImageTask_VerifySignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	data := []byte("image tarball")
+	localFile := filepath.Join(t.TempDir(), "image.tar")
+	if err := os.WriteFile(localFile, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := base64.StdEncoding.EncodeToString(sig)
+
+	grid := []struct {
+		name           string
+		signature      string
+		verifyTarballs []string
+		valid          bool
+	}{
+		{
+			name:           "signed",
+			signature:      signature,
+			verifyTarballs: []string{"https://dl.k8s.io/"},
+			valid:          true,
+		},
+		{
+			name:           "unsigned",
+			verifyTarballs: []string{"https://dl.k8s.io/"},
+		},
+		{
+			name:           "invalid signature",
+			signature:      base64.StdEncoding.EncodeToString([]byte("not a signature")),
+			verifyTarballs: []string{"https://dl.k8s.io/"},
+		},
+		{
+			name:           "unsigned and not verified",
+			verifyTarballs: []string{"https://artifacts.example.com/"},
+			valid:          true,
+		},
+		{
+			name:  "unsigned and no tarballs to verify",
+			valid: true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			task := &LoadImageTask{
+				// The canonical source is matched, not the mirror that the image was downloaded from
+				Sources:         []string{"https://artifacts.example.com/kube-apiserver.tar"},
+				CanonicalSource: "https://dl.k8s.io/release/v1.26.0/bin/linux/amd64/kube-apiserver.tar",
+				Signature:       g.signature,
+				PublicKeys:      []string{publicKey},
+				VerifyTarballs:  g.verifyTarballs,
+			}
+			err := task.verifySignature(task.Sources[0], localFile)
+			if g.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !g.valid && err == nil {
+				t.Errorf("expected verification to fail")
+			}
+		})
+	}
+}
@@ -256,6 +256,40 @@ func (e *Package) findYum(c *fi.NodeupContext) (*Package, error) {
 	}, nil
 }
 
+// InstalledVersion returns the version of the package installed on the distribution, or "" if it is not installed
+func (e *Package) InstalledVersion(d *distributions.Distribution) (string, error) {
+	var args []string
+	switch {
+	case d.IsDebianFamily():
+		args = []string{"dpkg-query", "-f", "${db:Status-Abbrev}${Version}\\n", "-W", e.Name}
+	case d.IsRHELFamily():
+		args = []string{"/usr/bin/rpm", "-q", e.Name, "--queryformat", "%{VERSION}-%{RELEASE}"}
+	default:
+		return "", fmt.Errorf("unsupported package system")
+	}
+
+	klog.V(2).Infof("Querying installed package: %s", strings.Join(args, " "))
+	cmd := exec.Command(args[0], args[1:]...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "no packages found") || strings.Contains(string(output), "is not installed") {
+			return "", nil
+		}
+		return "", fmt.Errorf("error querying installed package %q: %v: %s", e.Name, err, string(output))
+	}
+
+	if d.IsRHELFamily() {
+		return strings.TrimSpace(string(output)), nil
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		tokens := strings.Split(line, " ")
+		if len(tokens) == 2 && strings.HasPrefix(tokens[0], "i") {
+			return tokens[1], nil
+		}
+	}
+	return "", nil
+}
+
 func (e *Package) Run(c *fi.NodeupContext) error {
 	return fi.NodeupDefaultDeltaRunMethod(e, c)
 }
@@ -326,7 +360,7 @@ func (_ *Package) RenderLocal(t *local.LocalTarget, a, e, changes *Package) erro
 			args = []string{"apt-get", "install", "--yes", "--no-install-recommends"}
 			env = append(env, "DEBIAN_FRONTEND=noninteractive")
 		} else if d.IsRHELFamily() {
-			if d == distributions.DistributionRhel8 || d == distributions.DistributionRocky8 {
+			if d.HasDNF() {
 				args = []string{"/usr/bin/dnf", "install", "-y", "--setopt=install_weak_deps=False"}
 			} else {
 				args = []string{"/usr/bin/yum", "install", "-y"}
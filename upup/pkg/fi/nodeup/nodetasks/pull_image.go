@@ -17,12 +17,18 @@ limitations under the License.
 package nodetasks
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+	"k8s.io/kops/util/pkg/hashing"
 )
 
 // PullImageTask is responsible for pulling a docker image
@@ -65,12 +71,23 @@ func (e *PullImageTask) Run(c *fi.NodeupContext) error {
 		return fmt.Errorf("no runtime specified")
 	}
 
-	// Pull the container image
+	// Import the image if it was baked into the node image, or pull it.
+	// The prebaked images are OCI archives, which only containerd imports under the original reference.
 	var args []string
-	switch runtime {
-	case "docker":
+	var prebaked string
+	if runtime == "containerd" {
+		var err error
+		prebaked, err = findPrebakedImage(c, e.Name)
+		if err != nil {
+			klog.Warningf("ignoring prebaked image %q: %v", e.Name, err)
+		}
+	}
+	switch {
+	case prebaked != "":
+		args = []string{"ctr", "--namespace", "k8s.io", "images", "import", prebaked}
+	case runtime == "docker":
 		args = []string{"docker", "pull", e.Name}
-	case "containerd":
+	case runtime == "containerd":
 		args = []string{"ctr", "--namespace", "k8s.io", "images", "pull", e.Name}
 	default:
 		return fmt.Errorf("unknown container runtime: %s", runtime)
@@ -86,3 +103,45 @@ func (e *PullImageTask) Run(c *fi.NodeupContext) error {
 
 	return nil
 }
+
+// findPrebakedImage returns the path of the image archive recorded in the prebake manifest,
+// or an empty string if the image was not baked into the node image.
+func findPrebakedImage(c *fi.NodeupContext, image string) (string, error) {
+	target, ok := c.Target.(*local.LocalTarget)
+	if !ok || target.CacheDir == "" {
+		return "", nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(target.CacheDir, nodeup.PrebakeManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error reading prebake manifest: %w", err)
+	}
+	manifest := &nodeup.PrebakeManifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return "", fmt.Errorf("error parsing prebake manifest: %w", err)
+	}
+
+	for _, prebaked := range manifest.Images {
+		if prebaked.Name != image {
+			continue
+		}
+		file := filepath.Join(target.CacheDir, prebaked.File)
+		hash, err := hashing.FromString(prebaked.Hash)
+		if err != nil {
+			return "", err
+		}
+		actual, err := hash.Algorithm.HashFile(file)
+		if err != nil {
+			return "", err
+		}
+		if !actual.Equal(hash) {
+			return "", fmt.Errorf("hash of %q was %s, expected %s", file, actual, hash)
+		}
+		return file, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func TestFindPrebakedImage(t *testing.T) {
+	const image = "registry.k8s.io/pause:3.9"
+
+	cacheDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(cacheDir, "images"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(cacheDir, "images", "pause.tar")
+	if err := os.WriteFile(file, []byte("archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hashing.HashAlgorithmSHA256.HashFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherHash, err := hashing.HashAlgorithmSHA256.Hash(strings.NewReader("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid := []struct {
+		name     string
+		manifest *nodeup.PrebakeManifest
+		target   fi.NodeupTarget
+		image    string
+		expected string
+		err      bool
+	}{
+		{
+			name: "prebaked",
+			manifest: &nodeup.PrebakeManifest{
+				Images: []nodeup.PrebakedImage{{Name: image, File: "images/pause.tar", Hash: hash.String()}},
+			},
+			image:    image,
+			expected: file,
+		},
+		{
+			name: "other image",
+			manifest: &nodeup.PrebakeManifest{
+				Images: []nodeup.PrebakedImage{{Name: image, File: "images/pause.tar", Hash: hash.String()}},
+			},
+			image: "registry.k8s.io/pause:3.10",
+		},
+		{
+			name:  "no manifest",
+			image: image,
+		},
+		{
+			name: "hash mismatch",
+			manifest: &nodeup.PrebakeManifest{
+				Images: []nodeup.PrebakedImage{{Name: image, File: "images/pause.tar", Hash: otherHash.String()}},
+			},
+			image: image,
+			err:   true,
+		},
+		{
+			name: "missing archive",
+			manifest: &nodeup.PrebakeManifest{
+				Images: []nodeup.PrebakedImage{{Name: image, File: "images/missing.tar", Hash: hash.String()}},
+			},
+			image: image,
+			err:   true,
+		},
+		{
+			name: "no cache dir",
+			manifest: &nodeup.PrebakeManifest{
+				Images: []nodeup.PrebakedImage{{Name: image, File: "images/pause.tar", Hash: hash.String()}},
+			},
+			target: &local.LocalTarget{},
+			image:  image,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			manifestPath := filepath.Join(cacheDir, nodeup.PrebakeManifestFile)
+			if err := os.RemoveAll(manifestPath); err != nil {
+				t.Fatal(err)
+			}
+			if g.manifest != nil {
+				b, err := json.Marshal(g.manifest)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(manifestPath, b, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			target := g.target
+			if target == nil {
+				target = &local.LocalTarget{CacheDir: cacheDir}
+			}
+			c, err := fi.NewNodeupContext(context.Background(), target, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := findPrebakedImage(c, g.image)
+			if g.err {
+				if err == nil {
+					t.Errorf("expected error, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != g.expected {
+				t.Errorf("expected %q, got %q", g.expected, actual)
+			}
+		})
+	}
+}
@@ -84,15 +84,16 @@ func (s *Service) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTas
 	var deps []fi.NodeupTask
 	for _, v := range tasks {
 		// We assume that services depend on everything except for
-		// LoadImageTask or IssueCert. If there are any LoadImageTasks (e.g. we're
-		// launching a custom Kubernetes build), they all depend on
-		// the "docker.service" Service task.
+		// LoadImageTask, PullImageTask or IssueCert. If there are any LoadImageTasks
+		// (e.g. we're launching a custom Kubernetes build) or PullImageTasks
+		// (e.g. pre-pulled images), they all depend on the container runtime
+		// Service task, and the kubelet depends on them.
 		switch v := v.(type) {
 		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Chattr, *BindMount, *Archive, *Prefix, *UpdateEtcHostsTask:
 			deps = append(deps, v)
-		case *Service, *PullImageTask, *IssueCert, *BootstrapClientTask, *KubeConfig:
+		case *Service, *IssueCert, *BootstrapClientTask, *KubeConfig:
 			// ignore
-		case *LoadImageTask:
+		case *LoadImageTask, *PullImageTask:
 			if s.Name == kubeletService {
 				deps = append(deps, v)
 			}
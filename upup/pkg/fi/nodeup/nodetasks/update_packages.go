@@ -77,6 +77,9 @@ func (_ *UpdatePackages) RenderLocal(t *local.LocalTarget, a, e, changes *Update
 	var args []string
 	if d.IsDebianFamily() {
 		args = []string{"apt-get", "update"}
+	} else if d.HasDNF() {
+		// Probably not technically needed
+		args = []string{"/usr/bin/dnf", "check-update"}
 	} else if d.IsRHELFamily() {
 		// Probably not technically needed
 		args = []string{"/usr/bin/yum", "check-update"}
@@ -86,7 +89,7 @@ func (_ *UpdatePackages) RenderLocal(t *local.LocalTarget, a, e, changes *Update
 	klog.Infof("running command %s", args)
 	cmd := exec.Command(args[0], args[1:]...)
 	output, err := cmd.CombinedOutput()
-	// 'yum check-update' exits with 100 if it finds updates; treat it like a success
+	// 'yum check-update' and 'dnf check-update' exit with 100 if it finds updates; treat it like a success
 	if exitCode := cmd.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(); err != nil && exitCode != 100 {
 		return fmt.Errorf("error update packages: %v: %s", err, string(output))
 	}
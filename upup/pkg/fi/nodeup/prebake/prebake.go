@@ -0,0 +1,337 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prebake installs the assets and images of a nodeup configuration into a node image before boot.
+package prebake
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+const (
+	// DefaultCacheDir is the nodeup asset cache on the nodes
+	DefaultCacheDir = "/var/cache/nodeup"
+
+	// prebakeImagesDir is the directory of the image archives, in the nodeup cache
+	prebakeImagesDir = "images"
+
+	// containerdImageNameAnnotation is the annotation of the OCI index that containerd imports the image as
+	containerdImageNameAnnotation = "io.containerd.image.name"
+)
+
+// Prebake installs the assets and container images of a nodeup configuration into a root filesystem,
+// such as a chroot or a mounted disk image, so nodeup does not download them when the instance boots.
+type Prebake struct {
+	// Config is the nodeup configuration of the instance group
+	Config *nodeup.Config
+	// InstanceGroup is the name of the instance group
+	InstanceGroup string
+	// Architecture is the architecture of the instances
+	Architecture architectures.Architecture
+	// Root is the root of the filesystem to install into
+	Root string
+	// CacheDir is the nodeup cache directory on the instances; defaults to DefaultCacheDir
+	CacheDir string
+	// NodeupBinary is the nodeup asset, in the hash@url format, installed where the bootstrap script downloads it
+	NodeupBinary string
+}
+
+// NewPrebake builds a Prebake from a nodeup config file; an empty arch defaults to the architecture of nodeup
+func NewPrebake(configFile string, arch string) (*Prebake, error) {
+	b, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading nodeup config %q: %w", configFile, err)
+	}
+	config := &nodeup.Config{}
+	if err := utils.YamlUnmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("error parsing nodeup config %q: %w", configFile, err)
+	}
+
+	architecture := architectures.Architecture(arch)
+	if architecture == "" {
+		architecture, err = architectures.FindArchitecture()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, found := config.Assets[architecture]; !found {
+		return nil, fmt.Errorf("nodeup config %q has no assets for architecture %q", configFile, architecture)
+	}
+
+	return &Prebake{
+		Config:       config,
+		Architecture: architecture,
+	}, nil
+}
+
+// Run downloads the assets and images, and writes the prebake manifest
+func (p *Prebake) Run() error {
+	if p.Config == nil {
+		return fmt.Errorf("nodeup config is required")
+	}
+	if p.Root == "" {
+		return fmt.Errorf("root directory is required")
+	}
+	cacheDir := p.CacheDir
+	if cacheDir == "" {
+		cacheDir = DefaultCacheDir
+	}
+	localCacheDir := filepath.Join(p.Root, cacheDir)
+
+	manifest := &nodeup.PrebakeManifest{
+		InstanceGroup: p.InstanceGroup,
+		Architecture:  p.Architecture,
+	}
+
+	if p.NodeupBinary != "" {
+		// The bootstrap script only downloads nodeup if the hash of the existing binary does not match
+		if err := downloadAsset(p.NodeupBinary, filepath.Join(p.Root, "opt", "kops", "bin", "nodeup")); err != nil {
+			return fmt.Errorf("error installing nodeup: %w", err)
+		}
+		if err := os.Chmod(filepath.Join(p.Root, "opt", "kops", "bin", "nodeup"), 0o755); err != nil {
+			return err
+		}
+	}
+
+	// The asset store downloads the assets to the cache and extracts the archives, as nodeup does on boot
+	assetStore := fi.NewAssetStore(localCacheDir)
+	for _, asset := range p.Config.Assets[p.Architecture] {
+		klog.Infof("Installing asset %s", asset)
+		if err := assetStore.Add(asset); err != nil {
+			return fmt.Errorf("error installing asset %q: %w", asset, err)
+		}
+		manifest.Assets = append(manifest.Assets, asset)
+	}
+
+	for _, image := range p.Config.Images[p.Architecture] {
+		hash, err := hashing.FromString(image.Hash)
+		if err != nil {
+			return err
+		}
+		if len(image.Sources) == 0 {
+			return fmt.Errorf("no sources for image %q", image.Name)
+		}
+		asset := hash.String() + "@" + strings.Join(image.Sources, ",")
+		klog.Infof("Installing image %s", asset)
+		file := filepath.Join(localCacheDir, hash.String()+"_"+utils.SanitizeString(path.Base(image.Sources[0])))
+		if err := downloadAsset(asset, file); err != nil {
+			return fmt.Errorf("error installing image %q: %w", asset, err)
+		}
+		manifest.Assets = append(manifest.Assets, asset)
+	}
+
+	seen := make(map[string]bool)
+	for _, image := range append(append([]string{}, p.Config.PrePullImages...), p.Config.WarmPoolImages...) {
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+
+		prebaked, err := p.saveImage(localCacheDir, image)
+		if err != nil {
+			return fmt.Errorf("error saving image %q: %w", image, err)
+		}
+		manifest.Images = append(manifest.Images, *prebaked)
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error building prebake manifest: %w", err)
+	}
+	manifestPath := filepath.Join(localCacheDir, nodeup.PrebakeManifestFile)
+	if err := os.WriteFile(manifestPath, b, 0o644); err != nil {
+		return fmt.Errorf("error writing prebake manifest %q: %w", manifestPath, err)
+	}
+	klog.Infof("Wrote prebake manifest %s with %d assets and %d images", manifestPath, len(manifest.Assets), len(manifest.Images))
+
+	return nil
+}
+
+// saveImage pulls the image for the architecture of the instances, and saves it as an OCI archive that the container runtime can import.
+// The archive keeps the manifest of the registry, so the imported image matches the reference, including its digest.
+func (p *Prebake) saveImage(localCacheDir string, image string) (*nodeup.PrebakedImage, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reference %q: %w", image, err)
+	}
+
+	klog.Infof("Saving image %s", image)
+	desc, err := remote.Get(ref,
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: string(p.Architecture)}))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %q: %w", image, err)
+	}
+	// The image of the platform, when the reference is an image index
+	img, err := desc.Image()
+	if err != nil {
+		return nil, fmt.Errorf("fetching %q: %w", image, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "prebake")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			klog.Warningf("error deleting temp dir %q: %v", tmpDir, err)
+		}
+	}()
+
+	l, err := layout.Write(tmpDir, empty.Index)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.WriteImage(img); err != nil {
+		return nil, fmt.Errorf("writing %q: %w", image, err)
+	}
+	if desc.MediaType.IsIndex() {
+		// Only the image of the platform is saved, but the index is kept so the digest of the reference matches
+		if err := l.WriteBlob(desc.Digest, io.NopCloser(bytes.NewReader(desc.Manifest))); err != nil {
+			return nil, fmt.Errorf("writing %q: %w", image, err)
+		}
+	}
+	descriptor := desc.Descriptor
+	descriptor.Annotations = map[string]string{
+		containerdImageNameAnnotation: containerdImageName(ref),
+	}
+	if err := l.AppendDescriptor(descriptor); err != nil {
+		return nil, fmt.Errorf("writing %q: %w", image, err)
+	}
+
+	file := path.Join(prebakeImagesDir, utils.SanitizeString(image)+".tar")
+	localFile := filepath.Join(localCacheDir, file)
+	if err := os.MkdirAll(filepath.Dir(localFile), 0o755); err != nil {
+		return nil, err
+	}
+	if err := writeArchive(localFile, tmpDir); err != nil {
+		return nil, fmt.Errorf("writing %q: %w", localFile, err)
+	}
+
+	hash, err := hashing.HashAlgorithmSHA256.HashFile(localFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeup.PrebakedImage{
+		Name:   image,
+		File:   file,
+		Hash:   hash.String(),
+		Digest: desc.Digest.String(),
+	}, nil
+}
+
+// containerdImageName returns the name containerd and the kubelet use for the reference,
+// with the registry of Docker Hub normalized as docker.io
+func containerdImageName(ref name.Reference) string {
+	registry := ref.Context().RegistryStr()
+	if registry == name.DefaultRegistry {
+		registry = "docker.io"
+	}
+	separator := ":"
+	if _, ok := ref.(name.Digest); ok {
+		separator = "@"
+	}
+	return registry + "/" + ref.Context().RepositoryStr() + separator + ref.Identifier()
+}
+
+// writeArchive writes the files of the directory to a tar archive
+func writeArchive(file string, dir string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := tar.NewWriter(f)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := w.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// downloadAsset downloads an asset in the hash@url format to the file, trying each url in turn
+func downloadAsset(asset string, file string) error {
+	i := strings.Index(asset, "@")
+	if i == -1 {
+		return fmt.Errorf("asset %q has no hash", asset)
+	}
+	hash, err := hashing.FromString(asset[:i])
+	if err != nil {
+		return err
+	}
+
+	for _, url := range strings.Split(asset[i+1:], ",") {
+		_, err = fi.DownloadURL(url, file, hash)
+		if err != nil {
+			klog.Warningf("error downloading url %q: %v", url, err)
+			continue
+		}
+		return nil
+	}
+	return err
+}
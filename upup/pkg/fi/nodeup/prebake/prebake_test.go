@@ -0,0 +1,244 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prebake
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// readArchive returns the files of a tar archive
+func readArchive(t *testing.T, file string) map[string][]byte {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("error opening %q: %v", file, err)
+	}
+	defer f.Close()
+
+	files := make(map[string][]byte)
+	r := tar.NewReader(f)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("error reading %q: %v", file, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("error reading %q: %v", header.Name, err)
+		}
+		files[header.Name] = b
+	}
+}
+
+func TestRunSavesImagesWithDigest(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A multi-architecture image, referenced by the digest of its index
+	amd64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arm64, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}})
+	amd64Digest, err := amd64.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	arm64Digest, err := arm64.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexDigest, err := index.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexRef, err := name.ParseReference(u.Host + "/kube-proxy@" + indexDigest.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.WriteIndex(indexRef, index); err != nil {
+		t.Fatalf("error pushing index: %v", err)
+	}
+
+	// A single image, referenced by a tag
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgRef, err := name.ParseReference(u.Host + "/pause:3.9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(imgRef, img); err != nil {
+		t.Fatalf("error pushing image: %v", err)
+	}
+
+	root := t.TempDir()
+	p := &Prebake{
+		Config: &nodeup.Config{
+			PrePullImages:  []string{indexRef.String(), imgRef.String()},
+			WarmPoolImages: []string{imgRef.String()},
+		},
+		InstanceGroup: "nodes",
+		Architecture:  architectures.ArchitectureArm64,
+		Root:          root,
+	}
+	if err := p.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cacheDir := filepath.Join(root, DefaultCacheDir)
+	b, err := os.ReadFile(filepath.Join(cacheDir, nodeup.PrebakeManifestFile))
+	if err != nil {
+		t.Fatalf("error reading prebake manifest: %v", err)
+	}
+	manifest := &nodeup.PrebakeManifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		t.Fatalf("error parsing prebake manifest: %v", err)
+	}
+	if manifest.InstanceGroup != "nodes" || manifest.Architecture != architectures.ArchitectureArm64 {
+		t.Errorf("unexpected prebake manifest: %s", b)
+	}
+	if len(manifest.Images) != 2 {
+		t.Fatalf("expected 2 images, got %s", b)
+	}
+
+	grid := []struct {
+		prebaked nodeup.PrebakedImage
+		name     string
+		digest   v1.Hash
+		blobs    []v1.Hash
+		missing  []v1.Hash
+	}{
+		{
+			prebaked: manifest.Images[0],
+			name:     indexRef.String(),
+			digest:   indexDigest,
+			blobs:    []v1.Hash{arm64Digest},
+			missing:  []v1.Hash{amd64Digest},
+		},
+		{
+			prebaked: manifest.Images[1],
+			name:     imgRef.String(),
+			digest:   imgDigest,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			if g.prebaked.Name != g.name {
+				t.Errorf("expected image %q, got %q", g.name, g.prebaked.Name)
+			}
+			if g.prebaked.Digest != g.digest.String() {
+				t.Errorf("expected digest %q, got %q", g.digest, g.prebaked.Digest)
+			}
+
+			file := filepath.Join(cacheDir, g.prebaked.File)
+			hash, err := hashing.HashAlgorithmSHA256.HashFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash.String() != g.prebaked.Hash {
+				t.Errorf("expected hash %q, got %q", g.prebaked.Hash, hash)
+			}
+
+			files := readArchive(t, file)
+			if _, found := files["oci-layout"]; !found {
+				t.Errorf("archive has no oci-layout")
+			}
+			archiveIndex, err := v1.ParseIndexManifest(bytes.NewReader(files["index.json"]))
+			if err != nil {
+				t.Fatalf("error parsing index.json: %v", err)
+			}
+			if len(archiveIndex.Manifests) != 1 {
+				t.Fatalf("expected 1 manifest in index.json, got %d", len(archiveIndex.Manifests))
+			}
+			if archiveIndex.Manifests[0].Digest != g.digest {
+				t.Errorf("expected digest %q in index.json, got %q", g.digest, archiveIndex.Manifests[0].Digest)
+			}
+			if actual := archiveIndex.Manifests[0].Annotations[containerdImageNameAnnotation]; actual != g.name {
+				t.Errorf("expected image name %q, got %q", g.name, actual)
+			}
+			for _, blob := range append([]v1.Hash{g.digest}, g.blobs...) {
+				if _, found := files["blobs/sha256/"+blob.Hex]; !found {
+					t.Errorf("archive has no blob %s", blob)
+				}
+			}
+			for _, blob := range g.missing {
+				if _, found := files["blobs/sha256/"+blob.Hex]; found {
+					t.Errorf("archive has blob %s of another platform", blob)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerdImageName(t *testing.T) {
+	grid := map[string]string{
+		"nginx:1.25":                          "docker.io/library/nginx:1.25",
+		"registry.k8s.io/pause:3.9":           "registry.k8s.io/pause:3.9",
+		"registry.k8s.io/pause@sha256:" + hex: "registry.k8s.io/pause@sha256:" + hex,
+	}
+	for image, expected := range grid {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := containerdImageName(ref); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, image, actual)
+		}
+	}
+}
+
+const hex = "7031c1b283388d2c2e09b57badb803c05ebed362dc88d84b480cc47f72a21097"
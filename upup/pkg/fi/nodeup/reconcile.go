@@ -0,0 +1,307 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// NodeConditionConfigurationDrift is the node condition reporting whether the node has drifted from its kops configuration
+	NodeConditionConfigurationDrift v1.NodeConditionType = "KopsConfigurationDrift"
+
+	// DefaultReconcileKubeconfig is the kubeconfig used to report drift, when none is specified
+	DefaultReconcileKubeconfig = "/var/lib/kubelet/kubeconfig"
+
+	// maxDriftMessageTasks is the number of drifted tasks listed in the node condition message
+	maxDriftMessageTasks = 10
+)
+
+// Reconciler periodically compares the node against its nodeup configuration,
+// reporting any drift on the node object and optionally repairing it
+type Reconciler struct {
+	ConfigLocation string
+	CacheDir       string
+	// Interval is the time between two reconciliations
+	Interval time.Duration
+	// Apply re-applies the drifted tasks that are safe to apply on a running node
+	Apply bool
+	// KubeConfig is the kubeconfig used to report drift; defaults to DefaultReconcileKubeconfig
+	KubeConfig string
+
+	k8sClient kubernetes.Interface
+	// lastDrift is the drift reported by the previous reconciliation, to avoid repeating events
+	lastDrift string
+}
+
+// Run reconciles the node every Interval, until the context is cancelled
+func (r *Reconciler) Run(ctx context.Context) error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("reconcile interval must be positive, was %v", r.Interval)
+	}
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.reconcile(ctx); err != nil {
+			klog.Warningf("error reconciling node (will retry in %s): %v", r.Interval, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reconciler) reconcile(ctx context.Context) error {
+	var out bytes.Buffer
+	cmd := &NodeUpCommand{
+		ConfigLocation: r.ConfigLocation,
+		CacheDir:       r.CacheDir,
+		Target:         "dryrun",
+		reconcile:      true,
+	}
+	if err := cmd.Run(&out); err != nil {
+		if cmd.nodeName != "" {
+			r.reportError(ctx, cmd.nodeName, err)
+		}
+		return err
+	}
+	klog.V(2).Infof("dryrun report:\n%s", out.String())
+
+	drift := driftedTasks(cmd.changedTasks)
+	if len(drift) == 0 {
+		klog.Infof("node configuration has not drifted")
+		r.lastDrift = ""
+		return r.setCondition(ctx, cmd.nodeName, v1.ConditionFalse, "NoDrift", "Node matches its kops configuration")
+	}
+
+	names := sortedTaskNames(drift)
+	klog.Warningf("node configuration has drifted: %s", strings.Join(names, ", "))
+	message := driftMessage(names)
+	if err := r.setCondition(ctx, cmd.nodeName, v1.ConditionTrue, "DriftDetected", message); err != nil {
+		return err
+	}
+	if summary := strings.Join(names, ","); summary != r.lastDrift {
+		r.lastDrift = summary
+		r.recordEvent(ctx, cmd.nodeName, v1.EventTypeWarning, "ConfigurationDrift", message)
+	}
+
+	if !r.Apply {
+		return nil
+	}
+
+	repairable := make(map[string]fi.NodeupTask)
+	for name, task := range drift {
+		if isRepairable(task) {
+			repairable[name] = task
+		}
+	}
+	if len(repairable) == 0 {
+		klog.Infof("no drifted task can be re-applied on a running node")
+		return nil
+	}
+
+	applyCmd := &NodeUpCommand{
+		ConfigLocation: r.ConfigLocation,
+		CacheDir:       r.CacheDir,
+		Target:         "direct",
+		reconcile:      true,
+		taskFilter: func(name string, task fi.NodeupTask) bool {
+			_, found := repairable[name]
+			return found
+		},
+	}
+	if err := applyCmd.Run(&out); err != nil {
+		return fmt.Errorf("error re-applying drifted tasks: %w", err)
+	}
+	repaired := sortedTaskNames(repairable)
+	klog.Infof("re-applied drifted tasks: %s", strings.Join(repaired, ", "))
+	r.recordEvent(ctx, cmd.nodeName, v1.EventTypeNormal, "ConfigurationDriftRepaired", "Re-applied "+driftMessage(repaired))
+
+	// The next reconciliation reports any remaining drift
+	r.lastDrift = ""
+	return nil
+}
+
+// driftedTasks returns the changed tasks that represent drift of the node configuration.
+// Tasks which change on every run, such as certificates issued on the node, are not considered drift.
+func driftedTasks(changed map[string]fi.NodeupTask) map[string]fi.NodeupTask {
+	drift := make(map[string]fi.NodeupTask)
+	for name, task := range changed {
+		switch task := task.(type) {
+		case *nodetasks.File:
+			if _, ok := task.Contents.(*fi.NodeupTaskDependentResource); ok {
+				continue
+			}
+			drift[name] = task
+		case *nodetasks.Package, *nodetasks.Service:
+			drift[name] = task
+		}
+	}
+	return drift
+}
+
+// isRepairable returns true if the task can be re-applied without disrupting the running node
+func isRepairable(task fi.NodeupTask) bool {
+	switch task.(type) {
+	case *nodetasks.File, *nodetasks.Package:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedTaskNames(tasks map[string]fi.NodeupTask) []string {
+	var names []string
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func driftMessage(names []string) string {
+	if len(names) > maxDriftMessageTasks {
+		return fmt.Sprintf("%d tasks: %s and %d more", len(names), strings.Join(names[:maxDriftMessageTasks], ", "), len(names)-maxDriftMessageTasks)
+	}
+	return fmt.Sprintf("%d tasks: %s", len(names), strings.Join(names, ", "))
+}
+
+func (r *Reconciler) reportError(ctx context.Context, nodeName string, err error) {
+	if err := r.setCondition(ctx, nodeName, v1.ConditionUnknown, "ReconcileFailed", err.Error()); err != nil {
+		klog.Warningf("error reporting reconcile failure: %v", err)
+	}
+}
+
+func (r *Reconciler) kubernetesClient() (kubernetes.Interface, error) {
+	if r.k8sClient != nil {
+		return r.k8sClient, nil
+	}
+
+	kubeconfig := r.KubeConfig
+	if kubeconfig == "" {
+		kubeconfig = DefaultReconcileKubeconfig
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load kubeconfig %q: %w", kubeconfig, err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build kube client: %w", err)
+	}
+	r.k8sClient = k8sClient
+	return k8sClient, nil
+}
+
+func (r *Reconciler) setCondition(ctx context.Context, nodeName string, status v1.ConditionStatus, reason, message string) error {
+	k8sClient, err := r.kubernetesClient()
+	if err != nil {
+		return err
+	}
+
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %w", nodeName, err)
+	}
+
+	now := metav1.Now()
+	condition := v1.NodeCondition{
+		Type:               NodeConditionConfigurationDrift,
+		Status:             status,
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	}
+	for _, existing := range node.Status.Conditions {
+		if existing.Type == NodeConditionConfigurationDrift && existing.Status == status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []v1.NodeCondition{condition},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building node condition patch: %w", err)
+	}
+	if _, err := k8sClient.CoreV1().Nodes().PatchStatus(ctx, nodeName, patch); err != nil {
+		return fmt.Errorf("error setting condition on node %q: %w", nodeName, err)
+	}
+	return nil
+}
+
+func (r *Reconciler) recordEvent(ctx context.Context, nodeName string, eventType, reason, message string) {
+	k8sClient, err := r.kubernetesClient()
+	if err != nil {
+		klog.Warningf("error recording event: %v", err)
+		return
+	}
+
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		klog.Warningf("error getting node %q: %v", nodeName, err)
+		return
+	}
+
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: nodeName + ".",
+			Namespace:    metav1.NamespaceDefault,
+		},
+		InvolvedObject: v1.ObjectReference{
+			Kind: "Node",
+			Name: nodeName,
+			UID:  node.UID,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source: v1.EventSource{
+			Component: "kops-nodeup",
+			Host:      nodeName,
+		},
+	}
+	if _, err := k8sClient.CoreV1().Events(metav1.NamespaceDefault).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		klog.Warningf("error recording event on node %q: %v", nodeName, err)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func TestDriftedTasks(t *testing.T) {
+	changed := map[string]fi.NodeupTask{
+		"File//etc/kubernetes/kubelet.conf":  &nodetasks.File{Path: "/etc/kubernetes/kubelet.conf", Contents: fi.NewStringResource("config")},
+		"File//srv/kubernetes/kubelet.crt":   &nodetasks.File{Path: "/srv/kubernetes/kubelet.crt", Contents: &fi.NodeupTaskDependentResource{}},
+		"Package/conntrack":                  &nodetasks.Package{Name: "conntrack"},
+		"Service/kubelet.service":            &nodetasks.Service{Name: "kubelet.service"},
+		"Archive/kata-qemu":                  &nodetasks.Archive{Name: "kata-qemu"},
+		"UpdatePackages/update-packages":     &nodetasks.UpdatePackages{},
+		"LoadImageTask/kube-proxy-image.tar": &nodetasks.LoadImageTask{Name: "kube-proxy"},
+	}
+
+	actual := sortedTaskNames(driftedTasks(changed))
+	expected := []string{
+		"File//etc/kubernetes/kubelet.conf",
+		"Package/conntrack",
+		"Service/kubelet.service",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected drifted tasks: expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIsRepairable(t *testing.T) {
+	grid := []struct {
+		task     fi.NodeupTask
+		expected bool
+	}{
+		{task: &nodetasks.File{Path: "/etc/sysctl.d/99-k8s-general.conf"}, expected: true},
+		{task: &nodetasks.Package{Name: "conntrack"}, expected: true},
+		{task: &nodetasks.Service{Name: "kubelet.service"}, expected: false},
+		{task: &nodetasks.Archive{Name: "kata-qemu"}, expected: false},
+	}
+
+	for _, g := range grid {
+		if actual := isRepairable(g.task); actual != g.expected {
+			t.Errorf("unexpected repairable for %v: expected %v, got %v", g.task, g.expected, actual)
+		}
+	}
+}
+
+func TestDriftMessage(t *testing.T) {
+	var names []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		names = append(names, "File/"+name)
+	}
+
+	if actual, expected := driftMessage(names[:2]), "2 tasks: File/a, File/b"; actual != expected {
+		t.Errorf("unexpected message: expected %q, got %q", expected, actual)
+	}
+	if actual, expected := driftMessage(names), "12 tasks: File/a, File/b, File/c, File/d, File/e, File/f, File/g, File/h, File/i, File/j and 2 more"; actual != expected {
+		t.Errorf("unexpected message: expected %q, got %q", expected, actual)
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	ctx := context.Background()
+	transition := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	grid := []struct {
+		name               string
+		existing           []v1.NodeCondition
+		status             v1.ConditionStatus
+		keepTransitionTime bool
+	}{
+		{
+			name:   "new condition",
+			status: v1.ConditionTrue,
+		},
+		{
+			name: "unchanged status",
+			existing: []v1.NodeCondition{
+				{Type: NodeConditionConfigurationDrift, Status: v1.ConditionTrue, LastTransitionTime: transition},
+			},
+			status:             v1.ConditionTrue,
+			keepTransitionTime: true,
+		},
+		{
+			name: "changed status",
+			existing: []v1.NodeCondition{
+				{Type: NodeConditionConfigurationDrift, Status: v1.ConditionTrue, LastTransitionTime: transition},
+			},
+			status: v1.ConditionFalse,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: v1.NodeStatus{
+					Conditions: append([]v1.NodeCondition{
+						{Type: v1.NodeReady, Status: v1.ConditionTrue},
+					}, g.existing...),
+				},
+			}
+			r := &Reconciler{k8sClient: fake.NewSimpleClientset(node)}
+
+			if err := r.setCondition(ctx, "node-1", g.status, "DriftDetected", "1 tasks: Package/conntrack"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actual, err := r.k8sClient.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ready, drift *v1.NodeCondition
+			for i := range actual.Status.Conditions {
+				switch actual.Status.Conditions[i].Type {
+				case v1.NodeReady:
+					ready = &actual.Status.Conditions[i]
+				case NodeConditionConfigurationDrift:
+					drift = &actual.Status.Conditions[i]
+				}
+			}
+			if ready == nil {
+				t.Errorf("the Ready condition was removed: %v", actual.Status.Conditions)
+			}
+			if drift == nil {
+				t.Fatalf("the %s condition was not set: %v", NodeConditionConfigurationDrift, actual.Status.Conditions)
+			}
+			if drift.Status != g.status || drift.Reason != "DriftDetected" || drift.Message != "1 tasks: Package/conntrack" {
+				t.Errorf("unexpected condition: %v", drift)
+			}
+			if keep := drift.LastTransitionTime.Equal(&transition); keep != g.keepTransitionTime {
+				t.Errorf("unexpected transition time %v, previous %v", drift.LastTransitionTime, transition)
+			}
+		})
+	}
+}
+
+func TestSetConditionMissingNode(t *testing.T) {
+	r := &Reconciler{k8sClient: fake.NewSimpleClientset()}
+
+	if err := r.setCondition(context.Background(), "node-1", v1.ConditionTrue, "DriftDetected", "drift"); err == nil {
+		t.Errorf("expected an error for a missing node")
+	}
+}
+
+func TestRecordEvent(t *testing.T) {
+	ctx := context.Background()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "uid-1"}}
+	r := &Reconciler{k8sClient: fake.NewSimpleClientset(node)}
+
+	r.recordEvent(ctx, "node-1", v1.EventTypeWarning, "ConfigurationDrift", "1 tasks: Package/conntrack")
+
+	events, err := r.k8sClient.CoreV1().Events(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("expected a single event, got %v", events.Items)
+	}
+	event := events.Items[0]
+	if event.InvolvedObject.Kind != "Node" || event.InvolvedObject.Name != "node-1" || event.InvolvedObject.UID != "uid-1" {
+		t.Errorf("unexpected involved object: %v", event.InvolvedObject)
+	}
+	if event.Type != v1.EventTypeWarning || event.Reason != "ConfigurationDrift" || event.Message != "1 tasks: Package/conntrack" {
+		t.Errorf("unexpected event: %v", event)
+	}
+}
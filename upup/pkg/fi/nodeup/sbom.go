@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/sbom"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+// buildSBOM builds a software bill of materials of the file assets, images and operating system packages that nodeup installs.
+// The versions of the packages are the ones installed on the node, as they are chosen by the package manager of the distribution.
+func buildSBOM(clusterName string, taskMap map[string]fi.NodeupTask, distribution *distributions.Distribution, assets []string, images []*nodeup.Image) (*sbom.Document, error) {
+	doc := sbom.NewDocument(clusterName)
+	doc.Comment = "Lists the file assets, image tarballs and operating system packages installed on this node. " +
+		"The images of the addons and the assets of the other nodes of the cluster are listed by kops get assets --sbom."
+
+	for _, asset := range assets {
+		hash, urls := "", asset
+		// Assets are in the hash@url1,url2 format, or only list the urls
+		if i := strings.Index(asset, "@"); i != -1 && !strings.Contains(asset[:i], "/") {
+			hash, urls = asset[:i], asset[i+1:]
+		}
+		locations := strings.Split(urls, ",")
+		doc.Components = append(doc.Components, sbom.FileComponent(locations[0], "", hash))
+	}
+
+	for _, image := range images {
+		if len(image.Sources) == 0 {
+			continue
+		}
+		doc.Components = append(doc.Components, sbom.FileComponent(image.Sources[0], "", image.Hash))
+	}
+
+	var packages []*nodetasks.Package
+	var keys []string
+	for key := range taskMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if p, ok := taskMap[key].(*nodetasks.Package); ok {
+			packages = append(packages, p)
+			packages = append(packages, p.Deps...)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, p := range packages {
+		if seen[p.Name] {
+			continue
+		}
+		seen[p.Name] = true
+
+		version, err := p.InstalledVersion(distribution)
+		if err != nil {
+			return nil, fmt.Errorf("error determining version of package %q: %w", p.Name, err)
+		}
+
+		c := sbom.PackageComponent(distribution.PackageFormat(), distribution.Project(), p.Name, version)
+		if p.Source != nil {
+			c.SourceURL = *p.Source
+		}
+		if p.Hash != nil {
+			switch len(*p.Hash) {
+			case 64:
+				c.SHA256 = *p.Hash
+			case 40:
+				c.SHA1 = *p.Hash
+			}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"fmt"
+
+	"k8s.io/kops/nodeup/pkg/model"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/distributions"
+)
+
+// BuildInstanceGroupTaskGraph builds the graph of the tasks that nodeup runs on the instances of an instance group,
+// without running on one of them. The configuration is built with nodeup.NewConfig from the completed cluster spec
+// and the keypairs of the keystore, and the assets are placeholders, so the tasks that depend on what is only known
+// on the instance may differ: the names of the instance, the contents of the assets and the hardware of the instance.
+func BuildInstanceGroupTaskGraph(cluster *api.Cluster, ig *api.InstanceGroup, architecture architectures.Architecture, distribution distributions.Distribution, keyStore fi.CAStore, secretStore fi.SecretStoreReader) (*fi.TaskGraph, map[string]fi.NodeupTask, error) {
+	nodeupConfig, bootConfig := nodeup.NewConfig(cluster, ig)
+
+	// The keypairs are created by kops update cluster
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing keysets: %w", err)
+	}
+	for name, keyset := range keysets {
+		if keyset.Primary == nil {
+			continue
+		}
+		certificates, err := keyset.ToCertificateBytes()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %q certificates: %w", name, err)
+		}
+		nodeupConfig.CAs[name] = string(certificates)
+		nodeupConfig.KeypairIDs[name] = keyset.Primary.Id
+	}
+	if nodeupConfig.APIServerConfig != nil && keysets["service-account"] != nil {
+		nodeupConfig.APIServerConfig.ServiceAccountPublicKeys, err = keysets["service-account"].ToPublicKeys()
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding service-account keys: %w", err)
+		}
+	}
+
+	modelContext := &model.NodeupModelContext{
+		Architecture: architecture,
+		Assets:       fi.NewPlaceholderAssetStore(),
+		Cluster:      cluster,
+		Distribution: distribution,
+		BootConfig:   bootConfig,
+		NodeupConfig: nodeupConfig,
+		KeyStore:     keyStore,
+		SecretStore:  secretStore,
+	}
+	if err := modelContext.Init(); err != nil {
+		return nil, nil, err
+	}
+
+	loader := NewLoader(modelContext)
+	taskMap, err := loader.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building loader: %v", err)
+	}
+
+	return fi.BuildTaskGraph(taskMap, loader.TaskBuilders()), taskMap, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/architectures"
+	"k8s.io/kops/util/pkg/distributions"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestBuildInstanceGroupTaskGraph(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+	h.SetupMockAWS()
+	m, err := testutils.LoadModel("../../../../nodeup/pkg/model/tests/golden/minimal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud, err := cloudup.BuildCloud(m.Cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cloudup.PerformAssignments(m.Cluster, cloud); err != nil {
+		t.Fatal(err)
+	}
+	vfs.Context.ResetMemfsContext(true)
+	assetBuilder := assets.NewAssetBuilder(m.Cluster.Spec.Assets, m.Cluster.Spec.KubernetesVersion, false)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientset := vfsclientset.NewVFSClientset(basePath)
+	c, err := cloudup.PopulateClusterSpec(context.TODO(), clientset, m.Cluster, cloud, assetBuilder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The keypairs are created by kops update cluster
+	ks, err := clientset.KeyStore(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"kubernetes-ca", "service-account", "etcd-clients-ca", "apiserver-aggregator-ca", "etcd-manager-ca-main", "etcd-peers-ca-main", "etcd-clients-ca-main", "etcd-manager-ca-events", "etcd-peers-ca-events", "etcd-clients-ca-events", "kubelet", "kube-proxy"} {
+		req := pki.IssueCertRequest{Signer: name, Type: "ca", Subject: pkix.Name{CommonName: name}, Serial: big.NewInt(1)}
+		cert, key, _, err := pki.IssueCert(context.TODO(), &req, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item := &fi.KeysetItem{Id: "1", Certificate: cert, PrivateKey: key}
+		if err := ks.StoreKeyset(context.TODO(), name, &fi.Keyset{Items: map[string]*fi.KeysetItem{"1": item}, Primary: item}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ig := range m.InstanceGroups {
+		_, taskMap, err := BuildInstanceGroupTaskGraph(c, ig, architectures.ArchitectureAmd64, distributions.DistributionUbuntu2404, ks, nil)
+		if err != nil {
+			t.Fatalf("building task graph of %s: %v", ig.Name, err)
+		}
+		// The assets are placeholders named after the files that nodeup installs
+		for _, key := range []string{"File//usr/local/bin/kubelet", "File//usr/bin/containerd", "File//usr/sbin/runc", "Package/conntrack", "Service/kubelet.service"} {
+			if taskMap[key] == nil {
+				t.Errorf("task %q not found in the tasks of %s", key, ig.Name)
+			}
+		}
+		_, isControlPlane := taskMap["File//etc/kubernetes/manifests/kube-apiserver.manifest"]
+		if isControlPlane != ig.IsControlPlane() {
+			t.Errorf("expected kube-apiserver manifest in the tasks of %s: %v, got %v", ig.Name, ig.IsControlPlane(), isControlPlane)
+		}
+	}
+}
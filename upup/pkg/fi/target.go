@@ -29,6 +29,13 @@ type Target[T SubContext] interface {
 	DefaultCheckExisting() bool
 }
 
+// ImportingTarget is implemented by targets that check whether the resources of tasks already exist, in order to
+// import them, but otherwise render every task as if it did not exist, e.g. Terraform with import blocks.
+type ImportingTarget[T SubContext] interface {
+	// ImportExisting is called with the actual state of each task that was found, before the task is rendered
+	ImportExisting(actual, expected Task[T]) error
+}
+
 type CloudupTarget = Target[CloudupSubContext]
 type InstallTarget = Target[InstallSubContext]
 type NodeupTarget = Target[NodeupSubContext]
@@ -0,0 +1,378 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TaskGraphFormat is the format in which a task graph is written
+type TaskGraphFormat string
+
+const (
+	TaskGraphFormatDOT     TaskGraphFormat = "dot"
+	TaskGraphFormatMermaid TaskGraphFormat = "mermaid"
+	TaskGraphFormatJSON    TaskGraphFormat = "json"
+)
+
+// TaskGraphFormats is the list of supported task graph formats
+var TaskGraphFormats = []string{string(TaskGraphFormatDOT), string(TaskGraphFormatMermaid), string(TaskGraphFormatJSON)}
+
+// TaskGraph is the graph of tasks and their dependencies, sorted by key
+type TaskGraph struct {
+	Tasks []*TaskGraphNode `json:"tasks"`
+}
+
+// TaskGraphNode is a task in the task graph
+type TaskGraphNode struct {
+	// Key is the key of the task in the task map, e.g. VPC/example.com
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// Lifecycle is the lifecycle of the task, if it has one
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Builder is the model builder that created the task, if known
+	Builder string `json:"builder,omitempty"`
+	// Dependencies are the keys of the tasks that must run before the task
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// BuildTaskGraph computes the task graph, as used by the executor.
+// builders maps task keys to the model builders that created them; it can be nil.
+func BuildTaskGraph[T SubContext](tasks map[string]Task[T], builders map[string]string) *TaskGraph {
+	dependencies := FindTaskDependencies(tasks)
+
+	graph := &TaskGraph{}
+	for key, task := range tasks {
+		node := &TaskGraphNode{
+			Key:          key,
+			Type:         TypeNameForTask(task),
+			Builder:      builders[key],
+			Dependencies: dependencies[key],
+		}
+		if hl, ok := task.(HasLifecycle); ok {
+			node.Lifecycle = hl.GetLifecycle()
+		}
+		sort.Strings(node.Dependencies)
+		graph.Tasks = append(graph.Tasks, node)
+	}
+	sort.Slice(graph.Tasks, func(i, j int) bool {
+		return graph.Tasks[i].Key < graph.Tasks[j].Key
+	})
+	return graph
+}
+
+// Subgraph returns the graph of the specified tasks and everything they depend on
+func (g *TaskGraph) Subgraph(keys []string) *TaskGraph {
+	nodes := make(map[string]*TaskGraphNode)
+	for _, node := range g.Tasks {
+		nodes[node.Key] = node
+	}
+
+	included := make(map[string]bool)
+	var visit func(key string)
+	visit = func(key string) {
+		if included[key] || nodes[key] == nil {
+			return
+		}
+		included[key] = true
+		for _, dep := range nodes[key].Dependencies {
+			visit(dep)
+		}
+	}
+	for _, key := range keys {
+		visit(key)
+	}
+
+	subgraph := &TaskGraph{}
+	for _, node := range g.Tasks {
+		if included[node.Key] {
+			subgraph.Tasks = append(subgraph.Tasks, node)
+		}
+	}
+	return subgraph
+}
+
+// Write writes the task graph in the specified format
+func (g *TaskGraph) Write(out io.Writer, format TaskGraphFormat) error {
+	var b strings.Builder
+	switch format {
+	case TaskGraphFormatDOT:
+		b.WriteString("digraph tasks {\n")
+		for _, node := range g.Tasks {
+			fmt.Fprintf(&b, "  %s [label=%s];\n", strconv.Quote(node.Key), strconv.Quote(node.label()))
+		}
+		for _, node := range g.Tasks {
+			for _, dep := range node.Dependencies {
+				fmt.Fprintf(&b, "  %s -> %s;\n", strconv.Quote(node.Key), strconv.Quote(dep))
+			}
+		}
+		b.WriteString("}\n")
+
+	case TaskGraphFormatMermaid:
+		// Mermaid node IDs cannot contain most punctuation, so nodes are numbered
+		ids := make(map[string]string, len(g.Tasks))
+		for i, node := range g.Tasks {
+			ids[node.Key] = "t" + strconv.Itoa(i)
+		}
+		b.WriteString("flowchart LR\n")
+		for _, node := range g.Tasks {
+			label := strings.ReplaceAll(node.label(), "\"", "#quot;")
+			label = strings.ReplaceAll(label, "\n", "<br>")
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.Key], label)
+		}
+		for _, node := range g.Tasks {
+			for _, dep := range node.Dependencies {
+				fmt.Fprintf(&b, "  %s --> %s\n", ids[node.Key], ids[dep])
+			}
+		}
+
+	case TaskGraphFormatJSON:
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling task graph: %w", err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
+
+	default:
+		return fmt.Errorf("unknown task graph format %q, available formats: %s", format, strings.Join(TaskGraphFormats, ", "))
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func (n *TaskGraphNode) label() string {
+	if n.Lifecycle == "" {
+		return n.Key
+	}
+	return n.Key + "\n" + string(n.Lifecycle)
+}
+
+// SpecSource is an object that tasks are built from, e.g. the cluster spec
+type SpecSource struct {
+	// Name identifies the object, e.g. InstanceGroup/nodes
+	Name string
+	// Path is the path of the object within its source, e.g. spec; it can be empty
+	Path string
+	// Object is the object itself
+	Object interface{}
+}
+
+// TaskExplanation describes why a task exists
+type TaskExplanation struct {
+	TaskGraphNode
+
+	// Dependents are the keys of the tasks that depend on the task
+	Dependents []string `json:"dependents,omitempty"`
+	// Fields are the fields of the task with values that match spec fields
+	Fields []*TaskFieldSource `json:"fields,omitempty"`
+}
+
+// TaskFieldSource is a field of a task, and the spec fields with the same value
+type TaskFieldSource struct {
+	Field   string   `json:"field"`
+	Value   string   `json:"value"`
+	Sources []string `json:"sources"`
+}
+
+// ExplainTask explains why the task exists: the model builder that created it, the tasks that depend on it,
+// and the spec fields that have the same values as its fields.
+func (g *TaskGraph) ExplainTask(task interface{}, key string, sources []SpecSource) (*TaskExplanation, error) {
+	var node *TaskGraphNode
+	for _, n := range g.Tasks {
+		if n.Key == key {
+			node = n
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("task %q not found", key)
+	}
+
+	explanation := &TaskExplanation{TaskGraphNode: *node}
+	for _, n := range g.Tasks {
+		for _, dep := range n.Dependencies {
+			if dep == key {
+				explanation.Dependents = append(explanation.Dependents, n.Key)
+			}
+		}
+	}
+
+	specValues := make(map[string][]string)
+	for _, source := range sources {
+		root := source.Name
+		if source.Path != "" {
+			root += " " + source.Path
+		}
+		collectSpecValues(root, reflect.ValueOf(source.Object), specValues)
+	}
+
+	v := reflect.ValueOf(task)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Name == "Name" || field.Name == "Lifecycle" {
+				continue
+			}
+			for _, value := range taskFieldValues(v.Field(i)) {
+				if paths := specValues[value]; len(paths) != 0 {
+					sort.Strings(paths)
+					explanation.Fields = append(explanation.Fields, &TaskFieldSource{
+						Field:   field.Name,
+						Value:   value,
+						Sources: paths,
+					})
+				}
+			}
+		}
+	}
+
+	return explanation, nil
+}
+
+// Write writes the explanation as text, or as JSON
+func (e *TaskExplanation) Write(out io.Writer, format TaskGraphFormat) error {
+	if format == TaskGraphFormatJSON {
+		data, err := json.MarshalIndent(e, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling task explanation: %w", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task:       %s\n", e.Key)
+	fmt.Fprintf(&b, "Type:       %s\n", e.Type)
+	if e.Lifecycle != "" {
+		fmt.Fprintf(&b, "Lifecycle:  %s\n", e.Lifecycle)
+	}
+	if e.Builder != "" {
+		fmt.Fprintf(&b, "Created by: %s\n", e.Builder)
+	}
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "  %s\n", item)
+		}
+	}
+	writeList("Depends on", e.Dependencies)
+	writeList("Required by", e.Dependents)
+	if len(e.Fields) != 0 {
+		fmt.Fprintf(&b, "Spec fields with matching values:\n")
+		for _, f := range e.Fields {
+			fmt.Fprintf(&b, "  %s=%q from %s\n", f.Field, f.Value, strings.Join(f.Sources, ", "))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// minSpecValueLength skips short values, such as booleans, that would match many unrelated fields
+const minSpecValueLength = 4
+
+// collectSpecValues maps the string values of an object to their JSON paths
+func collectSpecValues(path string, v reflect.Value, values map[string][]string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectSpecValues(path, v.Elem(), values)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if name != "" {
+				fieldPath = path + "." + name
+			} else if !field.Anonymous {
+				fieldPath = path + "." + field.Name
+			}
+			collectSpecValues(fieldPath, v.Field(i), values)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectSpecValues(fmt.Sprintf("%s[%d]", path, i), v.Index(i), values)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		for _, k := range v.MapKeys() {
+			collectSpecValues(fmt.Sprintf("%s[%s]", path, k.String()), v.MapIndex(k), values)
+		}
+	case reflect.String:
+		if s := v.String(); len(s) >= minSpecValueLength {
+			values[s] = append(values[s], path)
+		}
+	}
+}
+
+// taskFieldValues returns the string values of a task field, not following references to other tasks
+func taskFieldValues(v reflect.Value) []string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || v.Elem().Kind() != reflect.String {
+			return nil
+		}
+		return taskFieldValues(v.Elem())
+	case reflect.Slice:
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, taskFieldValues(v.Index(i))...)
+		}
+		return values
+	case reflect.Map:
+		var values []string
+		for _, k := range v.MapKeys() {
+			values = append(values, taskFieldValues(v.MapIndex(k))...)
+		}
+		sort.Strings(values)
+		return values
+	case reflect.String:
+		if s := v.String(); len(s) >= minSpecValueLength {
+			return []string{s}
+		}
+	}
+	return nil
+}
+
+// ModelBuilderName returns the name of a model builder, as recorded in the task graph, e.g. awsmodel.NetworkModelBuilder
+func ModelBuilderName(builder interface{}) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", builder), "*")
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type graphTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+	CIDR      *string
+	Parent    *graphTask
+}
+
+var _ InstallTask = &graphTask{}
+
+func (t *graphTask) Run(_ *InstallContext) error {
+	return nil
+}
+
+func (t *graphTask) GetLifecycle() Lifecycle {
+	return t.Lifecycle
+}
+
+func (t *graphTask) SetLifecycle(lifecycle Lifecycle) {
+	t.Lifecycle = lifecycle
+}
+
+func buildTestTaskGraph() (map[string]InstallTask, *TaskGraph) {
+	vpc := &graphTask{Name: PtrTo("vpc"), Lifecycle: LifecycleSync, CIDR: PtrTo("10.0.0.0/16")}
+	subnet := &graphTask{Name: PtrTo("subnet"), Lifecycle: LifecycleExistsAndWarnIfChanges, CIDR: PtrTo("10.0.1.0/24"), Parent: vpc}
+	asg := &graphTask{Name: PtrTo("nodes.example.com"), Lifecycle: LifecycleSync, Parent: subnet}
+	tasks := map[string]InstallTask{
+		"graphTask/vpc":               vpc,
+		"graphTask/subnet":            subnet,
+		"graphTask/nodes.example.com": asg,
+	}
+	builders := map[string]string{
+		"graphTask/vpc": "model.NetworkModelBuilder",
+	}
+	return tasks, BuildTaskGraph(tasks, builders)
+}
+
+func TestTaskGraph_Write(t *testing.T) {
+	_, graph := buildTestTaskGraph()
+
+	grid := []struct {
+		format   TaskGraphFormat
+		expected string
+	}{
+		{
+			format: TaskGraphFormatDOT,
+			expected: `digraph tasks {
+  "graphTask/nodes.example.com" [label="graphTask/nodes.example.com\nSync"];
+  "graphTask/subnet" [label="graphTask/subnet\nExistsAndWarnIfChanges"];
+  "graphTask/vpc" [label="graphTask/vpc\nSync"];
+  "graphTask/nodes.example.com" -> "graphTask/subnet";
+  "graphTask/subnet" -> "graphTask/vpc";
+}
+`,
+		},
+		{
+			format: TaskGraphFormatMermaid,
+			expected: `flowchart LR
+  t0["graphTask/nodes.example.com<br>Sync"]
+  t1["graphTask/subnet<br>ExistsAndWarnIfChanges"]
+  t2["graphTask/vpc<br>Sync"]
+  t0 --> t1
+  t1 --> t2
+`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(string(g.format), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := graph.Write(&out, g.format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != g.expected {
+				t.Errorf("unexpected output\nexpected:\n%s\nactual:\n%s", g.expected, out.String())
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := graph.Write(&out, TaskGraphFormatJSON); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var actual TaskGraph
+		if err := json.Unmarshal(out.Bytes(), &actual); err != nil {
+			t.Fatalf("error parsing output: %v", err)
+		}
+		if !reflect.DeepEqual(&actual, graph) {
+			t.Errorf("unexpected graph: %s", out.String())
+		}
+	})
+
+	if err := graph.Write(&bytes.Buffer{}, "svg"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestTaskGraph_Subgraph(t *testing.T) {
+	_, graph := buildTestTaskGraph()
+
+	subgraph := graph.Subgraph([]string{"graphTask/subnet"})
+	var keys []string
+	for _, node := range subgraph.Tasks {
+		keys = append(keys, node.Key)
+	}
+	expected := []string{"graphTask/subnet", "graphTask/vpc"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected subgraph %v, got %v", expected, keys)
+	}
+}
+
+func TestTaskGraph_ExplainTask(t *testing.T) {
+	tasks, graph := buildTestTaskGraph()
+
+	type subnetSpec struct {
+		Name string `json:"name"`
+		CIDR string `json:"cidr"`
+	}
+	type clusterSpec struct {
+		NetworkCIDR string       `json:"networkCIDR"`
+		Subnets     []subnetSpec `json:"subnets"`
+	}
+	spec := clusterSpec{
+		NetworkCIDR: "10.0.0.0/16",
+		Subnets:     []subnetSpec{{Name: "subnet", CIDR: "10.0.1.0/24"}},
+	}
+	sources := []SpecSource{{Name: "Cluster/example.com", Path: "spec", Object: &spec}}
+
+	explanation, err := graph.ExplainTask(tasks["graphTask/vpc"], "graphTask/vpc", sources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if explanation.Builder != "model.NetworkModelBuilder" {
+		t.Errorf("unexpected builder %q", explanation.Builder)
+	}
+	if !reflect.DeepEqual(explanation.Dependents, []string{"graphTask/subnet"}) {
+		t.Errorf("unexpected dependents %v", explanation.Dependents)
+	}
+	expectedFields := []*TaskFieldSource{
+		{Field: "CIDR", Value: "10.0.0.0/16", Sources: []string{"Cluster/example.com spec.networkCIDR"}},
+	}
+	if !reflect.DeepEqual(explanation.Fields, expectedFields) {
+		t.Errorf("unexpected fields %v", explanation.Fields)
+	}
+
+	var out bytes.Buffer
+	if err := explanation.Write(&out, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"Created by: model.NetworkModelBuilder\n",
+		"Required by:\n  graphTask/subnet\n",
+		`CIDR="10.0.0.0/16" from Cluster/example.com spec.networkCIDR`,
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected %q in explanation:\n%s", s, out.String())
+		}
+	}
+
+	if _, err := graph.ExplainTask(nil, "graphTask/missing", sources); err == nil {
+		t.Errorf("expected error for unknown task")
+	}
+}
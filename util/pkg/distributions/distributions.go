@@ -37,21 +37,36 @@ type Distribution struct {
 }
 
 var (
-	DistributionDebian10     = Distribution{packageFormat: "deb", project: "debian", id: "buster", version: 10}
-	DistributionDebian11     = Distribution{packageFormat: "deb", project: "debian", id: "bullseye", version: 11}
-	DistributionUbuntu1804   = Distribution{packageFormat: "deb", project: "ubuntu", id: "bionic", version: 18.04}
-	DistributionUbuntu2004   = Distribution{packageFormat: "deb", project: "ubuntu", id: "focal", version: 20.04}
-	DistributionUbuntu2010   = Distribution{packageFormat: "deb", project: "ubuntu", id: "groovy", version: 20.10}
-	DistributionUbuntu2104   = Distribution{packageFormat: "deb", project: "ubuntu", id: "hirsute", version: 21.04}
-	DistributionUbuntu2110   = Distribution{packageFormat: "deb", project: "ubuntu", id: "impish", version: 21.10}
-	DistributionUbuntu2204   = Distribution{packageFormat: "deb", project: "ubuntu", id: "jammy", version: 22.04}
-	DistributionAmazonLinux2 = Distribution{packageFormat: "rpm", project: "amazonlinux2", id: "amazonlinux2", version: 0}
-	DistributionRhel8        = Distribution{packageFormat: "rpm", project: "rhel", id: "rhel8", version: 8}
-	DistributionRocky8       = Distribution{packageFormat: "rpm", project: "rocky", id: "rocky8", version: 8}
-	DistributionFlatcar      = Distribution{packageFormat: "", project: "flatcar", id: "flatcar", version: 0}
-	DistributionContainerOS  = Distribution{packageFormat: "", project: "containeros", id: "containeros", version: 0}
+	DistributionDebian10        = Distribution{packageFormat: "deb", project: "debian", id: "buster", version: 10}
+	DistributionDebian11        = Distribution{packageFormat: "deb", project: "debian", id: "bullseye", version: 11}
+	DistributionDebian12        = Distribution{packageFormat: "deb", project: "debian", id: "bookworm", version: 12}
+	DistributionUbuntu1804      = Distribution{packageFormat: "deb", project: "ubuntu", id: "bionic", version: 18.04}
+	DistributionUbuntu2004      = Distribution{packageFormat: "deb", project: "ubuntu", id: "focal", version: 20.04}
+	DistributionUbuntu2010      = Distribution{packageFormat: "deb", project: "ubuntu", id: "groovy", version: 20.10}
+	DistributionUbuntu2104      = Distribution{packageFormat: "deb", project: "ubuntu", id: "hirsute", version: 21.04}
+	DistributionUbuntu2110      = Distribution{packageFormat: "deb", project: "ubuntu", id: "impish", version: 21.10}
+	DistributionUbuntu2204      = Distribution{packageFormat: "deb", project: "ubuntu", id: "jammy", version: 22.04}
+	DistributionUbuntu2404      = Distribution{packageFormat: "deb", project: "ubuntu", id: "noble", version: 24.04}
+	DistributionAmazonLinux2    = Distribution{packageFormat: "rpm", project: "amazonlinux2", id: "amazonlinux2", version: 0}
+	DistributionAmazonLinux2023 = Distribution{packageFormat: "rpm", project: "amazonlinux2023", id: "amazonlinux2023", version: 2023}
+	DistributionRhel8           = Distribution{packageFormat: "rpm", project: "rhel", id: "rhel8", version: 8}
+	DistributionRhel9           = Distribution{packageFormat: "rpm", project: "rhel", id: "rhel9", version: 9}
+	DistributionRocky8          = Distribution{packageFormat: "rpm", project: "rocky", id: "rocky8", version: 8}
+	DistributionRocky9          = Distribution{packageFormat: "rpm", project: "rocky", id: "rocky9", version: 9}
+	DistributionFlatcar         = Distribution{packageFormat: "", project: "flatcar", id: "flatcar", version: 0}
+	DistributionContainerOS     = Distribution{packageFormat: "", project: "containeros", id: "containeros", version: 0}
 )
 
+// Project returns the entity that produces the distribution e.g. "debian" or "ubuntu" or "rhel" or "centos"
+func (d *Distribution) Project() string {
+	return d.project
+}
+
+// PackageFormat returns the packaging format used by the distribution; either deb or rpm, or "" for immutable OSes
+func (d *Distribution) PackageFormat() string {
+	return d.packageFormat
+}
+
 // IsDebianFamily returns true if this distribution uses deb packages and generally follows debian package names
 func (d *Distribution) IsDebianFamily() bool {
 	return d.packageFormat == "deb"
@@ -67,6 +82,36 @@ func (d *Distribution) IsRHELFamily() bool {
 	return d.packageFormat == "rpm"
 }
 
+// HasDNF returns true if this distribution installs rpm packages with dnf rather than yum
+func (d *Distribution) HasDNF() bool {
+	switch d.project {
+	case "rhel", "rocky":
+		return d.version >= 8
+	case "amazonlinux2023":
+		return true
+	default:
+		return false
+	}
+}
+
+// HasCgroupsV2 returns true if this distribution only uses the unified cgroup v2 hierarchy,
+// so cgroup v1 tooling such as cgroupfs-mount is no longer installed.
+// Earlier releases that can still boot with cgroup v1 keep that tooling, so existing nodes are unchanged.
+func (d *Distribution) HasCgroupsV2() bool {
+	switch d.project {
+	case "debian":
+		return d.version >= 12
+	case "ubuntu":
+		return d.version >= 24.04
+	case "rhel", "rocky":
+		return d.version >= 9
+	case "amazonlinux2023", "flatcar":
+		return true
+	default:
+		return false
+	}
+}
+
 // IsSystemd returns true if this distribution uses systemd
 func (d *Distribution) IsSystemd() bool {
 	return true
@@ -81,7 +126,7 @@ func (d *Distribution) DefaultUsers() ([]string, error) {
 		return []string{"ubuntu", "root"}, nil
 	case "centos":
 		return []string{"centos"}, nil
-	case "rhel", "amazonlinux2":
+	case "rhel", "amazonlinux2", "amazonlinux2023":
 		return []string{"ec2-user"}, nil
 	case "rocky":
 		return []string{"rocky"}, nil
@@ -50,10 +50,14 @@ func FindDistribution(rootfs string) (Distribution, error) {
 	switch distro {
 	case "amzn-2":
 		return DistributionAmazonLinux2, nil
+	case "amzn-2023":
+		return DistributionAmazonLinux2023, nil
 	case "debian-10":
 		return DistributionDebian10, nil
 	case "debian-11":
 		return DistributionDebian11, nil
+	case "debian-12":
+		return DistributionDebian12, nil
 	case "ubuntu-18.04":
 		return DistributionUbuntu1804, nil
 	case "ubuntu-20.04":
@@ -66,6 +70,8 @@ func FindDistribution(rootfs string) (Distribution, error) {
 		return DistributionUbuntu2110, nil
 	case "ubuntu-22.04":
 		return DistributionUbuntu2204, nil
+	case "ubuntu-24.04":
+		return DistributionUbuntu2404, nil
 	}
 
 	// Some distros have a more verbose VERSION_ID
@@ -78,11 +84,67 @@ func FindDistribution(rootfs string) (Distribution, error) {
 	if strings.HasPrefix(distro, "rhel-8.") {
 		return DistributionRhel8, nil
 	}
+	if strings.HasPrefix(distro, "rhel-9.") {
+		return DistributionRhel9, nil
+	}
 	if strings.HasPrefix(distro, "rocky-8.") {
 		return DistributionRocky8, nil
 	}
+	if strings.HasPrefix(distro, "rocky-9.") {
+		return DistributionRocky9, nil
+	}
 
 	// Some distros are not supported
 	klog.V(2).Infof("Contents of /etc/os-release:\n%s", osReleaseBytes)
 	return Distribution{}, fmt.Errorf("unsupported distro: %s", distro)
 }
+
+// imageDistributions are the parts of the image names of the distributions, in order of precedence.
+// The code names come first, so that the dates in image names are not mistaken for versions.
+var imageDistributions = []struct {
+	part         string
+	distribution Distribution
+}{
+	{"noble", DistributionUbuntu2404},
+	{"jammy", DistributionUbuntu2204},
+	{"focal", DistributionUbuntu2004},
+	{"bookworm", DistributionDebian12},
+	{"bullseye", DistributionDebian11},
+	{"buster", DistributionDebian10},
+	{"flatcar", DistributionFlatcar},
+	{"cos-cloud", DistributionContainerOS},
+	{"al2023", DistributionAmazonLinux2023},
+	{"amzn2", DistributionAmazonLinux2},
+	{"ubuntu-24", DistributionUbuntu2404},
+	{"ubuntu-22", DistributionUbuntu2204},
+	{"ubuntu-20", DistributionUbuntu2004},
+	{"debian-12", DistributionDebian12},
+	{"debian/release/12", DistributionDebian12},
+	{"debian-11", DistributionDebian11},
+	{"debian/release/11", DistributionDebian11},
+	{"debian-10", DistributionDebian10},
+	{"debian/release/10", DistributionDebian10},
+	{"rhel-9", DistributionRhel9},
+	{"rhel:9", DistributionRhel9},
+	{"rhel-arm64:9", DistributionRhel9},
+	{"rhel-8", DistributionRhel8},
+	{"rhel:8", DistributionRhel8},
+	{"rocky-9", DistributionRocky9},
+	{"rocky-linux-9", DistributionRocky9},
+	{"rockylinux-9", DistributionRocky9},
+	{"rocky-8", DistributionRocky8},
+	{"rocky-linux-8", DistributionRocky8},
+	{"rockylinux-8", DistributionRocky8},
+}
+
+// FindDistributionForImage guesses the distribution of a machine image from its name, when it is not running on it.
+// It returns false if the name does not identify a distribution, e.g. for custom images.
+func FindDistributionForImage(image string) (Distribution, bool) {
+	image = strings.ToLower(image)
+	for _, d := range imageDistributions {
+		if strings.Contains(image, d.part) {
+			return d.distribution, true
+		}
+	}
+	return Distribution{}, false
+}
@@ -34,6 +34,11 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionAmazonLinux2,
 		},
+		{
+			rootfs:   "amazonlinux2023",
+			err:      nil,
+			expected: DistributionAmazonLinux2023,
+		},
 		{
 			rootfs:   "centos7",
 			err:      fmt.Errorf("unsupported distro: centos-7"),
@@ -74,6 +79,11 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionDebian11,
 		},
+		{
+			rootfs:   "debian12",
+			err:      nil,
+			expected: DistributionDebian12,
+		},
 		{
 			rootfs:   "flatcar",
 			err:      nil,
@@ -89,11 +99,21 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionRhel8,
 		},
+		{
+			rootfs:   "rhel9",
+			err:      nil,
+			expected: DistributionRhel9,
+		},
 		{
 			rootfs:   "rocky8",
 			err:      nil,
 			expected: DistributionRocky8,
 		},
+		{
+			rootfs:   "rocky9",
+			err:      nil,
+			expected: DistributionRocky9,
+		},
 		{
 			rootfs:   "ubuntu1604",
 			err:      fmt.Errorf("unsupported distro: ubuntu-16.04"),
@@ -129,6 +149,11 @@ func TestFindDistribution(t *testing.T) {
 			err:      nil,
 			expected: DistributionUbuntu2204,
 		},
+		{
+			rootfs:   "ubuntu2404",
+			err:      nil,
+			expected: DistributionUbuntu2404,
+		},
 		{
 			rootfs:   "notfound",
 			err:      fmt.Errorf("reading /etc/os-release: open tests/notfound/etc/os-release: no such file or directory"),
@@ -147,3 +172,31 @@ func TestFindDistribution(t *testing.T) {
 		}
 	}
 }
+
+func TestFindDistributionForImage(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected Distribution
+		found    bool
+	}{
+		{"099720109477/ubuntu/images/hvm-ssd-gp3/ubuntu-noble-24.04-amd64-server-20240607", DistributionUbuntu2404, true},
+		{"099720109477/ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20240412", DistributionUbuntu2204, true},
+		{"ubuntu-os-cloud/family/ubuntu-2404-lts-amd64", DistributionUbuntu2404, true},
+		{"Canonical:ubuntu-24_04-lts:server:latest", DistributionUbuntu2404, true},
+		{"ssm:/aws/service/debian/release/12/latest/amd64", DistributionDebian12, true},
+		{"debian-cloud/family/debian-11-arm64", DistributionDebian11, true},
+		{"ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-x86_64", DistributionAmazonLinux2023, true},
+		{"redhat.com/RHEL-9.*_HVM-*-x86_64-*-Hourly2-GP*", DistributionRhel9, true},
+		{"RedHat:RHEL:9-lvm-gen2:latest", DistributionRhel9, true},
+		{"rockylinux.org/Rocky-9-EC2-Base-9.*.x86_64", DistributionRocky9, true},
+		{"ami-0123456789abcdef0", Distribution{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			actual, found := FindDistributionForImage(test.image)
+			if found != test.found || actual != test.expected {
+				t.Errorf("expected %v (%v), got %v (%v)", test.expected, test.found, actual, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package distributions
+
+// Packages returns the operating system packages that nodeup installs on the distribution, in addition to the packages of the instance group.
+// It returns nil for the distributions that nodeup does not install packages on.
+func (d *Distribution) Packages() []string {
+	// kubelet needs:
+	//   conntrack  - kops #5671
+	//   ebtables - kops #1711
+	//   ethtool - kops #1830
+	if d.IsDebianFamily() {
+		// From containerd: https://github.com/containerd/cri/blob/master/contrib/ansible/tasks/bootstrap_ubuntu.yaml
+		packages := []string{"bridge-utils"}
+		if !d.HasCgroupsV2() {
+			// cgroupfs-mount only mounts the cgroup v1 hierarchies
+			packages = append(packages, "cgroupfs-mount")
+		}
+		return append(packages,
+			"conntrack",
+			"ebtables",
+			"ethtool",
+			"iptables",
+			"libapparmor1",
+			"libseccomp2",
+			"libltdl7",
+			"pigz",
+			"socat",
+			"util-linux",
+		)
+	}
+
+	if d.IsRHELFamily() {
+		// From containerd: https://github.com/containerd/cri/blob/master/contrib/ansible/tasks/bootstrap_centos.yaml
+		packages := []string{
+			"conntrack-tools",
+			"ethtool",
+			"libseccomp",
+			"libtool-ltdl",
+			"socat",
+			"util-linux",
+		}
+		// Handle some packages differently for each distro
+		switch *d {
+		case DistributionRhel9, DistributionRocky9, DistributionAmazonLinux2023:
+			// ebtables and libcgroup are no longer packaged, and iptables is provided by iptables-nft
+			packages = append(packages, "iptables-nft")
+		default:
+			packages = append(packages, "ebtables", "iptables", "libcgroup")
+		}
+		switch *d {
+		case DistributionAmazonLinux2:
+			// Amazon Linux 2 doesn't have SELinux enabled by default
+		default:
+			packages = append(packages, "container-selinux", "pigz")
+		}
+		return packages
+	}
+
+	return nil
+}
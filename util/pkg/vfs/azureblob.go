@@ -40,8 +40,9 @@ type AzureBlobPath struct {
 }
 
 var (
-	_ Path    = &AzureBlobPath{}
-	_ HasHash = &AzureBlobPath{}
+	_ Path       = &AzureBlobPath{}
+	_ HasHash    = &AzureBlobPath{}
+	_ HasVersion = &AzureBlobPath{}
 )
 
 // NewAzureBlobPath returns a new AzureBlobPath.
@@ -201,6 +202,87 @@ func (p *AzureBlobPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl A
 	return err
 }
 
+// ReadFileVersion returns the content of the blob, using its ETag as the version.
+func (p *AzureBlobPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	client, err := p.getClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cURL, err := client.newContainerURL(p.container)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := cURL.NewBlockBlobURL(p.key).Download(
+		ctx,
+		0, /* offset */
+		azblob.CountToEnd,
+		azblob.BlobAccessConditions{},
+		false, /* rangeGetContentMD5 */
+		azblob.ClientProvidedKeyOptions{},
+	)
+	if err != nil {
+		serr, ok := err.(azblob.StorageError)
+		if ok && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", err
+	}
+	body := resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 10})
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, string(resp.ETag()), nil
+}
+
+// WriteFileIfVersion writes the blob, but only if its ETag still matches version.
+func (p *AzureBlobPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	client, err := p.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := data.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("error seeking to start of data stream: %v", err)
+	}
+
+	cURL, err := client.newContainerURL(p.container)
+	if err != nil {
+		return "", err
+	}
+	resp, err := cURL.NewBlockBlobURL(p.key).Upload(
+		ctx,
+		data,
+		azblob.BlobHTTPHeaders{
+			ContentType: "application/octet-stream",
+			ContentMD5:  md5Hash.HashValue,
+		},
+		azblob.Metadata{},
+		azblob.BlobAccessConditions{
+			ModifiedAccessConditions: azblob.ModifiedAccessConditions{IfMatch: azblob.ETag(version)},
+		},
+		azblob.AccessTierNone,
+		azblob.BlobTagsMap{},
+		azblob.ClientProvidedKeyOptions{},
+		azblob.ImmutabilityPolicyOptions{},
+	)
+	if err != nil {
+		serr, ok := err.(azblob.StorageError)
+		if ok && (serr.ServiceCode() == azblob.ServiceCodeConditionNotMet || serr.ServiceCode() == azblob.ServiceCodeBlobNotFound) {
+			return "", ErrVersionMismatch
+		}
+		return "", err
+	}
+	return string(resp.ETag()), nil
+}
+
 // Remove deletes the blob.
 func (p *AzureBlobPath) Remove() error {
 	ctx := context.TODO()
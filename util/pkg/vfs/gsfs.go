@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -53,6 +54,7 @@ var (
 	_ Path          = &GSPath{}
 	_ TerraformPath = &GSPath{}
 	_ HasHash       = &GSPath{}
+	_ HasVersion    = &GSPath{}
 )
 
 // gcsReadBackoff is the backoff strategy for GCS read retries
@@ -282,6 +284,82 @@ func (p *GSPath) WriteTo(out io.Writer) (int64, error) {
 	return io.Copy(out, response.Body)
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the object generation as the version
+func (p *GSPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	client, err := p.getStorageClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	response, err := client.Objects.Get(p.bucket, p.key).Context(ctx).Download()
+	if err != nil {
+		if isGCSNotFound(err) {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	if response == nil {
+		return nil, "", fmt.Errorf("no response returned from reading %s", p)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	generation := response.Header.Get("X-Goog-Generation")
+	if generation == "" {
+		return nil, "", fmt.Errorf("no generation returned from reading %s", p)
+	}
+	return data, generation, nil
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using an ifGenerationMatch precondition
+func (p *GSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid generation %q for %s: %v", version, p, err)
+	}
+
+	md5Hash, err := hashing.HashAlgorithmMD5.Hash(data)
+	if err != nil {
+		return "", err
+	}
+	if _, err := data.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
+	}
+
+	obj := &storage.Object{
+		Name:    p.key,
+		Md5Hash: base64.StdEncoding.EncodeToString(md5Hash.HashValue),
+	}
+	if acl != nil {
+		gsACL, ok := acl.(*GSAcl)
+		if !ok {
+			return "", fmt.Errorf("write to %s with ACL of unexpected type %T", p, acl)
+		}
+		obj.Acl = gsACL.Acl
+	}
+
+	klog.V(4).Infof("Writing file %q if generation is %d", p, generation)
+
+	client, err := p.getStorageClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	written, err := client.Objects.Insert(p.bucket, obj).IfGenerationMatch(generation).Context(ctx).Media(data).Do()
+	if err != nil {
+		if ae, ok := err.(*googleapi.Error); ok && ae.Code == http.StatusPreconditionFailed {
+			return "", ErrVersionMismatch
+		}
+		return "", fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return strconv.FormatInt(written.Generation, 10), nil
+}
+
 // ReadDir implements Path::ReadDir
 func (p *GSPath) ReadDir() ([]Path, error) {
 	ctx := context.TODO()
@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -36,11 +37,15 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+
+	// version is incremented on every write, and is used to implement HasVersion
+	version int64
 }
 
 var (
 	_ Path          = &MemFSPath{}
 	_ TerraformPath = &MemFSPath{}
+	_ HasVersion    = &MemFSPath{}
 )
 
 type MemFSContext struct {
@@ -112,6 +117,7 @@ func (p *MemFSPath) WriteFile(ctx context.Context, r io.ReadSeeker, acl ACL) err
 	}
 	p.contents = data
 	p.acl = acl
+	p.version++
 	return nil
 }
 
@@ -133,6 +139,31 @@ func (p *MemFSPath) ReadFile(ctx context.Context) ([]byte, error) {
 	return p.contents, nil
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion
+func (p *MemFSPath) ReadFileVersion(ctx context.Context) ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.FormatInt(p.version, 10), nil
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(ctx context.Context, r io.ReadSeeker, acl ACL, version string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil || strconv.FormatInt(p.version, 10) != version {
+		return "", ErrVersionMismatch
+	}
+	if err := p.WriteFile(ctx, r, acl); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(p.version, 10), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *MemFSPath) WriteTo(out io.Writer) (int64, error) {
 	if p.contents == nil {
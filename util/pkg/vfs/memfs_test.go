@@ -167,3 +167,39 @@ func TestMemFsReadTree(t *testing.T) {
 		}
 	}
 }
+
+func TestMemFsWriteFileIfVersion(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	memfspath := NewMemFSPath(NewMemFSContext(), "/root/subdir/versioned.data")
+	if err := memfspath.WriteFile(ctx, bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("Failed writing path: %v", err)
+	}
+
+	_, version, err := memfspath.ReadFileVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed reading path: %v", err)
+	}
+
+	newVersion, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v2")), nil, version)
+	if err != nil {
+		t.Fatalf("Failed conditional write: %v", err)
+	}
+	if newVersion == version {
+		t.Errorf("Expected version to change after write, still %q", version)
+	}
+
+	// Writing against the stale version should be rejected
+	_, err = memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("v3")), nil, version)
+	if err != ErrVersionMismatch {
+		t.Errorf("Expected ErrVersionMismatch, got: %v", err)
+	}
+
+	data, _, err := memfspath.ReadFileVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed reading path: %v", err)
+	}
+	if string(data) != "v2" {
+		t.Errorf("Expected content %q, got %q", "v2", string(data))
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrVersionMismatch is returned by WriteFileIfVersion when the file was modified
+// after the version was read.
+var ErrVersionMismatch = errors.New("file was modified concurrently")
+
+// HasVersion is implemented by Paths whose backing store can make writes conditional
+// on the revision of the file, e.g. an ETag or an object generation.
+type HasVersion interface {
+	// ReadFileVersion returns the contents of the file, along with an opaque token identifying the revision that was read.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileVersion(ctx context.Context) ([]byte, string, error)
+
+	// WriteFileIfVersion writes the file contents, but only if the file is still at the revision identified by version.
+	// It returns the token for the newly written revision, or ErrVersionMismatch if the file has been modified.
+	WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error)
+}